@@ -0,0 +1,36 @@
+package api
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+)
+
+// RequireToken wraps a handler so that it only serves requests carrying one of the given bearer tokens.
+// With no tokens configured every request is rejected, so the API is never exposed unauthenticated.
+func RequireToken(tokens []string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !isAuthorized(tokens, r) {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="slack-to-google-sheets-bot"`)
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next(w, r)
+	}
+}
+
+// isAuthorized reports whether the request's bearer token matches a configured token
+func isAuthorized(tokens []string, r *http.Request) bool {
+	header := r.Header.Get("Authorization")
+	if !strings.HasPrefix(header, "Bearer ") {
+		return false
+	}
+	presented := []byte(strings.TrimPrefix(header, "Bearer "))
+
+	for _, token := range tokens {
+		if token != "" && subtle.ConstantTimeCompare(presented, []byte(token)) == 1 {
+			return true
+		}
+	}
+	return false
+}
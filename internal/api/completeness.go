@@ -0,0 +1,33 @@
+package api
+
+import (
+	"net/http"
+
+	"slack-to-google-sheets-bot/internal/completeness"
+)
+
+// HandleCompleteness returns an http.HandlerFunc that reports message events received vs rows recorded per channel per day
+func HandleCompleteness(tracker *completeness.Tracker) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		counts := tracker.Snapshot()
+		if channelID := r.URL.Query().Get("channel"); channelID != "" {
+			var filtered []completeness.Count
+			for _, count := range counts {
+				if count.ChannelID == channelID {
+					filtered = append(filtered, count)
+				}
+			}
+			counts = filtered
+		}
+		if counts == nil {
+			counts = []completeness.Count{}
+		}
+
+		writeJSON(w, map[string]interface{}{"days": counts})
+	}
+}
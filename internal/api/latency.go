@@ -0,0 +1,24 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"slack-to-google-sheets-bot/internal/latency"
+)
+
+// HandleLatency returns an http.HandlerFunc that reports the latency from Slack event receipt to sheet write
+func HandleLatency(tracker *latency.Tracker) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		snapshot := tracker.Snapshot(time.Now())
+		if snapshot.Days == nil {
+			snapshot.Days = []latency.DaySummary{}
+		}
+		writeJSON(w, snapshot)
+	}
+}
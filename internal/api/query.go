@@ -0,0 +1,281 @@
+package api
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"slack-to-google-sheets-bot/internal/search"
+	"slack-to-google-sheets-bot/internal/sheets"
+	"slack-to-google-sheets-bot/internal/store"
+)
+
+const (
+	// defaultQueryLimit is the number of messages returned when no limit is given
+	defaultQueryLimit = 100
+	// maxQueryLimit caps the number of messages returned in a single response
+	maxQueryLimit = 1000
+)
+
+// Message is the JSON representation of a recorded message returned by the query API
+type Message struct {
+	ChannelID    string `json:"channel_id"`
+	ChannelName  string `json:"channel_name"`
+	Timestamp    string `json:"timestamp"` // RFC3339
+	User         string `json:"user"`
+	UserHandle   string `json:"user_handle"`
+	UserRealName string `json:"user_real_name"`
+	Text         string `json:"text"`
+	ThreadTS     string `json:"thread_ts,omitempty"`
+	MessageTS    string `json:"message_ts"`
+}
+
+// QueryResponse is the JSON body returned by the messages endpoint
+type QueryResponse struct {
+	Messages   []Message `json:"messages"`
+	Total      int       `json:"total"`
+	Limit      int       `json:"limit"`
+	Offset     int       `json:"offset"`
+	HasMore    bool      `json:"has_more"`
+	NextOffset *int      `json:"next_offset,omitempty"` // Offset of the next page, absent on the last page
+}
+
+// Channel is the JSON representation of a channel summary returned by the channels endpoint
+type Channel struct {
+	ChannelID    string `json:"channel_id"`
+	ChannelName  string `json:"channel_name"`
+	MessageCount int    `json:"message_count"`
+	FirstMessage string `json:"first_message,omitempty"` // RFC3339
+	LastMessage  string `json:"last_message,omitempty"`  // RFC3339
+}
+
+// ChannelsResponse is the JSON body returned by the channels endpoint
+type ChannelsResponse struct {
+	Channels []Channel `json:"channels"`
+}
+
+// NewMessage converts a message record into its API representation
+func NewMessage(record *sheets.MessageRecord) Message {
+	return Message{
+		ChannelID:    record.Channel,
+		ChannelName:  record.ChannelName,
+		Timestamp:    record.Timestamp.Format(time.RFC3339),
+		User:         record.User,
+		UserHandle:   record.UserHandle,
+		UserRealName: record.UserRealName,
+		Text:         record.Text,
+		ThreadTS:     record.ThreadTS,
+		MessageTS:    record.MessageTS,
+	}
+}
+
+// HandleMessages serves read-only queries over the message store.
+// Supported parameters: channel, user, from, to (RFC3339 or 2006-01-02), q (text), limit, offset.
+func HandleMessages(messageStore *store.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		query, err := parseQuery(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		records, total, err := messageStore.Query(query)
+		if err != nil {
+			log.Printf("Error querying message store: %v", err)
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+
+		response := QueryResponse{
+			Messages: make([]Message, 0, len(records)),
+			Total:    total,
+			Limit:    query.Limit,
+			Offset:   query.Offset,
+		}
+		for _, record := range records {
+			response.Messages = append(response.Messages, NewMessage(record))
+		}
+		if next := query.Offset + len(records); next < total {
+			response.HasMore = true
+			response.NextOffset = &next
+		}
+
+		writeJSON(w, response)
+	}
+}
+
+// SearchHit is a ranked search result
+type SearchHit struct {
+	Message
+	Score float64 `json:"score"`
+}
+
+// SearchResponse is the JSON body returned by the search endpoint
+type SearchResponse struct {
+	Results []SearchHit `json:"results"`
+}
+
+// HandleSearch serves ranked full-text search over the search index.
+// Supported parameters: q (required), channel, limit.
+func HandleSearch(index *search.Index) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		params := r.URL.Query()
+		query := params.Get("q")
+		if query == "" {
+			http.Error(w, errBadParam("q").Error(), http.StatusBadRequest)
+			return
+		}
+
+		limit := defaultQueryLimit
+		if value := params.Get("limit"); value != "" {
+			parsed, err := strconv.Atoi(value)
+			if err != nil || parsed <= 0 {
+				http.Error(w, errBadParam("limit").Error(), http.StatusBadRequest)
+				return
+			}
+			if parsed < maxQueryLimit {
+				limit = parsed
+			} else {
+				limit = maxQueryLimit
+			}
+		}
+
+		channelID := params.Get("channel")
+		if channelID != "" && !store.ValidChannelID(channelID) {
+			http.Error(w, errBadParam("channel").Error(), http.StatusBadRequest)
+			return
+		}
+
+		results := index.Search(query, channelID, limit)
+
+		response := SearchResponse{Results: make([]SearchHit, 0, len(results))}
+		for _, result := range results {
+			response.Results = append(response.Results, SearchHit{Message: NewMessage(result.Record), Score: result.Score})
+		}
+
+		writeJSON(w, response)
+	}
+}
+
+// HandleChannels lists the channels available in the message store
+func HandleChannels(messageStore *store.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		summaries, err := messageStore.Channels()
+		if err != nil {
+			log.Printf("Error listing channels in message store: %v", err)
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+
+		response := ChannelsResponse{Channels: make([]Channel, 0, len(summaries))}
+		for _, summary := range summaries {
+			channel := Channel{
+				ChannelID:    summary.ChannelID,
+				ChannelName:  summary.ChannelName,
+				MessageCount: summary.MessageCount,
+			}
+			if !summary.FirstMessage.IsZero() {
+				channel.FirstMessage = summary.FirstMessage.Format(time.RFC3339)
+				channel.LastMessage = summary.LastMessage.Format(time.RFC3339)
+			}
+			response.Channels = append(response.Channels, channel)
+		}
+
+		writeJSON(w, response)
+	}
+}
+
+// writeJSON writes a JSON response body
+func writeJSON(w http.ResponseWriter, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		log.Printf("Error encoding API response: %v", err)
+	}
+}
+
+// parseQuery builds a store query from URL parameters
+func parseQuery(r *http.Request) (store.Query, error) {
+	params := r.URL.Query()
+
+	query := store.Query{
+		ChannelID: params.Get("channel"),
+		User:      params.Get("user"),
+		Text:      params.Get("q"),
+		Limit:     defaultQueryLimit,
+	}
+	if query.ChannelID != "" && !store.ValidChannelID(query.ChannelID) {
+		return query, errBadParam("channel")
+	}
+
+	var err error
+	if query.From, err = parseTimeParam(params.Get("from")); err != nil {
+		return query, err
+	}
+	if query.To, err = parseTimeParam(params.Get("to")); err != nil {
+		return query, err
+	}
+
+	if value := params.Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit <= 0 {
+			return query, errBadParam("limit")
+		}
+		if limit > maxQueryLimit {
+			limit = maxQueryLimit
+		}
+		query.Limit = limit
+	}
+
+	if value := params.Get("offset"); value != "" {
+		offset, err := strconv.Atoi(value)
+		if err != nil || offset < 0 {
+			return query, errBadParam("offset")
+		}
+		query.Offset = offset
+	}
+
+	return query, nil
+}
+
+// parseTimeParam parses an RFC3339 timestamp or a plain date (interpreted in JST)
+func parseTimeParam(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", value, time.FixedZone("JST", 9*60*60)); err == nil {
+		return t, nil
+	}
+	return time.Time{}, errBadParam("from/to")
+}
+
+// paramError is returned for malformed query parameters
+type paramError string
+
+func (e paramError) Error() string {
+	return "invalid parameter: " + string(e)
+}
+
+// errBadParam creates an error for a malformed query parameter
+func errBadParam(name string) error {
+	return paramError(name)
+}
@@ -0,0 +1,20 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"slack-to-google-sheets-bot/internal/quota"
+)
+
+// HandleQuota returns an http.HandlerFunc that reports Sheets API usage against the quota
+func HandleQuota(tracker *quota.Tracker) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		writeJSON(w, tracker.Snapshot(time.Now()))
+	}
+}
@@ -0,0 +1,103 @@
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Entry represents a single audit log entry
+type Entry struct {
+	Time      time.Time `json:"time"`
+	Action    string    `json:"action"`
+	ChannelID string    `json:"channel_id,omitempty"`
+	User      string    `json:"user,omitempty"`
+	Detail    string    `json:"detail,omitempty"`
+}
+
+// Logger appends audit entries to a JSON Lines file
+type Logger struct {
+	tmpDir string
+	mutex  sync.Mutex
+}
+
+// NewLogger creates a new audit logger
+func NewLogger() *Logger {
+	return &Logger{
+		tmpDir: "/tmp/slack-bot-audit",
+	}
+}
+
+// getLogFilePath returns the file path of the audit log
+func (l *Logger) getLogFilePath() string {
+	return filepath.Join(l.tmpDir, "audit.jsonl")
+}
+
+// Record appends an entry to the audit log, filling in the time if it is not set
+func (l *Logger) Record(entry Entry) error {
+	if entry.Time.IsZero() {
+		entry.Time = time.Now()
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if err := os.MkdirAll(l.tmpDir, 0755); err != nil {
+		return fmt.Errorf("failed to create tmp directory: %v", err)
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit entry: %v", err)
+	}
+
+	file, err := os.OpenFile(l.getLogFilePath(), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %v", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write audit entry: %v", err)
+	}
+
+	log.Printf("Audit: action=%s, channel=%s, user=%s, detail=%s", entry.Action, entry.ChannelID, entry.User, entry.Detail)
+	return nil
+}
+
+// Entries returns the entries with the given action, oldest first
+func (l *Logger) Entries(action string) ([]Entry, error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	file, err := os.Open(l.getLogFilePath())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %v", err)
+	}
+	defer file.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue // A line cut short by a crash does not hide the rest of the log
+		}
+		if entry.Action == action {
+			entries = append(entries, entry)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read audit log: %v", err)
+	}
+	return entries, nil
+}
@@ -0,0 +1,106 @@
+package budget
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// ChannelUsage represents the number of messages recorded for a channel in a given month
+type ChannelUsage struct {
+	ChannelID string    `json:"channel_id"`
+	Month     string    `json:"month"` // "2006-01"
+	Count     int       `json:"count"`
+	Alerted   bool      `json:"alerted"` // Whether the alert has already been sent this month
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Manager tracks monthly recorded-message counts per channel
+type Manager struct {
+	tmpDir string
+	mutex  sync.Mutex
+}
+
+// NewManager creates a new budget manager
+func NewManager() *Manager {
+	return &Manager{
+		tmpDir: "/tmp/slack-bot-budget",
+	}
+}
+
+// getUsageFilePath returns the file path for a channel's usage counter
+func (m *Manager) getUsageFilePath(channelID string) string {
+	return filepath.Join(m.tmpDir, fmt.Sprintf("channel_%s.json", channelID))
+}
+
+// loadUsage loads the usage counter for a channel, returning an empty counter if none exists
+func (m *Manager) loadUsage(channelID string) (*ChannelUsage, error) {
+	data, err := os.ReadFile(m.getUsageFilePath(channelID))
+	if os.IsNotExist(err) {
+		return &ChannelUsage{ChannelID: channelID}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read usage file: %v", err)
+	}
+
+	var usage ChannelUsage
+	if err := json.Unmarshal(data, &usage); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal usage: %v", err)
+	}
+	return &usage, nil
+}
+
+// saveUsage persists the usage counter for a channel
+func (m *Manager) saveUsage(usage *ChannelUsage) error {
+	if err := os.MkdirAll(m.tmpDir, 0755); err != nil {
+		return fmt.Errorf("failed to create tmp directory: %v", err)
+	}
+
+	data, err := json.MarshalIndent(usage, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal usage: %v", err)
+	}
+
+	if err := os.WriteFile(m.getUsageFilePath(usage.ChannelID), data, 0644); err != nil {
+		return fmt.Errorf("failed to write usage file: %v", err)
+	}
+	return nil
+}
+
+// RecordMessages adds count recorded messages to the channel's counter for the current month.
+// It returns the updated monthly total and whether the threshold was crossed for the first time this month.
+// A threshold of 0 or less disables alerting.
+func (m *Manager) RecordMessages(channelID string, count, threshold int) (int, bool, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	usage, err := m.loadUsage(channelID)
+	if err != nil {
+		return 0, false, err
+	}
+
+	now := time.Now()
+	month := now.Format("2006-01")
+	if usage.Month != month {
+		usage.Month = month
+		usage.Count = 0
+		usage.Alerted = false
+	}
+
+	usage.Count += count
+	usage.UpdatedAt = now
+
+	crossed := false
+	if threshold > 0 && usage.Count >= threshold && !usage.Alerted {
+		usage.Alerted = true
+		crossed = true
+	}
+
+	if err := m.saveUsage(usage); err != nil {
+		return usage.Count, false, err
+	}
+	return usage.Count, crossed, nil
+}
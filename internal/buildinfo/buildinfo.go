@@ -0,0 +1,38 @@
+package buildinfo
+
+import "runtime/debug"
+
+// Version is the release of the bot, set at build time with
+// -ldflags "-X slack-to-google-sheets-bot/internal/buildinfo.Version=..." (the Makefile passes git describe)
+var Version = ""
+
+// String returns Version, or the VCS revision Go stamped into the binary when it was built without ldflags,
+// or "dev" when neither is known (e.g. go run)
+func String() string {
+	if Version != "" {
+		return Version
+	}
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "dev"
+	}
+	revision, modified := "", false
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			revision = setting.Value
+		case "vcs.modified":
+			modified = setting.Value == "true"
+		}
+	}
+	if revision == "" {
+		return "dev"
+	}
+	if len(revision) > 12 {
+		revision = revision[:12]
+	}
+	if modified {
+		revision += "-dirty"
+	}
+	return revision
+}
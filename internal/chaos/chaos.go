@@ -0,0 +1,141 @@
+package chaos
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"math/rand"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// simulatedTimeout is how long a request hangs before an injected timeout is returned
+const simulatedTimeout = 10 * time.Second
+
+// Rates are the shares (0 to 1) of requests that fail in each way
+type Rates struct {
+	// RateLimit answers Slack and Google requests with 429 and "Retry-After: 1"
+	RateLimit float64
+	// Timeout makes requests hang for simulatedTimeout and then fail with a timeout error
+	Timeout float64
+	// SheetsWrite answers Sheets/Drive write requests with 503, so batch writes fail chunk by chunk
+	SheetsWrite float64
+}
+
+// Enabled reports whether any failure is injected
+func (r Rates) Enabled() bool {
+	return r.RateLimit > 0 || r.Timeout > 0 || r.SheetsWrite > 0
+}
+
+var (
+	rates      Rates
+	random     = rand.New(rand.NewSource(time.Now().UnixNano()))
+	randomLock sync.Mutex
+)
+
+// SetRates turns failure injection on for transports returned by Transport; call it once at startup.
+// It is meant for validating retries and the retry spool against a test workspace, never for production.
+func SetRates(r Rates) {
+	rates = r
+}
+
+// Transport wraps an HTTP transport so requests fail at the configured rates; it returns base unchanged when
+// injection is off
+func Transport(base http.RoundTripper) http.RoundTripper {
+	if !rates.Enabled() {
+		return base
+	}
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &faultTransport{base: base}
+}
+
+// faultTransport injects failures before requests reach the real transport
+type faultTransport struct {
+	base http.RoundTripper
+}
+
+// timeoutError is returned for injected timeouts; like net/http's own, it reports Timeout() as true
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "chaos: simulated timeout awaiting response headers" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+// roll reports whether an event with the given probability happens
+func roll(probability float64) bool {
+	if probability <= 0 {
+		return false
+	}
+	randomLock.Lock()
+	defer randomLock.Unlock()
+	return random.Float64() < probability
+}
+
+// isGoogleRequest reports whether a request goes to the Sheets or Drive API (real or the load test fake)
+func isGoogleRequest(req *http.Request) bool {
+	return strings.HasSuffix(req.URL.Host, "googleapis.com") ||
+		strings.Contains(req.URL.Path, "/v4/spreadsheets") ||
+		strings.Contains(req.URL.Path, "/drive/v3/")
+}
+
+// RoundTrip fails the request as configured, or passes it on
+func (t *faultTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	google := isGoogleRequest(req)
+
+	if roll(rates.Timeout) {
+		log.Printf("chaos: injecting timeout for %s %s", req.Method, req.URL.Path)
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		select {
+		case <-time.After(simulatedTimeout):
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+		return nil, timeoutError{}
+	}
+
+	if roll(rates.RateLimit) {
+		log.Printf("chaos: injecting 429 for %s %s", req.Method, req.URL.Path)
+		body := `{"ok":false,"error":"ratelimited"}`
+		if google {
+			body = `{"error":{"code":429,"message":"chaos: simulated quota exceeded","status":"RESOURCE_EXHAUSTED"}}`
+		}
+		return fakeResponse(req, http.StatusTooManyRequests, body), nil
+	}
+
+	if google && req.Method != http.MethodGet && roll(rates.SheetsWrite) {
+		log.Printf("chaos: injecting 503 for %s %s", req.Method, req.URL.Path)
+		body := `{"error":{"code":503,"message":"chaos: simulated backend error","status":"UNAVAILABLE"}}`
+		return fakeResponse(req, http.StatusServiceUnavailable, body), nil
+	}
+
+	return t.base.RoundTrip(req)
+}
+
+// fakeResponse builds a JSON error response without contacting the server
+func fakeResponse(req *http.Request, status int, body string) *http.Response {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+	header := make(http.Header)
+	header.Set("Content-Type", "application/json")
+	if status == http.StatusTooManyRequests {
+		header.Set("Retry-After", "1")
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
@@ -0,0 +1,141 @@
+package completeness
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// historyDays is how many days of counts are kept
+const historyDays = 30
+
+// Count compares the message events Slack delivered for a channel on one day with the rows recorded for them
+type Count struct {
+	Date      string `json:"date"` // YYYY-MM-DD (JST)
+	ChannelID string `json:"channel_id"`
+	Received  int    `json:"received"` // Message events delivered by Slack
+	Recorded  int    `json:"recorded"` // Rows written to the sheet
+	Skipped   int    `json:"skipped"`  // Intentionally not recorded (opt-out, recording schedule, backfill in progress, ...)
+	Failed    int    `json:"failed"`   // Writes that failed
+	// Completeness is recorded / (received - skipped), or 1 when nothing was expected
+	Completeness float64 `json:"completeness"`
+}
+
+// Tracker counts received and recorded message events per channel per day
+type Tracker struct {
+	mutex    sync.Mutex
+	location *time.Location
+	days     map[string]map[string]*Count // Date -> channel ID -> count
+}
+
+var defaultTracker = NewTracker()
+
+// Default returns the process-wide tracker
+func Default() *Tracker {
+	return defaultTracker
+}
+
+// NewTracker creates an empty tracker that groups events by JST day
+func NewTracker() *Tracker {
+	return &Tracker{
+		location: time.FixedZone("JST", 9*60*60),
+		days:     make(map[string]map[string]*Count),
+	}
+}
+
+// Received counts a message event delivered for a channel
+func (t *Tracker) Received(channelID string, at time.Time) {
+	t.add(channelID, at, func(count *Count) { count.Received++ })
+}
+
+// Recorded counts a row written for a channel
+func (t *Tracker) Recorded(channelID string, at time.Time) {
+	t.add(channelID, at, func(count *Count) { count.Recorded++ })
+}
+
+// Skipped counts a message event that was intentionally not recorded
+func (t *Tracker) Skipped(channelID string, at time.Time) {
+	t.add(channelID, at, func(count *Count) { count.Skipped++ })
+}
+
+// Failed counts a message event whose row could not be written
+func (t *Tracker) Failed(channelID string, at time.Time) {
+	t.add(channelID, at, func(count *Count) { count.Failed++ })
+}
+
+// add updates the count of a channel for the day of the given time, dropping days beyond historyDays
+func (t *Tracker) add(channelID string, at time.Time, update func(count *Count)) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	date := at.In(t.location).Format("2006-01-02")
+	channels, exists := t.days[date]
+	if !exists {
+		channels = make(map[string]*Count)
+		t.days[date] = channels
+		t.prune()
+	}
+	count, exists := channels[channelID]
+	if !exists {
+		count = &Count{Date: date, ChannelID: channelID}
+		channels[channelID] = count
+	}
+	update(count)
+}
+
+// prune drops the oldest days beyond historyDays; callers must hold the mutex
+func (t *Tracker) prune() {
+	if len(t.days) <= historyDays {
+		return
+	}
+	dates := make([]string, 0, len(t.days))
+	for date := range t.days {
+		dates = append(dates, date)
+	}
+	sort.Strings(dates)
+	for _, date := range dates[:len(dates)-historyDays] {
+		delete(t.days, date)
+	}
+}
+
+// Snapshot returns the counts of all kept days, newest day first and by channel ID within a day
+func (t *Tracker) Snapshot() []Count {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	var counts []Count
+	for _, channels := range t.days {
+		for _, count := range channels {
+			counts = append(counts, withCompleteness(*count))
+		}
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Date != counts[j].Date {
+			return counts[i].Date > counts[j].Date
+		}
+		return counts[i].ChannelID < counts[j].ChannelID
+	})
+	return counts
+}
+
+// Day returns the counts of one day (YYYY-MM-DD) by channel ID
+func (t *Tracker) Day(date string) []Count {
+	var counts []Count
+	for _, count := range t.Snapshot() {
+		if count.Date == date {
+			counts = append(counts, count)
+		}
+	}
+	return counts
+}
+
+// withCompleteness fills in the completeness ratio of a count
+func withCompleteness(count Count) Count {
+	expected := count.Received - count.Skipped
+	if expected <= 0 {
+		count.Completeness = 1
+		return count
+	}
+	count.Completeness = float64(count.Recorded) / float64(expected)
+	return count
+}
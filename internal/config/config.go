@@ -3,16 +3,255 @@ package config
 import (
 	"log"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
+
+	"slack-to-google-sheets-bot/internal/settings"
 )
 
+// ChannelSettingsProvider supplies per-channel settings edited outside the environment, such as the settings sheet
+type ChannelSettingsProvider interface {
+	ChannelSetting(channelID, key string) (string, bool)
+}
+
 type Config struct {
 	SlackBotToken           string
 	SlackSigningSecret      string
 	GoogleSheetsCredentials string
-	SpreadsheetID           string
-	Port                    string
+
+	// SlackClientID, SlackClientSecret and SlackOAuthRedirectURL enable the OAuth install flow, so several workspaces
+	// can install the app; their events use the bot token stored at install time instead of SlackBotToken
+	SlackClientID         string
+	SlackClientSecret     string
+	SlackOAuthRedirectURL string
+	// InstallationDir is the durable data directory of the bot tokens stored by the OAuth install flow
+	InstallationDir string
+	// TeamSpreadsheetIDs maps the workspaces installed through the OAuth flow to their own spreadsheet, so one
+	// workspace's "show me" never shares another's tabs; events of installed workspaces without one are refused
+	TeamSpreadsheetIDs map[string]string
+
+	SpreadsheetID string
+	Port          string
+
+	// ThreadMirrorMode controls what the bot posts back into a thread once it concludes ("", "reaction" or "note")
+	ThreadMirrorMode string
+	// ThreadMirrorReaction is the emoji name used when ThreadMirrorMode is "reaction"
+	ThreadMirrorReaction string
+	// ThreadMirrorIdleMinutes is how long a thread must stay quiet before it is considered concluded
+	ThreadMirrorIdleMinutes int
+	// EditDebounceSeconds is how long edits are collected before their rows are rewritten together (0 writes each edit at once)
+	EditDebounceSeconds int
+
+	// AdminChannelID is the channel that receives operational notifications such as budget alerts
+	AdminChannelID string
+	// AdminUserIDs are the Slack users allowed to run admin-only commands such as "Reset! force"
+	AdminUserIDs []string
+	// LegalHoldDir is the durable data directory of the legal hold registry
+	LegalHoldDir string
+	// InstanceLockFile is the lock file kept next to the durable data directories (installations, message store,
+	// legal holds) so only one instance writes them; unlike the host lock in /tmp it can sit on a shared volume
+	InstanceLockFile string
+	// MessageAlertThresholds maps channel IDs (or "default") to a monthly recorded-message alert threshold
+	MessageAlertThresholds map[string]int
+
+	// RecordingSchedules maps channel IDs (or "default") to a recording window spec such as "09:00-21:00|weekdays"
+	RecordingSchedules map[string]string
+
+	// OptOutPolicy decides how messages by opted-out users are handled ("redact" or "skip")
+	OptOutPolicy string
+
+	// RecordProfileFields adds the poster's job title and team columns to the sheet
+	RecordProfileFields bool
+	// ProfileTeamFieldID is the ID of the custom Slack profile field that holds the poster's team
+	ProfileTeamFieldID string
+
+	// RecordTextStats adds character and word count columns to the sheet
+	RecordTextStats bool
+
+	// RecordReactions adds a reactions column that is kept up to date from reaction_added / reaction_removed events
+	RecordReactions bool
+	// RecordEngagement adds numeric reply count and reaction count columns, kept up to date from replies and reactions
+	RecordEngagement bool
+	// RecordFiles adds file name, type, size and permalink columns for shared files
+	RecordFiles bool
+	// RecordPins adds a pinned column that is toggled by pin_added / pin_removed events
+	RecordPins bool
+	// RecordTraceID adds an internal column with the trace ID of the event that wrote each row
+	RecordTraceID bool
+	// RecordPermalinks adds a column with the Slack link of each message
+	RecordPermalinks bool
+	// RecordDeletions adds a status column where rows of deleted messages are marked instead of being left as if live
+	RecordDeletions bool
+	// RecordEditHistory adds a column keeping the earlier texts of edited messages instead of only overwriting them
+	RecordEditHistory bool
+	// RecordTeamID adds a column with the ID of the workspace each message was posted from (Enterprise Grid and
+	// shared channels)
+	RecordTeamID bool
+	// SlackTeamIDs are the workspaces of an Enterprise Grid organization whose channels are listed for channel
+	// discovery; org-wide tokens can only list channels one workspace at a time
+	SlackTeamIDs []string
+	// SlackTeamID is the workspace an event or command came from on Enterprise Grid, set per request by WithTeam
+	// and sent with the API methods that org-wide tokens need it for
+	SlackTeamID string
+	// ThreadExportEmoji is the reaction (without colons) with which admins export a thread to its own tab ("" disables)
+	ThreadExportEmoji string
+	// TimestampFormat is "iso8601" to write posted times as RFC 3339 with the UTC offset plus a date column,
+	// or empty for "2006-01-02 15:04:05" in the channel's timezone
+	TimestampFormat string
+	// LinkFormulas writes the text of a message with exactly one link as a HYPERLINK formula
+	LinkFormulas bool
+
+	// RecordClientMetadata adds the posting app and the last editor columns to the sheet
+	RecordClientMetadata bool
+
+	// IdentityResolver selects how Slack user IDs are mapped to employee IDs ("csv", "http" or "" to disable)
+	IdentityResolver string
+	// IdentitySource is the CSV file path or HTTP URL template ("{user_id}" placeholder) for the resolver
+	IdentitySource string
+
+	// MessageStoreEnabled persists recorded messages locally and exposes the read-only query API
+	MessageStoreEnabled bool
+	// MessageStoreDir is the durable data directory of the message store, which the search index is rebuilt from
+	MessageStoreDir string
+	// APITokens are the bearer tokens accepted by the query API
+	APITokens []string
+
+	// NormalizeSearchText applies NFKC and zenkaku/hankaku normalization when indexing and searching
+	NormalizeSearchText bool
+	// NormalizeRecordedText applies the same normalization to message text written to the sheet
+	NormalizeRecordedText bool
+
+	// NotificationTargets maps channel IDs (or "default") to where job results go ("channel", "ops" or "dm")
+	NotificationTargets map[string]string
+	// RecordModes maps channel IDs (or "default") to "full", "metadata" (timestamps, users, message length and
+	// thread structure without the message text) or "encrypted" (metadata in the sheet, the content in an encrypted
+	// archive)
+	RecordModes map[string]string
+
+	// NotificationVerbosities maps channel IDs (or "default") to how much is posted ("full", "brief", "errors" or "none")
+	NotificationVerbosities map[string]string
+
+	// DefaultLanguage is the reply language used when the invoking user's Slack locale is unsupported ("ja" or "en")
+	DefaultLanguage string
+
+	// SettingsSheetEnabled reads per-channel settings from a tab of the spreadsheet
+	SettingsSheetEnabled bool
+	// SettingsSheetName is the name of the settings tab
+	SettingsSheetName string
+	// SettingsSheetRefreshMinutes is how often the settings tab is re-read
+	SettingsSheetRefreshMinutes int
+
+	// SheetsReadQuotaPerMinute and SheetsWriteQuotaPerMinute are the Sheets API quotas usage is compared against
+	SheetsReadQuotaPerMinute  int
+	SheetsWriteQuotaPerMinute int
+	// WeeklyAdminReport posts a weekly usage report to AdminChannelID
+	WeeklyAdminReport bool
+	// LatencySLOP95Seconds alerts AdminChannelID when the last hour's p95 from event receipt to sheet write exceeds it (0 disables)
+	LatencySLOP95Seconds int
+
+	// AccessReview posts a quarterly review of who the spreadsheets are shared with to AdminChannelID
+	AccessReview bool
+	// AccessReviewInternalDomains are the email domains not flagged as external in the access review
+	AccessReviewInternalDomains []string
+	// AccessReviewStaleDays flags grants made with "show me" longer ago than this many days
+	AccessReviewStaleDays int
+
+	// AuditSheetEnabled also appends audit entries to a tab of the spreadsheet
+	AuditSheetEnabled bool
+	// AuditSheetName is the name of the audit tab
+	AuditSheetName string
+
+	// DailyRollupEnabled maintains a tab with per-day message counts linking to the first row of each day
+	DailyRollupEnabled bool
+	// DailyRollupSheetName is the name of the rollup tab
+	DailyRollupSheetName string
+
+	// ArrangeTabs orders channel tabs by recent activity and colors them by the date of their last message
+	ArrangeTabs bool
+
+	// SpreadsheetSplitEnabled routes new channels to continuation spreadsheets once a spreadsheet gets too large
+	SpreadsheetSplitEnabled bool
+	// SpreadsheetSplitMaxTabs is the tab count at which a continuation spreadsheet is created
+	SpreadsheetSplitMaxTabs int
+	// SpreadsheetSplitMaxCells is the cell count at which a continuation spreadsheet is created (Google's limit is 10 million)
+	SpreadsheetSplitMaxCells int64
+	// SpreadsheetIndexSheetName is the tab of the original spreadsheet that links to the continuations
+	SpreadsheetIndexSheetName string
+
+	// MirrorSpreadsheetID is an optional second spreadsheet that receives the same writes in the background
+	MirrorSpreadsheetID string
+
+	// StateEncryptionKey (base64, 32 bytes) encrypts progress files, the message store and the retry spool at rest;
+	// StateEncryptionKeyFile reads it from a file instead, e.g. a mounted secret
+	StateEncryptionKey     string
+	StateEncryptionKeyFile string
+
+	// ExportPublicKeyFile is the ASCII-armored OpenPGP public key file the content of "encrypted" channels is
+	// encrypted to; EncryptedExportDir is where the encrypted archive files are written
+	ExportPublicKeyFile string
+	EncryptedExportDir  string
+
+	// SpreadsheetCredentials maps spreadsheet IDs to the service account credentials file used for them
+	SpreadsheetCredentials map[string]string
+
+	// SpreadsheetGroups maps spreadsheet IDs (or "default") to a Google Group granted read access; "show me" then
+	// adds people to the group instead of sharing the spreadsheet with each of them
+	SpreadsheetGroups map[string]string
+	// GroupAdminEmail is the Workspace admin the service account acts as (domain-wide delegation) to add group members
+	GroupAdminEmail string
+
+	// CompletenessSheetName is the tab that receives yesterday's received vs recorded counts every day (empty to disable)
+	CompletenessSheetName string
+
+	// ManifestSheetName is the tab that receives a manifest row (version, parameters, period, counts, checksum) after
+	// every backfill (empty to disable)
+	ManifestSheetName string
+
+	// ChannelInfoSheetName is the tab keeping the current topic and purpose of each channel (empty to disable)
+	ChannelInfoSheetName string
+
+	// ResetConfirmation makes "Reset!" reply with a preview of what would be deleted and wait for "Reset! confirm"
+	ResetConfirmation bool
+
+	// RecordDMChannels lists the DMs and group DMs to record ("*" records every one the bot is in); empty disables
+	RecordDMChannels []string
+
+	// RecordSharedChannels records channels shared with other organizations (Slack Connect); false refuses them
+	RecordSharedChannels bool
+
+	// RecordMembershipMessages records channel_join / channel_leave messages as system rows instead of dropping them
+	RecordMembershipMessages bool
+
+	// BackfillCooldownHours is how long after a completed backfill a re-invite does not trigger another one (0 disables)
+	BackfillCooldownHours int
+
+	// AutoDiscoverChannels lists the channels the bot is a member of at startup and every ChannelSyncIntervalMinutes,
+	// backfilling new ones and appending messages missed by the others
+	AutoDiscoverChannels       bool
+	ChannelSyncIntervalMinutes int
+	// IncrementalSyncMinutes appends the messages missed by every channel tab of the spreadsheets at this
+	// interval, e.g. the ones posted while the bot was down (0 disables)
+	IncrementalSyncMinutes int
+
+	// SlackAPIBaseURL and GoogleAPIEndpoint point the bot at fake backends for load testing (empty means the real APIs)
+	SlackAPIBaseURL   string
+	GoogleAPIEndpoint string
+	// DevSheets set to "local" writes the spreadsheets as CSV files under DevSheetsDir instead of Google Sheets, for
+	// development and demos without a Google project
+	DevSheets    string
+	DevSheetsDir string
+
+	// ChaosRateLimitRate, ChaosTimeoutRate and ChaosSheetsWriteFailureRate inject simulated 429s, timeouts and
+	// failed Sheets writes into this share (0 to 1) of API requests, for testing retries and the retry spool
+	ChaosRateLimitRate          float64
+	ChaosTimeoutRate            float64
+	ChaosSheetsWriteFailureRate float64
+
+	// ChannelSettings overrides the environment configuration per channel when set
+	ChannelSettings ChannelSettingsProvider
 }
 
 func Load() *Config {
@@ -22,12 +261,254 @@ func Load() *Config {
 	}
 
 	return &Config{
-		SlackBotToken:           os.Getenv("SLACK_BOT_TOKEN"),
-		SlackSigningSecret:      os.Getenv("SLACK_SIGNING_SECRET"),
-		GoogleSheetsCredentials: os.Getenv("GOOGLE_SHEETS_CREDENTIALS"),
-		SpreadsheetID:           os.Getenv("GOOGLE_SPREADSHEET_ID"),
-		Port:                    getEnvOrDefault("PORT", "8080"),
+		SlackBotToken:               os.Getenv("SLACK_BOT_TOKEN"),
+		SlackSigningSecret:          os.Getenv("SLACK_SIGNING_SECRET"),
+		SlackClientID:               os.Getenv("SLACK_CLIENT_ID"),
+		SlackClientSecret:           os.Getenv("SLACK_CLIENT_SECRET"),
+		SlackOAuthRedirectURL:       os.Getenv("SLACK_OAUTH_REDIRECT_URL"),
+		InstallationDir:             getEnvOrDefault("INSTALLATION_DIR", "installations"),
+		TeamSpreadsheetIDs:          parseChannelMap("TEAM_SPREADSHEET_IDS"),
+		GoogleSheetsCredentials:     os.Getenv("GOOGLE_SHEETS_CREDENTIALS"),
+		SpreadsheetID:               os.Getenv("GOOGLE_SPREADSHEET_ID"),
+		Port:                        getEnvOrDefault("PORT", "8080"),
+		ThreadMirrorMode:            os.Getenv("THREAD_MIRROR_MODE"),
+		ThreadMirrorReaction:        getEnvOrDefault("THREAD_MIRROR_REACTION", "memo"),
+		ThreadMirrorIdleMinutes:     getEnvIntOrDefault("THREAD_MIRROR_IDLE_MINUTES", 60),
+		EditDebounceSeconds:         getEnvIntOrDefault("EDIT_DEBOUNCE_SECONDS", 3),
+		AdminChannelID:              os.Getenv("ADMIN_CHANNEL_ID"),
+		AdminUserIDs:                getEnvList("ADMIN_USER_IDS"),
+		LegalHoldDir:                getEnvOrDefault("LEGAL_HOLD_DIR", "legal-hold"),
+		InstanceLockFile:            getEnvOrDefault("INSTANCE_LOCK_FILE", "slack-bot.lock"),
+		MessageAlertThresholds:      parseChannelIntMap("CHANNEL_MESSAGE_ALERT_THRESHOLDS"),
+		RecordingSchedules:          parseChannelMap("RECORDING_SCHEDULES"),
+		OptOutPolicy:                getEnvOrDefault("OPT_OUT_POLICY", "redact"),
+		RecordProfileFields:         getEnvBool("RECORD_PROFILE_FIELDS"),
+		ProfileTeamFieldID:          os.Getenv("PROFILE_TEAM_FIELD_ID"),
+		RecordClientMetadata:        getEnvBool("RECORD_CLIENT_METADATA"),
+		RecordTextStats:             getEnvBool("RECORD_TEXT_STATS"),
+		RecordReactions:             getEnvBool("RECORD_REACTIONS"),
+		RecordEngagement:            getEnvBool("RECORD_ENGAGEMENT"),
+		RecordFiles:                 getEnvBool("RECORD_FILES"),
+		RecordPins:                  getEnvBool("RECORD_PINS"),
+		RecordTraceID:               getEnvBool("RECORD_TRACE_ID"),
+		RecordPermalinks:            getEnvBool("RECORD_PERMALINKS"),
+		RecordDeletions:             getEnvBool("RECORD_DELETIONS"),
+		RecordEditHistory:           getEnvBool("RECORD_EDIT_HISTORY"),
+		RecordTeamID:                getEnvBool("RECORD_TEAM_ID"),
+		SlackTeamIDs:                getEnvList("SLACK_TEAM_IDS"),
+		ThreadExportEmoji:           strings.Trim(os.Getenv("THREAD_EXPORT_EMOJI"), ":"),
+		TimestampFormat:             strings.ToLower(os.Getenv("TIMESTAMP_FORMAT")),
+		LinkFormulas:                getEnvBool("LINK_FORMULAS"),
+		IdentityResolver:            os.Getenv("IDENTITY_RESOLVER"),
+		IdentitySource:              os.Getenv("IDENTITY_SOURCE"),
+		MessageStoreEnabled:         getEnvBool("MESSAGE_STORE_ENABLED"),
+		MessageStoreDir:             getEnvOrDefault("MESSAGE_STORE_DIR", "message-store"),
+		APITokens:                   getEnvList("API_TOKENS"),
+		NormalizeSearchText:         getEnvBool("NORMALIZE_SEARCH_TEXT"),
+		NormalizeRecordedText:       getEnvBool("NORMALIZE_RECORDED_TEXT"),
+		NotificationTargets:         parseChannelMap("NOTIFICATION_TARGETS"),
+		RecordModes:                 parseChannelMap("RECORD_MODE"),
+		NotificationVerbosities:     parseChannelMap("NOTIFICATION_VERBOSITY"),
+		DefaultLanguage:             getEnvOrDefault("DEFAULT_LANGUAGE", "ja"),
+		SettingsSheetEnabled:        getEnvBool("SETTINGS_SHEET_ENABLED"),
+		SettingsSheetName:           getEnvOrDefault("SETTINGS_SHEET_NAME", "settings"),
+		SettingsSheetRefreshMinutes: getEnvIntOrDefault("SETTINGS_SHEET_REFRESH_MINUTES", 10),
+		SheetsReadQuotaPerMinute:    getEnvIntOrDefault("SHEETS_READ_QUOTA_PER_MINUTE", 300),
+		SheetsWriteQuotaPerMinute:   getEnvIntOrDefault("SHEETS_WRITE_QUOTA_PER_MINUTE", 300),
+		WeeklyAdminReport:           getEnvBool("WEEKLY_ADMIN_REPORT"),
+		LatencySLOP95Seconds:        getEnvIntOrDefault("LATENCY_SLO_P95_SECONDS", 0),
+		AccessReview:                getEnvBool("ACCESS_REVIEW"),
+		AccessReviewInternalDomains: getEnvList("ACCESS_REVIEW_INTERNAL_DOMAINS"),
+		AccessReviewStaleDays:       getEnvIntOrDefault("ACCESS_REVIEW_STALE_DAYS", 180),
+		AuditSheetEnabled:           getEnvBool("AUDIT_SHEET_ENABLED"),
+		AuditSheetName:              getEnvOrDefault("AUDIT_SHEET_NAME", "audit"),
+		DailyRollupEnabled:          getEnvBool("DAILY_ROLLUP_ENABLED"),
+		DailyRollupSheetName:        getEnvOrDefault("DAILY_ROLLUP_SHEET_NAME", "rollup"),
+		ArrangeTabs:                 getEnvBool("ARRANGE_TABS"),
+		SpreadsheetSplitEnabled:     getEnvBool("SPREADSHEET_SPLIT_ENABLED"),
+		SpreadsheetSplitMaxTabs:     getEnvIntOrDefault("SPREADSHEET_SPLIT_MAX_TABS", 150),
+		SpreadsheetSplitMaxCells:    int64(getEnvIntOrDefault("SPREADSHEET_SPLIT_MAX_CELLS", 8000000)),
+		SpreadsheetIndexSheetName:   getEnvOrDefault("SPREADSHEET_INDEX_SHEET_NAME", "spreadsheets"),
+		MirrorSpreadsheetID:         os.Getenv("MIRROR_SPREADSHEET_ID"),
+		StateEncryptionKey:          os.Getenv("STATE_ENCRYPTION_KEY"),
+		StateEncryptionKeyFile:      os.Getenv("STATE_ENCRYPTION_KEY_FILE"),
+		ExportPublicKeyFile:         os.Getenv("ENCRYPTED_EXPORT_PUBLIC_KEY_FILE"),
+		EncryptedExportDir:          getEnvOrDefault("ENCRYPTED_EXPORT_DIR", "encrypted-export"),
+		SpreadsheetCredentials:      parseChannelMap("SPREADSHEET_CREDENTIALS"),
+		SpreadsheetGroups:           parseChannelMap("SPREADSHEET_GROUPS"),
+		GroupAdminEmail:             os.Getenv("GROUP_ADMIN_EMAIL"),
+		CompletenessSheetName:       os.Getenv("COMPLETENESS_SHEET_NAME"),
+		ManifestSheetName:           os.Getenv("MANIFEST_SHEET_NAME"),
+		ChannelInfoSheetName:        os.Getenv("CHANNEL_INFO_SHEET_NAME"),
+		ResetConfirmation:           getEnvBoolOrDefault("RESET_CONFIRMATION", true),
+		RecordDMChannels:            getEnvList("RECORD_DM_CHANNELS"),
+		RecordSharedChannels:        getEnvBoolOrDefault("RECORD_SHARED_CHANNELS", true),
+		RecordMembershipMessages:    getEnvBool("RECORD_MEMBERSHIP_MESSAGES"),
+		BackfillCooldownHours:       getEnvIntOrDefault("BACKFILL_COOLDOWN_HOURS", 24),
+		AutoDiscoverChannels:        getEnvBool("AUTO_DISCOVER_CHANNELS"),
+		ChannelSyncIntervalMinutes:  getEnvIntOrDefault("CHANNEL_SYNC_INTERVAL_MINUTES", 60),
+		IncrementalSyncMinutes:      getEnvIntOrDefault("INCREMENTAL_SYNC_INTERVAL_MINUTES", 0),
+		SlackAPIBaseURL:             os.Getenv("SLACK_API_BASE_URL"),
+		GoogleAPIEndpoint:           os.Getenv("GOOGLE_API_ENDPOINT"),
+		DevSheets:                   os.Getenv("DEV_SHEETS"),
+		DevSheetsDir:                getEnvOrDefault("DEV_SHEETS_DIR", "local-sheets"),
+		ChaosRateLimitRate:          getEnvRate("CHAOS_RATE_LIMIT_RATE"),
+		ChaosTimeoutRate:            getEnvRate("CHAOS_TIMEOUT_RATE"),
+		ChaosSheetsWriteFailureRate: getEnvRate("CHAOS_SHEETS_WRITE_FAILURE_RATE"),
+	}
+}
+
+// OAuthEnabled reports whether the OAuth install flow is configured
+func (c *Config) OAuthEnabled() bool {
+	return c.SlackClientID != "" && c.SlackClientSecret != ""
+}
+
+// WithBotToken returns a copy of the configuration that talks to Slack with another workspace's bot token
+func (c *Config) WithBotToken(token string) *Config {
+	copied := *c
+	copied.SlackBotToken = token
+	return &copied
+}
+
+// WithSpreadsheet returns a copy of the configuration that records into another spreadsheet. Continuation
+// spreadsheets are shared by all channels, so splitting is turned off for it.
+func (c *Config) WithSpreadsheet(spreadsheetID string) *Config {
+	copied := *c
+	copied.SpreadsheetID = spreadsheetID
+	copied.SpreadsheetSplitEnabled = false
+	return &copied
+}
+
+// WithTeam returns a copy of the configuration whose Slack API calls are made in the context of a workspace
+func (c *Config) WithTeam(teamID string) *Config {
+	copied := *c
+	copied.SlackTeamID = teamID
+	return &copied
+}
+
+// SpreadsheetGroup returns the Google Group that manages access to a spreadsheet, falling back to "default"
+func (c *Config) SpreadsheetGroup(spreadsheetID string) string {
+	if group, exists := c.SpreadsheetGroups[spreadsheetID]; exists {
+		return group
+	}
+	return c.SpreadsheetGroups["default"]
+}
+
+// IsAdmin reports whether a Slack user is listed in ADMIN_USER_IDS
+func (c *Config) IsAdmin(userID string) bool {
+	for _, adminID := range c.AdminUserIDs {
+		if adminID == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// RecordsDirectMessages reports whether a DM or group DM is listed in RECORD_DM_CHANNELS (or "*" is)
+func (c *Config) RecordsDirectMessages(channelID string) bool {
+	for _, id := range c.RecordDMChannels {
+		if id == "*" || id == channelID {
+			return true
+		}
 	}
+	return false
+}
+
+// MessageAlertThreshold returns the monthly alert threshold for a channel, falling back to "default" (0 means disabled)
+func (c *Config) MessageAlertThreshold(channelID string) int {
+	if value, exists := c.channelSetting(channelID, settings.KeyAlertThreshold); exists {
+		if threshold, err := strconv.Atoi(value); err == nil {
+			return threshold
+		}
+	}
+	if threshold, exists := c.MessageAlertThresholds[channelID]; exists {
+		return threshold
+	}
+	return c.MessageAlertThresholds["default"]
+}
+
+// RecordingSchedule returns the recording window spec for a channel, falling back to "default" (empty means always record)
+func (c *Config) RecordingSchedule(channelID string) string {
+	if spec, exists := c.channelSetting(channelID, settings.KeyRecordingSchedule); exists {
+		return spec
+	}
+	if spec, exists := c.RecordingSchedules[channelID]; exists {
+		return spec
+	}
+	return c.RecordingSchedules["default"]
+}
+
+// NotificationTarget returns where job results for a channel are delivered, falling back to "default" and then "channel"
+func (c *Config) NotificationTarget(channelID string) string {
+	if target, exists := c.channelSetting(channelID, settings.KeyNotificationTarget); exists {
+		return target
+	}
+	if target, exists := c.NotificationTargets[channelID]; exists {
+		return target
+	}
+	if target, exists := c.NotificationTargets["default"]; exists {
+		return target
+	}
+	return "channel"
+}
+
+// NotificationVerbosityFor returns how verbose job results for a channel are, falling back to "default" and then "full"
+func (c *Config) NotificationVerbosityFor(channelID string) string {
+	if verbosity, exists := c.channelSetting(channelID, settings.KeyNotificationVerbosity); exists {
+		return verbosity
+	}
+	if verbosity, exists := c.NotificationVerbosities[channelID]; exists {
+		return verbosity
+	}
+	if verbosity, exists := c.NotificationVerbosities["default"]; exists {
+		return verbosity
+	}
+	return "full"
+}
+
+// RecordMode returns how a channel is recorded: "full", "metadata" or "encrypted"
+func (c *Config) RecordMode(channelID string) string {
+	if mode, exists := c.channelSetting(channelID, settings.KeyRecordMode); exists {
+		return mode
+	}
+	if mode, exists := c.RecordModes[channelID]; exists {
+		return mode
+	}
+	if mode, exists := c.RecordModes["default"]; exists && mode != "" {
+		return mode
+	}
+	return "full"
+}
+
+// RecordsMetadataOnly reports whether a channel records only metadata, leaving out the message text
+func (c *Config) RecordsMetadataOnly(channelID string) bool {
+	return c.RecordMode(channelID) == "metadata"
+}
+
+// RecordsEncrypted reports whether the content of a channel goes to the encrypted archive instead of the sheet
+func (c *Config) RecordsEncrypted(channelID string) bool {
+	return c.RecordMode(channelID) == "encrypted"
+}
+
+// Location returns the timezone used for a channel's recorded timestamps (JST unless overridden per channel)
+func (c *Config) Location(channelID string) *time.Location {
+	if name, exists := c.channelSetting(channelID, settings.KeyTimezone); exists {
+		if location, err := time.LoadLocation(name); err == nil {
+			return location
+		}
+	}
+	return jst
+}
+
+// jst is Japan Standard Time, the default timezone of recorded timestamps
+var jst = time.FixedZone("JST", 9*60*60)
+
+// channelSetting looks up a per-channel override from the settings provider, if any
+func (c *Config) channelSetting(channelID, key string) (string, bool) {
+	if c.ChannelSettings == nil {
+		return "", false
+	}
+	return c.ChannelSettings.ChannelSetting(channelID, key)
 }
 
 func getEnvOrDefault(key, defaultValue string) string {
@@ -36,3 +517,95 @@ func getEnvOrDefault(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+// getEnvIntOrDefault returns the integer value of an environment variable, or the default if unset or invalid
+func getEnvIntOrDefault(key string, defaultValue int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		log.Printf("Warning: invalid integer for %s (%q), using default %d", key, value, defaultValue)
+		return defaultValue
+	}
+	return parsed
+}
+
+// getEnvRate returns a probability (0 to 1) from an environment variable, or 0 if unset or invalid
+func getEnvRate(key string) float64 {
+	value := os.Getenv(key)
+	if value == "" {
+		return 0
+	}
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil || parsed < 0 || parsed > 1 {
+		log.Printf("Warning: invalid rate for %s (%q), it must be between 0 and 1; using 0", key, value)
+		return 0
+	}
+	return parsed
+}
+
+// getEnvBool reports whether an environment variable is set to a truthy value ("true", "1", "yes")
+func getEnvBool(key string) bool {
+	switch strings.ToLower(os.Getenv(key)) {
+	case "true", "1", "yes":
+		return true
+	}
+	return false
+}
+
+// getEnvBoolOrDefault is getEnvBool for settings that are on unless explicitly set to "false", "0" or "no"
+func getEnvBoolOrDefault(key string, defaultValue bool) bool {
+	switch strings.ToLower(os.Getenv(key)) {
+	case "true", "1", "yes":
+		return true
+	case "false", "0", "no":
+		return false
+	}
+	return defaultValue
+}
+
+// getEnvList parses a comma-separated environment variable into a list, skipping empty entries
+func getEnvList(key string) []string {
+	var result []string
+	for _, entry := range strings.Split(os.Getenv(key), ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			result = append(result, entry)
+		}
+	}
+	return result
+}
+
+// parseChannelMap parses a "key=value,key=value" environment variable into a map
+func parseChannelMap(key string) map[string]string {
+	result := make(map[string]string)
+	value := os.Getenv(key)
+	if value == "" {
+		return result
+	}
+
+	for _, entry := range strings.Split(value, ",") {
+		parts := strings.SplitN(strings.TrimSpace(entry), "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			log.Printf("Warning: ignoring malformed entry %q in %s", entry, key)
+			continue
+		}
+		result[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+	}
+	return result
+}
+
+// parseChannelIntMap parses a "key=number,key=number" environment variable into a map
+func parseChannelIntMap(key string) map[string]int {
+	result := make(map[string]int)
+	for mapKey, value := range parseChannelMap(key) {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			log.Printf("Warning: ignoring non-integer value %q for %s in %s", value, mapKey, key)
+			continue
+		}
+		result[mapKey] = parsed
+	}
+	return result
+}
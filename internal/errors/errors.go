@@ -0,0 +1,140 @@
+package errors
+
+import (
+	"fmt"
+
+	"slack-to-google-sheets-bot/internal/i18n"
+)
+
+// Kind identifies a failure that is reported to Slack users
+type Kind string
+
+// Failures reported to the users of a channel
+const (
+	SheetsNotConfigured     Kind = "sheets_not_configured"
+	SheetsConnectFailed     Kind = "sheets_connect_failed"
+	SheetInitFailed         Kind = "sheet_init_failed"
+	SheetCheckFailed        Kind = "sheet_check_failed"
+	SheetClearFailed        Kind = "sheet_clear_failed"
+	HistoryFetchFailed      Kind = "history_fetch_failed"
+	HistoryWriteFailed      Kind = "history_write_failed"
+	NewMessagesFetchFailed  Kind = "new_messages_fetch_failed"
+	NewMessagesWriteFailed  Kind = "new_messages_write_failed"
+	ContinuationFetchFailed Kind = "continuation_fetch_failed"
+	ContinuationWriteFailed Kind = "continuation_write_failed"
+)
+
+// catalog holds the user-facing message templates (fmt verbs) per kind and language. They say what happened and
+// what the user can do, never the internal cause; that goes to AdminDetail.
+var catalog = map[Kind]map[string]string{
+	SheetsNotConfigured: {
+		i18n.Japanese: "⚠️ Google Sheetsの設定が完了していません。管理者にお問い合わせください。",
+		i18n.English:  "⚠️ Google Sheets is not configured yet. Please contact an administrator.",
+	},
+	SheetsConnectFailed: {
+		i18n.Japanese: "❌ Google Sheetsへの接続に失敗しました。管理者にお問い合わせください。",
+		i18n.English:  "❌ Could not connect to Google Sheets. Please contact an administrator.",
+	},
+	SheetInitFailed: {
+		i18n.Japanese: "❌ スプレッドシートの初期化に失敗しました。",
+		i18n.English:  "❌ Could not prepare the spreadsheet.",
+	},
+	SheetCheckFailed: {
+		i18n.Japanese: "❌ シートの確認に失敗しました。",
+		i18n.English:  "❌ Could not check the sheet.",
+	},
+	SheetClearFailed: {
+		i18n.Japanese: "❌ シートのクリアに失敗しました。",
+		i18n.English:  "❌ Could not clear the sheet.",
+	},
+	HistoryFetchFailed: {
+		i18n.Japanese: "❌ チャンネル履歴の取得に失敗しました。",
+		i18n.English:  "❌ Could not retrieve the channel history.",
+	},
+	HistoryWriteFailed: {
+		i18n.Japanese: "❌ スプレッドシートへの記録に失敗しました（4回試行後）\n" +
+			"記録対象: <%s|シートの %d〜%d 行目>\n" +
+			"ネットワークまたはAPI制限の問題の可能性があります。\n" +
+			"しばらく時間をおいてから再度お試しください。",
+		i18n.English: "❌ Could not write to the spreadsheet (after 4 attempts)\n" +
+			"Target: <%s|rows %d-%d of the sheet>\n" +
+			"This may be a network problem or an API limit.\n" +
+			"Please try again later.",
+	},
+	NewMessagesFetchFailed: {
+		i18n.Japanese: "⚠️ 処理中の新着メッセージ取得に失敗しました。一部のメッセージが記録されていない可能性があります。",
+		i18n.English:  "⚠️ Could not retrieve the messages posted while recording. Some messages may be missing.",
+	},
+	NewMessagesWriteFailed: {
+		i18n.Japanese: "❌ 処理中の新着メッセージの記録に失敗しました。再度実行してください。\n記録先: %s",
+		i18n.English:  "❌ Could not record the messages posted while recording. Please run it again.\nSheet: %s",
+	},
+	ContinuationFetchFailed: {
+		i18n.Japanese: "❌ 前回の記録以降のメッセージの取得に失敗しました。「Reset!」で履歴を取得し直すこともできます。",
+		i18n.English:  "❌ Could not retrieve the messages posted since the last recorded one. \"Reset!\" records the history again.",
+	},
+	ContinuationWriteFailed: {
+		i18n.Japanese: "❌ スプレッドシートへの記録に失敗しました。しばらく時間をおいてから再度お試しください。",
+		i18n.English:  "❌ Could not write to the spreadsheet. Please try again later.",
+	},
+}
+
+// causeLabel introduces the internal cause in admin messages
+var causeLabel = map[string]string{
+	i18n.Japanese: "エラー",
+	i18n.English:  "Error",
+}
+
+// Error is a failure with a message for channel users and the internal cause for admins and logs
+type Error struct {
+	Kind  Kind
+	Cause error
+	Args  []interface{} // Values for the fmt verbs of the user-facing message
+}
+
+// New creates an error of a kind; cause may be nil when there is no underlying error
+func New(kind Kind, cause error, args ...interface{}) *Error {
+	return &Error{Kind: kind, Cause: cause, Args: args}
+}
+
+// Error returns the kind and the internal cause, for logs
+func (e *Error) Error() string {
+	if e.Cause == nil {
+		return string(e.Kind)
+	}
+	return fmt.Sprintf("%s: %v", e.Kind, e.Cause)
+}
+
+// Unwrap returns the internal cause
+func (e *Error) Unwrap() error {
+	return e.Cause
+}
+
+// UserMessage returns the message for channel users in the given language, falling back to Japanese
+func (e *Error) UserMessage(lang string) string {
+	templates, exists := catalog[e.Kind]
+	if !exists {
+		return string(e.Kind)
+	}
+	template, exists := templates[lang]
+	if !exists {
+		template = templates[i18n.Japanese]
+	}
+	if len(e.Args) == 0 {
+		return template
+	}
+	return fmt.Sprintf(template, e.Args...)
+}
+
+// AdminDetail returns the user-facing message followed by the internal cause, for admins
+func (e *Error) AdminDetail(lang string) string {
+	message := e.UserMessage(lang)
+	if e.Cause == nil {
+		return message
+	}
+	label, exists := causeLabel[lang]
+	if !exists {
+		label = causeLabel[i18n.Japanese]
+	}
+	return fmt.Sprintf("%s\n%s: %v", message, label, e.Cause)
+}
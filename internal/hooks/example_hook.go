@@ -0,0 +1,25 @@
+//go:build example_hook
+
+package hooks
+
+import "log"
+
+// Built with "go build -tags example_hook", this hook logs every written record. Copy this file under another
+// name and build tag to add a site-specific hook.
+func init() {
+	Register(logHook{})
+}
+
+// logHook logs where each record was written
+type logHook struct{}
+
+// Name identifies the hook in logs
+func (logHook) Name() string {
+	return "example_log"
+}
+
+// AfterWrite logs the record and its row
+func (logHook) AfterWrite(written Written) error {
+	log.Printf("example_log: %s %s in %s row %d (updated: %t)", written.Channel, written.MessageTS, written.SheetName, written.Row, written.Updated)
+	return nil
+}
@@ -0,0 +1,76 @@
+package hooks
+
+import (
+	"log"
+	"sync"
+
+	"slack-to-google-sheets-bot/internal/sheets"
+)
+
+// Written is a record that was just written to a channel tab
+type Written struct {
+	*sheets.MessageRecord
+	SpreadsheetID string
+	SheetName     string
+	// Row is the 1-based sheet row, or 0 when the record was written in a batch and its row was not looked up
+	Row int
+	// Updated is true when the row of an already recorded message was rewritten (an edit)
+	Updated bool
+}
+
+// Hook is a site-specific extension run after records are written, e.g. a custom notification, an extra
+// column filled in later or a call to an external API. Hooks run in the goroutine that wrote the records, after
+// the write, so a slow hook delays the next write of that event; start a goroutine for slow work.
+type Hook interface {
+	// Name identifies the hook in logs
+	Name() string
+	// AfterWrite is called once per written record; an error is logged and does not affect the recording
+	AfterWrite(written Written) error
+}
+
+var (
+	registered      []Hook
+	registeredMutex sync.Mutex
+)
+
+// Register adds a hook. Call it from an init function of a file compiled into the binary, typically behind a build
+// tag (see example_hook.go), so hooks are added without changing the handler.
+func Register(hook Hook) {
+	registeredMutex.Lock()
+	defer registeredMutex.Unlock()
+	registered = append(registered, hook)
+	log.Printf("Registered record hook %s", hook.Name())
+}
+
+// Enabled reports whether any hook is registered
+func Enabled() bool {
+	registeredMutex.Lock()
+	defer registeredMutex.Unlock()
+	return len(registered) > 0
+}
+
+// Run calls every registered hook for each written record; errors and panics of a hook are logged so a broken
+// hook cannot stop the recording
+func Run(written []Written) {
+	registeredMutex.Lock()
+	hooks := append([]Hook(nil), registered...)
+	registeredMutex.Unlock()
+
+	for _, hook := range hooks {
+		for _, record := range written {
+			runHook(hook, record)
+		}
+	}
+}
+
+// runHook calls one hook for one record, recovering from a panic
+func runHook(hook Hook, written Written) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			log.Printf("Record hook %s panicked on message %s: %v", hook.Name(), written.MessageTS, recovered)
+		}
+	}()
+	if err := hook.AfterWrite(written); err != nil {
+		log.Printf("Record hook %s failed on message %s: %v", hook.Name(), written.MessageTS, err)
+	}
+}
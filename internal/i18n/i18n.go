@@ -0,0 +1,330 @@
+package i18n
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// Japanese is the bot's original reply language
+	Japanese = "ja"
+	// English is used for users whose Slack locale is English
+	English = "en"
+)
+
+// Message keys for replies to interactive commands
+const (
+	KeyHelp                = "help"
+	KeySheetsNotConfigured = "sheets_not_configured"
+	KeySheetsConnectFailed = "sheets_connect_failed"
+	KeyShowMeInvalidEmail  = "show_me_invalid_email"
+	KeyShowMeShareFailed   = "show_me_share_failed"
+	KeyShowMeShared        = "show_me_shared"
+	KeySearchDisabled      = "search_disabled"
+	KeySearchUsage         = "search_usage"
+	KeySearchNoResults     = "search_no_results"
+	KeySearchResults       = "search_results"
+	KeyFindUsage           = "find_usage"
+	KeyFindInvalidLink     = "find_invalid_link"
+	KeyFindChannelFailed   = "find_channel_failed"
+	KeyFindReadFailed      = "find_read_failed"
+	KeyFindNotRecorded     = "find_not_recorded"
+	KeyFindFound           = "find_found"
+	KeyFindNotMember       = "find_not_member"
+	KeyFindSentByDM        = "find_sent_by_dm"
+	KeySettingsList        = "settings_list"
+	KeySettingsUpdated     = "settings_updated"
+	KeySettingsRemoved     = "settings_removed"
+	KeySettingsInvalid     = "settings_invalid"
+	KeySettingsSaveFailed  = "settings_save_failed"
+	KeySettingsAdminOnly   = "settings_admin_only"
+	KeySettingsOwnerOnly   = "settings_owner_only"
+	KeyResortBusy          = "resort_busy"
+	KeyResortFailed        = "resort_failed"
+	KeyResortAlreadySorted = "resort_already_sorted"
+	KeyResortDone          = "resort_done"
+)
+
+// Message keys for replies to "Reset!" and the backfill it starts
+const (
+	KeyResetNoPending          = "reset_no_pending"
+	KeyResetForceAdminOnly     = "reset_force_admin_only"
+	KeyResetForceStopFailed    = "reset_force_stop_failed"
+	KeyResetStarted            = "reset_started"
+	KeyResetAlreadyCompleted   = "reset_already_completed"
+	KeyResetBackfillRunning    = "reset_backfill_running"
+	KeyBackfillCooldown        = "backfill_cooldown"
+	KeyResetPreviewTitle       = "reset_preview_title"
+	KeyResetPreviewUnreadable  = "reset_preview_unreadable"
+	KeyResetPreviewEmpty       = "reset_preview_empty"
+	KeyResetPreviewRows        = "reset_preview_rows"
+	KeyResetPreviewPeriod      = "reset_preview_period"
+	KeyResetPreviewRefetch     = "reset_preview_refetch"
+	KeyResetPreviewLost        = "reset_preview_lost"
+	KeyResetPreviewMirrorKept  = "reset_preview_mirror_kept"
+	KeyResetPreviewAskConfirm  = "reset_preview_ask_confirm"
+	KeyResetPreviewOnly        = "reset_preview_only"
+	KeyResetConfirmButton      = "reset_confirm_button"
+	KeyResetCancelButton       = "reset_cancel_button"
+	KeyResetHeldNotRun         = "reset_held_not_run"
+	KeyResetButtonNoPending    = "reset_button_no_pending"
+	KeyResetConfirmed          = "reset_confirmed"
+	KeyResetCancelNotRequester = "reset_cancel_not_requester"
+	KeyResetCancelled          = "reset_cancelled"
+)
+
+// catalog holds the message templates (fmt verbs) per key and language
+var catalog = map[string]map[string]string{
+	KeyHelp: {
+		Japanese: "🔗 ユーザーにスプレッドシート閲覧権限を付与するには「show me <メールアドレス>」とメンションしてください\n" +
+			"🔍 記録済みのメッセージを検索するには「search <キーワード>」とメンションしてください\n" +
+			"📍 メッセージが記録されたシートの行を調べるには「find <メッセージのリンク>」とメンションしてください\n" +
+			"⚙️ このチャンネルの設定を確認・変更するには「get」「set <設定項目> <値>」とメンションしてください\n" +
+			"🔢 シートの行を投稿日時順に並べ直すには「resort」とメンションしてください\n" +
+			"🤖 このチャンネルの記録を取得し直すには「Reset!」とメンションしてください（「Reset! preview」で削除される内容を確認できます）\n" +
+			"💬 どのコマンドも「/sheetbot <コマンド>」で実行できます\n",
+		English: "🔗 To grant a user read access to the spreadsheet, mention me with \"show me <email>\"\n" +
+			"🔍 To search recorded messages, mention me with \"search <keyword>\"\n" +
+			"📍 To find the sheet row of a message, mention me with \"find <message link>\"\n" +
+			"⚙️ To view or change this channel's settings, mention me with \"get\" or \"set <key> <value>\"\n" +
+			"🔢 To put the sheet rows back in chronological order, mention me with \"resort\"\n" +
+			"🤖 To re-record this channel from scratch, mention me with \"Reset!\" (\"Reset! preview\" shows what would be deleted)\n" +
+			"💬 Every command also works as \"/sheetbot <command>\"\n",
+	},
+	KeySheetsNotConfigured: {
+		Japanese: "⚠️ Google Sheetsの設定が完了していません。管理者にお問い合わせください。",
+		English:  "⚠️ Google Sheets is not configured yet. Please contact your administrator.",
+	},
+	KeySheetsConnectFailed: {
+		Japanese: "❌ Google Sheetsへの接続に失敗しました。",
+		English:  "❌ Could not connect to Google Sheets.",
+	},
+	KeyShowMeInvalidEmail: {
+		Japanese: "❌ 有効なメールアドレスが見つかりませんでした。\n使用例: `@bot show me test@example.com`",
+		English:  "❌ No valid email address found.\nExample: `@bot show me test@example.com`",
+	},
+	KeyShowMeShareFailed: {
+		Japanese: "❌ %s への権限付与に失敗しました（エラー: %v）",
+		English:  "❌ Could not grant access to %s (error: %v)",
+	},
+	KeyShowMeShared: {
+		Japanese: "✅ %s に<%s|スプレッドシート>の閲覧権限を付与しました。",
+		English:  "✅ Granted %s read access to the <%s|spreadsheet>.",
+	},
+	KeySearchDisabled: {
+		Japanese: "⚠️ 検索機能が有効になっていません（MESSAGE_STORE_ENABLED）。管理者にお問い合わせください。",
+		English:  "⚠️ Search is not enabled (MESSAGE_STORE_ENABLED). Please contact your administrator.",
+	},
+	KeySearchUsage: {
+		Japanese: "❌ 検索キーワードが見つかりませんでした。\n使用例: `@bot search リリース`",
+		English:  "❌ No search keyword found.\nExample: `@bot search release`",
+	},
+	KeySearchNoResults: {
+		Japanese: "🔍 「%s」に一致するメッセージは見つかりませんでした。",
+		English:  "🔍 No messages matched \"%s\".",
+	},
+	KeySearchResults: {
+		Japanese: "🔍 「%s」の検索結果（上位 %d 件）:",
+		English:  "🔍 Results for \"%s\" (top %d):",
+	},
+	KeyFindUsage: {
+		Japanese: "❌ メッセージのリンクが見つかりませんでした。\n" +
+			"使用例: `@bot find https://example.slack.com/archives/C0123456789/p1718000000123456`",
+		English: "❌ No message link found.\n" +
+			"Example: `@bot find https://example.slack.com/archives/C0123456789/p1718000000123456`",
+	},
+	KeyFindInvalidLink: {
+		Japanese: "❌ Slack のメッセージリンクとして認識できませんでした。",
+		English:  "❌ That does not look like a Slack message link.",
+	},
+	KeyFindChannelFailed: {
+		Japanese: "❌ リンク先のチャンネル情報を取得できませんでした。",
+		English:  "❌ Could not look up the linked channel.",
+	},
+	KeyFindReadFailed: {
+		Japanese: "❌ シートの読み込みに失敗しました。",
+		English:  "❌ Could not read the sheet.",
+	},
+	KeyFindNotRecorded: {
+		Japanese: "ℹ️ このメッセージはシート「%s」に記録されていません。",
+		English:  "ℹ️ This message is not recorded in sheet \"%s\".",
+	},
+	KeyFindFound: {
+		Japanese: "📍 このメッセージは<%s|シート「%s」の %d 行目>に記録されています。",
+		English:  "📍 This message is recorded in <%s|row %[3]d of sheet \"%[2]s\">.",
+	},
+	KeyFindNotMember: {
+		Japanese: "❌ ほかのチャンネルのメッセージは、そのチャンネルのメンバーにのみお答えできます。",
+		English:  "❌ Messages of other channels can only be looked up by members of that channel.",
+	},
+	KeyFindSentByDM: {
+		Japanese: "📬 ほかのチャンネルのメッセージのため、結果は DM でお送りします。",
+		English:  "📬 The link points to another channel, so I will send the result by DM.",
+	},
+	KeySettingsList: {
+		Japanese: "⚙️ このチャンネルの設定:\n%s\n変更するには「set <設定項目> <値>」、元に戻すには「unset <設定項目>」とメンションしてください",
+		English:  "⚙️ Settings for this channel:\n%s\nMention me with \"set <key> <value>\" to change one, or \"unset <key>\" to restore the default",
+	},
+	KeySettingsUpdated: {
+		Japanese: "✅ %s を %s に設定しました。",
+		English:  "✅ Set %s to %s.",
+	},
+	KeySettingsRemoved: {
+		Japanese: "✅ %s の設定を削除しました（現在の値: %s）。",
+		English:  "✅ Removed the %s setting (now: %s).",
+	},
+	KeySettingsInvalid: {
+		Japanese: "❌ 設定できませんでした: %v",
+		English:  "❌ Could not change the setting: %v",
+	},
+	KeySettingsSaveFailed: {
+		Japanese: "❌ 設定の保存に失敗しました。時間をおいて再度お試しください。",
+		English:  "❌ Could not save the setting. Please try again later.",
+	},
+	KeySettingsAdminOnly: {
+		Japanese: "⚠️ %s の変更は管理者のみ実行できます。",
+		English:  "⚠️ Only admins can change %s.",
+	},
+	KeySettingsOwnerOnly: {
+		Japanese: "⚠️ 設定の変更は管理者またはチャンネルの作成者のみ実行できます。「get」で現在の設定を確認できます。",
+		English:  "⚠️ Only admins and the channel's creator can change settings. Mention me with \"get\" to view them.",
+	},
+	KeyResortBusy: {
+		Japanese: "⏳ このチャンネルの履歴を取得中です。完了してから再度お試しください。",
+		English:  "⏳ This channel's history is being recorded. Please try again once it has finished.",
+	},
+	KeyResortFailed: {
+		Japanese: "❌ シートの並べ替えに失敗しました。",
+		English:  "❌ Could not sort the sheet.",
+	},
+	KeyResortAlreadySorted: {
+		Japanese: "✅ シート「%s」はすでに投稿日時順に並んでいます。",
+		English:  "✅ Sheet \"%s\" is already in chronological order.",
+	},
+	KeyResortDone: {
+		Japanese: "✅ シート「%s」を投稿日時順に並べ替え、%d 行の No. とスレッド参照を修正しました。",
+		English:  "✅ Sorted sheet \"%s\" chronologically and fixed the No. and thread references of %d rows.",
+	},
+	KeyResetNoPending: {
+		Japanese: "ℹ️ 確認待ちのリセットがありません（有効期限切れの可能性があります）。まず「Reset!」とメンションして内容を確認してください。",
+		English:  "ℹ️ There is no reset waiting for confirmation (it may have expired). Mention me with \"Reset!\" first to review it.",
+	},
+	KeyResetForceAdminOnly: {
+		Japanese: "⚠️ 「Reset! force」は管理者のみ実行できます。通常のリセットは「Reset!」とメンションしてください。",
+		English:  "⚠️ Only admins can run \"Reset! force\". For a normal reset, mention me with \"Reset!\".",
+	},
+	KeyResetForceStopFailed: {
+		Japanese: "❌ 実行中の履歴取得を停止できなかったため、リセットを中止しました。しばらく時間をおいてから再度お試しください。",
+		English:  "❌ The reset was aborted because the running history retrieval could not be stopped. Please try again later.",
+	},
+	KeyResetStarted: {
+		Japanese: "🔄 シートをリセットして過去のメッセージ履歴を再取得しています... (#%s)",
+		English:  "🔄 Resetting the sheet and retrieving the message history again... (#%s)",
+	},
+	KeyResetAlreadyCompleted: {
+		Japanese: "✅ このリクエストの履歴取得は %s に完了済みです（%d件記録）。\nもう一度取得し直す場合は、改めて「Reset!」とメンションしてください。",
+		English:  "✅ The history retrieval for this request already finished at %s (%d messages recorded).\nTo retrieve it again, mention me with \"Reset!\" once more.",
+	},
+	KeyResetBackfillRunning: {
+		Japanese: "⏳ このチャンネルの履歴は現在取得中です。完了までお待ちください。",
+		English:  "⏳ This channel's history is being retrieved right now. Please wait until it finishes.",
+	},
+	KeyBackfillCooldown: {
+		Japanese: "👋 このチャンネル (#%s) の履歴は %s に取得済みのため、今回は再取得しません。\n新しいメッセージはこれまで通り記録します。履歴を取得し直す場合は「Reset!」とメンションしてください。",
+		English:  "👋 The history of this channel (#%s) was already retrieved at %s, so it is not retrieved again.\nNew messages are recorded as before. To retrieve the history again, mention me with \"Reset!\".",
+	},
+	KeyResetPreviewTitle: {
+		Japanese: "🔍 リセットの確認 (#%s)",
+		English:  "🔍 Reset preview (#%s)",
+	},
+	KeyResetPreviewUnreadable: {
+		Japanese: "⚠️ シートの内容を確認できませんでした。",
+		English:  "⚠️ Could not read the sheet.",
+	},
+	KeyResetPreviewEmpty: {
+		Japanese: "• 削除される行: なし（シート「%s」は空です）",
+		English:  "• Rows to be deleted: none (sheet \"%s\" is empty)",
+	},
+	KeyResetPreviewRows: {
+		Japanese: "• 削除される行: %d行（シート「%s」）",
+		English:  "• Rows to be deleted: %d (sheet \"%s\")",
+	},
+	KeyResetPreviewPeriod: {
+		Japanese: "• 記録されている期間: %s 〜 %s",
+		English:  "• Recorded period: %s to %s",
+	},
+	KeyResetPreviewRefetch: {
+		Japanese: "• 削除後、Slack から過去のメッセージ履歴を取得し直して記録します",
+		English:  "• After the deletion, the message history is retrieved from Slack and recorded again",
+	},
+	KeyResetPreviewLost: {
+		Japanese: "• Slack から取得できないメッセージ（削除済み・保存期間外など）は復元されません。必要に応じて事前にシートのコピーを作成してください",
+		English:  "• Messages Slack no longer returns (deleted, past retention, etc.) are not restored. Make a copy of the sheet beforehand if needed",
+	},
+	KeyResetPreviewMirrorKept: {
+		Japanese: "• バックアップ用のミラースプレッドシートは削除されず、記録済みの行はそのまま残ります",
+		English:  "• The backup mirror spreadsheet is not cleared and keeps its recorded rows",
+	},
+	KeyResetPreviewAskConfirm: {
+		Japanese: "実行する場合は %d 分以内に「リセットを実行」を押すか、「Reset! confirm」とメンションしてください。",
+		English:  "To proceed, press \"Run reset\" or mention me with \"Reset! confirm\" within %d minutes.",
+	},
+	KeyResetPreviewOnly: {
+		Japanese: "ℹ️ これはプレビューです。シートは変更されていません。",
+		English:  "ℹ️ This is only a preview. The sheet has not been changed.",
+	},
+	KeyResetConfirmButton: {
+		Japanese: "リセットを実行",
+		English:  "Run reset",
+	},
+	KeyResetCancelButton: {
+		Japanese: "キャンセル",
+		English:  "Cancel",
+	},
+	KeyResetHeldNotRun: {
+		Japanese: "🔒 リーガルホールド中のため、リセットは実行されませんでした。",
+		English:  "🔒 The reset was not run because the channel is under legal hold.",
+	},
+	KeyResetButtonNoPending: {
+		Japanese: "ℹ️ 確認待ちのリセットがありません。リセットを依頼した本人のみ実行でき、有効期限が切れている場合はもう一度「Reset!」とメンションしてください。",
+		English:  "ℹ️ There is no reset waiting for confirmation. Only the user who asked for the reset can run it; if it has expired, mention me with \"Reset!\" again.",
+	},
+	KeyResetConfirmed: {
+		Japanese: "✅ <@%s> がリセットを実行しました。",
+		English:  "✅ <@%s> ran the reset.",
+	},
+	KeyResetCancelNotRequester: {
+		Japanese: "ℹ️ キャンセルできるのはリセットを依頼した本人のみです。",
+		English:  "ℹ️ Only the user who asked for the reset can cancel it.",
+	},
+	KeyResetCancelled: {
+		Japanese: "🚫 <@%s> がリセットをキャンセルしました。シートは変更されていません。",
+		English:  "🚫 <@%s> cancelled the reset. The sheet has not been changed.",
+	},
+}
+
+// T returns the message for a key in the given language, falling back to Japanese
+func T(lang, key string, args ...interface{}) string {
+	templates, exists := catalog[key]
+	if !exists {
+		return key
+	}
+	template, exists := templates[lang]
+	if !exists {
+		template = templates[Japanese]
+	}
+	if len(args) == 0 {
+		return template
+	}
+	return fmt.Sprintf(template, args...)
+}
+
+// FromLocale maps a Slack locale such as "en-US" or "ja-JP" to a supported language
+func FromLocale(locale string) (string, bool) {
+	lang := strings.ToLower(strings.SplitN(strings.ReplaceAll(locale, "_", "-"), "-", 2)[0])
+	switch lang {
+	case Japanese, English:
+		return lang, true
+	}
+	return "", false
+}
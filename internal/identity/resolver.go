@@ -0,0 +1,167 @@
+package identity
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Resolver maps a Slack user ID to an internal employee ID.
+// Implementations return an empty string (and no error) when the user is unknown.
+type Resolver interface {
+	Resolve(slackUserID string) (string, error)
+}
+
+// NewResolver creates a resolver of the given kind ("csv" or "http") reading from source.
+// For "csv", source is a file path; for "http", source is a URL template containing "{user_id}".
+// Additional backends (e.g. LDAP) can be added by implementing Resolver and registering a kind here.
+func NewResolver(kind, source string) (Resolver, error) {
+	var resolver Resolver
+	var err error
+
+	switch kind {
+	case "csv":
+		resolver, err = NewCSVResolver(source)
+	case "http":
+		resolver, err = NewHTTPResolver(source)
+	default:
+		return nil, fmt.Errorf("unknown identity resolver kind %q", kind)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return newCachingResolver(resolver), nil
+}
+
+// CSVResolver resolves employee IDs from a "slack_user_id,employee_id" CSV file loaded at startup
+type CSVResolver struct {
+	mapping map[string]string
+}
+
+// NewCSVResolver loads a CSV mapping file; a header row starting with "slack_user_id" is skipped
+func NewCSVResolver(path string) (*CSVResolver, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open identity CSV: %v", err)
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+
+	mapping := make(map[string]string)
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse identity CSV: %v", err)
+		}
+		if len(row) < 2 || strings.EqualFold(strings.TrimSpace(row[0]), "slack_user_id") {
+			continue
+		}
+		mapping[strings.TrimSpace(row[0])] = strings.TrimSpace(row[1])
+	}
+
+	return &CSVResolver{mapping: mapping}, nil
+}
+
+// Resolve returns the employee ID mapped to the Slack user ID
+func (r *CSVResolver) Resolve(slackUserID string) (string, error) {
+	return r.mapping[slackUserID], nil
+}
+
+// HTTPResolver resolves employee IDs via an HTTP lookup service.
+// The response may be JSON ({"employee_id": "..."}) or a plain-text ID; 404 means unknown.
+type HTTPResolver struct {
+	urlTemplate string
+	httpClient  *http.Client
+}
+
+// NewHTTPResolver creates an HTTP resolver from a URL template containing "{user_id}"
+func NewHTTPResolver(urlTemplate string) (*HTTPResolver, error) {
+	if !strings.Contains(urlTemplate, "{user_id}") {
+		return nil, fmt.Errorf("identity HTTP URL must contain {user_id}")
+	}
+	return &HTTPResolver{
+		urlTemplate: urlTemplate,
+		httpClient:  &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+// Resolve queries the lookup service for the Slack user ID
+func (r *HTTPResolver) Resolve(slackUserID string) (string, error) {
+	lookupURL := strings.ReplaceAll(r.urlTemplate, "{user_id}", url.QueryEscape(slackUserID))
+
+	resp, err := r.httpClient.Get(lookupURL)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return "", nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("identity lookup returned status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+
+	var payload struct {
+		EmployeeID string `json:"employee_id"`
+	}
+	if err := json.Unmarshal(body, &payload); err == nil {
+		return payload.EmployeeID, nil
+	}
+
+	return strings.TrimSpace(string(body)), nil
+}
+
+// cachingResolver memoizes successful lookups of another resolver
+type cachingResolver struct {
+	resolver Resolver
+	cache    map[string]string
+	mutex    sync.Mutex
+}
+
+// newCachingResolver wraps a resolver with an in-memory cache
+func newCachingResolver(resolver Resolver) *cachingResolver {
+	return &cachingResolver{
+		resolver: resolver,
+		cache:    make(map[string]string),
+	}
+}
+
+// Resolve returns the cached employee ID or delegates to the wrapped resolver
+func (r *cachingResolver) Resolve(slackUserID string) (string, error) {
+	r.mutex.Lock()
+	if employeeID, exists := r.cache[slackUserID]; exists {
+		r.mutex.Unlock()
+		return employeeID, nil
+	}
+	r.mutex.Unlock()
+
+	employeeID, err := r.resolver.Resolve(slackUserID)
+	if err != nil {
+		return "", err
+	}
+
+	r.mutex.Lock()
+	r.cache[slackUserID] = employeeID
+	r.mutex.Unlock()
+
+	return employeeID, nil
+}
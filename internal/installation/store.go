@@ -0,0 +1,136 @@
+package installation
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"slack-to-google-sheets-bot/internal/statecrypt"
+)
+
+// Installation is the bot token of one workspace that installed the app through the OAuth flow
+type Installation struct {
+	TeamID      string    `json:"team_id"`
+	TeamName    string    `json:"team_name"`
+	BotToken    string    `json:"bot_token"`
+	BotUserID   string    `json:"bot_user_id"`
+	InstalledBy string    `json:"installed_by"`
+	InstalledAt time.Time `json:"installed_at"`
+}
+
+// legacyFilePath is where installations were kept before they moved to a data directory; it is read and moved when
+// the new file does not exist yet
+const legacyFilePath = "/tmp/slack-bot-installations/installations.json"
+
+// Store persists the installations by team ID in a data directory that survives restarts; the file is encrypted
+// when a state encryption key is set
+type Store struct {
+	dir   string
+	mutex sync.Mutex
+}
+
+// NewStore creates an installation store kept in dir
+func NewStore(dir string) *Store {
+	return &Store{
+		dir: dir,
+	}
+}
+
+// getFilePath returns the file path of the installations
+func (s *Store) getFilePath() string {
+	return filepath.Join(s.dir, "installations.json")
+}
+
+// load reads the installations; callers must hold the mutex
+func (s *Store) load() (map[string]Installation, error) {
+	installations := make(map[string]Installation)
+
+	path := s.getFilePath()
+	data, err := statecrypt.ReadFile(path)
+	if os.IsNotExist(err) {
+		path = legacyFilePath
+		data, err = statecrypt.ReadFile(path)
+		if os.IsNotExist(err) {
+			return installations, nil
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read installations: %v", err)
+	}
+
+	if err := json.Unmarshal(data, &installations); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal installations: %v", err)
+	}
+
+	if path == legacyFilePath {
+		if err := s.save(installations); err != nil {
+			log.Printf("Warning: Could not move %d installations from %s to %s: %v", len(installations), legacyFilePath, s.getFilePath(), err)
+		} else {
+			log.Printf("Moved %d installations from %s to %s", len(installations), legacyFilePath, s.getFilePath())
+		}
+	}
+	return installations, nil
+}
+
+// save writes the installations; callers must hold the mutex
+func (s *Store) save(installations map[string]Installation) error {
+	if err := os.MkdirAll(s.dir, 0700); err != nil {
+		return fmt.Errorf("failed to create installation directory: %v", err)
+	}
+
+	data, err := json.MarshalIndent(installations, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal installations: %v", err)
+	}
+
+	if err := statecrypt.WriteFile(s.getFilePath(), data); err != nil {
+		return fmt.Errorf("failed to write installations: %v", err)
+	}
+	return nil
+}
+
+// Save stores (or replaces, on reinstall) the installation of a workspace
+func (s *Store) Save(installation Installation) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	installations, err := s.load()
+	if err != nil {
+		return err
+	}
+	installations[installation.TeamID] = installation
+	return s.save(installations)
+}
+
+// Get returns the installation of a workspace, if it installed the app through the OAuth flow
+func (s *Store) Get(teamID string) (Installation, bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	installations, err := s.load()
+	if err != nil {
+		return Installation{}, false, err
+	}
+	installation, exists := installations[teamID]
+	return installation, exists, nil
+}
+
+// Delete removes the installation of a workspace that uninstalled the app
+func (s *Store) Delete(teamID string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	installations, err := s.load()
+	if err != nil {
+		return err
+	}
+	if _, exists := installations[teamID]; !exists {
+		return nil
+	}
+	delete(installations, teamID)
+	return s.save(installations)
+}
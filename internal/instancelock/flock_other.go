@@ -0,0 +1,15 @@
+//go:build !unix
+
+package instancelock
+
+import "os"
+
+// lockFile does nothing where flock is unavailable; the bot is deployed on Linux
+func lockFile(file *os.File) error {
+	return nil
+}
+
+// unlockFile does nothing where flock is unavailable
+func unlockFile(file *os.File) error {
+	return nil
+}
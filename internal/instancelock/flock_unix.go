@@ -0,0 +1,18 @@
+//go:build unix
+
+package instancelock
+
+import (
+	"os"
+	"syscall"
+)
+
+// lockFile takes an exclusive flock without blocking
+func lockFile(file *os.File) error {
+	return syscall.Flock(int(file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+}
+
+// unlockFile releases the flock
+func unlockFile(file *os.File) error {
+	return syscall.Flock(int(file.Fd()), syscall.LOCK_UN)
+}
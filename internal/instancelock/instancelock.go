@@ -0,0 +1,57 @@
+package instancelock
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// HostPath is the lock file guarding the host-local state (/tmp/slack-bot-*): progress files, the retry spool,
+// run history and the other registries are written without coordination between processes
+const HostPath = "/tmp/slack-bot.lock"
+
+// Lock is an exclusive lock held for the lifetime of the process
+type Lock struct {
+	file *os.File
+}
+
+// Acquire takes the lock at path without waiting. When another live process holds it, the error names that process
+// so the duplicate can be found and stopped. The lock is released by the OS when the process exits.
+//
+// The lock is advisory and only excludes processes that see the same file: instances on other hosts are stopped
+// only when the file is on a shared volume whose filesystem supports flock, which many network filesystems do not.
+func Acquire(path string) (*Lock, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lock file %s: %v", path, err)
+	}
+
+	if err := lockFile(file); err != nil {
+		holder := readHolder(file)
+		file.Close()
+		if holder != "" {
+			return nil, fmt.Errorf("another instance (PID %s) holds %s: %v", holder, path, err)
+		}
+		return nil, fmt.Errorf("another instance holds %s: %v", path, err)
+	}
+
+	// Record our PID for the error message of the next instance
+	if err := file.Truncate(0); err == nil {
+		file.WriteAt([]byte(strconv.Itoa(os.Getpid())+"\n"), 0)
+	}
+	return &Lock{file: file}, nil
+}
+
+// Release unlocks and closes the lock file
+func (l *Lock) Release() {
+	unlockFile(l.file)
+	l.file.Close()
+}
+
+// readHolder returns the PID written by the process holding the lock, or "" if it cannot be read
+func readHolder(file *os.File) string {
+	buffer := make([]byte, 32)
+	n, _ := file.ReadAt(buffer, 0)
+	return strings.TrimSpace(string(buffer[:n]))
+}
@@ -0,0 +1,180 @@
+package latency
+
+import (
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// historyDays is how many past days of percentiles are kept for the weekly report
+	historyDays = 7
+	// maxSamples caps the samples kept per day and for the recent window; beyond it a uniform random subset is kept
+	maxSamples = 50000
+	// RecentWindow is the window the SLO is checked against
+	RecentWindow = time.Hour
+)
+
+// Summary holds latency percentiles in seconds
+type Summary struct {
+	Count int     `json:"count"`
+	P50   float64 `json:"p50_seconds"`
+	P95   float64 `json:"p95_seconds"`
+	P99   float64 `json:"p99_seconds"`
+	Max   float64 `json:"max_seconds"`
+}
+
+// DaySummary is the latency of one calendar day
+type DaySummary struct {
+	Date string `json:"date"` // YYYY-MM-DD (JST)
+	Summary
+}
+
+// Snapshot is the latency from receiving a Slack event to its row being written to the sheet
+type Snapshot struct {
+	LastHour   Summary      `json:"last_hour"`
+	Today      DaySummary   `json:"today"`
+	Days       []DaySummary `json:"days"` // Previous days, oldest first
+	SLOSeconds float64      `json:"slo_p95_seconds,omitempty"`
+}
+
+// timedSample is one measured latency
+type timedSample struct {
+	at       time.Time
+	duration time.Duration
+}
+
+// Tracker measures the time from event receipt to durable record per message
+type Tracker struct {
+	mutex      sync.Mutex
+	location   *time.Location
+	slo        time.Duration
+	recent     []timedSample // Within RecentWindow, oldest first
+	today      string
+	todaySeen  int
+	todayDurs  []time.Duration // Reservoir sample of today's latencies
+	days       []DaySummary
+	randomizer *rand.Rand
+}
+
+var defaultTracker = NewTracker()
+
+// Default returns the process-wide tracker
+func Default() *Tracker {
+	return defaultTracker
+}
+
+// NewTracker creates an empty tracker
+func NewTracker() *Tracker {
+	return &Tracker{
+		location:   time.FixedZone("JST", 9*60*60),
+		randomizer: rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// SetSLO sets the p95 target reported with snapshots (0 means none)
+func (t *Tracker) SetSLO(slo time.Duration) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.slo = slo
+}
+
+// SLO returns the p95 target (0 means none)
+func (t *Tracker) SLO() time.Duration {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return t.slo
+}
+
+// Record adds the latency of one message recorded at the given time
+func (t *Tracker) Record(duration time.Duration, at time.Time) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.rollDay(at)
+	t.pruneRecent(at)
+	if len(t.recent) < maxSamples {
+		t.recent = append(t.recent, timedSample{at: at, duration: duration})
+	}
+
+	// Reservoir sampling keeps the day's percentiles unbiased with bounded memory
+	t.todaySeen++
+	if len(t.todayDurs) < maxSamples {
+		t.todayDurs = append(t.todayDurs, duration)
+	} else if index := t.randomizer.Intn(t.todaySeen); index < maxSamples {
+		t.todayDurs[index] = duration
+	}
+}
+
+// rollDay closes the previous day's summary when the date changes; callers must hold the mutex
+func (t *Tracker) rollDay(now time.Time) {
+	date := now.In(t.location).Format("2006-01-02")
+	if date == t.today {
+		return
+	}
+	if t.today != "" && t.todaySeen > 0 {
+		summary := summarize(t.todayDurs)
+		summary.Count = t.todaySeen
+		t.days = append(t.days, DaySummary{Date: t.today, Summary: summary})
+		if len(t.days) > historyDays {
+			t.days = t.days[len(t.days)-historyDays:]
+		}
+	}
+	t.today, t.todaySeen, t.todayDurs = date, 0, nil
+}
+
+// pruneRecent drops samples older than RecentWindow; callers must hold the mutex
+func (t *Tracker) pruneRecent(now time.Time) {
+	cutoff := now.Add(-RecentWindow)
+	index := 0
+	for index < len(t.recent) && t.recent[index].at.Before(cutoff) {
+		index++
+	}
+	t.recent = t.recent[index:]
+}
+
+// Snapshot returns the percentiles of the last hour, today and the previous days
+func (t *Tracker) Snapshot(now time.Time) Snapshot {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.rollDay(now)
+	t.pruneRecent(now)
+
+	recent := make([]time.Duration, len(t.recent))
+	for i, sample := range t.recent {
+		recent[i] = sample.duration
+	}
+	today := summarize(t.todayDurs)
+	today.Count = t.todaySeen
+
+	return Snapshot{
+		LastHour:   summarize(recent),
+		Today:      DaySummary{Date: t.today, Summary: today},
+		Days:       append([]DaySummary{}, t.days...),
+		SLOSeconds: t.slo.Seconds(),
+	}
+}
+
+// summarize computes the percentiles of a set of latencies
+func summarize(durations []time.Duration) Summary {
+	if len(durations) == 0 {
+		return Summary{}
+	}
+	sorted := append([]time.Duration{}, durations...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	return Summary{
+		Count: len(sorted),
+		P50:   percentile(sorted, 50).Seconds(),
+		P95:   percentile(sorted, 95).Seconds(),
+		P99:   percentile(sorted, 99).Seconds(),
+		Max:   sorted[len(sorted)-1].Seconds(),
+	}
+}
+
+// percentile returns the p-th percentile (0-100) of sorted latencies
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	index := int(float64(len(sorted)-1) * p / 100)
+	return sorted[index]
+}
@@ -0,0 +1,145 @@
+package legalhold
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Hold is a legal hold placed on a channel
+type Hold struct {
+	PlacedBy string    `json:"placed_by"`
+	PlacedAt time.Time `json:"placed_at"`
+	Reason   string    `json:"reason,omitempty"`
+}
+
+// legacyRegistryFile is where holds were kept before the registry moved to a data directory; it is read once when
+// the new registry does not exist yet
+const legacyRegistryFile = "/tmp/slack-bot-legalhold/channels.json"
+
+// unreadableHold is reported for every channel while the registry cannot be read, so no recorded data is deleted
+// on the assumption that nothing is held
+var unreadableHold = Hold{PlacedBy: "unknown", Reason: "legal hold registry could not be read"}
+
+// Registry keeps track of channels under legal hold, whose recorded data must not be deleted. It lives in a data
+// directory that survives restarts, because a lost registry would silently lift every hold.
+type Registry struct {
+	dir      string
+	mutex    sync.Mutex
+	channels map[string]Hold // channel ID -> hold
+	loaded   bool
+}
+
+// NewRegistry creates a legal hold registry kept in dir
+func NewRegistry(dir string) *Registry {
+	return &Registry{
+		dir:      dir,
+		channels: make(map[string]Hold),
+	}
+}
+
+// getRegistryFilePath returns the file path of the legal hold registry
+func (r *Registry) getRegistryFilePath() string {
+	return filepath.Join(r.dir, "channels.json")
+}
+
+// load reads the registry from disk until it succeeds once; callers must hold the mutex. A registry that exists
+// but cannot be read or parsed is an error, and is read again on the next call.
+func (r *Registry) load() error {
+	if r.loaded {
+		return nil
+	}
+
+	path := r.getRegistryFilePath()
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		path = legacyRegistryFile
+		data, err = os.ReadFile(path)
+		if os.IsNotExist(err) {
+			r.loaded = true
+			return nil
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read legal hold registry %s: %v", path, err)
+	}
+
+	channels := make(map[string]Hold)
+	if err := json.Unmarshal(data, &channels); err != nil {
+		return fmt.Errorf("failed to parse legal hold registry %s: %v", path, err)
+	}
+	r.channels = channels
+	r.loaded = true
+
+	if path == legacyRegistryFile {
+		if err := r.save(); err != nil {
+			log.Printf("ERROR: Could not move %d legal holds from %s to %s: %v", len(channels), legacyRegistryFile, r.getRegistryFilePath(), err)
+		} else {
+			log.Printf("Moved %d legal holds from %s to %s", len(channels), legacyRegistryFile, r.getRegistryFilePath())
+		}
+	}
+	return nil
+}
+
+// save writes the registry to disk; callers must hold the mutex
+func (r *Registry) save() error {
+	if err := os.MkdirAll(r.dir, 0700); err != nil {
+		return fmt.Errorf("failed to create legal hold directory: %v", err)
+	}
+
+	data, err := json.MarshalIndent(r.channels, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal legal hold registry: %v", err)
+	}
+
+	if err := os.WriteFile(r.getRegistryFilePath(), data, 0600); err != nil {
+		return fmt.Errorf("failed to write legal hold registry: %v", err)
+	}
+	return nil
+}
+
+// Place puts a channel under legal hold
+func (r *Registry) Place(channelID, userID, reason string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	// Writing a registry that could not be read would drop the holds in it
+	if err := r.load(); err != nil {
+		return err
+	}
+	r.channels[channelID] = Hold{PlacedBy: userID, PlacedAt: time.Now(), Reason: reason}
+	return r.save()
+}
+
+// Release lifts the legal hold of a channel, reporting whether it was held
+func (r *Registry) Release(channelID string) (bool, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if err := r.load(); err != nil {
+		return false, err
+	}
+	if _, exists := r.channels[channelID]; !exists {
+		return false, nil
+	}
+	delete(r.channels, channelID)
+	return true, r.save()
+}
+
+// HoldOf returns the legal hold of a channel, if it is held. While the registry cannot be read every channel is
+// treated as held.
+func (r *Registry) HoldOf(channelID string) (Hold, bool) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if err := r.load(); err != nil {
+		log.Printf("ERROR: Legal hold registry is unreadable, treating channel %s as held until it is fixed: %v", channelID, err)
+		return unreadableHold, true
+	}
+	hold, exists := r.channels[channelID]
+	return hold, exists
+}
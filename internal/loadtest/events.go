@@ -0,0 +1,97 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"time"
+)
+
+// eventGenerator builds synthetic Slack event_callback payloads
+type eventGenerator struct {
+	channels    int
+	users       int
+	threadRatio float64
+	editRatio   float64
+	random      *rand.Rand
+	sequence    int
+	recentTS    map[string][]string // channel ID -> recent top-level message timestamps
+}
+
+// newEventGenerator creates a generator for the given channel and user counts
+func newEventGenerator(opts options) *eventGenerator {
+	return &eventGenerator{
+		channels:    opts.channels,
+		users:       opts.users,
+		threadRatio: opts.threadRatio,
+		editRatio:   opts.editRatio,
+		random:      rand.New(rand.NewSource(time.Now().UnixNano())),
+		recentTS:    make(map[string][]string),
+	}
+}
+
+// next returns the JSON body of the next event; it is not safe for concurrent use
+func (g *eventGenerator) next() ([]byte, error) {
+	g.sequence++
+	channelID := fmt.Sprintf("CLOAD%04d", g.random.Intn(g.channels)+1)
+	userID := fmt.Sprintf("ULOAD%04d", g.random.Intn(g.users)+1)
+	ts := fmt.Sprintf("%d.%06d", time.Now().Unix(), g.sequence%1000000)
+	recent := g.recentTS[channelID]
+
+	inner := map[string]interface{}{
+		"type":         "message",
+		"channel":      channelID,
+		"channel_type": "channel",
+		"event_ts":     ts,
+	}
+
+	roll := g.random.Float64()
+	switch {
+	case roll < g.editRatio && len(recent) > 0:
+		edited := recent[g.random.Intn(len(recent))]
+		inner["subtype"] = "message_changed"
+		inner["ts"] = ts
+		inner["message"] = map[string]interface{}{
+			"type":   "message",
+			"user":   userID,
+			"text":   fmt.Sprintf("load test message (edited #%d)", g.sequence),
+			"ts":     edited,
+			"edited": map[string]string{"user": userID, "ts": ts},
+		}
+	case roll < g.editRatio+g.threadRatio && len(recent) > 0:
+		inner["user"] = userID
+		inner["text"] = fmt.Sprintf("load test reply #%d", g.sequence)
+		inner["ts"] = ts
+		inner["thread_ts"] = recent[g.random.Intn(len(recent))]
+	default:
+		inner["user"] = userID
+		inner["text"] = fmt.Sprintf("load test message #%d", g.sequence)
+		inner["ts"] = ts
+		recent = append(recent, ts)
+		if len(recent) > 20 {
+			recent = recent[len(recent)-20:]
+		}
+		g.recentTS[channelID] = recent
+	}
+
+	return json.Marshal(map[string]interface{}{
+		"type":       "event_callback",
+		"team_id":    "TLOAD0001",
+		"api_app_id": "ALOAD0001",
+		"event_id":   fmt.Sprintf("EvLOAD%08d", g.sequence),
+		"event_time": time.Now().Unix(),
+		"event":      inner,
+	})
+}
+
+// sign computes the X-Slack-Request-Timestamp and X-Slack-Signature headers for a body
+func sign(signingSecret string, body []byte, now time.Time) (string, string) {
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(signingSecret))
+	mac.Write([]byte(fmt.Sprintf("v0:%s:%s", timestamp, string(body))))
+	return timestamp, "v0=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,302 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/api/sheets/v4"
+)
+
+// BackendStats counts the calls the bot made to the fake backends
+type BackendStats struct {
+	Calls        map[string]int // "slack <method>" or "sheets <operation>" -> count
+	SheetsReads  int
+	SheetsWrites int
+	RowsAppended int
+}
+
+// fakeTab is one tab of the in-memory spreadsheet
+type fakeTab struct {
+	id   int64
+	rows [][]interface{}
+}
+
+// fakeBackends serves minimal in-memory versions of the Slack Web API and the Sheets/Drive APIs
+type fakeBackends struct {
+	latency time.Duration
+
+	mutex     sync.Mutex
+	calls     map[string]int
+	reads     int
+	writes    int
+	appended  int
+	tabs      map[string]*fakeTab
+	tabOrder  []string
+	nextTabID int64
+	nextTS    int64
+}
+
+// newFakeBackends creates fake backends that delay every response by latency
+func newFakeBackends(latency time.Duration) *fakeBackends {
+	return &fakeBackends{
+		latency:   latency,
+		calls:     make(map[string]int),
+		tabs:      make(map[string]*fakeTab),
+		nextTabID: 1000,
+	}
+}
+
+// count records one backend call; callers must hold the mutex
+func (f *fakeBackends) count(name string) {
+	f.calls[name]++
+}
+
+// stats returns a copy of the call counters
+func (f *fakeBackends) stats() BackendStats {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	calls := make(map[string]int, len(f.calls))
+	for name, count := range f.calls {
+		calls[name] = count
+	}
+	return BackendStats{Calls: calls, SheetsReads: f.reads, SheetsWrites: f.writes, RowsAppended: f.appended}
+}
+
+// writeJSON writes a JSON response
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(value)
+}
+
+// slackHandler serves /api/<method> with canned responses for the methods the bot uses
+func (f *fakeBackends) slackHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(f.latency)
+		method := strings.TrimPrefix(r.URL.Path, "/api/")
+
+		f.mutex.Lock()
+		f.count("slack " + method)
+		f.nextTS++
+		ts := fmt.Sprintf("%d.%06d", time.Now().Unix(), f.nextTS%1000000)
+		f.mutex.Unlock()
+
+		switch method {
+		case "users.info":
+			userID := r.URL.Query().Get("user")
+			writeJSON(w, http.StatusOK, map[string]interface{}{
+				"ok":   true,
+				"user": map[string]interface{}{"id": userID, "name": "user-" + strings.ToLower(userID), "real_name": "Load " + userID, "locale": "ja-JP"},
+			})
+		case "conversations.info":
+			channelID := r.URL.Query().Get("channel")
+			writeJSON(w, http.StatusOK, map[string]interface{}{
+				"ok":      true,
+				"channel": map[string]interface{}{"id": channelID, "name": "load-" + strings.ToLower(channelID)},
+			})
+		case "bots.info":
+			botID := r.URL.Query().Get("bot")
+			writeJSON(w, http.StatusOK, map[string]interface{}{"ok": true, "bot": map[string]interface{}{"id": botID, "name": "loadbot"}})
+		case "users.profile.get":
+			writeJSON(w, http.StatusOK, map[string]interface{}{"ok": true, "profile": map[string]interface{}{}})
+		case "conversations.history", "conversations.replies":
+			writeJSON(w, http.StatusOK, map[string]interface{}{"ok": true, "messages": []interface{}{}, "has_more": false})
+		default:
+			writeJSON(w, http.StatusOK, map[string]interface{}{"ok": true, "ts": ts, "channel": r.URL.Query().Get("channel")})
+		}
+	})
+}
+
+// googleHandler serves the subset of the Sheets v4 and Drive v3 APIs the bot uses, backed by one in-memory spreadsheet
+func (f *fakeBackends) googleHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(f.latency)
+		path := r.URL.Path
+
+		if strings.HasPrefix(path, "/drive/v3/") {
+			f.mutex.Lock()
+			f.count("drive " + r.Method)
+			f.mutex.Unlock()
+			writeJSON(w, http.StatusOK, map[string]interface{}{"id": "fake-permission"})
+			return
+		}
+
+		rest := strings.TrimPrefix(path, "/v4/spreadsheets/")
+		if rest == path {
+			http.NotFound(w, r)
+			return
+		}
+		spreadsheetID, valuesRange, hasValues := strings.Cut(rest, "/values/")
+
+		f.mutex.Lock()
+		defer f.mutex.Unlock()
+		if r.Method == http.MethodGet {
+			f.reads++
+		} else {
+			f.writes++
+		}
+
+		switch {
+		case strings.HasSuffix(spreadsheetID, "/values:batchUpdate") && r.Method == http.MethodPost:
+			f.count("sheets values.batchUpdate")
+			f.batchUpdateValues(w, r)
+		case strings.HasSuffix(spreadsheetID, ":batchUpdate") && r.Method == http.MethodPost:
+			f.count("sheets batchUpdate")
+			f.batchUpdate(w, r)
+		case !hasValues && r.Method == http.MethodGet:
+			f.count("sheets get")
+			writeJSON(w, http.StatusOK, f.spreadsheet(spreadsheetID))
+		case hasValues && r.Method == http.MethodGet:
+			f.count("sheets values.get")
+			tab, startRow := f.tabForRange(valuesRange)
+			var values [][]interface{}
+			if startRow-1 < len(tab.rows) {
+				values = tab.rows[startRow-1:]
+			}
+			writeJSON(w, http.StatusOK, &sheets.ValueRange{Range: valuesRange, Values: values})
+		case hasValues && strings.HasSuffix(valuesRange, ":append"):
+			f.count("sheets values.append")
+			f.appendValues(w, r, strings.TrimSuffix(valuesRange, ":append"))
+		case hasValues && r.Method == http.MethodPut:
+			f.count("sheets values.update")
+			f.updateValues(w, r, valuesRange)
+		default:
+			f.count("sheets unsupported")
+			writeJSON(w, http.StatusNotImplemented, map[string]interface{}{"error": map[string]interface{}{"code": 501, "message": "not supported by the fake"}})
+		}
+	})
+}
+
+// spreadsheet builds the spreadsheet resource; callers must hold the mutex
+func (f *fakeBackends) spreadsheet(spreadsheetID string) *sheets.Spreadsheet {
+	spreadsheet := &sheets.Spreadsheet{SpreadsheetId: spreadsheetID}
+	for _, title := range f.tabOrder {
+		spreadsheet.Sheets = append(spreadsheet.Sheets, &sheets.Sheet{
+			Properties: &sheets.SheetProperties{SheetId: f.tabs[title].id, Title: title},
+		})
+	}
+	return spreadsheet
+}
+
+// addTab creates a tab if it does not exist yet; callers must hold the mutex
+func (f *fakeBackends) addTab(title string) *fakeTab {
+	if tab, exists := f.tabs[title]; exists {
+		return tab
+	}
+	f.nextTabID++
+	tab := &fakeTab{id: f.nextTabID}
+	f.tabs[title] = tab
+	f.tabOrder = append(f.tabOrder, title)
+	return tab
+}
+
+// tabForRange resolves an A1 range such as "name!A2:G" to its tab and 1-based start row; callers must hold the mutex
+func (f *fakeBackends) tabForRange(a1Range string) (*fakeTab, int) {
+	title, cells, _ := strings.Cut(a1Range, "!")
+	title = strings.Trim(title, "'")
+
+	startRow := 1
+	start, _, _ := strings.Cut(cells, ":")
+	if digits := strings.TrimLeft(start, "ABCDEFGHIJKLMNOPQRSTUVWXYZ"); digits != "" {
+		fmt.Sscanf(digits, "%d", &startRow)
+	}
+	return f.addTab(title), startRow
+}
+
+// batchUpdate applies addSheet and updateSheetProperties requests; callers must hold the mutex
+func (f *fakeBackends) batchUpdate(w http.ResponseWriter, r *http.Request) {
+	var request sheets.BatchUpdateSpreadsheetRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]interface{}{"error": map[string]interface{}{"code": 400, "message": err.Error()}})
+		return
+	}
+
+	response := &sheets.BatchUpdateSpreadsheetResponse{}
+	for _, req := range request.Requests {
+		reply := &sheets.Response{}
+		switch {
+		case req.AddSheet != nil && req.AddSheet.Properties != nil:
+			tab := f.addTab(req.AddSheet.Properties.Title)
+			reply.AddSheet = &sheets.AddSheetResponse{Properties: &sheets.SheetProperties{SheetId: tab.id, Title: req.AddSheet.Properties.Title}}
+		case req.UpdateSheetProperties != nil && req.UpdateSheetProperties.Properties != nil:
+			properties := req.UpdateSheetProperties.Properties
+			for i, title := range f.tabOrder {
+				if f.tabs[title].id == properties.SheetId && properties.Title != "" && properties.Title != title {
+					f.tabs[properties.Title] = f.tabs[title]
+					delete(f.tabs, title)
+					f.tabOrder[i] = properties.Title
+					break
+				}
+			}
+		}
+		response.Replies = append(response.Replies, reply)
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// appendValues appends rows after the last row of a tab; callers must hold the mutex
+func (f *fakeBackends) appendValues(w http.ResponseWriter, r *http.Request, a1Range string) {
+	var valueRange sheets.ValueRange
+	if err := json.NewDecoder(r.Body).Decode(&valueRange); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]interface{}{"error": map[string]interface{}{"code": 400, "message": err.Error()}})
+		return
+	}
+
+	tab, _ := f.tabForRange(a1Range)
+	first := len(tab.rows) + 1
+	tab.rows = append(tab.rows, valueRange.Values...)
+	f.appended += len(valueRange.Values)
+
+	title, _, _ := strings.Cut(a1Range, "!")
+	writeJSON(w, http.StatusOK, &sheets.AppendValuesResponse{
+		Updates: &sheets.UpdateValuesResponse{
+			UpdatedRange: fmt.Sprintf("%s!A%d:A%d", title, first, len(tab.rows)),
+			UpdatedRows:  int64(len(valueRange.Values)),
+		},
+	})
+}
+
+// updateValues overwrites rows starting at the range's first row; callers must hold the mutex
+func (f *fakeBackends) updateValues(w http.ResponseWriter, r *http.Request, a1Range string) {
+	var valueRange sheets.ValueRange
+	if err := json.NewDecoder(r.Body).Decode(&valueRange); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]interface{}{"error": map[string]interface{}{"code": 400, "message": err.Error()}})
+		return
+	}
+
+	f.writeRows(a1Range, valueRange.Values)
+	writeJSON(w, http.StatusOK, &sheets.UpdateValuesResponse{UpdatedRange: a1Range, UpdatedRows: int64(len(valueRange.Values))})
+}
+
+// batchUpdateValues overwrites the rows of several ranges; callers must hold the mutex
+func (f *fakeBackends) batchUpdateValues(w http.ResponseWriter, r *http.Request) {
+	var request sheets.BatchUpdateValuesRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]interface{}{"error": map[string]interface{}{"code": 400, "message": err.Error()}})
+		return
+	}
+
+	response := &sheets.BatchUpdateValuesResponse{}
+	for _, valueRange := range request.Data {
+		f.writeRows(valueRange.Range, valueRange.Values)
+		response.TotalUpdatedRows += int64(len(valueRange.Values))
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// writeRows overwrites rows starting at the range's first row; callers must hold the mutex
+func (f *fakeBackends) writeRows(a1Range string, rows [][]interface{}) {
+	tab, startRow := f.tabForRange(a1Range)
+	for i, row := range rows {
+		index := startRow - 1 + i
+		for len(tab.rows) <= index {
+			tab.rows = append(tab.rows, []interface{}{})
+		}
+		tab.rows[index] = row
+	}
+}
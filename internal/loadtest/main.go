@@ -0,0 +1,89 @@
+// Command loadtest replays synthetic Slack events against a locally running bot whose Slack and
+// Google APIs are replaced by in-process fakes, and reports throughput, latency and queue depth.
+//
+// Start the harness first (it serves the fake backends and waits for the bot), then start the bot with:
+//
+//	SLACK_API_BASE_URL=http://localhost:9101/api/ GOOGLE_API_ENDPOINT=http://localhost:9102 \
+//	GOOGLE_SHEETS_CREDENTIALS=fake GOOGLE_SPREADSHEET_ID=loadtest go run main.go
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"time"
+)
+
+// options are the command line settings of a load test run
+type options struct {
+	target         string
+	signingSecret  string
+	rate           float64
+	duration       time.Duration
+	channels       int
+	users          int
+	threadRatio    float64
+	editRatio      float64
+	slackAddr      string
+	googleAddr     string
+	backendLatency time.Duration
+	startupWait    time.Duration
+	drainTimeout   time.Duration
+	pollInterval   time.Duration
+}
+
+func main() {
+	opts := options{}
+	flag.StringVar(&opts.target, "target", "http://localhost:8080", "base URL of the bot under test")
+	flag.StringVar(&opts.signingSecret, "signing-secret", os.Getenv("SLACK_SIGNING_SECRET"), "signing secret the bot verifies requests with")
+	flag.Float64Var(&opts.rate, "rate", 20, "events sent per second")
+	flag.DurationVar(&opts.duration, "duration", 30*time.Second, "how long events are sent")
+	flag.IntVar(&opts.channels, "channels", 5, "number of synthetic channels")
+	flag.IntVar(&opts.users, "users", 50, "number of synthetic users")
+	flag.Float64Var(&opts.threadRatio, "thread-ratio", 0.2, "share of messages posted as thread replies")
+	flag.Float64Var(&opts.editRatio, "edit-ratio", 0.05, "share of events that edit an earlier message")
+	flag.StringVar(&opts.slackAddr, "fake-slack-addr", ":9101", "listen address of the fake Slack Web API (empty to disable)")
+	flag.StringVar(&opts.googleAddr, "fake-google-addr", ":9102", "listen address of the fake Sheets/Drive API (empty to disable)")
+	flag.DurationVar(&opts.backendLatency, "backend-latency", 50*time.Millisecond, "latency added to every fake backend response")
+	flag.DurationVar(&opts.startupWait, "startup-wait", 2*time.Minute, "how long to wait for the bot's /health endpoint")
+	flag.DurationVar(&opts.drainTimeout, "drain-timeout", 2*time.Minute, "how long to wait for queued events after sending stops")
+	flag.DurationVar(&opts.pollInterval, "poll-interval", 200*time.Millisecond, "how often the queue depth is sampled")
+	flag.Parse()
+
+	if opts.signingSecret == "" {
+		log.Fatal("-signing-secret (or SLACK_SIGNING_SECRET) is required")
+	}
+	if opts.rate <= 0 || opts.channels <= 0 || opts.users <= 0 {
+		log.Fatal("-rate, -channels and -users must be positive")
+	}
+
+	backends := newFakeBackends(opts.backendLatency)
+	if opts.slackAddr != "" {
+		go serve(opts.slackAddr, backends.slackHandler())
+		log.Printf("Fake Slack Web API listening on %s (SLACK_API_BASE_URL=http://localhost%s/api/)", opts.slackAddr, opts.slackAddr)
+	}
+	if opts.googleAddr != "" {
+		go serve(opts.googleAddr, backends.googleHandler())
+		log.Printf("Fake Sheets/Drive API listening on %s (GOOGLE_API_ENDPOINT=http://localhost%s)", opts.googleAddr, opts.googleAddr)
+	}
+
+	runner := newRunner(opts)
+	if err := runner.waitForTarget(opts.startupWait); err != nil {
+		log.Fatalf("Bot is not reachable: %v", err)
+	}
+
+	log.Printf("Sending %.1f events/s for %s to %s", opts.rate, opts.duration, opts.target)
+	report := runner.run()
+	report.Backend = backends.stats()
+
+	fmt.Print(report.String())
+}
+
+// serve runs an HTTP server for a fake backend, stopping the process if it cannot listen
+func serve(addr string, handler http.Handler) {
+	if err := http.ListenAndServe(addr, handler); err != nil {
+		log.Fatalf("Fake backend on %s stopped: %v", addr, err)
+	}
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Report summarizes one load test run
+type Report struct {
+	Rate          float64
+	Sent          int
+	SendDuration  time.Duration
+	DrainDuration time.Duration
+	Latencies     []time.Duration
+	Statuses      map[int]int
+	Errors        int
+	QueueSamples  []int
+	Backend       BackendStats
+}
+
+// percentile returns the p-th percentile (0-100) of sorted latencies
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	index := int(float64(len(sorted)-1) * p / 100)
+	return sorted[index]
+}
+
+// String renders the report as plain text
+func (r *Report) String() string {
+	var b strings.Builder
+
+	accepted := r.Statuses[http.StatusOK]
+	fmt.Fprintf(&b, "\n=== Load test report ===\n")
+	fmt.Fprintf(&b, "Events sent:        %d (target %.1f/s over %s)\n", r.Sent, r.Rate, r.SendDuration.Round(time.Millisecond))
+	fmt.Fprintf(&b, "Accepted (200):     %d\n", accepted)
+	for _, status := range sortedKeys(r.Statuses) {
+		if status != http.StatusOK {
+			fmt.Fprintf(&b, "HTTP %d:           %d\n", status, r.Statuses[status])
+		}
+	}
+	if r.Errors > 0 {
+		fmt.Fprintf(&b, "Transport errors:   %d\n", r.Errors)
+	}
+
+	if r.SendDuration > 0 {
+		fmt.Fprintf(&b, "Ingest throughput:  %.1f events/s\n", float64(accepted)/r.SendDuration.Seconds())
+	}
+	if total := r.SendDuration + r.DrainDuration; total > 0 {
+		fmt.Fprintf(&b, "Processed:          %.1f events/s including %s to drain the queue\n",
+			float64(accepted)/total.Seconds(), r.DrainDuration.Round(time.Millisecond))
+	}
+
+	sorted := append([]time.Duration{}, r.Latencies...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	if len(sorted) > 0 {
+		fmt.Fprintf(&b, "Ack latency:        p50 %s, p95 %s, p99 %s, max %s\n",
+			percentile(sorted, 50).Round(time.Microsecond), percentile(sorted, 95).Round(time.Microsecond),
+			percentile(sorted, 99).Round(time.Microsecond), sorted[len(sorted)-1].Round(time.Microsecond))
+	}
+
+	if len(r.QueueSamples) > 0 {
+		maxDepth, sum := 0, 0
+		for _, depth := range r.QueueSamples {
+			sum += depth
+			if depth > maxDepth {
+				maxDepth = depth
+			}
+		}
+		fmt.Fprintf(&b, "Queue depth:        max %d, avg %.1f, last %d (%d samples)\n",
+			maxDepth, float64(sum)/float64(len(r.QueueSamples)), r.QueueSamples[len(r.QueueSamples)-1], len(r.QueueSamples))
+	}
+
+	fmt.Fprintf(&b, "Backend calls:\n")
+	for _, name := range sortedNames(r.Backend.Calls) {
+		fmt.Fprintf(&b, "  %-28s %d\n", name, r.Backend.Calls[name])
+	}
+	if accepted > 0 {
+		fmt.Fprintf(&b, "Sheets writes/event: %.2f, Sheets reads/event: %.2f, rows appended: %d\n",
+			float64(r.Backend.SheetsWrites)/float64(accepted), float64(r.Backend.SheetsReads)/float64(accepted), r.Backend.RowsAppended)
+	}
+	return b.String()
+}
+
+// sortedKeys returns the keys of an int map in ascending order
+func sortedKeys(m map[int]int) []int {
+	keys := make([]int, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Ints(keys)
+	return keys
+}
+
+// sortedNames returns the keys of a string map in ascending order
+func sortedNames(m map[string]int) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// runner sends events at a fixed rate and samples the bot's queue depth
+type runner struct {
+	opts       options
+	httpClient *http.Client
+	generator  *eventGenerator
+
+	mutex     sync.Mutex
+	latencies []time.Duration
+	statuses  map[int]int
+	errors    int
+}
+
+// newRunner creates a runner for the given options
+func newRunner(opts options) *runner {
+	return &runner{
+		opts:       opts,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		generator:  newEventGenerator(opts),
+		statuses:   make(map[int]int),
+	}
+}
+
+// waitForTarget polls the bot's health endpoint until it answers or the timeout passes
+func (r *runner) waitForTarget(timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		if _, err := r.queueDepth(); err == nil {
+			return nil
+		} else if time.Now().After(deadline) {
+			return err
+		}
+		log.Printf("Waiting for %s/health ...", r.opts.target)
+		time.Sleep(2 * time.Second)
+	}
+}
+
+// queueDepth reads the number of events the bot is still handling from its health endpoint
+func (r *runner) queueDepth() (int, error) {
+	resp, err := r.httpClient.Get(r.opts.target + "/health")
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	var health struct {
+		InFlightEvents int `json:"in_flight_events"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&health); err != nil {
+		return 0, fmt.Errorf("failed to decode health response: %v", err)
+	}
+	return health.InFlightEvents, nil
+}
+
+// run sends events for the configured duration, waits for the queue to drain and returns the report
+func (r *runner) run() *Report {
+	report := &Report{Rate: r.opts.rate}
+
+	stopSampling := make(chan struct{})
+	samplingDone := make(chan struct{})
+	go func() {
+		defer close(samplingDone)
+		r.sampleQueueDepth(report, stopSampling)
+	}()
+
+	var wg sync.WaitGroup
+	ticker := time.NewTicker(time.Duration(float64(time.Second) / r.opts.rate))
+	start := time.Now()
+	for time.Since(start) < r.opts.duration {
+		<-ticker.C
+		body, err := r.generator.next()
+		if err != nil {
+			log.Printf("Error generating event: %v", err)
+			continue
+		}
+		report.Sent++
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r.send(body)
+		}()
+	}
+	ticker.Stop()
+	wg.Wait()
+	report.SendDuration = time.Since(start)
+
+	// Keep sampling until the bot has handled everything it accepted
+	drainStart := time.Now()
+	for time.Since(drainStart) < r.opts.drainTimeout {
+		depth, err := r.queueDepth()
+		if err == nil && depth == 0 {
+			break
+		}
+		time.Sleep(r.opts.pollInterval)
+	}
+	report.DrainDuration = time.Since(drainStart)
+	close(stopSampling)
+	<-samplingDone
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	report.Latencies = r.latencies
+	report.Statuses = r.statuses
+	report.Errors = r.errors
+	return report
+}
+
+// send posts one signed event to the bot and records the response latency
+func (r *runner) send(body []byte) {
+	timestamp, signature := sign(r.opts.signingSecret, body, time.Now())
+	req, err := http.NewRequest(http.MethodPost, r.opts.target+"/slack/events", bytes.NewReader(body))
+	if err != nil {
+		r.recordError()
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Slack-Request-Timestamp", timestamp)
+	req.Header.Set("X-Slack-Signature", signature)
+
+	started := time.Now()
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		r.recordError()
+		return
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	latency := time.Since(started)
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.latencies = append(r.latencies, latency)
+	r.statuses[resp.StatusCode]++
+}
+
+// recordError counts a request that got no HTTP response
+func (r *runner) recordError() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.errors++
+}
+
+// sampleQueueDepth polls the queue depth into the report until stop is closed
+func (r *runner) sampleQueueDepth(report *Report, stop <-chan struct{}) {
+	ticker := time.NewTicker(r.opts.pollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			depth, err := r.queueDepth()
+			if err != nil {
+				continue
+			}
+			report.QueueSamples = append(report.QueueSamples, depth)
+		}
+	}
+}
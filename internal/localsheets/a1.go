@@ -0,0 +1,82 @@
+package localsheets
+
+import (
+	"strconv"
+	"strings"
+)
+
+// gridRange is a parsed A1 range; rows and columns are 0-based, and an end of -1 means open-ended
+type gridRange struct {
+	title    string
+	startRow int
+	endRow   int // exclusive
+	startCol int
+	endCol   int // exclusive
+}
+
+// parseA1 parses ranges such as "name!A2:G", "'name'!1:1", "name!G:G" or a bare tab title
+func parseA1(a1Range string) gridRange {
+	title, cells, hasCells := strings.Cut(a1Range, "!")
+	parsed := gridRange{title: strings.ReplaceAll(strings.Trim(title, "'"), "''", "'"), endRow: -1, endCol: -1}
+	if !hasCells || cells == "" {
+		return parsed
+	}
+
+	start, end, hasEnd := strings.Cut(cells, ":")
+	startCol, startRow := parseCell(start)
+	if startCol >= 0 {
+		parsed.startCol = startCol
+	}
+	if startRow >= 0 {
+		parsed.startRow = startRow
+	}
+	if !hasEnd {
+		// A single cell
+		if startCol >= 0 {
+			parsed.endCol = startCol + 1
+		}
+		if startRow >= 0 {
+			parsed.endRow = startRow + 1
+		}
+		return parsed
+	}
+
+	endCol, endRow := parseCell(end)
+	if endCol >= 0 {
+		parsed.endCol = endCol + 1
+	}
+	if endRow >= 0 {
+		parsed.endRow = endRow + 1
+	}
+	return parsed
+}
+
+// parseCell parses a cell reference such as "G5", "G" or "5" into a 0-based column and row (-1 when absent)
+func parseCell(cell string) (int, int) {
+	letters := strings.TrimRight(cell, "0123456789")
+	digits := cell[len(letters):]
+
+	column := -1
+	if letters != "" {
+		column = 0
+		for _, letter := range strings.ToUpper(letters) {
+			column = column*26 + int(letter-'A'+1)
+		}
+		column--
+	}
+
+	row := -1
+	if number, err := strconv.Atoi(digits); err == nil && number > 0 {
+		row = number - 1
+	}
+	return column, row
+}
+
+// columnLetter converts a 0-based column index into its A1 letter (0 -> A, 26 -> AA)
+func columnLetter(index int) string {
+	letter := ""
+	for index++; index > 0; index = (index - 1) / 26 {
+		letter = string(rune('A'+(index-1)%26)) + letter
+	}
+	return letter
+}
@@ -0,0 +1,439 @@
+package localsheets
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"google.golang.org/api/sheets/v4"
+)
+
+// Server emulates the parts of the Sheets and Drive APIs the bot uses, keeping every spreadsheet as a directory of
+// CSV files (one per tab) so the bot can be developed and demonstrated without a Google project. Formatting requests
+// (tab colors, notes, ...) are accepted and ignored.
+type Server struct {
+	dir string
+
+	mutex        sync.Mutex
+	spreadsheets map[string]*spreadsheet
+	created      int
+}
+
+// Start serves the emulator on a local port, storing spreadsheets under dir, and returns its endpoint for
+// sheets.SetAPIEndpoint
+func Start(dir string) (string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("unable to create %s: %v", dir, err)
+	}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", fmt.Errorf("unable to listen for the local sheets emulator: %v", err)
+	}
+
+	server := &Server{dir: dir, spreadsheets: make(map[string]*spreadsheet)}
+	go func() {
+		if err := http.Serve(listener, server); err != nil {
+			log.Printf("Local sheets emulator stopped: %v", err)
+		}
+	}()
+	return "http://" + listener.Addr().String(), nil
+}
+
+// writeJSON writes a JSON response
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(value)
+}
+
+// writeError writes an error in the Google API format
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]interface{}{"error": map[string]interface{}{"code": status, "message": message}})
+}
+
+// ServeHTTP routes Sheets requests (/v4/spreadsheets/...) and Drive requests (/drive/v3/...)
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if strings.HasPrefix(r.URL.Path, "/drive/v3/") {
+		s.serveDrive(w, r)
+		return
+	}
+
+	rest := strings.TrimPrefix(r.URL.Path, "/v4/spreadsheets/")
+	if rest == r.URL.Path {
+		writeError(w, http.StatusNotFound, "not supported by the local sheets emulator")
+		return
+	}
+	spreadsheetID, valuesPath, hasValues := strings.Cut(rest, "/values")
+	spreadsheetID, method, _ := strings.Cut(spreadsheetID, ":")
+
+	book, err := s.spreadsheet(spreadsheetID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	switch {
+	case !hasValues && method == "batchUpdate":
+		s.batchUpdate(w, r, book)
+	case !hasValues:
+		writeJSON(w, http.StatusOK, book.resource())
+	case valuesPath == ":batchGet":
+		var ranges []*sheets.ValueRange
+		for _, a1Range := range r.URL.Query()["ranges"] {
+			ranges = append(ranges, readRange(book, a1Range, r.URL.Query().Get("valueRenderOption")))
+		}
+		writeJSON(w, http.StatusOK, &sheets.BatchGetValuesResponse{SpreadsheetId: spreadsheetID, ValueRanges: ranges})
+	case valuesPath == ":batchUpdate":
+		var request sheets.BatchUpdateValuesRequest
+		if !decode(w, r, &request) {
+			return
+		}
+		for _, valueRange := range request.Data {
+			if !writeRange(book, valueRange.Range, valueRange.Values, request.ValueInputOption == "USER_ENTERED") {
+				writeError(w, http.StatusBadRequest, "Unable to parse range: "+valueRange.Range)
+				return
+			}
+		}
+		s.save(w, book, &sheets.BatchUpdateValuesResponse{SpreadsheetId: spreadsheetID})
+	default:
+		// Ranges contain colons themselves, so only the known custom methods are cut off
+		a1Range, action := strings.TrimPrefix(valuesPath, "/"), ""
+		for _, suffix := range []string{"append", "clear"} {
+			if strings.HasSuffix(a1Range, ":"+suffix) {
+				a1Range, action = strings.TrimSuffix(a1Range, ":"+suffix), suffix
+			}
+		}
+		s.serveValues(w, r, book, a1Range, action)
+	}
+}
+
+// serveValues handles the requests on one range: get, update, append and clear
+func (s *Server) serveValues(w http.ResponseWriter, r *http.Request, book *spreadsheet, a1Range, action string) {
+	userEntered := r.URL.Query().Get("valueInputOption") == "USER_ENTERED"
+
+	switch {
+	case action == "" && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, readRange(book, a1Range, r.URL.Query().Get("valueRenderOption")))
+	case action == "" && r.Method == http.MethodPut:
+		var valueRange sheets.ValueRange
+		if !decode(w, r, &valueRange) {
+			return
+		}
+		if !writeRange(book, a1Range, valueRange.Values, userEntered) {
+			writeError(w, http.StatusBadRequest, "Unable to parse range: "+a1Range)
+			return
+		}
+		s.save(w, book, &sheets.UpdateValuesResponse{UpdatedRange: a1Range, UpdatedRows: int64(len(valueRange.Values))})
+	case action == "append":
+		var valueRange sheets.ValueRange
+		if !decode(w, r, &valueRange) {
+			return
+		}
+		area := parseA1(a1Range)
+		sheet := book.tab(area.title)
+		if sheet == nil {
+			writeError(w, http.StatusBadRequest, "Unable to parse range: "+a1Range)
+			return
+		}
+		first := sheet.lastRow()
+		sheet.write(first, area.startCol, valueRange.Values, userEntered)
+
+		width := 1
+		for _, row := range valueRange.Values {
+			if len(row) > width {
+				width = len(row)
+			}
+		}
+		updated := fmt.Sprintf("%s!%s%d:%s%d", area.title, columnLetter(area.startCol), first+1,
+			columnLetter(area.startCol+width-1), first+len(valueRange.Values))
+		s.save(w, book, &sheets.AppendValuesResponse{
+			Updates: &sheets.UpdateValuesResponse{UpdatedRange: updated, UpdatedRows: int64(len(valueRange.Values))},
+		})
+	case action == "clear":
+		area := parseA1(a1Range)
+		if sheet := book.tab(area.title); sheet != nil {
+			sheet.clear(area)
+		}
+		s.save(w, book, &sheets.ClearValuesResponse{ClearedRange: a1Range})
+	default:
+		writeError(w, http.StatusNotImplemented, "not supported by the local sheets emulator")
+	}
+}
+
+// batchUpdate applies the structural requests the bot sends; requests that only change formatting are ignored
+func (s *Server) batchUpdate(w http.ResponseWriter, r *http.Request, book *spreadsheet) {
+	var request sheets.BatchUpdateSpreadsheetRequest
+	if !decode(w, r, &request) {
+		return
+	}
+
+	response := &sheets.BatchUpdateSpreadsheetResponse{SpreadsheetId: book.id}
+	for _, req := range request.Requests {
+		reply := &sheets.Response{}
+		switch {
+		case req.AddSheet != nil && req.AddSheet.Properties != nil:
+			if book.tab(req.AddSheet.Properties.Title) != nil {
+				writeError(w, http.StatusBadRequest, fmt.Sprintf("A sheet with the name %q already exists", req.AddSheet.Properties.Title))
+				return
+			}
+			sheet := book.addTab(req.AddSheet.Properties.Title)
+			reply.AddSheet = &sheets.AddSheetResponse{Properties: book.properties(sheet)}
+		case req.DeleteSheet != nil:
+			book.deleteTab(req.DeleteSheet.SheetId)
+		case req.UpdateSheetProperties != nil && req.UpdateSheetProperties.Properties != nil:
+			book.updateProperties(req.UpdateSheetProperties)
+		case req.DeleteDimension != nil && req.DeleteDimension.Range != nil:
+			area := req.DeleteDimension.Range
+			if sheet := book.tabByID(area.SheetId); sheet != nil {
+				end := int(area.EndIndex)
+				if end == 0 {
+					end = int(^uint(0) >> 1)
+				}
+				if area.Dimension == "COLUMNS" {
+					sheet.deleteColumns(int(area.StartIndex), end)
+				} else {
+					sheet.deleteRows(int(area.StartIndex), end)
+				}
+			}
+		case req.InsertDimension != nil && req.InsertDimension.Range != nil:
+			area := req.InsertDimension.Range
+			if sheet := book.tabByID(area.SheetId); sheet != nil {
+				if area.Dimension == "COLUMNS" {
+					sheet.insertColumns(int(area.StartIndex), int(area.EndIndex-area.StartIndex))
+				} else {
+					sheet.insertRows(int(area.StartIndex), int(area.EndIndex-area.StartIndex))
+				}
+			}
+		case req.MoveDimension != nil && req.MoveDimension.Source != nil && req.MoveDimension.Source.Dimension == "COLUMNS":
+			area := req.MoveDimension.Source
+			if sheet := book.tabByID(area.SheetId); sheet != nil {
+				sheet.moveColumns(int(area.StartIndex), int(area.EndIndex), int(req.MoveDimension.DestinationIndex))
+			}
+		case req.UpdateCells != nil && strings.Contains(req.UpdateCells.Fields, "userEnteredValue"):
+			book.updateCells(req.UpdateCells)
+		case req.CreateDeveloperMetadata != nil && req.CreateDeveloperMetadata.DeveloperMetadata != nil:
+			metadata := req.CreateDeveloperMetadata.DeveloperMetadata
+			if metadata.Location != nil {
+				if sheet := book.tabByID(metadata.Location.SheetId); sheet != nil {
+					metadata.MetadataId = book.nextID
+					book.nextID++
+					sheet.Metadata = append(sheet.Metadata, metadata)
+					reply.CreateDeveloperMetadata = &sheets.CreateDeveloperMetadataResponse{DeveloperMetadata: metadata}
+				}
+			}
+		case req.UpdateDeveloperMetadata != nil && req.UpdateDeveloperMetadata.DeveloperMetadata != nil:
+			book.updateMetadata(req.UpdateDeveloperMetadata)
+		}
+		response.Replies = append(response.Replies, reply)
+	}
+	s.save(w, book, response)
+}
+
+// serveDrive answers the Drive calls the bot makes: continuation spreadsheets are created as new directories, and
+// sharing is accepted without effect since local files have no permissions to manage
+func (s *Server) serveDrive(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/drive/v3/files")
+	switch {
+	case strings.Contains(path, "/permissions") && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, map[string]interface{}{"permissions": []interface{}{}})
+	case strings.Contains(path, "/permissions"):
+		writeJSON(w, http.StatusOK, map[string]interface{}{"id": "local"})
+	case path == "" && r.Method == http.MethodPost:
+		var file struct {
+			Name string `json:"name"`
+		}
+		if !decode(w, r, &file) {
+			return
+		}
+		// Skip the IDs of spreadsheets created by earlier runs
+		var id string
+		for {
+			s.created++
+			id = fmt.Sprintf("local-%d", s.created)
+			if _, err := os.Stat(filepath.Join(s.dir, fileName(id))); os.IsNotExist(err) {
+				break
+			}
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"id": id, "name": file.Name})
+	case r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, map[string]interface{}{"id": strings.TrimPrefix(path, "/"), "name": strings.TrimPrefix(path, "/")})
+	default:
+		writeError(w, http.StatusNotImplemented, "not supported by the local sheets emulator")
+	}
+}
+
+// spreadsheet returns a loaded spreadsheet; callers must hold the mutex
+func (s *Server) spreadsheet(id string) (*spreadsheet, error) {
+	if book, exists := s.spreadsheets[id]; exists {
+		return book, nil
+	}
+	book, err := loadSpreadsheet(s.dir, id)
+	if err != nil {
+		return nil, err
+	}
+	s.spreadsheets[id] = book
+	return book, nil
+}
+
+// save writes the changed tabs to disk, then the response
+func (s *Server) save(w http.ResponseWriter, book *spreadsheet, response interface{}) {
+	if err := book.save(); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// decode reads a JSON request body, answering 400 when it cannot be parsed
+func decode(w http.ResponseWriter, r *http.Request, value interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(value); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return false
+	}
+	return true
+}
+
+// readRange reads the values of an A1 range
+func readRange(book *spreadsheet, a1Range, renderOption string) *sheets.ValueRange {
+	area := parseA1(a1Range)
+	valueRange := &sheets.ValueRange{Range: a1Range, MajorDimension: "ROWS"}
+	if sheet := book.tab(area.title); sheet != nil {
+		valueRange.Values = sheet.read(area, renderOption == "UNFORMATTED_VALUE")
+	}
+	return valueRange
+}
+
+// writeRange writes values starting at the first cell of an A1 range; it returns false when the tab does not exist
+func writeRange(book *spreadsheet, a1Range string, values [][]interface{}, userEntered bool) bool {
+	area := parseA1(a1Range)
+	sheet := book.tab(area.title)
+	if sheet == nil {
+		return false
+	}
+	sheet.write(area.startRow, area.startCol, values, userEntered)
+	return true
+}
+
+// resource returns the spreadsheet resource with the properties and developer metadata of its tabs
+func (book *spreadsheet) resource() *sheets.Spreadsheet {
+	resource := &sheets.Spreadsheet{
+		SpreadsheetId: book.id,
+		Properties:    &sheets.SpreadsheetProperties{Title: book.id},
+	}
+	for _, sheet := range book.tabs {
+		resource.Sheets = append(resource.Sheets, &sheets.Sheet{
+			Properties:        book.properties(sheet),
+			DeveloperMetadata: sheet.Metadata,
+		})
+	}
+	return resource
+}
+
+// properties returns the properties of a tab; the grid is at least the size of a new Google Sheets tab
+func (book *spreadsheet) properties(sheet *tab) *sheets.SheetProperties {
+	index := 0
+	for i, other := range book.tabs {
+		if other == sheet {
+			index = i
+		}
+	}
+	rows, columns := len(sheet.rows), sheet.columnCount()
+	if rows < 1000 {
+		rows = 1000
+	}
+	if columns < 26 {
+		columns = 26
+	}
+	return &sheets.SheetProperties{
+		SheetId:        sheet.ID,
+		Title:          sheet.Title,
+		Index:          int64(index),
+		SheetType:      "GRID",
+		GridProperties: &sheets.GridProperties{RowCount: int64(rows), ColumnCount: int64(columns)},
+	}
+}
+
+// updateProperties applies a title or index change of a tab
+func (book *spreadsheet) updateProperties(request *sheets.UpdateSheetPropertiesRequest) {
+	properties := request.Properties
+	sheet := book.tabByID(properties.SheetId)
+	if sheet == nil {
+		return
+	}
+	if strings.Contains(request.Fields, "title") && properties.Title != "" && properties.Title != sheet.Title {
+		book.renameTab(sheet, properties.Title)
+	}
+	if strings.Contains(request.Fields, "index") {
+		for i, other := range book.tabs {
+			if other == sheet {
+				book.tabs = append(book.tabs[:i], book.tabs[i+1:]...)
+				break
+			}
+		}
+		index := int(properties.Index)
+		if index > len(book.tabs) {
+			index = len(book.tabs)
+		}
+		book.tabs = append(book.tabs[:index], append([]*tab{sheet}, book.tabs[index:]...)...)
+	}
+}
+
+// updateCells writes the user-entered values of an UpdateCells request
+func (book *spreadsheet) updateCells(request *sheets.UpdateCellsRequest) {
+	var sheetID int64
+	var startRow, startCol int
+	switch {
+	case request.Range != nil:
+		sheetID, startRow, startCol = request.Range.SheetId, int(request.Range.StartRowIndex), int(request.Range.StartColumnIndex)
+	case request.Start != nil:
+		sheetID, startRow, startCol = request.Start.SheetId, int(request.Start.RowIndex), int(request.Start.ColumnIndex)
+	}
+	sheet := book.tabByID(sheetID)
+	if sheet == nil {
+		return
+	}
+
+	for i, row := range request.Rows {
+		for j, cell := range row.Values {
+			text := ""
+			if value := cell.UserEnteredValue; value != nil {
+				switch {
+				case value.StringValue != nil:
+					text = *value.StringValue
+				case value.NumberValue != nil:
+					text = cellText(*value.NumberValue, false)
+				case value.BoolValue != nil:
+					text = cellText(*value.BoolValue, false)
+				case value.FormulaValue != nil:
+					text = *value.FormulaValue
+				}
+			}
+			sheet.set(startRow+i, startCol+j, text)
+		}
+	}
+}
+
+// updateMetadata changes the value of developer metadata looked up by ID
+func (book *spreadsheet) updateMetadata(request *sheets.UpdateDeveloperMetadataRequest) {
+	for _, filter := range request.DataFilters {
+		if filter.DeveloperMetadataLookup == nil {
+			continue
+		}
+		for _, sheet := range book.tabs {
+			for _, metadata := range sheet.Metadata {
+				if metadata.MetadataId == filter.DeveloperMetadataLookup.MetadataId {
+					metadata.MetadataValue = request.DeveloperMetadata.MetadataValue
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,347 @@
+package localsheets
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"google.golang.org/api/sheets/v4"
+)
+
+// tabsFile lists the tabs of a spreadsheet directory with their IDs, order and developer metadata
+const tabsFile = "tabs.json"
+
+// tab is one tab of a local spreadsheet; cells are kept as the strings written to its CSV file
+type tab struct {
+	ID       int64                       `json:"id"`
+	Title    string                      `json:"title"`
+	File     string                      `json:"file"`
+	Metadata []*sheets.DeveloperMetadata `json:"metadata,omitempty"`
+	rows     [][]string
+	dirty    bool
+}
+
+// spreadsheet is a local spreadsheet stored as a directory with one CSV file per tab
+type spreadsheet struct {
+	id     string
+	dir    string
+	tabs   []*tab
+	nextID int64
+}
+
+// loadSpreadsheet reads a spreadsheet directory, or starts an empty spreadsheet when it does not exist yet
+func loadSpreadsheet(dir, id string) (*spreadsheet, error) {
+	book := &spreadsheet{id: id, dir: filepath.Join(dir, fileName(id)), nextID: 1}
+
+	data, err := os.ReadFile(filepath.Join(book.dir, tabsFile))
+	if os.IsNotExist(err) {
+		return book, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to read %s: %v", tabsFile, err)
+	}
+	if err := json.Unmarshal(data, &book.tabs); err != nil {
+		return nil, fmt.Errorf("unable to parse %s: %v", tabsFile, err)
+	}
+
+	for _, sheet := range book.tabs {
+		if sheet.ID >= book.nextID {
+			book.nextID = sheet.ID + 1
+		}
+		file, err := os.Open(filepath.Join(book.dir, sheet.File))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("unable to open %s: %v", sheet.File, err)
+		}
+		reader := csv.NewReader(file)
+		reader.FieldsPerRecord = -1
+		sheet.rows, err = reader.ReadAll()
+		file.Close()
+		if err != nil {
+			return nil, fmt.Errorf("unable to read %s: %v", sheet.File, err)
+		}
+	}
+	return book, nil
+}
+
+// save writes the tabs changed since the last save and the tab list
+func (book *spreadsheet) save() error {
+	if err := os.MkdirAll(book.dir, 0755); err != nil {
+		return fmt.Errorf("unable to create %s: %v", book.dir, err)
+	}
+
+	for _, sheet := range book.tabs {
+		if !sheet.dirty {
+			continue
+		}
+		file, err := os.Create(filepath.Join(book.dir, sheet.File))
+		if err != nil {
+			return fmt.Errorf("unable to create %s: %v", sheet.File, err)
+		}
+		writer := csv.NewWriter(file)
+		writer.WriteAll(sheet.rows)
+		file.Close()
+		if err := writer.Error(); err != nil {
+			return fmt.Errorf("unable to write %s: %v", sheet.File, err)
+		}
+		sheet.dirty = false
+	}
+
+	data, err := json.MarshalIndent(book.tabs, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(book.dir, tabsFile), data, 0644)
+}
+
+// fileName turns a tab title or spreadsheet ID into a file name
+func fileName(name string) string {
+	return strings.NewReplacer("/", "_", `\`, "_").Replace(name)
+}
+
+// tab returns the tab with the given title, or nil
+func (book *spreadsheet) tab(title string) *tab {
+	for _, sheet := range book.tabs {
+		if sheet.Title == title {
+			return sheet
+		}
+	}
+	return nil
+}
+
+// tabByID returns the tab with the given sheet ID, or nil
+func (book *spreadsheet) tabByID(id int64) *tab {
+	for _, sheet := range book.tabs {
+		if sheet.ID == id {
+			return sheet
+		}
+	}
+	return nil
+}
+
+// addTab creates a tab, or returns the existing one with that title
+func (book *spreadsheet) addTab(title string) *tab {
+	if sheet := book.tab(title); sheet != nil {
+		return sheet
+	}
+	sheet := &tab{ID: book.nextID, Title: title, File: fileName(title) + ".csv", dirty: true}
+	book.nextID++
+	book.tabs = append(book.tabs, sheet)
+	return sheet
+}
+
+// renameTab changes the title of a tab and moves its CSV file
+func (book *spreadsheet) renameTab(sheet *tab, title string) {
+	os.Remove(filepath.Join(book.dir, sheet.File))
+	sheet.Title = title
+	sheet.File = fileName(title) + ".csv"
+	sheet.dirty = true
+}
+
+// deleteTab removes a tab and its CSV file
+func (book *spreadsheet) deleteTab(id int64) {
+	for i, sheet := range book.tabs {
+		if sheet.ID == id {
+			os.Remove(filepath.Join(book.dir, sheet.File))
+			book.tabs = append(book.tabs[:i], book.tabs[i+1:]...)
+			return
+		}
+	}
+}
+
+// columnCount returns the width of the widest row
+func (sheet *tab) columnCount() int {
+	count := 0
+	for _, row := range sheet.rows {
+		if len(row) > count {
+			count = len(row)
+		}
+	}
+	return count
+}
+
+// lastRow returns the number of rows up to the last one with a non-empty cell
+func (sheet *tab) lastRow() int {
+	for i := len(sheet.rows) - 1; i >= 0; i-- {
+		for _, cell := range sheet.rows[i] {
+			if cell != "" {
+				return i + 1
+			}
+		}
+	}
+	return 0
+}
+
+// set writes a cell, growing the tab as needed
+func (sheet *tab) set(row, column int, value string) {
+	for len(sheet.rows) <= row {
+		sheet.rows = append(sheet.rows, nil)
+	}
+	for len(sheet.rows[row]) <= column {
+		sheet.rows[row] = append(sheet.rows[row], "")
+	}
+	sheet.rows[row][column] = value
+	sheet.dirty = true
+}
+
+// get reads a cell; cells outside the tab are empty
+func (sheet *tab) get(row, column int) string {
+	if row >= len(sheet.rows) || column >= len(sheet.rows[row]) {
+		return ""
+	}
+	return sheet.rows[row][column]
+}
+
+// write writes rows of values starting at a cell
+func (sheet *tab) write(startRow, startCol int, values [][]interface{}, userEntered bool) {
+	for i, row := range values {
+		for j, value := range row {
+			sheet.set(startRow+i, startCol+j, cellText(value, userEntered))
+		}
+	}
+}
+
+// read returns the cells of a range as the Sheets API does: trailing empty cells and rows are left out
+func (sheet *tab) read(area gridRange, unformatted bool) [][]interface{} {
+	endRow, endCol := area.endRow, area.endCol
+	if endRow < 0 || endRow > len(sheet.rows) {
+		endRow = len(sheet.rows)
+	}
+	if endCol < 0 {
+		endCol = sheet.columnCount()
+	}
+
+	var values [][]interface{}
+	for i := area.startRow; i < endRow; i++ {
+		var row []interface{}
+		for j := area.startCol; j < endCol; j++ {
+			row = append(row, cellValue(sheet.get(i, j), unformatted))
+		}
+		for len(row) > 0 && row[len(row)-1] == "" {
+			row = row[:len(row)-1]
+		}
+		values = append(values, row)
+	}
+	for len(values) > 0 && len(values[len(values)-1]) == 0 {
+		values = values[:len(values)-1]
+	}
+	return values
+}
+
+// clear empties the cells of a range
+func (sheet *tab) clear(area gridRange) {
+	for i := area.startRow; i < len(sheet.rows) && (area.endRow < 0 || i < area.endRow); i++ {
+		for j := area.startCol; j < len(sheet.rows[i]) && (area.endCol < 0 || j < area.endCol); j++ {
+			sheet.rows[i][j] = ""
+		}
+	}
+	sheet.dirty = true
+}
+
+// deleteRows removes the rows [start, end)
+func (sheet *tab) deleteRows(start, end int) {
+	if start >= len(sheet.rows) {
+		return
+	}
+	if end > len(sheet.rows) {
+		end = len(sheet.rows)
+	}
+	sheet.rows = append(sheet.rows[:start], sheet.rows[end:]...)
+	sheet.dirty = true
+}
+
+// deleteColumns removes the columns [start, end) of every row
+func (sheet *tab) deleteColumns(start, end int) {
+	for i, row := range sheet.rows {
+		if start >= len(row) {
+			continue
+		}
+		if end > len(row) {
+			sheet.rows[i] = row[:start]
+			continue
+		}
+		sheet.rows[i] = append(row[:start], row[end:]...)
+	}
+	sheet.dirty = true
+}
+
+// insertRows inserts count empty rows before row start
+func (sheet *tab) insertRows(start, count int) {
+	if start >= len(sheet.rows) {
+		return
+	}
+	sheet.rows = append(sheet.rows[:start], append(make([][]string, count), sheet.rows[start:]...)...)
+	sheet.dirty = true
+}
+
+// insertColumns inserts count empty columns before column start in every row
+func (sheet *tab) insertColumns(start, count int) {
+	for i, row := range sheet.rows {
+		if start >= len(row) {
+			continue
+		}
+		sheet.rows[i] = append(row[:start], append(make([]string, count), row[start:]...)...)
+	}
+	sheet.dirty = true
+}
+
+// moveColumns moves the columns [start, end) so they begin at destination, given before the move as the API does
+func (sheet *tab) moveColumns(start, end, destination int) {
+	width := sheet.columnCount()
+	if destination > width {
+		width = destination
+	}
+	for i, row := range sheet.rows {
+		for len(row) < width {
+			row = append(row, "")
+		}
+		moved := append([]string{}, row[start:end]...)
+		rest := append(append([]string{}, row[:start]...), row[end:]...)
+		at := destination
+		if destination > start {
+			at -= end - start
+		}
+		sheet.rows[i] = append(rest[:at], append(moved, rest[at:]...)...)
+	}
+	sheet.dirty = true
+}
+
+// cellText converts a written value to the text stored in the CSV file. USER_ENTERED text marked as plain with a
+// leading apostrophe is stored without it; formulas are stored as they are, since nothing evaluates them.
+func cellText(value interface{}, userEntered bool) string {
+	switch typed := value.(type) {
+	case nil:
+		return ""
+	case string:
+		if userEntered {
+			return strings.TrimPrefix(typed, "'")
+		}
+		return typed
+	case float64:
+		return strconv.FormatFloat(typed, 'f', -1, 64)
+	case bool:
+		return strings.ToUpper(strconv.FormatBool(typed))
+	default:
+		return fmt.Sprint(typed)
+	}
+}
+
+// cellValue converts stored text back to a value; UNFORMATTED_VALUE reads return numbers and booleans as such
+func cellValue(text string, unformatted bool) interface{} {
+	if !unformatted {
+		return text
+	}
+	if number, err := strconv.ParseFloat(text, 64); err == nil {
+		return number
+	}
+	if text == "TRUE" || text == "FALSE" {
+		return text == "TRUE"
+	}
+	return text
+}
@@ -0,0 +1,109 @@
+package optout
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Registry keeps track of users who opted out of message recording
+type Registry struct {
+	tmpDir string
+	mutex  sync.Mutex
+	users  map[string]time.Time // user ID -> opt-out time
+	loaded bool
+}
+
+// NewRegistry creates a new opt-out registry
+func NewRegistry() *Registry {
+	return NewRegistryAt("/tmp/slack-bot-optout")
+}
+
+// NewRegistryAt creates an opt-out registry kept in dir
+func NewRegistryAt(dir string) *Registry {
+	return &Registry{
+		tmpDir: dir,
+		users:  make(map[string]time.Time),
+	}
+}
+
+// getRegistryFilePath returns the file path of the opt-out registry
+func (r *Registry) getRegistryFilePath() string {
+	return filepath.Join(r.tmpDir, "users.json")
+}
+
+// load reads the registry from disk once; callers must hold the mutex
+func (r *Registry) load() {
+	if r.loaded {
+		return
+	}
+	r.loaded = true
+
+	data, err := os.ReadFile(r.getRegistryFilePath())
+	if os.IsNotExist(err) {
+		return
+	}
+	if err != nil {
+		log.Printf("Warning: Could not read opt-out registry: %v", err)
+		return
+	}
+
+	if err := json.Unmarshal(data, &r.users); err != nil {
+		log.Printf("Warning: Could not parse opt-out registry: %v", err)
+	}
+}
+
+// save writes the registry to disk; callers must hold the mutex
+func (r *Registry) save() error {
+	if err := os.MkdirAll(r.tmpDir, 0755); err != nil {
+		return fmt.Errorf("failed to create tmp directory: %v", err)
+	}
+
+	data, err := json.MarshalIndent(r.users, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal opt-out registry: %v", err)
+	}
+
+	if err := os.WriteFile(r.getRegistryFilePath(), data, 0644); err != nil {
+		return fmt.Errorf("failed to write opt-out registry: %v", err)
+	}
+	return nil
+}
+
+// OptOut registers a user as opted out of recording
+func (r *Registry) OptOut(userID string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.load()
+	r.users[userID] = time.Now()
+	return r.save()
+}
+
+// OptIn removes a user from the opt-out registry
+func (r *Registry) OptIn(userID string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.load()
+	delete(r.users, userID)
+	return r.save()
+}
+
+// IsOptedOut reports whether a user has opted out of recording
+func (r *Registry) IsOptedOut(userID string) bool {
+	if userID == "" {
+		return false
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.load()
+	_, exists := r.users[userID]
+	return exists
+}
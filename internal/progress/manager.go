@@ -9,10 +9,12 @@ import (
 	"time"
 
 	"slack-to-google-sheets-bot/internal/sheets"
+	"slack-to-google-sheets-bot/internal/statecrypt"
 )
 
 // ChannelProgress represents the progress state of channel history retrieval
 type ChannelProgress struct {
+	SchemaVersion     int                     `json:"schema_version"`
 	ChannelID         string                  `json:"channel_id"`
 	ChannelName       string                  `json:"channel_name"`
 	StartTime         time.Time               `json:"start_time"`
@@ -38,7 +40,7 @@ func NewManager() *Manager {
 
 // ensureTmpDir creates the temporary directory if it doesn't exist
 func (m *Manager) ensureTmpDir() error {
-	if err := os.MkdirAll(m.tmpDir, 0755); err != nil {
+	if err := os.MkdirAll(m.tmpDir, 0700); err != nil {
 		return fmt.Errorf("failed to create tmp directory: %v", err)
 	}
 	return nil
@@ -56,6 +58,7 @@ func (m *Manager) SaveProgress(progress *ChannelProgress) error {
 	}
 
 	progress.LastUpdated = time.Now()
+	progress.SchemaVersion = SchemaVersion
 
 	filePath := m.getProgressFilePath(progress.ChannelID)
 	data, err := json.MarshalIndent(progress, "", "  ")
@@ -63,7 +66,7 @@ func (m *Manager) SaveProgress(progress *ChannelProgress) error {
 		return fmt.Errorf("failed to marshal progress: %v", err)
 	}
 
-	if err := os.WriteFile(filePath, data, 0644); err != nil {
+	if err := statecrypt.WriteFile(filePath, data); err != nil {
 		return fmt.Errorf("failed to write progress file: %v", err)
 	}
 
@@ -72,7 +75,9 @@ func (m *Manager) SaveProgress(progress *ChannelProgress) error {
 	return nil
 }
 
-// LoadProgress loads progress from a temporary file
+// LoadProgress loads progress from a temporary file. Files written by an older build are migrated to the current
+// schema and saved again; a file that cannot be read (e.g. written by a newer build before a rollback) is moved
+// aside with an error instead of being overwritten by a fresh start.
 func (m *Manager) LoadProgress(channelID string) (*ChannelProgress, error) {
 	filePath := m.getProgressFilePath(channelID)
 
@@ -80,21 +85,31 @@ func (m *Manager) LoadProgress(channelID string) (*ChannelProgress, error) {
 		return nil, nil // No existing progress
 	}
 
-	data, err := os.ReadFile(filePath)
+	data, err := statecrypt.ReadFile(filePath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read progress file: %v", err)
 	}
 
-	var progress ChannelProgress
-	if err := json.Unmarshal(data, &progress); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal progress: %v", err)
+	progress, version, err := decodeProgress(data)
+	if err != nil {
+		keptPath := fmt.Sprintf("%s.unreadable-%d", filePath, time.Now().Unix())
+		if renameErr := os.Rename(filePath, keptPath); renameErr != nil {
+			return nil, fmt.Errorf("%v (could not move the file aside: %v)", err, renameErr)
+		}
+		return nil, fmt.Errorf("%v; moved it to %s", err, keptPath)
+	}
+	if version < SchemaVersion {
+		log.Printf("Migrating progress for channel %s from schema version %d to %d", channelID, version, SchemaVersion)
+		if err := m.SaveProgress(progress); err != nil {
+			return nil, err
+		}
 	}
 
 	log.Printf("Progress loaded for channel %s: %d/%d messages, phase: %s, last updated: %s",
 		progress.ChannelID, progress.ProcessedMessages, progress.TotalMessages,
 		progress.Phase, progress.LastUpdated.Format("2006-01-02 15:04:05"))
 
-	return &progress, nil
+	return progress, nil
 }
 
 // HasProgress checks if there's existing progress for a channel
@@ -0,0 +1,91 @@
+package progress
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// SchemaVersion is the version of the progress file format written by this build. Bump it whenever a change to
+// ChannelProgress or sheets.MessageRecord would make an older file read differently (a renamed or retyped field,
+// a changed meaning), and add the migration from the previous version to migrations.
+const SchemaVersion = 2
+
+// migrations upgrade a decoded progress file from the version they are keyed by to the next one. Files written
+// before the schema was versioned have no schema_version and are version 1.
+var migrations = map[int]func(document map[string]interface{}) error{
+	1: migrateV1,
+}
+
+// NewerSchemaError is returned for a progress file written by a newer build, e.g. after a rollback mid-backfill
+type NewerSchemaError struct {
+	Version int
+}
+
+// Error says which versions were involved
+func (e *NewerSchemaError) Error() string {
+	return fmt.Sprintf("progress file has schema version %d, newer than the supported %d", e.Version, SchemaVersion)
+}
+
+// decodeProgress reads a progress file of any known schema version, migrating older ones to the current version,
+// and reports the version it was written with
+func decodeProgress(data []byte) (*ChannelProgress, int, error) {
+	var document map[string]interface{}
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, 0, fmt.Errorf("failed to unmarshal progress: %v", err)
+	}
+
+	version := 1
+	if value, exists := document["schema_version"].(float64); exists {
+		version = int(value)
+	}
+	if version > SchemaVersion {
+		return nil, version, &NewerSchemaError{Version: version}
+	}
+
+	for from := version; from < SchemaVersion; from++ {
+		migrate, exists := migrations[from]
+		if !exists {
+			return nil, version, fmt.Errorf("no migration of progress schema version %d", from)
+		}
+		if err := migrate(document); err != nil {
+			return nil, version, fmt.Errorf("failed to migrate progress from schema version %d: %v", from, err)
+		}
+		document["schema_version"] = from + 1
+	}
+
+	migrated, err := json.Marshal(document)
+	if err != nil {
+		return nil, version, fmt.Errorf("failed to marshal migrated progress: %v", err)
+	}
+	var progress ChannelProgress
+	if err := json.Unmarshal(migrated, &progress); err != nil {
+		return nil, version, fmt.Errorf("failed to unmarshal progress: %v", err)
+	}
+	return &progress, version, nil
+}
+
+// migrateV1 fills in what files from before versioning could leave out: an empty phase meant fetching, a null
+// message list an empty one, and early records lacked their channel, which is the progress file's channel
+func migrateV1(document map[string]interface{}) error {
+	if phase, _ := document["phase"].(string); phase == "" {
+		document["phase"] = "fetching"
+	}
+
+	messages, _ := document["messages"].([]interface{})
+	if messages == nil {
+		document["messages"] = []interface{}{}
+	}
+	for _, message := range messages {
+		record, isObject := message.(map[string]interface{})
+		if !isObject {
+			return fmt.Errorf("message is not an object: %v", message)
+		}
+		if channel, _ := record["Channel"].(string); channel == "" {
+			record["Channel"] = document["channel_id"]
+		}
+		if channelName, _ := record["ChannelName"].(string); channelName == "" {
+			record["ChannelName"] = document["channel_name"]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,207 @@
+package quota
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// historyDays is how many days of per-day usage are kept for projections
+	historyDays = 7
+	// warnRatio is the share of a per-minute quota that triggers a warning
+	warnRatio = 0.8
+	// projectionHorizonDays is how far ahead a projected quota breach is reported
+	projectionHorizonDays = 30
+)
+
+// DayUsage is the Sheets API usage of one calendar day
+type DayUsage struct {
+	Date                string `json:"date"` // YYYY-MM-DD (JST)
+	Reads               int    `json:"reads"`
+	Writes              int    `json:"writes"`
+	PeakReadsPerMinute  int    `json:"peak_reads_per_minute"`
+	PeakWritesPerMinute int    `json:"peak_writes_per_minute"`
+}
+
+// Snapshot is the current Sheets API usage compared with the configured quota
+type Snapshot struct {
+	ReadsLastMinute        int        `json:"reads_last_minute"`
+	WritesLastMinute       int        `json:"writes_last_minute"`
+	ReadQuotaPerMinute     int        `json:"read_quota_per_minute"`
+	WriteQuotaPerMinute    int        `json:"write_quota_per_minute"`
+	ReadHeadroomPerMinute  int        `json:"read_headroom_per_minute"`
+	WriteHeadroomPerMinute int        `json:"write_headroom_per_minute"`
+	Days                   []DayUsage `json:"days"` // Oldest first, today last
+	Warnings               []string   `json:"warnings,omitempty"`
+}
+
+// Tracker counts Sheets API read and write calls
+type Tracker struct {
+	mutex               sync.Mutex
+	location            *time.Location
+	readQuotaPerMinute  int
+	writeQuotaPerMinute int
+	recentReads         []time.Time // Calls within the last minute
+	recentWrites        []time.Time
+	days                []DayUsage // Oldest first
+}
+
+var defaultTracker = NewTracker(300, 300)
+
+// Default returns the process-wide tracker
+func Default() *Tracker {
+	return defaultTracker
+}
+
+// NewTracker creates a tracker for the given per-minute quotas
+func NewTracker(readQuotaPerMinute, writeQuotaPerMinute int) *Tracker {
+	return &Tracker{
+		location:            time.FixedZone("JST", 9*60*60),
+		readQuotaPerMinute:  readQuotaPerMinute,
+		writeQuotaPerMinute: writeQuotaPerMinute,
+	}
+}
+
+// SetQuotas changes the per-minute quotas usage is compared against
+func (t *Tracker) SetQuotas(readQuotaPerMinute, writeQuotaPerMinute int) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.readQuotaPerMinute = readQuotaPerMinute
+	t.writeQuotaPerMinute = writeQuotaPerMinute
+}
+
+// Record counts one API call made at the given time
+func (t *Tracker) Record(isWrite bool, at time.Time) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.prune(at)
+	day := t.today(at)
+	if isWrite {
+		t.recentWrites = append(t.recentWrites, at)
+		day.Writes++
+		if len(t.recentWrites) > day.PeakWritesPerMinute {
+			day.PeakWritesPerMinute = len(t.recentWrites)
+		}
+	} else {
+		t.recentReads = append(t.recentReads, at)
+		day.Reads++
+		if len(t.recentReads) > day.PeakReadsPerMinute {
+			day.PeakReadsPerMinute = len(t.recentReads)
+		}
+	}
+}
+
+// prune drops calls older than a minute; callers must hold the mutex
+func (t *Tracker) prune(now time.Time) {
+	cutoff := now.Add(-time.Minute)
+	t.recentReads = dropBefore(t.recentReads, cutoff)
+	t.recentWrites = dropBefore(t.recentWrites, cutoff)
+}
+
+// dropBefore removes the leading timestamps that are before the cutoff
+func dropBefore(times []time.Time, cutoff time.Time) []time.Time {
+	i := 0
+	for i < len(times) && times[i].Before(cutoff) {
+		i++
+	}
+	return times[i:]
+}
+
+// today returns the usage entry for the day of the given time, starting a new day if needed; callers must hold the mutex
+func (t *Tracker) today(at time.Time) *DayUsage {
+	date := at.In(t.location).Format("2006-01-02")
+	if len(t.days) == 0 || t.days[len(t.days)-1].Date != date {
+		t.days = append(t.days, DayUsage{Date: date})
+		if len(t.days) > historyDays {
+			t.days = t.days[len(t.days)-historyDays:]
+		}
+	}
+	return &t.days[len(t.days)-1]
+}
+
+// Snapshot returns the current usage, headroom and quota warnings
+func (t *Tracker) Snapshot(now time.Time) Snapshot {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.prune(now)
+	snapshot := Snapshot{
+		ReadsLastMinute:        len(t.recentReads),
+		WritesLastMinute:       len(t.recentWrites),
+		ReadQuotaPerMinute:     t.readQuotaPerMinute,
+		WriteQuotaPerMinute:    t.writeQuotaPerMinute,
+		ReadHeadroomPerMinute:  t.readQuotaPerMinute - len(t.recentReads),
+		WriteHeadroomPerMinute: t.writeQuotaPerMinute - len(t.recentWrites),
+		Days:                   append([]DayUsage{}, t.days...),
+	}
+
+	var readPeaks, writePeaks []int
+	for _, day := range t.days {
+		readPeaks = append(readPeaks, day.PeakReadsPerMinute)
+		writePeaks = append(writePeaks, day.PeakWritesPerMinute)
+	}
+	if warning := projectWarning("read", readPeaks, t.readQuotaPerMinute); warning != "" {
+		snapshot.Warnings = append(snapshot.Warnings, warning)
+	}
+	if warning := projectWarning("write", writePeaks, t.writeQuotaPerMinute); warning != "" {
+		snapshot.Warnings = append(snapshot.Warnings, warning)
+	}
+	return snapshot
+}
+
+// projectWarning checks daily per-minute peaks against a quota and extrapolates their linear trend
+func projectWarning(kind string, peaks []int, quota int) string {
+	if len(peaks) == 0 || quota <= 0 {
+		return ""
+	}
+
+	latest := peaks[len(peaks)-1]
+	highest := 0
+	for _, peak := range peaks {
+		if peak > highest {
+			highest = peak
+		}
+	}
+	if float64(highest) >= warnRatio*float64(quota) {
+		return fmt.Sprintf("%s peak reached %d/min of the %d/min quota this week", kind, highest, quota)
+	}
+
+	if len(peaks) < 2 {
+		return ""
+	}
+	slope := float64(latest-peaks[0]) / float64(len(peaks)-1)
+	if slope <= 0 {
+		return ""
+	}
+	daysLeft := float64(quota-latest) / slope
+	if daysLeft <= projectionHorizonDays {
+		return fmt.Sprintf("%s peak is growing by %.1f/min per day and is projected to hit the %d/min quota in about %.0f days",
+			kind, slope, quota, daysLeft)
+	}
+	return ""
+}
+
+// Transport wraps an HTTP transport and records every Sheets API request in the tracker
+func (t *Tracker) Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &countingTransport{tracker: t, base: base}
+}
+
+// countingTransport counts requests to the Sheets API (GET = read, anything else = write)
+type countingTransport struct {
+	tracker *Tracker
+	base    http.RoundTripper
+}
+
+func (c *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if strings.HasPrefix(req.URL.Host, "sheets.googleapis.com") {
+		c.tracker.Record(req.Method != http.MethodGet, time.Now())
+	}
+	return c.base.RoundTrip(req)
+}
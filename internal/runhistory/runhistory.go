@@ -0,0 +1,121 @@
+package runhistory
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// maxRuns is how many completed runs are kept
+const maxRuns = 500
+
+// Run is a completed history backfill of a channel
+type Run struct {
+	ChannelID   string    `json:"channel_id"`
+	TriggerTS   string    `json:"trigger_ts"` // Timestamp of the mention (or join event) that started the run
+	Initial     bool      `json:"initial"`    // Started by the bot joining the channel rather than "Reset!"
+	StartedAt   time.Time `json:"started_at"`
+	CompletedAt time.Time `json:"completed_at"`
+	Rows        int       `json:"rows"`
+}
+
+// Store persists completed runs so redelivered or repeated triggers can be recognized
+type Store struct {
+	tmpDir string
+	mutex  sync.Mutex
+}
+
+// NewStore creates a new run history store
+func NewStore() *Store {
+	return &Store{
+		tmpDir: "/tmp/slack-bot-runs",
+	}
+}
+
+// getFilePath returns the file path of the run history
+func (s *Store) getFilePath() string {
+	return filepath.Join(s.tmpDir, "runs.json")
+}
+
+// load reads all runs, oldest first; callers must hold the mutex
+func (s *Store) load() ([]Run, error) {
+	data, err := os.ReadFile(s.getFilePath())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read run history: %v", err)
+	}
+
+	var runs []Run
+	if err := json.Unmarshal(data, &runs); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal run history: %v", err)
+	}
+	return runs, nil
+}
+
+// RecordCompleted appends a completed run, dropping the oldest runs beyond maxRuns
+func (s *Store) RecordCompleted(run Run) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	runs, err := s.load()
+	if err != nil {
+		return err
+	}
+	runs = append(runs, run)
+	if len(runs) > maxRuns {
+		runs = runs[len(runs)-maxRuns:]
+	}
+
+	if err := os.MkdirAll(s.tmpDir, 0755); err != nil {
+		return fmt.Errorf("failed to create tmp directory: %v", err)
+	}
+
+	data, err := json.MarshalIndent(runs, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal run history: %v", err)
+	}
+
+	if err := os.WriteFile(s.getFilePath(), data, 0644); err != nil {
+		return fmt.Errorf("failed to write run history: %v", err)
+	}
+	return nil
+}
+
+// Completed returns the completed run started by the given trigger, if any
+func (s *Store) Completed(channelID, triggerTS string) (*Run, bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	runs, err := s.load()
+	if err != nil {
+		return nil, false, err
+	}
+	for i := len(runs) - 1; i >= 0; i-- {
+		if runs[i].ChannelID == channelID && runs[i].TriggerTS == triggerTS {
+			return &runs[i], true, nil
+		}
+	}
+	return nil, false, nil
+}
+
+// Latest returns the most recently completed run of a channel, if any
+func (s *Store) Latest(channelID string) (*Run, bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	runs, err := s.load()
+	if err != nil {
+		return nil, false, err
+	}
+	for i := len(runs) - 1; i >= 0; i-- {
+		if runs[i].ChannelID == channelID {
+			return &runs[i], true, nil
+		}
+	}
+	return nil, false, nil
+}
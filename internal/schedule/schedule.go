@@ -0,0 +1,75 @@
+package schedule
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Schedule describes when messages in a channel may be recorded
+type Schedule struct {
+	// StartMinute and EndMinute are minutes since midnight; a window where End < Start wraps past midnight
+	StartMinute  int
+	EndMinute    int
+	HasWindow    bool
+	WeekdaysOnly bool
+}
+
+// Parse parses a schedule spec such as "09:00-21:00", "weekdays" or "09:00-21:00|weekdays"
+func Parse(spec string) (*Schedule, error) {
+	s := &Schedule{}
+
+	for _, token := range strings.Split(spec, "|") {
+		token = strings.TrimSpace(token)
+		switch {
+		case token == "":
+			continue
+		case strings.EqualFold(token, "weekdays"):
+			s.WeekdaysOnly = true
+		case strings.Contains(token, "-"):
+			bounds := strings.SplitN(token, "-", 2)
+			start, err := parseClock(bounds[0])
+			if err != nil {
+				return nil, err
+			}
+			end, err := parseClock(bounds[1])
+			if err != nil {
+				return nil, err
+			}
+			s.StartMinute = start
+			s.EndMinute = end
+			s.HasWindow = true
+		default:
+			return nil, fmt.Errorf("unknown schedule token %q", token)
+		}
+	}
+
+	return s, nil
+}
+
+// parseClock parses "HH:MM" into minutes since midnight
+func parseClock(value string) (int, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(value))
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q: %v", value, err)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// Allows reports whether a message posted at t may be recorded (t should already be in the schedule's timezone)
+func (s *Schedule) Allows(t time.Time) bool {
+	if s.WeekdaysOnly && (t.Weekday() == time.Saturday || t.Weekday() == time.Sunday) {
+		return false
+	}
+
+	if !s.HasWindow {
+		return true
+	}
+
+	minute := t.Hour()*60 + t.Minute()
+	if s.StartMinute <= s.EndMinute {
+		return minute >= s.StartMinute && minute < s.EndMinute
+	}
+	// Window wraps past midnight (e.g. 22:00-06:00)
+	return minute >= s.StartMinute || minute < s.EndMinute
+}
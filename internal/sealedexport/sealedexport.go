@@ -0,0 +1,102 @@
+package sealedexport
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+)
+
+// fileExtension marks archive files as OpenPGP messages of JSON Lines
+const fileExtension = ".jsonl.gpg"
+
+var (
+	recipients openpgp.EntityList
+	archiveDir string
+	mutex      sync.Mutex
+)
+
+// LoadPublicKey reads the ASCII-armored OpenPGP public key (e.g. from `gpg --export --armor`) that archives are
+// encrypted to, and the directory they are written under
+func LoadPublicKey(path, dir string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to read export public key file: %v", err)
+	}
+	defer file.Close()
+
+	keyRing, err := openpgp.ReadArmoredKeyRing(file)
+	if err != nil {
+		return fmt.Errorf("export public key is not a valid armored OpenPGP key: %v", err)
+	}
+	if len(keyRing) == 0 {
+		return fmt.Errorf("export public key file contains no key")
+	}
+	// Fail at startup rather than on the first message when the key cannot encrypt (e.g. it has no encryption subkey)
+	probe, err := openpgp.Encrypt(io.Discard, keyRing, nil, nil, nil)
+	if err != nil {
+		return fmt.Errorf("export public key cannot be used for encryption: %v", err)
+	}
+	probe.Close()
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	recipients = keyRing
+	archiveDir = dir
+	return nil
+}
+
+// Enabled reports whether a public key is loaded
+func Enabled() bool {
+	mutex.Lock()
+	defer mutex.Unlock()
+	return recipients != nil
+}
+
+// Write encrypts JSON lines to the public key and writes them as a new archive file of a channel, named after the
+// time of the write so files sort in the order they were written. The bot cannot read the archive back; only the
+// holder of the private key can.
+func Write(channelID string, lines [][]byte) (string, error) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	if recipients == nil {
+		return "", fmt.Errorf("no export public key is loaded")
+	}
+
+	var encrypted bytes.Buffer
+	plaintext, err := openpgp.Encrypt(&encrypted, recipients, nil, &openpgp.FileHints{FileName: channelID + ".jsonl"}, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to start encryption: %v", err)
+	}
+	for _, line := range lines {
+		if _, err := plaintext.Write(append(line, '\n')); err != nil {
+			return "", fmt.Errorf("failed to encrypt: %v", err)
+		}
+	}
+	if err := plaintext.Close(); err != nil {
+		return "", fmt.Errorf("failed to encrypt: %v", err)
+	}
+
+	dir := filepath.Join(archiveDir, channelID)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", fmt.Errorf("failed to create %s: %v", dir, err)
+	}
+	path := filepath.Join(dir, time.Now().UTC().Format("20060102T150405.000000000Z")+fileExtension)
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return "", fmt.Errorf("failed to create %s: %v", path, err)
+	}
+	if _, err := file.Write(encrypted.Bytes()); err != nil {
+		file.Close()
+		return "", fmt.Errorf("failed to write %s: %v", path, err)
+	}
+	if err := file.Close(); err != nil {
+		return "", fmt.Errorf("failed to write %s: %v", path, err)
+	}
+	return path, nil
+}
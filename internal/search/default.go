@@ -0,0 +1,35 @@
+package search
+
+import (
+	"log"
+	"sync"
+
+	"slack-to-google-sheets-bot/internal/store"
+)
+
+var (
+	defaultIndex     *Index
+	defaultIndexOnce sync.Once
+	normalizeDefault bool
+)
+
+// EnableNormalization turns on Japanese text normalization for the default index; call it before Default
+func EnableNormalization() {
+	normalizeDefault = true
+}
+
+// Default returns the process-wide index, built from the message store on first use
+func Default() *Index {
+	defaultIndexOnce.Do(func() {
+		defaultIndex = NewIndex(normalizeDefault)
+
+		records, _, err := store.Default().Query(store.Query{})
+		if err != nil {
+			log.Printf("Warning: Could not load message store into search index: %v", err)
+			return
+		}
+		defaultIndex.Add(records)
+		log.Printf("Search index built with %d messages", defaultIndex.Size())
+	})
+	return defaultIndex
+}
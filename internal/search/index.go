@@ -0,0 +1,232 @@
+package search
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+
+	"slack-to-google-sheets-bot/internal/sheets"
+	"slack-to-google-sheets-bot/internal/textnorm"
+)
+
+// Result is a ranked search hit
+type Result struct {
+	Record *sheets.MessageRecord
+	Score  float64
+}
+
+// Index is an in-memory inverted index over message text, ranked by BM25.
+// Latin text is indexed by word; Japanese (kanji/kana) runs are indexed as character bigrams
+// so that queries match without a morphological analyzer.
+// It keeps no files of its own: the durable copy is the message store, which Default rebuilds it from at startup.
+// A search engine such as bleve was left out to avoid its large dependency tree and a second on-disk format to
+// keep in step with the store; revisit that if the index outgrows memory.
+type Index struct {
+	mutex    sync.RWMutex
+	postings map[string]map[string]int // term -> document key -> term frequency
+	docs     map[string]*sheets.MessageRecord
+	docTerms map[string][]string // document key -> terms, used to remove stale postings on update
+	totalLen int
+
+	// normalize applies Japanese text normalization to documents and queries before tokenizing
+	normalize bool
+}
+
+// NewIndex creates an empty index; when normalize is true, NFKC/width normalization is applied before tokenizing
+func NewIndex(normalize bool) *Index {
+	return &Index{
+		normalize: normalize,
+		postings:  make(map[string]map[string]int),
+		docs:      make(map[string]*sheets.MessageRecord),
+		docTerms:  make(map[string][]string),
+	}
+}
+
+// documentKey returns the unique key of a record in the index
+func documentKey(record *sheets.MessageRecord) string {
+	return record.Channel + "/" + record.MessageTS
+}
+
+// Add indexes records, replacing earlier versions of the same message (edits); deleted messages are removed
+func (idx *Index) Add(records []*sheets.MessageRecord) {
+	idx.mutex.Lock()
+	defer idx.mutex.Unlock()
+
+	for _, record := range records {
+		key := documentKey(record)
+		idx.remove(key)
+		if record.Status != "" {
+			continue
+		}
+
+		stored := *record
+		terms := idx.tokenize(record.Text)
+		idx.docs[key] = &stored
+		idx.docTerms[key] = terms
+		idx.totalLen += len(terms)
+
+		for _, term := range terms {
+			if idx.postings[term] == nil {
+				idx.postings[term] = make(map[string]int)
+			}
+			idx.postings[term][key]++
+		}
+	}
+}
+
+// remove drops a document from the index; callers must hold the write lock
+func (idx *Index) remove(key string) {
+	terms, exists := idx.docTerms[key]
+	if !exists {
+		return
+	}
+
+	for _, term := range terms {
+		if posting := idx.postings[term]; posting != nil {
+			delete(posting, key)
+			if len(posting) == 0 {
+				delete(idx.postings, term)
+			}
+		}
+	}
+	idx.totalLen -= len(terms)
+	delete(idx.docTerms, key)
+	delete(idx.docs, key)
+}
+
+// Size returns the number of indexed documents
+func (idx *Index) Size() int {
+	idx.mutex.RLock()
+	defer idx.mutex.RUnlock()
+	return len(idx.docs)
+}
+
+// Search returns documents containing every query term ranked by BM25, optionally limited to one channel
+func (idx *Index) Search(query, channelID string, limit int) []Result {
+	terms := uniqueTerms(idx.tokenize(query))
+	if len(terms) == 0 {
+		return nil
+	}
+
+	idx.mutex.RLock()
+	defer idx.mutex.RUnlock()
+
+	// Start from the rarest term to keep the candidate set small
+	sort.Slice(terms, func(i, j int) bool {
+		return len(idx.postings[terms[i]]) < len(idx.postings[terms[j]])
+	})
+
+	candidates := make(map[string]bool)
+	for key := range idx.postings[terms[0]] {
+		if channelID == "" || idx.docs[key].Channel == channelID {
+			candidates[key] = true
+		}
+	}
+	for _, term := range terms[1:] {
+		posting := idx.postings[term]
+		for key := range candidates {
+			if posting[key] == 0 {
+				delete(candidates, key)
+			}
+		}
+	}
+
+	const k1, b = 1.2, 0.75
+	docCount := float64(len(idx.docs))
+	avgLen := float64(idx.totalLen) / math.Max(docCount, 1)
+
+	var results []Result
+	for key := range candidates {
+		docLen := float64(len(idx.docTerms[key]))
+		score := 0.0
+		for _, term := range terms {
+			df := float64(len(idx.postings[term]))
+			tf := float64(idx.postings[term][key])
+			idf := math.Log(1 + (docCount-df+0.5)/(df+0.5))
+			score += idf * tf * (k1 + 1) / (tf + k1*(1-b+b*docLen/avgLen))
+		}
+		record := *idx.docs[key]
+		results = append(results, Result{Record: &record, Score: score})
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].Record.Timestamp.After(results[j].Record.Timestamp)
+	})
+
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+	return results
+}
+
+// tokenize tokenizes text, normalizing it first when enabled
+func (idx *Index) tokenize(text string) []string {
+	if idx.normalize {
+		text = textnorm.Normalize(text)
+	}
+	return Tokenize(text)
+}
+
+// Tokenize splits text into index terms: lowercase words for Latin text and bigrams for Japanese runs
+func Tokenize(text string) []string {
+	var terms []string
+	var word []rune
+	var cjk []rune
+
+	flushWord := func() {
+		if len(word) > 0 {
+			terms = append(terms, string(word))
+			word = word[:0]
+		}
+	}
+	flushCJK := func() {
+		if len(cjk) == 1 {
+			terms = append(terms, string(cjk))
+		}
+		for i := 0; i+1 < len(cjk); i++ {
+			terms = append(terms, string(cjk[i:i+2]))
+		}
+		cjk = cjk[:0]
+	}
+
+	for _, r := range strings.ToLower(text) {
+		switch {
+		case isCJK(r):
+			flushWord()
+			cjk = append(cjk, r)
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			flushCJK()
+			word = append(word, r)
+		default:
+			flushWord()
+			flushCJK()
+		}
+	}
+	flushWord()
+	flushCJK()
+
+	return terms
+}
+
+// isCJK reports whether r is a kanji, hiragana or katakana character
+func isCJK(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana) || r == 'ー'
+}
+
+// uniqueTerms removes duplicate terms while preserving order
+func uniqueTerms(terms []string) []string {
+	seen := make(map[string]bool)
+	var unique []string
+	for _, term := range terms {
+		if !seen[term] {
+			seen[term] = true
+			unique = append(unique, term)
+		}
+	}
+	return unique
+}
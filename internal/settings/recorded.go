@@ -0,0 +1,114 @@
+package settings
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// Change is a recording setting whose value differs from the one the channel's rows were last written with
+type Change struct {
+	Name     string
+	Previous string
+	Current  string
+}
+
+// String formats the change as "name previous→current"
+func (c Change) String() string {
+	return fmt.Sprintf("%s %s→%s", c.Name, c.Previous, c.Current)
+}
+
+// RecordedSettings remembers, per channel, the settings its rows were last written with, so a change (e.g. of the
+// timezone) can be pointed out where the formatting of the sheet changes
+type RecordedSettings struct {
+	tmpDir string
+	mutex  sync.Mutex
+	values map[string]map[string]string // channel ID -> setting name -> value
+	loaded bool
+}
+
+var defaultRecorded = NewRecordedSettings()
+
+// NewRecordedSettings creates a tracker backed by a file in the local settings directory
+func NewRecordedSettings() *RecordedSettings {
+	return &RecordedSettings{
+		tmpDir: "/tmp/slack-bot-settings",
+		values: make(map[string]map[string]string),
+	}
+}
+
+// Recorded returns the process-wide tracker of recorded settings
+func Recorded() *RecordedSettings {
+	return defaultRecorded
+}
+
+// getFilePath returns the file path of the recorded settings
+func (r *RecordedSettings) getFilePath() string {
+	return filepath.Join(r.tmpDir, "recorded.json")
+}
+
+// load reads the recorded settings once; callers must hold the lock
+func (r *RecordedSettings) load() {
+	if r.loaded {
+		return
+	}
+	r.loaded = true
+
+	data, err := os.ReadFile(r.getFilePath())
+	if os.IsNotExist(err) {
+		return
+	}
+	if err != nil {
+		log.Printf("Warning: Could not read recorded settings: %v", err)
+		return
+	}
+	if err := json.Unmarshal(data, &r.values); err != nil {
+		log.Printf("Warning: Could not parse recorded settings: %v", err)
+	}
+}
+
+// save writes the recorded settings; callers must hold the lock
+func (r *RecordedSettings) save() error {
+	if err := os.MkdirAll(r.tmpDir, 0755); err != nil {
+		return fmt.Errorf("failed to create tmp directory: %v", err)
+	}
+
+	data, err := json.MarshalIndent(r.values, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal recorded settings: %v", err)
+	}
+
+	if err := os.WriteFile(r.getFilePath(), data, 0644); err != nil {
+		return fmt.Errorf("failed to write recorded settings: %v", err)
+	}
+	return nil
+}
+
+// Changes stores current as the settings of a channel's rows and returns how they differ from the stored ones,
+// sorted by name. A channel seen for the first time has no changes.
+func (r *RecordedSettings) Changes(channelID string, current map[string]string) ([]Change, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.load()
+
+	previous, exists := r.values[channelID]
+	var changes []Change
+	if exists {
+		for name, value := range current {
+			if before, known := previous[name]; known && before != value {
+				changes = append(changes, Change{Name: name, Previous: before, Current: value})
+			}
+		}
+		sort.Slice(changes, func(i, j int) bool { return changes[i].Name < changes[j].Name })
+	}
+
+	if exists && len(changes) == 0 && len(previous) == len(current) {
+		return nil, nil
+	}
+	r.values[channelID] = current
+	return changes, r.save()
+}
@@ -0,0 +1,243 @@
+package settings
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"slack-to-google-sheets-bot/internal/schedule"
+)
+
+// Keys of per-channel settings
+const (
+	KeyTimezone              = "timezone"
+	KeyRecordingSchedule     = "recording_schedule"
+	KeyAlertThreshold        = "alert_threshold"
+	KeyNotificationTarget    = "notification_target"
+	KeyNotificationVerbosity = "notification_verbosity"
+	KeyRecordMode            = "record_mode"
+)
+
+// Keys lists the supported setting keys in display order
+var Keys = []string{
+	KeyTimezone,
+	KeyRecordingSchedule,
+	KeyAlertThreshold,
+	KeyNotificationTarget,
+	KeyNotificationVerbosity,
+	KeyRecordMode,
+}
+
+// Validate checks that a value is acceptable for a setting key
+func Validate(key, value string) error {
+	switch key {
+	case KeyTimezone:
+		if _, err := time.LoadLocation(value); err != nil {
+			return fmt.Errorf("unknown timezone %q", value)
+		}
+	case KeyRecordingSchedule:
+		if _, err := schedule.Parse(value); err != nil {
+			return err
+		}
+	case KeyAlertThreshold:
+		threshold, err := strconv.Atoi(value)
+		if err != nil || threshold < 0 {
+			return fmt.Errorf("alert threshold must be a non-negative integer, got %q", value)
+		}
+	case KeyNotificationTarget:
+		if !oneOf(value, "channel", "ops", "dm") {
+			return fmt.Errorf("notification target must be channel, ops or dm, got %q", value)
+		}
+	case KeyNotificationVerbosity:
+		if !oneOf(value, "full", "brief", "errors", "none") {
+			return fmt.Errorf("notification verbosity must be full, brief, errors or none, got %q", value)
+		}
+	case KeyRecordMode:
+		if !oneOf(value, "full", "metadata", "encrypted") {
+			return fmt.Errorf("record mode must be full, metadata or encrypted, got %q", value)
+		}
+	default:
+		return fmt.Errorf("unknown setting %q (supported: %s)", key, strings.Join(Keys, ", "))
+	}
+	return nil
+}
+
+// IsKnownKey reports whether key is a supported setting key
+func IsKnownKey(key string) bool {
+	return oneOf(key, Keys...)
+}
+
+// oneOf reports whether value equals one of the options
+func oneOf(value string, options ...string) bool {
+	for _, option := range options {
+		if value == option {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseRows parses settings sheet rows of the form [channel ID, key, value], skipping blank rows
+// and returning one error per invalid row (row numbers are 1-based sheet rows, data starting at row 2)
+func ParseRows(rows [][]interface{}) (map[string]map[string]string, []error) {
+	values := make(map[string]map[string]string)
+	var errs []error
+
+	for i, row := range rows {
+		cells := make([]string, 3)
+		for j := 0; j < len(cells) && j < len(row); j++ {
+			cells[j] = strings.TrimSpace(fmt.Sprintf("%v", row[j]))
+		}
+		channelID, key, value := cells[0], strings.ToLower(cells[1]), cells[2]
+		if channelID == "" && key == "" && value == "" {
+			continue
+		}
+
+		if channelID == "" || key == "" {
+			errs = append(errs, fmt.Errorf("row %d: channel ID and setting key are required", i+2))
+			continue
+		}
+		if err := Validate(key, value); err != nil {
+			errs = append(errs, fmt.Errorf("row %d: %v", i+2, err))
+			continue
+		}
+
+		if values[channelID] == nil {
+			values[channelID] = make(map[string]string)
+		}
+		values[channelID][key] = value
+	}
+
+	return values, errs
+}
+
+// Registry holds per-channel settings that override the environment configuration.
+// Values set through commands (stored locally) take precedence over the settings sheet.
+type Registry struct {
+	tmpDir      string
+	mutex       sync.Mutex
+	sheet       map[string]map[string]string // channel ID -> key -> value, from the settings sheet
+	local       map[string]map[string]string // channel ID -> key -> value, set via commands
+	localLoaded bool
+}
+
+var defaultRegistry = NewRegistry()
+
+// NewRegistry creates a settings registry backed by the local settings file
+func NewRegistry() *Registry {
+	return &Registry{
+		tmpDir: "/tmp/slack-bot-settings",
+		sheet:  make(map[string]map[string]string),
+		local:  make(map[string]map[string]string),
+	}
+}
+
+// Default returns the process-wide settings registry
+func Default() *Registry {
+	return defaultRegistry
+}
+
+// ReplaceSheetSettings swaps in the settings most recently read from the settings sheet
+func (r *Registry) ReplaceSheetSettings(values map[string]map[string]string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.sheet = values
+}
+
+// getLocalFilePath returns the file path of the locally stored settings
+func (r *Registry) getLocalFilePath() string {
+	return filepath.Join(r.tmpDir, "channels.json")
+}
+
+// loadLocal reads the locally stored settings once; callers must hold the write lock
+func (r *Registry) loadLocal() {
+	if r.localLoaded {
+		return
+	}
+	r.localLoaded = true
+
+	data, err := os.ReadFile(r.getLocalFilePath())
+	if os.IsNotExist(err) {
+		return
+	}
+	if err != nil {
+		log.Printf("Warning: Could not read local settings: %v", err)
+		return
+	}
+
+	if err := json.Unmarshal(data, &r.local); err != nil {
+		log.Printf("Warning: Could not parse local settings: %v", err)
+	}
+}
+
+// saveLocal writes the locally stored settings; callers must hold the write lock
+func (r *Registry) saveLocal() error {
+	if err := os.MkdirAll(r.tmpDir, 0755); err != nil {
+		return fmt.Errorf("failed to create tmp directory: %v", err)
+	}
+
+	data, err := json.MarshalIndent(r.local, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal local settings: %v", err)
+	}
+
+	if err := os.WriteFile(r.getLocalFilePath(), data, 0644); err != nil {
+		return fmt.Errorf("failed to write local settings: %v", err)
+	}
+	return nil
+}
+
+// Set validates and stores a channel setting locally, returning the previous local value
+func (r *Registry) Set(channelID, key, value string) (string, error) {
+	if err := Validate(key, value); err != nil {
+		return "", err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.loadLocal()
+
+	previous := r.local[channelID][key]
+	if r.local[channelID] == nil {
+		r.local[channelID] = make(map[string]string)
+	}
+	r.local[channelID][key] = value
+	return previous, r.saveLocal()
+}
+
+// Unset removes a locally stored channel setting, returning the removed value
+func (r *Registry) Unset(channelID, key string) (string, error) {
+	if !IsKnownKey(key) {
+		return "", fmt.Errorf("unknown setting %q (supported: %s)", key, strings.Join(Keys, ", "))
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.loadLocal()
+
+	previous := r.local[channelID][key]
+	delete(r.local[channelID], key)
+	if len(r.local[channelID]) == 0 {
+		delete(r.local, channelID)
+	}
+	return previous, r.saveLocal()
+}
+
+// ChannelSetting returns a channel's value for a setting key, if one is set
+func (r *Registry) ChannelSetting(channelID, key string) (string, bool) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.loadLocal()
+
+	if value, exists := r.local[channelID][key]; exists {
+		return value, true
+	}
+	value, exists := r.sheet[channelID][key]
+	return value, exists
+}
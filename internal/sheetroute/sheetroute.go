@@ -0,0 +1,129 @@
+package sheetroute
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Continuation is a spreadsheet created after the previous one approached its limits
+type Continuation struct {
+	SpreadsheetID string    `json:"spreadsheet_id"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// routes is the persisted routing state
+type routes struct {
+	Continuations []Continuation    `json:"continuations"`
+	Channels      map[string]string `json:"channels"` // Channel ID -> spreadsheet ID
+}
+
+// Store persists which spreadsheet each channel is recorded in
+type Store struct {
+	tmpDir string
+	mutex  sync.Mutex
+}
+
+// NewStore creates a new routing store
+func NewStore() *Store {
+	return &Store{
+		tmpDir: "/tmp/slack-bot-spreadsheets",
+	}
+}
+
+// getFilePath returns the file path of the routing state
+func (s *Store) getFilePath() string {
+	return filepath.Join(s.tmpDir, "routes.json")
+}
+
+// load reads the routing state; callers must hold the mutex
+func (s *Store) load() (*routes, error) {
+	state := &routes{Channels: make(map[string]string)}
+
+	data, err := os.ReadFile(s.getFilePath())
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read spreadsheet routes: %v", err)
+	}
+
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal spreadsheet routes: %v", err)
+	}
+	if state.Channels == nil {
+		state.Channels = make(map[string]string)
+	}
+	return state, nil
+}
+
+// save writes the routing state; callers must hold the mutex
+func (s *Store) save(state *routes) error {
+	if err := os.MkdirAll(s.tmpDir, 0755); err != nil {
+		return fmt.Errorf("failed to create tmp directory: %v", err)
+	}
+
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal spreadsheet routes: %v", err)
+	}
+
+	if err := os.WriteFile(s.getFilePath(), data, 0644); err != nil {
+		return fmt.Errorf("failed to write spreadsheet routes: %v", err)
+	}
+	return nil
+}
+
+// SpreadsheetFor returns the spreadsheet a channel was assigned to, if any
+func (s *Store) SpreadsheetFor(channelID string) (string, bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	state, err := s.load()
+	if err != nil {
+		return "", false, err
+	}
+	spreadsheetID, exists := state.Channels[channelID]
+	return spreadsheetID, exists, nil
+}
+
+// Assign records the spreadsheet a channel is recorded in
+func (s *Store) Assign(channelID, spreadsheetID string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	state, err := s.load()
+	if err != nil {
+		return err
+	}
+	state.Channels[channelID] = spreadsheetID
+	return s.save(state)
+}
+
+// Continuations returns the continuation spreadsheets, oldest first
+func (s *Store) Continuations() ([]Continuation, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	state, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	return state.Continuations, nil
+}
+
+// AddContinuation records a new continuation spreadsheet; new channels are routed to the latest one
+func (s *Store) AddContinuation(spreadsheetID string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	state, err := s.load()
+	if err != nil {
+		return err
+	}
+	state.Continuations = append(state.Continuations, Continuation{SpreadsheetID: spreadsheetID, CreatedAt: time.Now()})
+	return s.save(state)
+}
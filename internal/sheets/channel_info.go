@@ -0,0 +1,64 @@
+package sheets
+
+import (
+	"fmt"
+
+	"google.golang.org/api/sheets/v4"
+)
+
+// channelInfoHeaders is the header row of the tab keeping the current topic and purpose of each channel
+var channelInfoHeaders = []interface{}{"チャンネルID", "チャンネル名", "トピック", "説明", "最終変更日時", "最終変更者"}
+
+// ChannelInfoField is a column of the channel info tab that changes through channel_topic / channel_purpose messages
+type ChannelInfoField int
+
+// Changeable columns of the channel info tab (0-based)
+const (
+	ChannelInfoTopic   ChannelInfoField = 2
+	ChannelInfoPurpose ChannelInfoField = 3
+)
+
+// UpdateChannelInfoRow sets the topic or purpose of a channel in the channel info tab, adding the channel's row if it
+// has none; the other cells of the row are kept
+func (c *Client) UpdateChannelInfoRow(spreadsheetID, sheetName, channelID, channelName string, field ChannelInfoField, value, changedAt, changedBy string) error {
+	if _, err := c.ensureAuxiliarySheet(spreadsheetID, sheetName, channelInfoHeaders); err != nil {
+		return err
+	}
+
+	lastColumn := columnLetter(len(channelInfoHeaders))
+	resp, err := c.service.Spreadsheets.Values.Get(spreadsheetID, fmt.Sprintf("%s!A:%s", sheetName, lastColumn)).Do()
+	if err != nil {
+		return fmt.Errorf("unable to read channel info sheet: %v", err)
+	}
+
+	row := make([]interface{}, len(channelInfoHeaders))
+	for i := range row {
+		row[i] = ""
+	}
+	targetRow := -1
+	for i, existing := range resp.Values {
+		if i > 0 && len(existing) > 0 && fmt.Sprintf("%v", existing[0]) == channelID {
+			targetRow = i + 1
+			copy(row, existing)
+			break
+		}
+	}
+	row[0], row[1], row[field], row[4], row[5] = channelID, channelName, value, changedAt, changedBy
+
+	return retryWithBackoff(func() error {
+		if targetRow < 0 {
+			_, err := c.service.Spreadsheets.Values.Append(
+				spreadsheetID,
+				fmt.Sprintf("%s!A:%s", sheetName, lastColumn),
+				&sheets.ValueRange{Values: [][]interface{}{row}},
+			).ValueInputOption("RAW").Do()
+			return err
+		}
+		_, err := c.service.Spreadsheets.Values.Update(
+			spreadsheetID,
+			fmt.Sprintf("%s!A%d:%s%d", sheetName, targetRow, lastColumn, targetRow),
+			&sheets.ValueRange{Values: [][]interface{}{row}},
+		).ValueInputOption("RAW").Do()
+		return err
+	}, fmt.Sprintf("update channel info of %s", channelID))
+}
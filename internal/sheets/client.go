@@ -4,21 +4,27 @@ import (
 	"context"
 	"fmt"
 	"log"
-	"os"
+	"net/http"
+	"regexp"
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"google.golang.org/api/drive/v3"
 	"google.golang.org/api/option"
 	"google.golang.org/api/sheets/v4"
+
+	"slack-to-google-sheets-bot/internal/chaos"
+	"slack-to-google-sheets-bot/internal/quota"
+	"slack-to-google-sheets-bot/internal/textnorm"
 )
 
-// Expected headers for Google Sheets
-var expectedHeaders = []interface{}{
+// Base headers for Google Sheets (columns A-G); optional columns are appended after these
+var baseHeaders = []interface{}{
 	"No.",
-	"投稿日時（JST）",
+	"投稿日時",
 	"発信者（ハンドル名）",
 	"発信者（本名）",
 	"発言内容",
@@ -26,42 +32,347 @@ var expectedHeaders = []interface{}{
 	"投稿ID",
 }
 
+// Column is an optional column appended after the base columns
+type Column struct {
+	Header string
+	Value  func(record *MessageRecord) interface{}
+	// Backfill derives the value from the other cells of a row (keyed by header) when the column is added to a tab
+	// that already has rows; columns without it are left empty there
+	Backfill func(cells map[string]string) interface{}
+}
+
+var (
+	// ColumnUserTitle records the poster's job title from their Slack profile
+	ColumnUserTitle = Column{
+		Header: "発信者（役職）",
+		Value:  func(record *MessageRecord) interface{} { return record.UserTitle },
+	}
+	// ColumnUserTeam records the poster's team from their Slack profile
+	ColumnUserTeam = Column{
+		Header: "発信者（チーム）",
+		Value:  func(record *MessageRecord) interface{} { return record.UserTeam },
+	}
+	// ColumnEmployeeID records the poster's internal employee ID resolved from an external directory
+	ColumnEmployeeID = Column{
+		Header: "社員ID",
+		Value:  func(record *MessageRecord) interface{} { return record.EmployeeID },
+	}
+	// ColumnAppSource records which app or integration posted the message
+	ColumnAppSource = Column{
+		Header: "投稿元アプリ",
+		Value:  func(record *MessageRecord) interface{} { return record.AppSource },
+	}
+	// ColumnCharCount records the number of characters of the message text (excluding whitespace)
+	ColumnCharCount = Column{
+		Header:   "文字数",
+		Value:    func(record *MessageRecord) interface{} { return textnorm.CharCount(record.Text) },
+		Backfill: func(cells map[string]string) interface{} { return textnorm.CharCount(cells[textHeader]) },
+	}
+	// ColumnWordCount records the approximate, Japanese-aware word count of the message text
+	ColumnWordCount = Column{
+		Header:   "単語数",
+		Value:    func(record *MessageRecord) interface{} { return textnorm.WordCount(record.Text) },
+		Backfill: func(cells map[string]string) interface{} { return textnorm.WordCount(cells[textHeader]) },
+	}
+	// ColumnReactions records the emoji reactions of the message with their counts
+	ColumnReactions = Column{
+		Header: "リアクション",
+		Value:  func(record *MessageRecord) interface{} { return record.Reactions },
+	}
+	// ColumnFileName records the names of the files shared with the message
+	ColumnFileName = Column{
+		Header: "ファイル名",
+		Value: func(record *MessageRecord) interface{} {
+			return joinFiles(record.Files, func(file FileRecord) string { return file.Name })
+		},
+	}
+	// ColumnFileType records the types of the files shared with the message
+	ColumnFileType = Column{
+		Header: "ファイル形式",
+		Value: func(record *MessageRecord) interface{} {
+			return joinFiles(record.Files, func(file FileRecord) string { return file.Type })
+		},
+	}
+	// ColumnFileSize records the sizes in bytes of the files shared with the message
+	ColumnFileSize = Column{
+		Header: "ファイルサイズ（バイト）",
+		Value: func(record *MessageRecord) interface{} {
+			if len(record.Files) == 1 {
+				return record.Files[0].Size // Keep a single size numeric so it can be summed
+			}
+			return joinFiles(record.Files, func(file FileRecord) string { return strconv.Itoa(file.Size) })
+		},
+	}
+	// ColumnFilePermalink records the Slack permalinks of the files shared with the message
+	ColumnFilePermalink = Column{
+		Header: "ファイルリンク",
+		Value: func(record *MessageRecord) interface{} {
+			return joinFiles(record.Files, func(file FileRecord) string { return file.Permalink })
+		},
+	}
+	// ColumnPinned records whether the message is pinned to the channel
+	ColumnPinned = Column{
+		Header: "ピン留め",
+		Value:  func(record *MessageRecord) interface{} { return record.Pinned },
+	}
+	// ColumnTraceID records the trace ID of the event or backfill run that wrote the row
+	ColumnTraceID = Column{
+		Header: "トレースID",
+		Value:  func(record *MessageRecord) interface{} { return record.TraceID },
+	}
+	// ColumnEditedBy records who last edited the message
+	ColumnEditedBy = Column{
+		Header: "編集者",
+		Value:  func(record *MessageRecord) interface{} { return record.EditedBy },
+	}
+	// ColumnReplyCount records the number of thread replies of a thread parent
+	ColumnReplyCount = Column{
+		Header: "返信数",
+		Value:  func(record *MessageRecord) interface{} { return record.ReplyCount },
+	}
+	// ColumnReactionCount records the total number of reactions of the message
+	ColumnReactionCount = Column{
+		Header: "リアクション数",
+		Value:  func(record *MessageRecord) interface{} { return record.ReactionCount },
+	}
+	// ColumnPermalink records the Slack link of the message; rows are written RAW, and Sheets shows a URL as a link
+	ColumnPermalink = Column{
+		Header: "メッセージリンク",
+		Value:  func(record *MessageRecord) interface{} { return record.Permalink },
+	}
+	// ColumnStatus records whether the message was deleted ("deleted <time>"), and is empty while it exists
+	ColumnStatus = Column{
+		Header: "状態",
+		Value:  func(record *MessageRecord) interface{} { return record.Status },
+	}
+	// ColumnTeamID records the ID of the workspace the message was posted from, which tells the workspaces of an
+	// Enterprise Grid organization apart in shared channels
+	ColumnTeamID = Column{
+		Header: "ワークスペースID",
+		Value:  func(record *MessageRecord) interface{} { return record.TeamID },
+	}
+	// ColumnEditHistory records the texts of the message before each of its edits, oldest first
+	ColumnEditHistory = Column{
+		Header: "編集履歴",
+		Value:  func(record *MessageRecord) interface{} { return record.EditHistory },
+	}
+)
+
+// sheetIDCache remembers tab gids by spreadsheet and sheet name so row links can be built without extra API calls
+var (
+	sheetIDCache      = make(map[string]int64)
+	sheetIDCacheMutex sync.Mutex
+)
+
 type Client struct {
 	service      *sheets.Service
 	driveService *drive.Service
+	extraColumns []Column
+	// iso8601Timestamps writes the posted time column as RFC 3339 (see UseISO8601Timestamps)
+	iso8601Timestamps bool
+	// linkFormulas writes message texts with one link as HYPERLINK formulas (see UseLinkFormulas)
+	linkFormulas bool
+}
+
+// AddColumns enables optional columns, appended in the given order after the base columns
+func (c *Client) AddColumns(columns ...Column) {
+	c.extraColumns = append(c.extraColumns, columns...)
+}
+
+// headers returns the full header row including optional columns
+func (c *Client) headers() []interface{} {
+	headers := append([]interface{}{}, baseHeaders...)
+	if c.iso8601Timestamps {
+		headers[1] = postedAtISO8601Header
+	}
+	for _, column := range c.extraColumns {
+		headers = append(headers, column.Header)
+	}
+	return headers
+}
+
+// lastColumn returns the column letter of the last column in use
+func (c *Client) lastColumn() string {
+	return columnLetter(len(baseHeaders) + len(c.extraColumns))
+}
+
+// columnNumber returns the 1-based column number of an enabled optional column, or -1 if it is not enabled
+func (c *Client) columnNumber(column Column) int {
+	for i, extra := range c.extraColumns {
+		if extra.Header == column.Header {
+			return len(baseHeaders) + i + 1
+		}
+	}
+	return -1
+}
+
+// columnRange returns the A1 notation covering all columns of a sheet
+func (c *Client) columnRange(sheetName string) string {
+	return fmt.Sprintf("%s!A:%s", sheetName, c.lastColumn())
+}
+
+// headerRange returns the A1 notation of a sheet's header row
+func (c *Client) headerRange(sheetName string) string {
+	return fmt.Sprintf("%s!A1:%s1", sheetName, c.lastColumn())
+}
+
+// buildRow builds the cell values for a record, including optional columns
+func (c *Client) buildRow(rowNumber int, record *MessageRecord, threadParentNo string) []interface{} {
+	row := []interface{}{
+		rowNumber,
+		c.formatPostedAt(record.Timestamp),
+		record.UserHandle,
+		record.UserRealName,
+		record.Text,
+		threadParentNo,
+		record.MessageTS,
+	}
+	for _, column := range c.extraColumns {
+		row = append(row, column.Value(record))
+	}
+	return c.userEnteredRow(row)
+}
+
+// RowRange returns the A1 range covering all columns of the given rows (without sheet name)
+func (c *Client) RowRange(firstRow, lastRow int) string {
+	return fmt.Sprintf("A%d:%s%d", firstRow, c.lastColumn(), lastRow)
+}
+
+// rowFromA1Range extracts the first row number from an A1 range such as "Sheet!A5:G5" (0 if it cannot be parsed)
+func rowFromA1Range(a1Range string) int {
+	if idx := strings.LastIndex(a1Range, "!"); idx >= 0 {
+		a1Range = a1Range[idx+1:]
+	}
+	start := strings.SplitN(a1Range, ":", 2)[0]
+	row, err := strconv.Atoi(strings.TrimLeft(start, "ABCDEFGHIJKLMNOPQRSTUVWXYZ"))
+	if err != nil {
+		return 0
+	}
+	return row
+}
+
+// rememberSheetID stores a tab gid in the cache
+func rememberSheetID(spreadsheetID, sheetName string, sheetID int64) {
+	sheetIDCacheMutex.Lock()
+	defer sheetIDCacheMutex.Unlock()
+	sheetIDCache[spreadsheetID+"/"+sheetName] = sheetID
+}
+
+// rememberSheetIDs stores the gids of all tabs returned by a spreadsheet lookup
+func rememberSheetIDs(spreadsheetID string, sheetList []*sheets.Sheet) {
+	for _, sheet := range sheetList {
+		if sheet.Properties != nil {
+			rememberSheetID(spreadsheetID, sheet.Properties.Title, sheet.Properties.SheetId)
+		}
+	}
+}
+
+// forgetSheetID removes a tab gid from the cache, e.g. after the tab was renamed
+func forgetSheetID(spreadsheetID, sheetName string) {
+	sheetIDCacheMutex.Lock()
+	defer sheetIDCacheMutex.Unlock()
+	delete(sheetIDCache, spreadsheetID+"/"+sheetName)
+}
+
+// cachedSheetID returns a previously seen tab gid
+func cachedSheetID(spreadsheetID, sheetName string) (int64, bool) {
+	sheetIDCacheMutex.Lock()
+	defer sheetIDCacheMutex.Unlock()
+	sheetID, exists := sheetIDCache[spreadsheetID+"/"+sheetName]
+	return sheetID, exists
+}
+
+// rememberAddedSheet stores the gid of a tab created through an AddSheet batch update
+func rememberAddedSheet(spreadsheetID string, response *sheets.BatchUpdateSpreadsheetResponse) {
+	if response == nil {
+		return
+	}
+	for _, reply := range response.Replies {
+		if reply != nil && reply.AddSheet != nil && reply.AddSheet.Properties != nil {
+			rememberSheetID(spreadsheetID, reply.AddSheet.Properties.Title, reply.AddSheet.Properties.SheetId)
+		}
+	}
+}
+
+// columnLetter converts a 1-based column index into its A1 letter (1 -> A, 27 -> AA)
+func columnLetter(index int) string {
+	letter := ""
+	for index > 0 {
+		index--
+		letter = string(rune('A'+index%26)) + letter
+		index /= 26
+	}
+	return letter
+}
+
+// apiEndpoint, when set, points the Sheets and Drive services at an unauthenticated fake server for load tests
+var apiEndpoint string
+
+// SetAPIEndpoint overrides the Google API endpoint; Sheets requests go to <endpoint>/v4/... and Drive requests to <endpoint>/drive/v3/...
+func SetAPIEndpoint(endpoint string) {
+	apiEndpoint = strings.TrimSuffix(endpoint, "/")
+}
+
+// newFakeBackendClient creates a client that talks to the fake backend at apiEndpoint without credentials
+func newFakeBackendClient(ctx context.Context) (*Client, error) {
+	log.Printf("Using fake Google API endpoint: %s", apiEndpoint)
+
+	// Injected failures (CHAOS_*) also apply to the fakes, so retries can be exercised under load
+	options := []option.ClientOption{option.WithoutAuthentication()}
+	if transport := chaos.Transport(nil); transport != nil {
+		options = append(options, option.WithHTTPClient(&http.Client{Transport: transport}))
+	}
+
+	service, err := sheets.NewService(ctx, append(options, option.WithEndpoint(apiEndpoint+"/"))...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create sheets service: %v", err)
+	}
+
+	driveService, err := drive.NewService(ctx, append(options, option.WithEndpoint(apiEndpoint+"/drive/v3/"))...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create drive service: %v", err)
+	}
+
+	return &Client{
+		service:      service,
+		driveService: driveService,
+	}, nil
 }
 
 func NewClient(credentialsJSON string) (*Client, error) {
 	ctx := context.Background()
 
-	var credentialsData []byte
-	var err error
+	if apiEndpoint != "" {
+		return newFakeBackendClient(ctx)
+	}
 
-	// Check if credentialsJSON is a file path or JSON content
-	// File path criteria: shorter than 512 chars, ends with .json, and doesn't start with {
-	isFilePath := len(credentialsJSON) < 512 &&
-		strings.HasSuffix(credentialsJSON, ".json") &&
-		!strings.HasPrefix(strings.TrimSpace(credentialsJSON), "{")
+	transport, err := credentialsTransport(credentialsJSON)
+	if err != nil {
+		return nil, err
+	}
 
-	if isFilePath {
-		// It's likely a file path, try to read the file
-		credentialsData, err = os.ReadFile(credentialsJSON)
-		if err != nil {
-			return nil, fmt.Errorf("unable to read credentials file '%s': %v", credentialsJSON, err)
+	// Spreadsheets mapped to other service accounts are accessed with their own credentials
+	if len(spreadsheetCredentials) > 0 {
+		bySpreadsheet := make(map[string]http.RoundTripper, len(spreadsheetCredentials))
+		for spreadsheetID, credentials := range spreadsheetCredentials {
+			spreadsheetTransport, err := credentialsTransport(credentials)
+			if err != nil {
+				return nil, fmt.Errorf("unable to load credentials for spreadsheet %s: %v", spreadsheetID, err)
+			}
+			bySpreadsheet[spreadsheetID] = spreadsheetTransport
 		}
-		log.Printf("Read credentials from file: %s (%d bytes)", credentialsJSON, len(credentialsData))
-	} else {
-		// It's JSON content
-		credentialsData = []byte(credentialsJSON)
-		log.Printf("Using credentials as JSON content (%d bytes)", len(credentialsData))
+		transport = &spreadsheetRoutingTransport{fallback: transport, bySpreadsheet: bySpreadsheet}
 	}
 
-	service, err := sheets.NewService(ctx, option.WithCredentialsJSON(credentialsData))
+	// Count Sheets API calls for quota metrics
+	httpClient := &http.Client{Transport: quota.Default().Transport(chaos.Transport(transport))}
+
+	service, err := sheets.NewService(ctx, option.WithHTTPClient(httpClient))
 	if err != nil {
 		return nil, fmt.Errorf("unable to create sheets service: %v", err)
 	}
 
-	driveService, err := drive.NewService(ctx, option.WithCredentialsJSON(credentialsData))
+	driveService, err := drive.NewService(ctx, option.WithHTTPClient(httpClient))
 	if err != nil {
 		return nil, fmt.Errorf("unable to create drive service: %v", err)
 	}
@@ -74,6 +385,27 @@ func NewClient(credentialsJSON string) (*Client, error) {
 
 const maxRetryAttempts = 4
 
+// retryDelayUnit is the wait before the second attempt; each later attempt waits one unit longer
+var retryDelayUnit = time.Second
+
+// writeChunkSize is the number of rows sent per API call by batch writes, so one failing call only loses that chunk
+const writeChunkSize = 50
+
+// PartialWriteError is returned by batch writes when some chunks could not be written after retries
+type PartialWriteError struct {
+	Written int
+	Failed  []*MessageRecord
+	Err     error
+}
+
+func (e *PartialWriteError) Error() string {
+	return fmt.Sprintf("%d of %d messages could not be written: %v", len(e.Failed), e.Written+len(e.Failed), e.Err)
+}
+
+func (e *PartialWriteError) Unwrap() error {
+	return e.Err
+}
+
 // retryWithBackoff executes a function with exponential backoff retry logic
 func retryWithBackoff(operation func() error, description string) error {
 	var lastErr error
@@ -95,7 +427,7 @@ func retryWithBackoff(operation func() error, description string) error {
 		}
 
 		// Sleep for attempt seconds (1s, 2s, 3s)
-		delay := time.Duration(attempt) * time.Second
+		delay := time.Duration(attempt) * retryDelayUnit
 		log.Printf("Retrying %s in %v (attempt %d)...", description, delay, attempt+1)
 		time.Sleep(delay)
 	}
@@ -105,30 +437,65 @@ func retryWithBackoff(operation func() error, description string) error {
 }
 
 type MessageRecord struct {
-	Timestamp    time.Time
-	Channel      string
-	ChannelName  string
-	User         string
-	UserHandle   string
-	UserRealName string
-	Text         string
-	ThreadTS     string
-	MessageTS    string
-}
-
-func (c *Client) WriteMessage(spreadsheetID string, record *MessageRecord) error {
+	Timestamp     time.Time
+	Channel       string
+	ChannelName   string
+	User          string
+	UserHandle    string
+	UserRealName  string
+	Text          string
+	ThreadTS      string
+	MessageTS     string
+	UserTitle     string
+	UserTeam      string
+	EmployeeID    string
+	AppSource     string // App or integration that posted the message ("name (app ID)")
+	EditorID      string // Slack user ID of the last editor
+	EditedBy      string // Handle name of the last editor
+	Reactions     string // Reaction summary such as ":+1: 3, :tada: 1"
+	Files         []FileRecord
+	Pinned        bool   // Whether the message is pinned to the channel
+	TraceID       string // ID of the event (or backfill run) that wrote the row, for following it through the logs
+	Permalink     string // Link to the message in Slack
+	ReplyCount    int    // Number of thread replies (thread parents only)
+	ReactionCount int    // Total number of reactions of all emoji
+	EditHistory   string // Texts before each edit, filled in from the sheet when an edit is recorded
+	Redacted      bool   // Content was replaced (opt-out, metadata-only or encrypted), so no edit history is kept
+	Status        string // "deleted <time>" once the message was deleted
+	TeamID        string // Workspace the message was posted from
+}
+
+// FileRecord is a file shared with a message
+type FileRecord struct {
+	Name      string
+	Type      string
+	Size      int // Bytes
+	Permalink string
+}
+
+// joinFiles renders one value per file, one per line, for messages with several files
+func joinFiles(files []FileRecord, value func(file FileRecord) string) string {
+	values := make([]string, 0, len(files))
+	for _, file := range files {
+		values = append(values, value(file))
+	}
+	return strings.Join(values, "\n")
+}
+
+// WriteMessage appends a message to its channel sheet and returns the 1-based row it occupies (0 if unknown)
+func (c *Client) WriteMessage(spreadsheetID string, record *MessageRecord) (int, error) {
 	// Determine sheet name: "ChannelName-ChannelID"
-	sheetName := fmt.Sprintf("%s-%s", record.ChannelName, record.Channel)
+	sheetName := SheetName(record.Channel, record.ChannelName)
 
 	// Ensure sheet exists (handles creation and name updates)
 	if err := c.ensureChannelSheetExists(spreadsheetID, record.Channel, record.ChannelName); err != nil {
-		return err
+		return 0, err
 	}
 
 	// Get sheet data once for all operations (efficiency)
 	sheetData, err := c.getSheetData(spreadsheetID, sheetName)
 	if err != nil {
-		return fmt.Errorf("failed to get sheet data: %v", err)
+		return 0, fmt.Errorf("failed to get sheet data: %v", err)
 	}
 
 	// Check and fix header if needed
@@ -137,14 +504,14 @@ func (c *Client) WriteMessage(spreadsheetID string, record *MessageRecord) error
 		// Reload data after header fix
 		sheetData, err = c.getSheetData(spreadsheetID, sheetName)
 		if err != nil {
-			return fmt.Errorf("failed to reload sheet data after header fix: %v", err)
+			return 0, fmt.Errorf("failed to reload sheet data after header fix: %v", err)
 		}
 	}
 
 	// Check for duplicates using already loaded data
-	if c.messageExistsInData(sheetData, record.MessageTS) {
+	if existingRow := c.findMessageRowInData(sheetData, record.MessageTS); existingRow > 0 {
 		log.Printf("Message %s already exists in sheet %s, skipping", record.MessageTS, sheetName)
-		return nil
+		return existingRow, nil
 	}
 
 	// Get the next row number (No.) from loaded data
@@ -158,32 +525,27 @@ func (c *Client) WriteMessage(spreadsheetID string, record *MessageRecord) error
 		}
 	}
 
-	values := []interface{}{
-		nextRowNumber,
-		record.Timestamp.Format("2006-01-02 15:04:05"),
-		record.UserHandle,
-		record.UserRealName,
-		record.Text,
-		threadParentNo,
-		record.MessageTS,
-	}
+	values := c.buildRow(nextRowNumber, record, threadParentNo)
 
 	// Append the row
 	valueRange := &sheets.ValueRange{
 		Values: [][]interface{}{values},
 	}
 
-	_, err = c.service.Spreadsheets.Values.Append(
+	response, err := c.service.Spreadsheets.Values.Append(
 		spreadsheetID,
-		sheetName+"!A:G",
+		c.columnRange(sheetName),
 		valueRange,
-	).ValueInputOption("RAW").Do()
+	).ValueInputOption(c.rowInputOption()).Do()
 
 	if err != nil {
-		return fmt.Errorf("unable to write data to sheet: %v", err)
+		return 0, fmt.Errorf("unable to write data to sheet: %v", err)
 	}
 
-	return nil
+	if response.Updates != nil {
+		return rowFromA1Range(response.Updates.UpdatedRange), nil
+	}
+	return 0, nil
 }
 
 func (c *Client) ensureSheetExists(spreadsheetID, sheetName string) error {
@@ -192,6 +554,7 @@ func (c *Client) ensureSheetExists(spreadsheetID, sheetName string) error {
 	if err != nil {
 		return fmt.Errorf("unable to get spreadsheet: %v", err)
 	}
+	rememberSheetIDs(spreadsheetID, spreadsheet.Sheets)
 
 	// Check if sheet exists
 	for _, sheet := range spreadsheet.Sheets {
@@ -215,20 +578,21 @@ func (c *Client) ensureSheetExists(spreadsheetID, sheetName string) error {
 		Requests: requests,
 	}
 
-	_, err = c.service.Spreadsheets.BatchUpdate(spreadsheetID, batchUpdateRequest).Do()
+	response, err := c.service.Spreadsheets.BatchUpdate(spreadsheetID, batchUpdateRequest).Do()
 	if err != nil {
 		return fmt.Errorf("unable to create sheet: %v", err)
 	}
+	rememberAddedSheet(spreadsheetID, response)
 
 	// Add headers
 
 	headerRange := &sheets.ValueRange{
-		Values: [][]interface{}{expectedHeaders},
+		Values: [][]interface{}{c.headers()},
 	}
 
 	_, err = c.service.Spreadsheets.Values.Update(
 		spreadsheetID,
-		sheetName+"!A1:G1",
+		c.headerRange(sheetName),
 		headerRange,
 	).ValueInputOption("RAW").Do()
 
@@ -253,8 +617,9 @@ func (c *Client) ensureChannelSheetExists(spreadsheetID, channelID, channelName
 	if err != nil {
 		return fmt.Errorf("unable to get spreadsheet: %v", err)
 	}
+	rememberSheetIDs(spreadsheetID, spreadsheet.Sheets)
 
-	expectedSheetName := fmt.Sprintf("%s-%s", channelName, channelID)
+	expectedSheetName := SheetName(channelID, channelName)
 	var existingSheet *sheets.Sheet
 	var sheetToRename *sheets.Sheet
 
@@ -262,8 +627,8 @@ func (c *Client) ensureChannelSheetExists(spreadsheetID, channelID, channelName
 	for _, sheet := range spreadsheet.Sheets {
 		sheetTitle := sheet.Properties.Title
 
-		// Check if sheet name ends with the channel ID (exact match)
-		if strings.HasSuffix(sheetTitle, "-"+channelID) {
+		// Check if sheet name ends with the channel ID (exact match), or is the participant-based name of a direct message
+		if isConversationSheet(sheetTitle, channelID) {
 			existingSheet = sheet
 			// Check if the name needs updating
 			if sheetTitle != expectedSheetName {
@@ -273,6 +638,13 @@ func (c *Client) ensureChannelSheetExists(spreadsheetID, channelID, channelName
 		}
 	}
 
+	// Existing tabs are brought to the current column layout the first time they are written to
+	if existingSheet != nil {
+		if _, err := c.migrateSheetOnce(spreadsheetID, existingSheet); err != nil {
+			return fmt.Errorf("unable to migrate sheet: %v", err)
+		}
+	}
+
 	// If sheet exists and name needs updating
 	if sheetToRename != nil {
 		log.Printf("Updating sheet name from '%s' to '%s'", sheetToRename.Properties.Title, expectedSheetName)
@@ -296,6 +668,7 @@ func (c *Client) ensureChannelSheetExists(spreadsheetID, channelID, channelName
 			return fmt.Errorf("unable to rename sheet: %v", err)
 		}
 
+		rememberSheetID(spreadsheetID, expectedSheetName, sheetToRename.Properties.SheetId)
 		log.Printf("Sheet renamed successfully to '%s'", expectedSheetName)
 		return nil
 	}
@@ -320,34 +693,79 @@ func (c *Client) ensureChannelSheetExists(spreadsheetID, channelID, channelName
 		},
 	}
 
-	_, err = c.service.Spreadsheets.BatchUpdate(spreadsheetID, createRequest).Do()
+	response, err := c.service.Spreadsheets.BatchUpdate(spreadsheetID, createRequest).Do()
 	if err != nil {
 		return fmt.Errorf("unable to create sheet: %v", err)
 	}
+	rememberAddedSheet(spreadsheetID, response)
 
 	// Add headers to new sheet
 
 	headerRange := &sheets.ValueRange{
-		Values: [][]interface{}{expectedHeaders},
+		Values: [][]interface{}{c.headers()},
 	}
 
 	_, err = c.service.Spreadsheets.Values.Update(
 		spreadsheetID,
-		expectedSheetName+"!A1:G1",
+		c.headerRange(expectedSheetName),
 		headerRange,
 	).ValueInputOption("RAW").Do()
 
 	if err != nil {
 		log.Printf("Warning: unable to add headers to new sheet: %v", err)
+	} else if sheetID, exists := cachedSheetID(spreadsheetID, expectedSheetName); exists {
+		c.markSchemaVersion(spreadsheetID, sheetID)
 	}
 
 	log.Printf("Sheet created successfully: '%s'", expectedSheetName)
 	return nil
 }
 
+// RenameChannelSheet renames an existing channel tab to "channelName-channelID" and returns its previous title.
+// It returns "" when the channel has no tab or the tab already has the expected title; no tab is created.
+func (c *Client) RenameChannelSheet(spreadsheetID, channelID, channelName string) (string, error) {
+	spreadsheet, err := c.service.Spreadsheets.Get(spreadsheetID).Do()
+	if err != nil {
+		return "", fmt.Errorf("unable to get spreadsheet: %v", err)
+	}
+
+	expectedSheetName := SheetName(channelID, channelName)
+	for _, sheet := range spreadsheet.Sheets {
+		oldName := sheet.Properties.Title
+		if !isConversationSheet(oldName, channelID) {
+			continue
+		}
+		if oldName == expectedSheetName {
+			return "", nil
+		}
+
+		updateRequest := &sheets.BatchUpdateSpreadsheetRequest{
+			Requests: []*sheets.Request{
+				{
+					UpdateSheetProperties: &sheets.UpdateSheetPropertiesRequest{
+						Properties: &sheets.SheetProperties{
+							SheetId: sheet.Properties.SheetId,
+							Title:   expectedSheetName,
+						},
+						Fields: "title",
+					},
+				},
+			},
+		}
+		if _, err := c.service.Spreadsheets.BatchUpdate(spreadsheetID, updateRequest).Do(); err != nil {
+			return "", fmt.Errorf("unable to rename sheet: %v", err)
+		}
+
+		forgetSheetID(spreadsheetID, oldName)
+		rememberSheetID(spreadsheetID, expectedSheetName, sheet.Properties.SheetId)
+		return oldName, nil
+	}
+	return "", nil
+}
+
 func (c *Client) getSheetData(spreadsheetID, sheetName string) (*sheets.ValueRange, error) {
 	// Get all data from the sheet in one API call
-	resp, err := c.service.Spreadsheets.Values.Get(spreadsheetID, sheetName+"!A:G").Do()
+	resp, err := c.service.Spreadsheets.Values.Get(spreadsheetID, c.columnRange(sheetName)).Do()
 	if err != nil {
 		return nil, err
 	}
@@ -362,6 +780,7 @@ func (c *Client) ensureCorrectHeader(spreadsheetID, sheetName string, sheetData
 		needsHeaderUpdate = true
 		log.Printf("Sheet %s has no data, adding header", sheetName)
 	} else {
+		expectedHeaders := c.headers()
 		headerRow := sheetData.Values[0]
 		if len(headerRow) != len(expectedHeaders) {
 			needsHeaderUpdate = true
@@ -382,12 +801,12 @@ func (c *Client) ensureCorrectHeader(spreadsheetID, sheetName string, sheetData
 	if needsHeaderUpdate {
 		log.Printf("Updating header for sheet %s", sheetName)
 		headerRange := &sheets.ValueRange{
-			Values: [][]interface{}{expectedHeaders},
+			Values: [][]interface{}{c.headers()},
 		}
 
 		_, err := c.service.Spreadsheets.Values.Update(
 			spreadsheetID,
-			sheetName+"!A1:G1",
+			c.headerRange(sheetName),
 			headerRange,
 		).ValueInputOption("RAW").Do()
 
@@ -413,6 +832,113 @@ func (c *Client) messageExistsInData(sheetData *sheets.ValueRange, messageTS str
 	return false
 }
 
+// findMessageRowInData returns the 1-based sheet row of a message, or -1 if it is not recorded
+func (c *Client) findMessageRowInData(sheetData *sheets.ValueRange, messageTS string) int {
+	for i, row := range sheetData.Values {
+		if i == 0 {
+			continue // Skip header
+		}
+		if len(row) > 6 && row[6] == messageTS {
+			return i + 1 // Convert to 1-based indexing
+		}
+	}
+	return -1
+}
+
+// MessageTimestamps returns the distinct message timestamps (投稿ID) recorded in a sheet
+func (c *Client) MessageTimestamps(spreadsheetID, sheetName string) (map[string]bool, error) {
+	sheetData, err := c.getSheetData(spreadsheetID, sheetName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sheet data: %v", err)
+	}
+
+	seen := make(map[string]bool)
+	for i, row := range sheetData.Values {
+		if i == 0 {
+			continue // Skip header
+		}
+		if len(row) > 6 {
+			if messageTS, ok := row[6].(string); ok && messageTS != "" {
+				seen[messageTS] = true
+			}
+		}
+	}
+	return seen, nil
+}
+
+// SheetSummary returns the number of data rows of a tab and the first and last posted times (column B) in it
+func (c *Client) SheetSummary(spreadsheetID, sheetName string) (rows int, first, last string, err error) {
+	sheetData, err := c.getSheetData(spreadsheetID, sheetName)
+	if err != nil {
+		return 0, "", "", fmt.Errorf("failed to get sheet data: %v", err)
+	}
+
+	// Tabs may mix both posted time formats after TIMESTAMP_FORMAT changed, so times are compared when they parse
+	jst := time.FixedZone("JST", 9*60*60)
+	before := func(a, b string) bool {
+		timeA, errA := parsePostedAt(a, jst)
+		timeB, errB := parsePostedAt(b, jst)
+		if errA == nil && errB == nil {
+			return timeA.Before(timeB)
+		}
+		return a < b
+	}
+
+	for i, row := range sheetData.Values {
+		if i == 0 || len(row) == 0 {
+			continue // Skip header and blank rows
+		}
+		rows++
+		if len(row) < 2 {
+			continue
+		}
+		postedAt := fmt.Sprintf("%v", row[1])
+		if postedAt == "" {
+			continue
+		}
+		if first == "" || before(postedAt, first) {
+			first = postedAt
+		}
+		if last == "" || before(last, postedAt) {
+			last = postedAt
+		}
+	}
+	return rows, first, last, nil
+}
+
+// LastMessageTS returns the number of recorded rows and the newest message timestamp (column G) of a sheet
+func (c *Client) LastMessageTS(spreadsheetID, sheetName string) (int, string, error) {
+	sheetData, err := c.getSheetData(spreadsheetID, sheetName)
+	if err != nil {
+		return 0, "", fmt.Errorf("failed to get sheet data: %v", err)
+	}
+
+	rows, lastTS, newest := 0, "", 0.0
+	for i, row := range sheetData.Values {
+		if i == 0 || len(row) == 0 {
+			continue // Skip header and blank rows
+		}
+		rows++
+		if len(row) <= 6 {
+			continue
+		}
+		messageTS := fmt.Sprintf("%v", row[6])
+		if ts, err := strconv.ParseFloat(messageTS, 64); err == nil && ts > newest {
+			newest, lastTS = ts, messageTS
+		}
+	}
+	return rows, lastTS, nil
+}
+
+// FindMessageRow returns the 1-based sheet row of a recorded message, or -1 if it is not in the sheet
+func (c *Client) FindMessageRow(spreadsheetID, sheetName, messageTS string) (int, error) {
+	sheetData, err := c.getSheetData(spreadsheetID, sheetName)
+	if err != nil {
+		return -1, fmt.Errorf("failed to get sheet data: %v", err)
+	}
+	return c.findMessageRowInData(sheetData, messageTS), nil
+}
+
 func (c *Client) getNextRowNumberFromData(sheetData *sheets.ValueRange) int {
 	// Count rows (subtract 1 for header row, then add 1 for next number)
 	rowCount := len(sheetData.Values)
@@ -446,6 +972,40 @@ func (c *Client) findThreadParentNoInData(sheetData *sheets.ValueRange, threadTS
 	return 0
 }
 
+// FindThreadRows returns the first and last sheet row numbers (1-based, header is row 1)
+// occupied by a thread parent and its replies, along with the number of matching rows
+func (c *Client) FindThreadRows(spreadsheetID, sheetName, threadTS string) (firstRow, lastRow, count int, err error) {
+	sheetData, err := c.getSheetData(spreadsheetID, sheetName)
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("failed to get sheet data: %v", err)
+	}
+
+	parentNo := c.findThreadParentNoInData(sheetData, threadTS)
+
+	for i, row := range sheetData.Values {
+		if i == 0 {
+			continue // Skip header
+		}
+
+		isParent := len(row) > 6 && row[6] == threadTS
+		isReply := parentNo > 0 && len(row) > 5 && fmt.Sprintf("%v", row[5]) == strconv.Itoa(parentNo)
+		if !isParent && !isReply {
+			continue
+		}
+
+		sheetRow := i + 1
+		if firstRow == 0 || sheetRow < firstRow {
+			firstRow = sheetRow
+		}
+		if sheetRow > lastRow {
+			lastRow = sheetRow
+		}
+		count++
+	}
+
+	return firstRow, lastRow, count, nil
+}
+
 func (c *Client) ClearSheetData(spreadsheetID, sheetName string) error {
 	// Get sheet properties to find the sheet ID
 	spreadsheet, err := c.service.Spreadsheets.Get(spreadsheetID).Do()
@@ -453,6 +1013,8 @@ func (c *Client) ClearSheetData(spreadsheetID, sheetName string) error {
 		return fmt.Errorf("unable to get spreadsheet: %v", err)
 	}
 
+	rememberSheetIDs(spreadsheetID, spreadsheet.Sheets)
+
 	var sheetID int64
 	found := false
 	for _, sheet := range spreadsheet.Sheets {
@@ -504,7 +1066,7 @@ func (c *Client) WriteBatchMessages(spreadsheetID string, records []*MessageReco
 	})
 
 	// Use the first record to determine sheet name (all should be same channel)
-	sheetName := fmt.Sprintf("%s-%s", records[0].ChannelName, records[0].Channel)
+	sheetName := SheetName(records[0].Channel, records[0].ChannelName)
 
 	// Ensure sheet exists
 	if err := c.ensureChannelSheetExists(spreadsheetID, records[0].Channel, records[0].ChannelName); err != nil {
@@ -564,40 +1126,47 @@ func (c *Client) WriteBatchMessages(spreadsheetID string, records []*MessageReco
 			}
 		}
 
-		values = append(values, []interface{}{
-			rowNumber,
-			record.Timestamp.Format("2006-01-02 15:04:05"),
-			record.UserHandle,
-			record.UserRealName,
-			record.Text,
-			threadParentNo,
-			record.MessageTS,
-		})
+		values = append(values, c.buildRow(rowNumber, record, threadParentNo))
 	}
 
-	// Batch insert all new messages
-	if len(values) > 0 {
+	// Batch insert all new messages chunk by chunk. Writing stops at the first chunk that fails: the No. and thread
+	// parent numbers of later chunks assume the rows before them were written, so they are returned as failed too.
+	partial := &PartialWriteError{}
+	for start := 0; start < len(values); start += writeChunkSize {
+		end := start + writeChunkSize
+		if end > len(values) {
+			end = len(values)
+		}
+
 		err := retryWithBackoff(func() error {
 			valueRange := &sheets.ValueRange{
-				Values: values,
+				Values: values[start:end],
 			}
 
 			_, err := c.service.Spreadsheets.Values.Append(
 				spreadsheetID,
-				sheetName+"!A:G",
+				c.columnRange(sheetName),
 				valueRange,
-			).ValueInputOption("RAW").Do()
+			).ValueInputOption(c.rowInputOption()).Do()
 
 			return err
-		}, fmt.Sprintf("write %d messages to sheet %s", len(values), sheetName))
+		}, fmt.Sprintf("write messages %d-%d to sheet %s", start+1, end, sheetName))
 
 		if err != nil {
-			return fmt.Errorf("unable to write batch data to sheet: %v", err)
+			log.Printf("Error writing messages %d-%d to sheet %s, not writing the remaining %d: %v",
+				start+1, end, sheetName, len(values)-end, err)
+			partial.Failed = append(partial.Failed, newRecords[start:]...)
+			partial.Err = err
+			break
 		}
+		partial.Written += end - start
+	}
 
-		log.Printf("Successfully wrote %d messages to sheet %s in chronological order", len(values), sheetName)
+	if len(partial.Failed) > 0 {
+		return partial
 	}
 
+	log.Printf("Successfully wrote %d messages to sheet %s in chronological order", len(values), sheetName)
 	return nil
 }
 
@@ -608,7 +1177,7 @@ func (c *Client) WriteMessagesStreamingWithProgress(spreadsheetID string, record
 	}
 
 	// Use the first record to determine sheet name (all should be same channel)
-	sheetName := fmt.Sprintf("%s-%s", records[0].ChannelName, records[0].Channel)
+	sheetName := SheetName(records[0].Channel, records[0].ChannelName)
 
 	// Ensure sheet exists
 	if err := c.ensureChannelSheetExists(spreadsheetID, records[0].Channel, records[0].ChannelName); err != nil {
@@ -687,15 +1256,7 @@ func (c *Client) WriteMessagesStreamingWithProgress(spreadsheetID string, record
 				}
 			}
 
-			values = append(values, []interface{}{
-				rowNumber,
-				record.Timestamp.Format("2006-01-02 15:04:05"),
-				record.UserHandle,
-				record.UserRealName,
-				record.Text,
-				threadParentNo,
-				record.MessageTS,
-			})
+			values = append(values, c.buildRow(rowNumber, record, threadParentNo))
 		}
 
 		// Write this batch to sheet
@@ -707,9 +1268,9 @@ func (c *Client) WriteMessagesStreamingWithProgress(spreadsheetID string, record
 
 				_, err := c.service.Spreadsheets.Values.Append(
 					spreadsheetID,
-					sheetName+"!A:G",
+					c.columnRange(sheetName),
 					valueRange,
-				).ValueInputOption("RAW").Do()
+				).ValueInputOption(c.rowInputOption()).Do()
 
 				return err
 			}, fmt.Sprintf("stream write batch %d-%d to sheet %s", i+1, end, sheetName))
@@ -738,7 +1299,7 @@ func (c *Client) WriteMessagesStreamingWithProgress(spreadsheetID string, record
 
 // WriteBatchMessagesFromRow2 writes messages starting from row 2, ignoring existing data
 // Used for initial execution and reset operations to ensure consistent positioning
-func (c *Client) WriteBatchMessagesFromRow2(spreadsheetID string, records []*MessageRecord) error {
+func (c *Client) WriteBatchMessagesFromRow2(spreadsheetID string, records []*MessageRecord, progressCallback func(written, total int)) error {
 	if len(records) == 0 {
 		return nil
 	}
@@ -749,7 +1310,7 @@ func (c *Client) WriteBatchMessagesFromRow2(spreadsheetID string, records []*Mes
 	})
 
 	// Use the first record to determine sheet name (all should be same channel)
-	sheetName := fmt.Sprintf("%s-%s", records[0].ChannelName, records[0].Channel)
+	sheetName := SheetName(records[0].Channel, records[0].ChannelName)
 
 	// Ensure sheet exists
 	if err := c.ensureChannelSheetExists(spreadsheetID, records[0].Channel, records[0].ChannelName); err != nil {
@@ -784,132 +1345,610 @@ func (c *Client) WriteBatchMessagesFromRow2(spreadsheetID string, records []*Mes
 			}
 		}
 
-		values = append(values, []interface{}{
-			rowNumber,
-			record.Timestamp.Format("2006-01-02 15:04:05"),
-			record.UserHandle,
-			record.UserRealName,
-			record.Text,
-			threadParentNo,
-			record.MessageTS,
-		})
+		values = append(values, c.buildRow(rowNumber, record, threadParentNo))
 	}
 
-	// Write all messages starting from row 2, replacing any existing data
-	if len(values) > 0 {
+	// Write all messages starting from row 2 chunk by chunk, replacing any existing data. Writing stops at the first
+	// chunk that fails: later chunks would leave its rows blank and a gap in No., so they are returned as failed too
+	// and appended after the written rows when retried.
+	partial := &PartialWriteError{}
+	for start := 0; start < len(values); start += writeChunkSize {
+		end := start + writeChunkSize
+		if end > len(values) {
+			end = len(values)
+		}
+
 		err := retryWithBackoff(func() error {
 			valueRange := &sheets.ValueRange{
-				Values: values,
+				Values: values[start:end],
 			}
 
-			// Use Update instead of Append to write starting from row 2
-			startRange := fmt.Sprintf("%s!A2:G%d", sheetName, len(values)+1)
+			// Use Update instead of Append to write at fixed rows starting from row 2
+			chunkRange := fmt.Sprintf("%s!%s", sheetName, c.RowRange(start+2, end+1))
 			_, err := c.service.Spreadsheets.Values.Update(
 				spreadsheetID,
-				startRange,
+				chunkRange,
 				valueRange,
-			).ValueInputOption("RAW").Do()
+			).ValueInputOption(c.rowInputOption()).Do()
 
 			return err
-		}, fmt.Sprintf("write %d messages from row 2 to sheet %s", len(values), sheetName))
+		}, fmt.Sprintf("write messages %d-%d from row 2 to sheet %s", start+1, end, sheetName))
 
 		if err != nil {
-			return fmt.Errorf("unable to write batch data from row 2 to sheet: %v", err)
+			log.Printf("Error writing messages %d-%d from row 2 to sheet %s, not writing the remaining %d: %v",
+				start+1, end, sheetName, len(values)-end, err)
+			partial.Failed = append(partial.Failed, records[start:]...)
+			partial.Err = err
+			break
+		}
+		partial.Written += end - start
+
+		if progressCallback != nil {
+			progressCallback(end, len(values))
 		}
+	}
 
-		log.Printf("Successfully wrote %d messages from row 2 to sheet %s", len(values), sheetName)
+	if len(partial.Failed) > 0 {
+		return partial
 	}
 
+	log.Printf("Successfully wrote %d messages from row 2 to sheet %s", len(values), sheetName)
 	return nil
 }
 
-// UpdateMessage updates an existing message in the sheet based on message timestamp
-func (c *Client) UpdateMessage(spreadsheetID string, record *MessageRecord) error {
-	// Determine sheet name: "ChannelName-ChannelID"
-	sheetName := fmt.Sprintf("%s-%s", record.ChannelName, record.Channel)
+// MergeMessages adds backfilled history to a channel tab without overwriting rows that are already recorded.
+// An empty tab is written from row 2; otherwise messages not in the tab yet are appended, and the tab is
+// resorted when some of them are older than the rows already there. Existing rows keep their content.
+func (c *Client) MergeMessages(spreadsheetID string, records []*MessageRecord, progressCallback func(written, total int)) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	sheetName := SheetName(records[0].Channel, records[0].ChannelName)
+	if err := c.ensureChannelSheetExists(spreadsheetID, records[0].Channel, records[0].ChannelName); err != nil {
+		return err
+	}
 
-	// Get sheet data to find the message
 	sheetData, err := c.getSheetData(spreadsheetID, sheetName)
 	if err != nil {
 		return fmt.Errorf("failed to get sheet data: %v", err)
 	}
+	if len(sheetData.Values) <= 1 {
+		return c.WriteBatchMessagesFromRow2(spreadsheetID, records, progressCallback)
+	}
 
-	// Find the row containing the message to update
-	var targetRow int = -1
-	for i, row := range sheetData.Values {
-		if i == 0 {
-			continue // Skip header
+	// Find the newest recorded message to tell whether appending keeps the tab in order
+	latestTS := 0.0
+	for _, row := range sheetData.Values[1:] {
+		if len(row) > 6 {
+			if messageTS, err := strconv.ParseFloat(fmt.Sprintf("%v", row[6]), 64); err == nil && messageTS > latestTS {
+				latestTS = messageTS
+			}
 		}
-		if len(row) > 6 && row[6] == record.MessageTS {
-			targetRow = i + 1 // Convert to 1-based indexing
-			break
+	}
+	outOfOrder := false
+	for _, record := range records {
+		messageTS, err := strconv.ParseFloat(record.MessageTS, 64)
+		if err == nil && messageTS < latestTS && !c.messageExistsInData(sheetData, record.MessageTS) {
+			outOfOrder = true
+			break
 		}
 	}
 
-	if targetRow == -1 {
-		log.Printf("Message %s not found in sheet %s for update", record.MessageTS, sheetName)
-		return fmt.Errorf("message not found for update")
+	log.Printf("Merging %d history messages into sheet %s with %d existing rows", len(records), sheetName, len(sheetData.Values)-1)
+	writeErr := c.WriteBatchMessages(spreadsheetID, records)
+	if _, partial := writeErr.(*PartialWriteError); writeErr != nil && !partial {
+		return writeErr
+	}
+	if progressCallback != nil {
+		progressCallback(len(records), len(records))
+	}
+
+	if outOfOrder {
+		if _, err := c.ResortSheet(spreadsheetID, sheetName); err != nil {
+			log.Printf("Warning: could not resort sheet %s after merging history: %v", sheetName, err)
+		}
 	}
+	return writeErr
+}
 
-	// Get the existing row number to preserve it (ensure it's a number, not a string)
-	existingRowData := sheetData.Values[targetRow-1] // Convert back to 0-based for array access
-	var rowNumber int = targetRow - 1                // Default fallback
-	if len(existingRowData) > 0 {
-		// Try to parse the existing row number as an integer
-		if existingRowNum, ok := existingRowData[0].(float64); ok {
-			rowNumber = int(existingRowNum)
-		} else if existingRowStr, ok := existingRowData[0].(string); ok {
-			if parsedNum, err := strconv.Atoi(existingRowStr); err == nil {
-				rowNumber = parsedNum
+// UpdateMessage updates an existing message in the sheet based on message timestamp and returns the updated 1-based row
+func (c *Client) UpdateMessage(spreadsheetID string, record *MessageRecord) (int, error) {
+	rows, err := c.UpdateMessages(spreadsheetID, []*MessageRecord{record})
+	if err != nil {
+		return rows[record.MessageTS], err
+	}
+	row, exists := rows[record.MessageTS]
+	if !exists {
+		return 0, fmt.Errorf("message not found for update")
+	}
+	return row, nil
+}
+
+// UpdateMessages rewrites the rows of edited messages with one sheet read and one values.batchUpdate call per
+// channel tab, and returns the updated 1-based row of each message by MessageTS; messages not found in the sheet
+// are left out
+func (c *Client) UpdateMessages(spreadsheetID string, records []*MessageRecord) (map[string]int, error) {
+	rows := make(map[string]int)
+
+	// Group by tab, keeping the order the tabs first appear in
+	var sheetNames []string
+	bySheet := make(map[string][]*MessageRecord)
+	for _, record := range records {
+		sheetName := SheetName(record.Channel, record.ChannelName)
+		if _, exists := bySheet[sheetName]; !exists {
+			sheetNames = append(sheetNames, sheetName)
+		}
+		bySheet[sheetName] = append(bySheet[sheetName], record)
+	}
+
+	for _, sheetName := range sheetNames {
+		// Get sheet data to find the messages
+		sheetData, err := c.getSheetData(spreadsheetID, sheetName)
+		if err != nil {
+			return rows, fmt.Errorf("failed to get sheet data: %v", err)
+		}
+
+		var data []*sheets.ValueRange
+		var messageTSs []string
+		targetRows := make(map[string]int)
+		for _, record := range bySheet[sheetName] {
+			// Find the row containing the message to update
+			targetRow := c.findMessageRowInData(sheetData, record.MessageTS)
+			if targetRow == -1 {
+				log.Printf("Message %s not found in sheet %s for update", record.MessageTS, sheetName)
+				continue
+			}
+
+			// Preserve the existing row number (ensure it's a number, not a string)
+			rowNumber := existingRowNumber(sheetData.Values[targetRow-1], targetRow-1)
+			// The earlier texts in the sheet are exactly what a redacted record must not carry, so its history is cleared
+			if record.Redacted {
+				record.EditHistory = ""
+			} else if historyColumn := c.columnNumber(ColumnEditHistory); historyColumn > 0 {
+				record.EditHistory = editHistory(sheetData.Values[targetRow-1], historyColumn, record.Text)
+			}
+
+			// Find thread parent No. if this is a thread reply
+			threadParentNo := ""
+			if record.ThreadTS != "" && record.ThreadTS != record.MessageTS {
+				if parentNo := c.findThreadParentNoInData(sheetData, record.ThreadTS); parentNo > 0 {
+					threadParentNo = fmt.Sprintf("%d", parentNo)
+				}
 			}
+
+			data = append(data, &sheets.ValueRange{
+				Range:  fmt.Sprintf("%s!A%d:%s%d", sheetName, targetRow, c.lastColumn(), targetRow),
+				Values: [][]interface{}{c.buildRow(rowNumber, record, threadParentNo)},
+			})
+			messageTSs = append(messageTSs, record.MessageTS)
+			targetRows[record.MessageTS] = targetRow
 		}
+		if len(data) == 0 {
+			continue
+		}
+
+		// Update all rows of the tab in one request
+		err = retryWithBackoff(func() error {
+			_, err := c.service.Spreadsheets.Values.BatchUpdate(spreadsheetID, &sheets.BatchUpdateValuesRequest{
+				ValueInputOption: c.rowInputOption(),
+				Data:             data,
+			}).Do()
+			return err
+		}, fmt.Sprintf("update %d messages in sheet %s", len(data), sheetName))
+		if err != nil {
+			return rows, fmt.Errorf("unable to update messages in sheet: %v", err)
+		}
+
+		for messageTS, targetRow := range targetRows {
+			rows[messageTS] = targetRow
+		}
+		log.Printf("Successfully updated %d messages in sheet %s: %s", len(data), sheetName, strings.Join(messageTSs, ", "))
 	}
 
-	// Find thread parent No. if this is a thread reply (preserve existing logic)
-	threadParentNo := ""
-	if record.ThreadTS != "" && record.ThreadTS != record.MessageTS {
-		if parentNo := c.findThreadParentNoInData(sheetData, record.ThreadTS); parentNo > 0 {
-			threadParentNo = fmt.Sprintf("%d", parentNo)
+	return rows, nil
+}
+
+// existingRowNumber reads the No. cell of a row, or returns fallback if it is not a number
+func existingRowNumber(row []interface{}, fallback int) int {
+	if len(row) == 0 {
+		return fallback
+	}
+	if number, ok := row[0].(float64); ok {
+		return int(number)
+	}
+	if text, ok := row[0].(string); ok {
+		if number, err := strconv.Atoi(text); err == nil {
+			return number
 		}
 	}
+	return fallback
+}
 
-	// Prepare updated values
-	values := []interface{}{
-		rowNumber, // Preserve original row number
-		record.Timestamp.Format("2006-01-02 15:04:05"),
-		record.UserHandle,
-		record.UserRealName,
-		record.Text,
-		threadParentNo,
-		record.MessageTS,
+// UpdateReactions rewrites the reactions cell of a recorded message and returns its row (-1 if the message is not recorded)
+func (c *Client) UpdateReactions(spreadsheetID, sheetName, messageTS, summary string) (int, error) {
+	return c.updateMessageCell(spreadsheetID, sheetName, messageTS, ColumnReactions, summary)
+}
+
+// UpdateReactionCount rewrites the reaction count cell of a recorded message and returns its row (-1 if the message
+// is not recorded)
+func (c *Client) UpdateReactionCount(spreadsheetID, sheetName, messageTS string, count int) (int, error) {
+	return c.updateMessageCell(spreadsheetID, sheetName, messageTS, ColumnReactionCount, count)
+}
+
+// RefreshReplyCount sets the reply count cell of a thread parent to the number of its replies in the tab and returns
+// the parent's 1-based row (-1 if the parent is not in the sheet)
+func (c *Client) RefreshReplyCount(spreadsheetID, sheetName, threadTS string) (int, error) {
+	columnNumber := c.columnNumber(ColumnReplyCount)
+	if columnNumber < 0 {
+		return -1, fmt.Errorf("%s column is not enabled", ColumnReplyCount.Header)
 	}
 
-	// Update the specific row
-	err = retryWithBackoff(func() error {
-		valueRange := &sheets.ValueRange{
-			Values: [][]interface{}{values},
+	sheetData, err := c.getSheetData(spreadsheetID, sheetName)
+	if err != nil {
+		return -1, fmt.Errorf("failed to get sheet data: %v", err)
+	}
+	row := c.findMessageRowInData(sheetData, threadTS)
+	if row < 0 {
+		return -1, nil
+	}
+	parentNo := strconv.Itoa(c.findThreadParentNoInData(sheetData, threadTS))
+
+	replies := 0
+	for i, values := range sheetData.Values {
+		if i > 0 && len(values) > 5 && fmt.Sprintf("%v", values[5]) == parentNo {
+			replies++
 		}
+	}
 
-		updateRange := fmt.Sprintf("%s!A%d:G%d", sheetName, targetRow, targetRow)
+	err = retryWithBackoff(func() error {
 		_, err := c.service.Spreadsheets.Values.Update(
 			spreadsheetID,
-			updateRange,
-			valueRange,
+			fmt.Sprintf("%s!%s%d", sheetName, columnLetter(columnNumber), row),
+			&sheets.ValueRange{Values: [][]interface{}{{replies}}},
 		).ValueInputOption("RAW").Do()
+		return err
+	}, fmt.Sprintf("update reply count of %s in sheet %s", threadTS, sheetName))
+	if err != nil {
+		return row, fmt.Errorf("unable to update reply count in sheet: %v", err)
+	}
+	return row, nil
+}
+
+// UpdatePinned sets the pinned cell of a recorded message and returns its 1-based row (-1 if the message is not in the sheet)
+func (c *Client) UpdatePinned(spreadsheetID, sheetName, messageTS string, pinned bool) (int, error) {
+	return c.updateMessageCell(spreadsheetID, sheetName, messageTS, ColumnPinned, pinned)
+}
+
+// UpdateStatus rewrites the status cell of a recorded message and returns its row (-1 if the message is not recorded)
+func (c *Client) UpdateStatus(spreadsheetID, sheetName, messageTS, status string) (int, error) {
+	return c.updateMessageCell(spreadsheetID, sheetName, messageTS, ColumnStatus, status)
+}
+
+// updateMessageCell overwrites one optional column of a recorded message and returns its 1-based row
+// (-1 if the message is not in the sheet)
+func (c *Client) updateMessageCell(spreadsheetID, sheetName, messageTS string, column Column, value interface{}) (int, error) {
+	columnNumber := c.columnNumber(column)
+	if columnNumber < 0 {
+		return -1, fmt.Errorf("%s column is not enabled", column.Header)
+	}
+
+	row, err := c.FindMessageRow(spreadsheetID, sheetName, messageTS)
+	if err != nil || row < 0 {
+		return row, err
+	}
 
+	err = retryWithBackoff(func() error {
+		_, err := c.service.Spreadsheets.Values.Update(
+			spreadsheetID,
+			fmt.Sprintf("%s!%s%d", sheetName, columnLetter(columnNumber), row),
+			&sheets.ValueRange{Values: [][]interface{}{{value}}},
+		).ValueInputOption("RAW").Do()
 		return err
-	}, fmt.Sprintf("update message %s in sheet %s", record.MessageTS, sheetName))
+	}, fmt.Sprintf("update %s of %s in sheet %s", column.Header, messageTS, sheetName))
+	if err != nil {
+		return row, fmt.Errorf("unable to update %s in sheet: %v", column.Header, err)
+	}
+	return row, nil
+}
+
+// channelSheetPattern matches the titles of channel tabs ("<channel name>-<channel ID>")
+var channelSheetPattern = regexp.MustCompile(`-[CGD][A-Z0-9]{6,}$`)
 
+// rollupHeaders is the header row of the daily rollup tab
+var rollupHeaders = []interface{}{"日付", "シート", "メッセージ数", "移動"}
+
+// BuildDailyRollup rewrites the rollup tab with one row per channel tab and day (newest day first),
+// each linking to the first row of that day, and returns the number of rollup rows
+func (c *Client) BuildDailyRollup(spreadsheetID, rollupSheetName string) (int, error) {
+	spreadsheet, err := c.service.Spreadsheets.Get(spreadsheetID).Do()
 	if err != nil {
-		return fmt.Errorf("unable to update message in sheet: %v", err)
+		return 0, fmt.Errorf("unable to get spreadsheet: %v", err)
+	}
+	rememberSheetIDs(spreadsheetID, spreadsheet.Sheets)
+
+	var channelSheets []*sheets.SheetProperties
+	var ranges []string
+	for _, sheet := range spreadsheet.Sheets {
+		if isChannelSheet(sheet.Properties.Title) {
+			channelSheets = append(channelSheets, sheet.Properties)
+			ranges = append(ranges, fmt.Sprintf("%s!B2:B", sheet.Properties.Title))
+		}
 	}
 
-	log.Printf("Successfully updated message %s in sheet %s", record.MessageTS, sheetName)
+	var rows [][]interface{}
+	if len(ranges) > 0 {
+		response, err := c.service.Spreadsheets.Values.BatchGet(spreadsheetID).Ranges(ranges...).Do()
+		if err != nil {
+			return 0, fmt.Errorf("unable to read channel sheets: %v", err)
+		}
+
+		for i, valueRange := range response.ValueRanges {
+			if i >= len(channelSheets) {
+				break
+			}
+			properties := channelSheets[i]
+
+			var dates []string
+			counts := make(map[string]int)
+			firstRows := make(map[string]int)
+			for j, row := range valueRange.Values {
+				if len(row) == 0 {
+					continue
+				}
+				date := fmt.Sprintf("%v", row[0])
+				if len(date) < 10 {
+					continue
+				}
+				date = date[:10]
+				if _, seen := counts[date]; !seen {
+					dates = append(dates, date)
+					firstRows[date] = j + 2 // Data starts at row 2
+				}
+				counts[date]++
+			}
+
+			sort.Sort(sort.Reverse(sort.StringSlice(dates)))
+			for _, date := range dates {
+				link := fmt.Sprintf(`=HYPERLINK("#gid=%d&range=A%d", "=== %s · %d件 ===")`,
+					properties.SheetId, firstRows[date], date, counts[date])
+				rows = append(rows, []interface{}{date, properties.Title, counts[date], link})
+			}
+		}
+	}
+
+	if _, err := c.ensureAuxiliarySheet(spreadsheetID, rollupSheetName, rollupHeaders); err != nil {
+		return 0, err
+	}
+
+	_, err = c.service.Spreadsheets.Values.Clear(spreadsheetID, fmt.Sprintf("%s!A2:D", rollupSheetName), &sheets.ClearValuesRequest{}).Do()
+	if err != nil {
+		return 0, fmt.Errorf("unable to clear rollup sheet: %v", err)
+	}
+	if len(rows) == 0 {
+		return 0, nil
+	}
+
+	// USER_ENTERED so the HYPERLINK formulas become links
+	_, err = c.service.Spreadsheets.Values.Update(
+		spreadsheetID,
+		fmt.Sprintf("%s!A2:D%d", rollupSheetName, len(rows)+1),
+		&sheets.ValueRange{Values: rows},
+	).ValueInputOption("USER_ENTERED").Do()
+	if err != nil {
+		return 0, fmt.Errorf("unable to write rollup sheet: %v", err)
+	}
+	return len(rows), nil
+}
+
+// Tab colors by recency of the last message
+var (
+	tabColorRecent = &sheets.Color{Red: 0.20, Green: 0.66, Blue: 0.33} // Within a week
+	tabColorActive = &sheets.Color{Red: 0.98, Green: 0.74, Blue: 0.02} // Within 30 days
+	tabColorStale  = &sheets.Color{Red: 0.60, Green: 0.60, Blue: 0.60} // Older or empty
+)
+
+// channelActivity is the activity of one channel tab used to order and color it
+type channelActivity struct {
+	properties  *sheets.SheetProperties
+	recentCount int // Messages in the last 30 days
+	lastMessage time.Time
+}
+
+// ArrangeTabs keeps the non-channel tabs (rollup, settings, audit, ...) first in their current order,
+// then orders channel tabs by messages in the last 30 days and colors them by the date of their last message.
+// Timestamps in the sheet are interpreted in now's location. It returns the number of channel tabs arranged.
+func (c *Client) ArrangeTabs(spreadsheetID string, now time.Time) (int, error) {
+	spreadsheet, err := c.service.Spreadsheets.Get(spreadsheetID).Do()
+	if err != nil {
+		return 0, fmt.Errorf("unable to get spreadsheet: %v", err)
+	}
+	rememberSheetIDs(spreadsheetID, spreadsheet.Sheets)
+
+	var otherSheets []*sheets.SheetProperties
+	var channels []*channelActivity
+	var ranges []string
+	for _, sheet := range spreadsheet.Sheets {
+		if isChannelSheet(sheet.Properties.Title) {
+			channels = append(channels, &channelActivity{properties: sheet.Properties})
+			ranges = append(ranges, fmt.Sprintf("%s!B2:B", sheet.Properties.Title))
+		} else {
+			otherSheets = append(otherSheets, sheet.Properties)
+		}
+	}
+	if len(channels) == 0 {
+		return 0, nil
+	}
+
+	response, err := c.service.Spreadsheets.Values.BatchGet(spreadsheetID).Ranges(ranges...).Do()
+	if err != nil {
+		return 0, fmt.Errorf("unable to read channel sheets: %v", err)
+	}
+
+	monthAgo := now.AddDate(0, 0, -30)
+	for i, valueRange := range response.ValueRanges {
+		if i >= len(channels) {
+			break
+		}
+		for _, row := range valueRange.Values {
+			if len(row) == 0 {
+				continue
+			}
+			timestamp, err := parsePostedAt(fmt.Sprintf("%v", row[0]), now.Location())
+			if err != nil {
+				continue
+			}
+			if timestamp.After(channels[i].lastMessage) {
+				channels[i].lastMessage = timestamp
+			}
+			if timestamp.After(monthAgo) {
+				channels[i].recentCount++
+			}
+		}
+	}
+
+	sort.SliceStable(channels, func(i, j int) bool {
+		if channels[i].recentCount != channels[j].recentCount {
+			return channels[i].recentCount > channels[j].recentCount
+		}
+		return channels[i].lastMessage.After(channels[j].lastMessage)
+	})
+
+	// Move tabs into place front to back so each move only shifts tabs that are not yet placed
+	var requests []*sheets.Request
+	index := int64(0)
+	for _, properties := range otherSheets {
+		requests = append(requests, &sheets.Request{
+			UpdateSheetProperties: &sheets.UpdateSheetPropertiesRequest{
+				Properties: &sheets.SheetProperties{SheetId: properties.SheetId, Index: index, ForceSendFields: []string{"Index"}},
+				Fields:     "index",
+			},
+		})
+		index++
+	}
+	for _, channel := range channels {
+		color := tabColorStale
+		switch {
+		case now.Sub(channel.lastMessage) <= 7*24*time.Hour:
+			color = tabColorRecent
+		case channel.lastMessage.After(monthAgo):
+			color = tabColorActive
+		}
+
+		requests = append(requests, &sheets.Request{
+			UpdateSheetProperties: &sheets.UpdateSheetPropertiesRequest{
+				Properties: &sheets.SheetProperties{
+					SheetId:         channel.properties.SheetId,
+					Index:           index,
+					TabColorStyle:   &sheets.ColorStyle{RgbColor: color},
+					ForceSendFields: []string{"Index"},
+				},
+				Fields: "index,tabColorStyle",
+			},
+		})
+		index++
+	}
+
+	_, err = c.service.Spreadsheets.BatchUpdate(spreadsheetID, &sheets.BatchUpdateSpreadsheetRequest{Requests: requests}).Do()
+	if err != nil {
+		return 0, fmt.Errorf("unable to arrange tabs: %v", err)
+	}
+	return len(channels), nil
+}
+
+// settingsHeaders is the header row of the per-channel settings tab
+var settingsHeaders = []interface{}{"チャンネルID", "設定項目", "値", "メモ"}
+
+// ReadSettingsRows returns the data rows (channel ID, key, value) of the settings tab, creating the tab if missing
+func (c *Client) ReadSettingsRows(spreadsheetID, sheetName string) ([][]interface{}, error) {
+	created, err := c.ensureAuxiliarySheet(spreadsheetID, sheetName, settingsHeaders)
+	if err != nil {
+		return nil, err
+	}
+	if created {
+		return nil, nil
+	}
+
+	resp, err := c.service.Spreadsheets.Values.Get(spreadsheetID, fmt.Sprintf("%s!A2:C", sheetName)).Do()
+	if err != nil {
+		return nil, fmt.Errorf("unable to read settings sheet: %v", err)
+	}
+	return resp.Values, nil
+}
+
+// auditHeaders is the header row of the audit tab
+var auditHeaders = []interface{}{"日時", "操作", "チャンネルID", "ユーザーID", "詳細"}
+
+// AppendAuditRow appends one row (time, action, channel ID, user ID, detail) to the audit tab, creating the tab if missing
+func (c *Client) AppendAuditRow(spreadsheetID, sheetName string, row []interface{}) error {
+	if _, err := c.ensureAuxiliarySheet(spreadsheetID, sheetName, auditHeaders); err != nil {
+		return err
+	}
+
+	_, err := c.service.Spreadsheets.Values.Append(
+		spreadsheetID,
+		fmt.Sprintf("%s!A:%s", sheetName, columnLetter(len(auditHeaders))),
+		&sheets.ValueRange{Values: [][]interface{}{row}},
+	).ValueInputOption("RAW").Do()
+	if err != nil {
+		return fmt.Errorf("unable to append audit row: %v", err)
+	}
 	return nil
 }
 
+// ensureAuxiliarySheet creates a non-channel tab (settings, audit...) with its header row if it does not exist yet
+// and reports whether it was created
+func (c *Client) ensureAuxiliarySheet(spreadsheetID, sheetName string, headers []interface{}) (bool, error) {
+	spreadsheet, err := c.service.Spreadsheets.Get(spreadsheetID).Do()
+	if err != nil {
+		return false, fmt.Errorf("unable to get spreadsheet: %v", err)
+	}
+	rememberSheetIDs(spreadsheetID, spreadsheet.Sheets)
+
+	for _, sheet := range spreadsheet.Sheets {
+		if sheet.Properties.Title == sheetName {
+			return false, nil
+		}
+	}
+
+	log.Printf("Creating sheet: '%s'", sheetName)
+	createRequest := &sheets.BatchUpdateSpreadsheetRequest{
+		Requests: []*sheets.Request{
+			{
+				AddSheet: &sheets.AddSheetRequest{
+					Properties: &sheets.SheetProperties{
+						Title: sheetName,
+					},
+				},
+			},
+		},
+	}
+	response, err := c.service.Spreadsheets.BatchUpdate(spreadsheetID, createRequest).Do()
+	if err != nil {
+		return false, fmt.Errorf("unable to create sheet %s: %v", sheetName, err)
+	}
+	rememberAddedSheet(spreadsheetID, response)
+
+	headerRange := &sheets.ValueRange{
+		Values: [][]interface{}{headers},
+	}
+	_, err = c.service.Spreadsheets.Values.Update(
+		spreadsheetID,
+		fmt.Sprintf("%s!A1:%s1", sheetName, columnLetter(len(headers))),
+		headerRange,
+	).ValueInputOption("RAW").Do()
+	if err != nil {
+		return true, fmt.Errorf("unable to write headers of sheet %s: %v", sheetName, err)
+	}
+	return true, nil
+}
+
 // GetSheetID gets the sheet ID (gid) for a specific sheet name
 func (c *Client) GetSheetID(spreadsheetID, sheetName string) (int64, error) {
+	if sheetID, exists := cachedSheetID(spreadsheetID, sheetName); exists {
+		return sheetID, nil
+	}
+
 	var sheetID int64
 	var err error
 
@@ -918,6 +1957,7 @@ func (c *Client) GetSheetID(spreadsheetID, sheetName string) (int64, error) {
 		if getErr != nil {
 			return fmt.Errorf("unable to get spreadsheet: %v", getErr)
 		}
+		rememberSheetIDs(spreadsheetID, spreadsheet.Sheets)
 
 		// Find the sheet by name
 		for _, sheet := range spreadsheet.Sheets {
@@ -957,3 +1997,185 @@ func (c *Client) ShareSpreadsheet(spreadsheetID, email string) error {
 		return nil
 	}, fmt.Sprintf("share spreadsheet with %s", email))
 }
+
+// Permission is one Drive sharing grant of a spreadsheet
+type Permission struct {
+	Type         string // user, group, domain or anyone
+	Role         string // owner, writer, commenter or reader
+	EmailAddress string
+	Domain       string
+	Deleted      bool // The account was deleted but the grant remains
+}
+
+// ListPermissions returns who the spreadsheet is shared with
+func (c *Client) ListPermissions(spreadsheetID string) ([]Permission, error) {
+	var result []Permission
+	err := retryWithBackoff(func() error {
+		result = nil
+		pageToken := ""
+		for {
+			call := c.driveService.Permissions.List(spreadsheetID).
+				Fields("nextPageToken, permissions(type, role, emailAddress, domain, deleted)").
+				SupportsAllDrives(true)
+			if pageToken != "" {
+				call = call.PageToken(pageToken)
+			}
+			list, err := call.Do()
+			if err != nil {
+				return err
+			}
+			for _, permission := range list.Permissions {
+				result = append(result, Permission{
+					Type:         permission.Type,
+					Role:         permission.Role,
+					EmailAddress: permission.EmailAddress,
+					Domain:       permission.Domain,
+					Deleted:      permission.Deleted,
+				})
+			}
+			if list.NextPageToken == "" {
+				return nil
+			}
+			pageToken = list.NextPageToken
+		}
+	}, fmt.Sprintf("list permissions of spreadsheet %s", spreadsheetID))
+	if err != nil {
+		return nil, fmt.Errorf("unable to list permissions: %v", err)
+	}
+	return result, nil
+}
+
+// HasChannelSheet reports whether the spreadsheet has a tab for the channel
+func (c *Client) HasChannelSheet(spreadsheetID, channelID string) (bool, error) {
+	spreadsheet, err := c.service.Spreadsheets.Get(spreadsheetID).Do()
+	if err != nil {
+		return false, fmt.Errorf("unable to get spreadsheet: %v", err)
+	}
+	rememberSheetIDs(spreadsheetID, spreadsheet.Sheets)
+
+	for _, sheet := range spreadsheet.Sheets {
+		if isConversationSheet(sheet.Properties.Title, channelID) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// ChannelSheets returns the channel ID and tab name of every "<channel name>-<channel ID>" tab of the spreadsheet;
+// direct messages named after their participants are not included since their titles carry no ID
+func (c *Client) ChannelSheets(spreadsheetID string) (map[string]string, error) {
+	spreadsheet, err := c.service.Spreadsheets.Get(spreadsheetID).Do()
+	if err != nil {
+		return nil, fmt.Errorf("unable to get spreadsheet: %v", err)
+	}
+	rememberSheetIDs(spreadsheetID, spreadsheet.Sheets)
+
+	channelSheets := make(map[string]string)
+	for _, sheet := range spreadsheet.Sheets {
+		title := sheet.Properties.Title
+		if channelSheetPattern.MatchString(title) {
+			channelSheets[title[strings.LastIndex(title, "-")+1:]] = title
+		}
+	}
+	return channelSheets, nil
+}
+
+// SpreadsheetUsage returns the number of tabs and the number of cells (rows x columns of every tab) of a spreadsheet
+func (c *Client) SpreadsheetUsage(spreadsheetID string) (int, int64, error) {
+	spreadsheet, err := c.service.Spreadsheets.Get(spreadsheetID).Do()
+	if err != nil {
+		return 0, 0, fmt.Errorf("unable to get spreadsheet: %v", err)
+	}
+
+	var cells int64
+	for _, sheet := range spreadsheet.Sheets {
+		if grid := sheet.Properties.GridProperties; grid != nil {
+			cells += grid.RowCount * grid.ColumnCount
+		}
+	}
+	return len(spreadsheet.Sheets), cells, nil
+}
+
+// CreateContinuation creates an empty spreadsheet named after the original with a "(number)" suffix
+// in the same Drive folder and shares it with the same users, groups and domains as the original
+func (c *Client) CreateContinuation(originalID string, number int) (string, error) {
+	original, err := c.driveService.Files.Get(originalID).Fields("name", "parents").SupportsAllDrives(true).Do()
+	if err != nil {
+		return "", fmt.Errorf("unable to get original spreadsheet: %v", err)
+	}
+
+	created, err := c.driveService.Files.Create(&drive.File{
+		Name:     fmt.Sprintf("%s (%d)", original.Name, number),
+		MimeType: "application/vnd.google-apps.spreadsheet",
+		Parents:  original.Parents,
+	}).SupportsAllDrives(true).Do()
+	if err != nil {
+		return "", fmt.Errorf("unable to create continuation spreadsheet: %v", err)
+	}
+
+	permissions, err := c.driveService.Permissions.List(originalID).
+		Fields("permissions(type,role,emailAddress,domain)").SupportsAllDrives(true).Do()
+	if err != nil {
+		log.Printf("Warning: Could not read permissions of spreadsheet %s: %v", originalID, err)
+		return created.Id, nil
+	}
+	for _, permission := range permissions.Permissions {
+		if permission.Role == "owner" || permission.Type == "anyone" {
+			continue
+		}
+		copied := &drive.Permission{Type: permission.Type, Role: permission.Role, EmailAddress: permission.EmailAddress, Domain: permission.Domain}
+		if _, err := c.driveService.Permissions.Create(created.Id, copied).SendNotificationEmail(false).SupportsAllDrives(true).Do(); err != nil {
+			log.Printf("Warning: Could not share continuation spreadsheet with %s%s: %v", permission.EmailAddress, permission.Domain, err)
+		}
+	}
+	return created.Id, nil
+}
+
+// spreadsheetIndexHeaders is the header row of the tab listing continuation spreadsheets
+var spreadsheetIndexHeaders = []interface{}{"作成日時", "スプレッドシート", "備考"}
+
+// AppendSpreadsheetIndexRow links a continuation spreadsheet from the index tab of the original, creating the tab if missing
+func (c *Client) AppendSpreadsheetIndexRow(spreadsheetID, sheetName, continuationID, note string) error {
+	if _, err := c.ensureAuxiliarySheet(spreadsheetID, sheetName, spreadsheetIndexHeaders); err != nil {
+		return err
+	}
+
+	row := []interface{}{
+		time.Now().Format("2006-01-02 15:04:05"),
+		fmt.Sprintf(`=HYPERLINK("https://docs.google.com/spreadsheets/d/%s/edit", "%s")`, continuationID, continuationID),
+		note,
+	}
+	_, err := c.service.Spreadsheets.Values.Append(
+		spreadsheetID,
+		fmt.Sprintf("%s!A:%s", sheetName, columnLetter(len(spreadsheetIndexHeaders))),
+		&sheets.ValueRange{Values: [][]interface{}{row}},
+	).ValueInputOption("USER_ENTERED").Do()
+	if err != nil {
+		return fmt.Errorf("unable to append spreadsheet index row: %v", err)
+	}
+	return nil
+}
+
+// completenessHeaders is the header row of the tab with daily received vs recorded counts
+var completenessHeaders = []interface{}{"日付", "チャンネルID", "受信イベント数", "記録数", "スキップ数", "失敗数", "記録率"}
+
+// AppendCompletenessRows appends daily per-channel counts (date, channel ID, received, recorded, skipped, failed, ratio),
+// creating the tab if missing
+func (c *Client) AppendCompletenessRows(spreadsheetID, sheetName string, rows [][]interface{}) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	if _, err := c.ensureAuxiliarySheet(spreadsheetID, sheetName, completenessHeaders); err != nil {
+		return err
+	}
+
+	_, err := c.service.Spreadsheets.Values.Append(
+		spreadsheetID,
+		fmt.Sprintf("%s!A:%s", sheetName, columnLetter(len(completenessHeaders))),
+		&sheets.ValueRange{Values: rows},
+	).ValueInputOption("RAW").Do()
+	if err != nil {
+		return fmt.Errorf("unable to append completeness rows: %v", err)
+	}
+	return nil
+}
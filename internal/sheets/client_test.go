@@ -0,0 +1,204 @@
+package sheets
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// testRecords returns n messages of one channel, one minute apart
+func testRecords(n int) []*MessageRecord {
+	start := time.Date(2025, 6, 1, 9, 0, 0, 0, time.UTC)
+	records := make([]*MessageRecord, n)
+	for i := range records {
+		records[i] = &MessageRecord{
+			Timestamp:    start.Add(time.Duration(i) * time.Minute),
+			Channel:      "C0TEST",
+			ChannelName:  "general",
+			User:         "U0TEST",
+			UserHandle:   "alice",
+			UserRealName: "Alice",
+			Text:         fmt.Sprintf("message %d", i+1),
+			MessageTS:    fmt.Sprintf("%d.000100", start.Unix()+int64(i)*60),
+		}
+	}
+	return records
+}
+
+func TestWriteBatchMessagesPartialWrites(t *testing.T) {
+	tests := []struct {
+		name        string
+		records     int
+		failChunk   func(appended int) bool
+		wantWritten int
+		wantFailed  int
+		wantAppends int
+	}{
+		{
+			name:        "single chunk",
+			records:     30,
+			wantWritten: 30,
+			wantAppends: 1,
+		},
+		{
+			name:        "every chunk written",
+			records:     120,
+			wantWritten: 120,
+			wantAppends: 3,
+		},
+		{
+			name:        "first chunk fails",
+			records:     120,
+			failChunk:   func(appended int) bool { return appended == 0 },
+			wantWritten: 0,
+			wantFailed:  120,
+			wantAppends: maxRetryAttempts,
+		},
+		{
+			name:        "second chunk fails, later chunks are not written",
+			records:     120,
+			failChunk:   func(appended int) bool { return appended == 1 },
+			wantWritten: writeChunkSize,
+			wantFailed:  120 - writeChunkSize,
+			wantAppends: 1 + maxRetryAttempts,
+		},
+		{
+			name:        "last chunk fails",
+			records:     120,
+			failChunk:   func(appended int) bool { return appended == 2 },
+			wantWritten: 2 * writeChunkSize,
+			wantFailed:  120 - 2*writeChunkSize,
+			wantAppends: 2 + maxRetryAttempts,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backend := newFakeBackend(t)
+			backend.failChunk = tt.failChunk
+			records := testRecords(tt.records)
+
+			err := backend.client(t).WriteBatchMessages("spreadsheet", records)
+
+			if tt.wantFailed == 0 {
+				if err != nil {
+					t.Fatalf("WriteBatchMessages: %v", err)
+				}
+			} else {
+				var partial *PartialWriteError
+				if !errors.As(err, &partial) {
+					t.Fatalf("WriteBatchMessages error = %v, want *PartialWriteError", err)
+				}
+				if partial.Written != tt.wantWritten || len(partial.Failed) != tt.wantFailed {
+					t.Fatalf("written %d, failed %d; want %d, %d", partial.Written, len(partial.Failed), tt.wantWritten, tt.wantFailed)
+				}
+				// The failed records are the unwritten tail, in order, so a retry appends them after the written rows
+				for i, record := range partial.Failed {
+					if record != records[tt.wantWritten+i] {
+						t.Fatalf("failed record %d is %s, want %s", i, record.MessageTS, records[tt.wantWritten+i].MessageTS)
+					}
+				}
+			}
+
+			rows := backend.rows(SheetName("C0TEST", "general"))
+			if len(rows)-1 != tt.wantWritten {
+				t.Fatalf("sheet has %d data rows, want %d", len(rows)-1, tt.wantWritten)
+			}
+			if backend.appends != tt.wantAppends {
+				t.Fatalf("append calls = %d, want %d", backend.appends, tt.wantAppends)
+			}
+		})
+	}
+}
+
+func TestWriteBatchMessagesRetriesTransientFailure(t *testing.T) {
+	backend := newFakeBackend(t)
+	failures := 0
+	backend.failChunk = func(appended int) bool {
+		if appended == 1 && failures < maxRetryAttempts-1 {
+			failures++
+			return true
+		}
+		return false
+	}
+
+	if err := backend.client(t).WriteBatchMessages("spreadsheet", testRecords(120)); err != nil {
+		t.Fatalf("WriteBatchMessages: %v", err)
+	}
+	if rows := backend.rows(SheetName("C0TEST", "general")); len(rows)-1 != 120 {
+		t.Fatalf("sheet has %d data rows, want 120", len(rows)-1)
+	}
+}
+
+func TestWriteBatchMessagesRetryAfterPartialWrite(t *testing.T) {
+	backend := newFakeBackend(t)
+	backend.failChunk = func(appended int) bool { return appended == 1 }
+	client := backend.client(t)
+
+	var partial *PartialWriteError
+	if err := client.WriteBatchMessages("spreadsheet", testRecords(120)); !errors.As(err, &partial) {
+		t.Fatalf("WriteBatchMessages error = %v, want *PartialWriteError", err)
+	}
+
+	// Writing the failed records again continues the No. of the written rows without duplicating any of them
+	backend.failChunk = nil
+	if err := client.WriteBatchMessages("spreadsheet", partial.Failed); err != nil {
+		t.Fatalf("WriteBatchMessages retry: %v", err)
+	}
+	rows := backend.rows(SheetName("C0TEST", "general"))
+	if len(rows)-1 != 120 {
+		t.Fatalf("sheet has %d data rows, want 120", len(rows)-1)
+	}
+	for i, row := range rows[1:] {
+		if number := existingRowNumber(row, -1); number != i+1 {
+			t.Fatalf("row %d has No. %d, want %d", i+2, number, i+1)
+		}
+	}
+}
+
+func TestUpdateMessagesEditHistory(t *testing.T) {
+	tests := []struct {
+		name        string
+		redacted    bool
+		text        string
+		wantHistory string
+	}{
+		{"edited", false, "message 1 (edited)", fmt.Sprintf(editRevisionPrefix, 1) + "message 1"},
+		{"unchanged text", false, "message 1", ""},
+		// The earlier text in the sheet is the content redaction removes, so it must not move into the history
+		{"redacted", true, "[message by opted-out user]", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backend := newFakeBackend(t)
+			client := backend.client(t)
+			client.AddColumns(ColumnEditHistory)
+
+			original := testRecords(1)[0]
+			sheetName := SheetName(original.Channel, original.ChannelName)
+			backend.setRows(sheetName, [][]interface{}{client.headers(), client.buildRow(1, original, "")})
+
+			updated := *original
+			updated.Text = tt.text
+			updated.Redacted = tt.redacted
+			rows, err := client.UpdateMessages("spreadsheet", []*MessageRecord{&updated})
+			if err != nil {
+				t.Fatalf("UpdateMessages: %v", err)
+			}
+			if rows[original.MessageTS] != 2 {
+				t.Fatalf("updated rows = %v, want %s in row 2", rows, original.MessageTS)
+			}
+
+			row := backend.rows(sheetName)[1]
+			historyColumn := client.columnNumber(ColumnEditHistory)
+			if got := fmt.Sprint(row[historyColumn-1]); got != tt.wantHistory {
+				t.Fatalf("edit history = %q, want %q", got, tt.wantHistory)
+			}
+			if got := fmt.Sprint(row[textColumnIndex]); got != tt.text {
+				t.Fatalf("text = %q, want %q", got, tt.text)
+			}
+		})
+	}
+}
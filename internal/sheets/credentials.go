@@ -0,0 +1,180 @@
+package sheets
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/option"
+	"google.golang.org/api/sheets/v4"
+	htransport "google.golang.org/api/transport/http"
+)
+
+// spreadsheetCredentials maps spreadsheet IDs to the credentials (JSON content or file path) used for them
+var spreadsheetCredentials map[string]string
+
+// SetSpreadsheetCredentials selects other service accounts for some spreadsheets (e.g. one per department);
+// spreadsheets not in the map use the credentials passed to NewClient
+func SetSpreadsheetCredentials(credentials map[string]string) {
+	spreadsheetCredentials = credentials
+}
+
+// isCredentialsFile reports whether credentials are a file path rather than JSON content: shorter than 512 chars,
+// ending with .json and not starting with {
+func isCredentialsFile(credentials string) bool {
+	return len(credentials) < 512 &&
+		strings.HasSuffix(credentials, ".json") &&
+		!strings.HasPrefix(strings.TrimSpace(credentials), "{")
+}
+
+// credentialsTransport returns an authenticated transport for credentials given as JSON content or as a file path
+func credentialsTransport(credentials string) (http.RoundTripper, error) {
+	if isCredentialsFile(credentials) {
+		// It's likely a file path; the file is watched so a rotated key is picked up without a restart
+		return getRotatingTransport(credentials)
+	}
+
+	// It's JSON content
+	log.Printf("Using credentials as JSON content (%d bytes)", len(credentials))
+	return newAuthenticatedTransport([]byte(credentials))
+}
+
+// spreadsheetRoutingTransport sends requests for mapped spreadsheets with their own credentials
+type spreadsheetRoutingTransport struct {
+	fallback      http.RoundTripper
+	bySpreadsheet map[string]http.RoundTripper
+}
+
+// RoundTrip picks the transport by the spreadsheet ID in the Sheets (/v4/spreadsheets/<id>) or Drive (/files/<id>) URL
+func (t *spreadsheetRoutingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if transport, exists := t.bySpreadsheet[spreadsheetIDFromPath(req.URL.Path)]; exists {
+		return transport.RoundTrip(req)
+	}
+	return t.fallback.RoundTrip(req)
+}
+
+// spreadsheetIDFromPath extracts the spreadsheet (file) ID from a Sheets or Drive API path
+func spreadsheetIDFromPath(path string) string {
+	for _, prefix := range []string{"/v4/spreadsheets/", "/files/"} {
+		index := strings.Index(path, prefix)
+		if index < 0 {
+			continue
+		}
+		id := path[index+len(prefix):]
+		if end := strings.IndexAny(id, "/:"); end >= 0 {
+			id = id[:end]
+		}
+		return id
+	}
+	return ""
+}
+
+// credentialsCheckInterval is how often a credentials file is checked for a rotated key
+const credentialsCheckInterval = 30 * time.Second
+
+// rotatingTransport authenticates requests with the service account key currently in a credentials file.
+// When the file changes (e.g. a mounted secret after quarterly key rotation), the authenticated transport is
+// rebuilt so clients that live for a whole backfill switch to the new key without a restart.
+type rotatingTransport struct {
+	path string
+
+	mutex       sync.Mutex
+	credentials []byte
+	modTime     time.Time
+	size        int64
+	lastCheck   time.Time
+	current     http.RoundTripper
+}
+
+// rotatingTransports shares one transport per credentials file between all clients
+var (
+	rotatingTransports      = make(map[string]*rotatingTransport)
+	rotatingTransportsMutex sync.Mutex
+)
+
+// getRotatingTransport returns the shared transport for a credentials file, loading the key on first use
+func getRotatingTransport(path string) (*rotatingTransport, error) {
+	rotatingTransportsMutex.Lock()
+	defer rotatingTransportsMutex.Unlock()
+
+	if transport, exists := rotatingTransports[path]; exists {
+		return transport, nil
+	}
+
+	transport := &rotatingTransport{path: path}
+	if err := transport.reload(); err != nil {
+		return nil, err
+	}
+	rotatingTransports[path] = transport
+	return transport, nil
+}
+
+// reload reads the credentials file and rebuilds the authenticated transport if the key changed;
+// callers must hold the mutex (or own the transport exclusively)
+func (t *rotatingTransport) reload() error {
+	info, err := os.Stat(t.path)
+	if err != nil {
+		return fmt.Errorf("unable to read credentials file '%s': %v", t.path, err)
+	}
+	t.lastCheck = time.Now()
+	if t.current != nil && info.ModTime().Equal(t.modTime) && info.Size() == t.size {
+		return nil
+	}
+
+	credentials, err := os.ReadFile(t.path)
+	if err != nil {
+		return fmt.Errorf("unable to read credentials file '%s': %v", t.path, err)
+	}
+	if t.current != nil && bytes.Equal(credentials, t.credentials) {
+		t.modTime, t.size = info.ModTime(), info.Size()
+		return nil
+	}
+
+	authenticated, err := newAuthenticatedTransport(credentials)
+	if err != nil {
+		return err
+	}
+
+	if t.current != nil {
+		log.Printf("Credentials file %s changed, switched to the rotated service account key", t.path)
+	} else {
+		log.Printf("Read credentials from file: %s (%d bytes)", t.path, len(credentials))
+	}
+	t.credentials = credentials
+	t.modTime, t.size = info.ModTime(), info.Size()
+	t.current = authenticated
+	return nil
+}
+
+// RoundTrip sends the request with the current key, checking the file for a rotated key at most every credentialsCheckInterval
+func (t *rotatingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.mutex.Lock()
+	if time.Since(t.lastCheck) >= credentialsCheckInterval {
+		// A half-written or invalid file keeps the previous key until the next check
+		if err := t.reload(); err != nil {
+			log.Printf("Warning: Could not reload credentials, keeping the current key: %v", err)
+		}
+	}
+	current := t.current
+	t.mutex.Unlock()
+
+	return current.RoundTrip(req)
+}
+
+// newAuthenticatedTransport builds a transport that authenticates with a service account key
+func newAuthenticatedTransport(credentials []byte) (http.RoundTripper, error) {
+	httpClient, _, err := htransport.NewClient(context.Background(),
+		option.WithCredentialsJSON(credentials),
+		option.WithScopes(sheets.SpreadsheetsScope, drive.DriveScope))
+	if err != nil {
+		return nil, fmt.Errorf("unable to create authenticated HTTP client: %v", err)
+	}
+	return httpClient.Transport, nil
+}
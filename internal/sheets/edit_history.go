@@ -0,0 +1,31 @@
+package sheets
+
+import (
+	"fmt"
+	"strings"
+)
+
+// editRevisionPrefix starts each earlier text in the edit history cell, numbered from the original text ("[版1] ")
+const editRevisionPrefix = "[版%d] "
+
+// editHistory returns the edit history cell of a recorded row with the row's current text appended, when an edit
+// changes it to newText. Edits that leave the text as it is (e.g. Slack adding a link preview) keep the history.
+func editHistory(row []interface{}, historyColumn int, newText string) string {
+	cell := func(index int) string {
+		if index < len(row) {
+			return fmt.Sprintf("%v", row[index])
+		}
+		return ""
+	}
+	history := cell(historyColumn - 1)
+	previous := cell(textColumnIndex)
+	if previous == "" || previous == newText {
+		return history
+	}
+
+	if history == "" {
+		return fmt.Sprintf(editRevisionPrefix, 1) + previous
+	}
+	revisions := strings.Count(history, "\n[版") + 1
+	return history + "\n" + fmt.Sprintf(editRevisionPrefix, revisions+1) + previous
+}
@@ -0,0 +1,173 @@
+package sheets
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/api/sheets/v4"
+)
+
+// fakeBackend is a minimal in-memory Sheets API with one spreadsheet, enough for the write and update paths
+type fakeBackend struct {
+	mutex     sync.Mutex
+	tabs      map[string][][]interface{}
+	tabIDs    map[string]int64
+	tabOrder  []string
+	appends   int // Append calls received, including failed ones
+	appended  int // Append calls that were applied
+	failChunk func(appended int) bool
+}
+
+// newFakeBackend starts a fake backend, points NewClient at it and retries without waiting, all for one test
+func newFakeBackend(t *testing.T) *fakeBackend {
+	t.Helper()
+	backend := &fakeBackend{tabs: make(map[string][][]interface{}), tabIDs: make(map[string]int64)}
+	server := httptest.NewServer(backend)
+
+	previousEndpoint, previousDelay := apiEndpoint, retryDelayUnit
+	SetAPIEndpoint(server.URL)
+	retryDelayUnit = time.Millisecond
+	t.Cleanup(func() {
+		server.Close()
+		apiEndpoint, retryDelayUnit = previousEndpoint, previousDelay
+	})
+	return backend
+}
+
+// client returns a sheets client talking to the backend
+func (f *fakeBackend) client(t *testing.T) *Client {
+	t.Helper()
+	client, err := NewClient("")
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	return client
+}
+
+// rows returns a copy of the rows of a tab, header included
+func (f *fakeBackend) rows(title string) [][]interface{} {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return append([][]interface{}{}, f.tabs[title]...)
+}
+
+// setRows replaces the rows of a tab, creating it if needed
+func (f *fakeBackend) setRows(title string, rows [][]interface{}) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.addTab(title)
+	f.tabs[title] = rows
+}
+
+// addTab creates a tab if it does not exist yet; callers must hold the mutex
+func (f *fakeBackend) addTab(title string) {
+	if _, exists := f.tabIDs[title]; exists {
+		return
+	}
+	f.tabIDs[title] = int64(100 + len(f.tabOrder))
+	f.tabOrder = append(f.tabOrder, title)
+}
+
+// ServeHTTP routes the Sheets API calls the client makes
+func (f *fakeBackend) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	rest := strings.TrimPrefix(r.URL.Path, "/v4/spreadsheets/")
+	spreadsheetID, valuesRange, hasValues := strings.Cut(rest, "/values/")
+	switch {
+	case strings.HasSuffix(spreadsheetID, "/values:batchUpdate"):
+		var request sheets.BatchUpdateValuesRequest
+		json.NewDecoder(r.Body).Decode(&request)
+		for _, valueRange := range request.Data {
+			f.writeRows(valueRange.Range, valueRange.Values)
+		}
+		writeFakeJSON(w, http.StatusOK, &sheets.BatchUpdateValuesResponse{})
+	case strings.HasSuffix(spreadsheetID, ":batchUpdate"):
+		var request sheets.BatchUpdateSpreadsheetRequest
+		json.NewDecoder(r.Body).Decode(&request)
+		response := &sheets.BatchUpdateSpreadsheetResponse{}
+		for _, req := range request.Requests {
+			reply := &sheets.Response{}
+			if req.AddSheet != nil {
+				f.addTab(req.AddSheet.Properties.Title)
+				reply.AddSheet = &sheets.AddSheetResponse{Properties: &sheets.SheetProperties{
+					SheetId: f.tabIDs[req.AddSheet.Properties.Title],
+					Title:   req.AddSheet.Properties.Title,
+				}}
+			}
+			response.Replies = append(response.Replies, reply)
+		}
+		writeFakeJSON(w, http.StatusOK, response)
+	case !hasValues:
+		spreadsheet := &sheets.Spreadsheet{SpreadsheetId: spreadsheetID}
+		for _, title := range f.tabOrder {
+			spreadsheet.Sheets = append(spreadsheet.Sheets, &sheets.Sheet{
+				Properties: &sheets.SheetProperties{SheetId: f.tabIDs[title], Title: title},
+			})
+		}
+		writeFakeJSON(w, http.StatusOK, spreadsheet)
+	case strings.HasSuffix(valuesRange, ":append"):
+		f.appends++
+		if f.failChunk != nil && f.failChunk(f.appended) {
+			writeFakeJSON(w, http.StatusBadRequest, map[string]interface{}{"error": map[string]interface{}{"code": 400, "message": "injected failure"}})
+			return
+		}
+		f.appended++
+		var valueRange sheets.ValueRange
+		json.NewDecoder(r.Body).Decode(&valueRange)
+		title := fakeTabTitle(strings.TrimSuffix(valuesRange, ":append"))
+		f.addTab(title)
+		f.tabs[title] = append(f.tabs[title], valueRange.Values...)
+		writeFakeJSON(w, http.StatusOK, &sheets.AppendValuesResponse{})
+	case r.Method == http.MethodGet:
+		title := fakeTabTitle(valuesRange)
+		f.addTab(title)
+		writeFakeJSON(w, http.StatusOK, &sheets.ValueRange{Range: valuesRange, Values: f.tabs[title]})
+	case r.Method == http.MethodPut:
+		var valueRange sheets.ValueRange
+		json.NewDecoder(r.Body).Decode(&valueRange)
+		f.writeRows(valuesRange, valueRange.Values)
+		writeFakeJSON(w, http.StatusOK, &sheets.UpdateValuesResponse{})
+	default:
+		writeFakeJSON(w, http.StatusNotImplemented, map[string]interface{}{"error": map[string]interface{}{"code": 501, "message": "not supported by the fake"}})
+	}
+}
+
+// writeRows overwrites rows starting at the first row of an A1 range; callers must hold the mutex
+func (f *fakeBackend) writeRows(a1Range string, rows [][]interface{}) {
+	title := fakeTabTitle(a1Range)
+	f.addTab(title)
+
+	startRow := 1
+	_, cells, _ := strings.Cut(a1Range, "!")
+	start, _, _ := strings.Cut(cells, ":")
+	if digits := strings.TrimLeft(start, "ABCDEFGHIJKLMNOPQRSTUVWXYZ"); digits != "" {
+		fmt.Sscanf(digits, "%d", &startRow)
+	}
+	for i, row := range rows {
+		index := startRow - 1 + i
+		for len(f.tabs[title]) <= index {
+			f.tabs[title] = append(f.tabs[title], []interface{}{})
+		}
+		f.tabs[title][index] = row
+	}
+}
+
+// fakeTabTitle returns the tab of an A1 range such as "'name'!A2:G"
+func fakeTabTitle(a1Range string) string {
+	title, _, _ := strings.Cut(a1Range, "!")
+	return strings.Trim(title, "'")
+}
+
+func writeFakeJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(value)
+}
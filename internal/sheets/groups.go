@@ -0,0 +1,85 @@
+package sheets
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"golang.org/x/oauth2/google"
+	admin "google.golang.org/api/admin/directory/v1"
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/option"
+)
+
+// ShareWithGroup grants a Google Group read access to a spreadsheet, so access is managed by the group's members
+func (c *Client) ShareWithGroup(spreadsheetID, groupEmail string) error {
+	return retryWithBackoff(func() error {
+		permission := &drive.Permission{
+			Role:         "reader",
+			Type:         "group",
+			EmailAddress: groupEmail,
+		}
+
+		_, err := c.driveService.Permissions.Create(spreadsheetID, permission).SendNotificationEmail(false).SupportsAllDrives(true).Do()
+		if err != nil {
+			if strings.Contains(err.Error(), "Permission already exists") ||
+				strings.Contains(err.Error(), "already has access") {
+				return nil
+			}
+			return fmt.Errorf("unable to share spreadsheet with group: %v", err)
+		}
+
+		log.Printf("Granted reader access to group %s for spreadsheet %s", groupEmail, spreadsheetID)
+		return nil
+	}, fmt.Sprintf("share spreadsheet with group %s", groupEmail))
+}
+
+// GroupClient adds members to Google Groups through the Admin SDK Directory API
+type GroupClient struct {
+	service *admin.Service
+}
+
+// NewGroupClient creates a Directory API client acting as a Workspace admin through the domain-wide delegation of
+// the service account (credentials as JSON content or a file path), with the group member scope only
+func NewGroupClient(credentials, adminEmail string) (*GroupClient, error) {
+	credentialsJSON := []byte(credentials)
+	if isCredentialsFile(credentials) {
+		data, err := os.ReadFile(credentials)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read credentials file '%s': %v", credentials, err)
+		}
+		credentialsJSON = data
+	}
+
+	jwtConfig, err := google.JWTConfigFromJSON(credentialsJSON, admin.AdminDirectoryGroupMemberScope)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse service account credentials: %v", err)
+	}
+	jwtConfig.Subject = adminEmail
+
+	ctx := context.Background()
+	service, err := admin.NewService(ctx, option.WithHTTPClient(jwtConfig.Client(ctx)))
+	if err != nil {
+		return nil, fmt.Errorf("unable to create directory service: %v", err)
+	}
+	return &GroupClient{service: service}, nil
+}
+
+// AddMember adds a user to a group; a user who is already a member is not an error
+func (g *GroupClient) AddMember(groupEmail, email string) error {
+	return retryWithBackoff(func() error {
+		_, err := g.service.Members.Insert(groupEmail, &admin.Member{Email: email, Role: "MEMBER"}).Do()
+		if err != nil {
+			if strings.Contains(err.Error(), "Member already exists") {
+				log.Printf("%s is already a member of group %s", email, groupEmail)
+				return nil
+			}
+			return fmt.Errorf("unable to add member to group: %v", err)
+		}
+
+		log.Printf("Added %s to group %s", email, groupEmail)
+		return nil
+	}, fmt.Sprintf("add %s to group %s", email, groupEmail))
+}
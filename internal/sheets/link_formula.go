@@ -0,0 +1,93 @@
+package sheets
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// maxLinkFormulaText is the longest message text turned into a HYPERLINK formula; Sheets rejects formulas over
+// 50,000 characters, and the link target and quoting need room too
+const maxLinkFormulaText = 40000
+
+// textColumnIndex is the 0-based index of the message text in a row built by buildRow
+const textColumnIndex = 4
+
+// textHeader is the header of the message text column
+var textHeader = baseHeaders[textColumnIndex].(string)
+
+// linkURLRe matches the web links left in a formatted message text
+var linkURLRe = regexp.MustCompile(`https?://\S+`)
+
+// UseLinkFormulas writes the text cell of a message containing exactly one link as a HYPERLINK formula, so the cell
+// opens the link when clicked. Rows are then written with USER_ENTERED, and every other string cell is marked as
+// plain text with a leading apostrophe so that text that looks like a number, date or formula is kept as it is.
+func (c *Client) UseLinkFormulas() {
+	c.linkFormulas = true
+}
+
+// rowInputOption returns the value input option for writing rows built by buildRow
+func (c *Client) rowInputOption() string {
+	if c.linkFormulas {
+		return "USER_ENTERED"
+	}
+	return "RAW"
+}
+
+// userEnteredRow prepares a row built by buildRow for USER_ENTERED: the text cell becomes a HYPERLINK formula when it
+// has exactly one link, and the other strings are kept as text
+func (c *Client) userEnteredRow(row []interface{}) []interface{} {
+	if !c.linkFormulas {
+		return row
+	}
+	for i, value := range row {
+		text, isString := value.(string)
+		if !isString || text == "" {
+			continue
+		}
+		if i == textColumnIndex {
+			if formula := hyperlinkFormula(text); formula != "" {
+				row[i] = formula
+				continue
+			}
+		}
+		row[i] = "'" + text
+	}
+	return row
+}
+
+// hyperlinkFormula returns a HYPERLINK formula showing text and opening its only link, or "" when text has no link,
+// several different links or is too long
+func hyperlinkFormula(text string) string {
+	if len(text) > maxLinkFormulaText {
+		return ""
+	}
+
+	link := ""
+	for _, match := range linkURLRe.FindAllString(text, -1) {
+		match = trimLinkPunctuation(match)
+		if link != "" && match != link {
+			return ""
+		}
+		link = match
+	}
+	if link == "" {
+		return ""
+	}
+	return fmt.Sprintf(`=HYPERLINK("%s", "%s")`, strings.ReplaceAll(link, `"`, `""`), strings.ReplaceAll(text, `"`, `""`))
+}
+
+// trimLinkPunctuation drops the closing parenthesis or punctuation that follows a link in running text, keeping
+// parentheses that belong to the URL (e.g. Wikipedia links)
+func trimLinkPunctuation(link string) string {
+	for {
+		trimmed := strings.TrimRight(link, ".,;:!?")
+		if strings.HasSuffix(trimmed, ")") && strings.Count(trimmed, ")") > strings.Count(trimmed, "(") {
+			trimmed = strings.TrimSuffix(trimmed, ")")
+		}
+		if trimmed == link {
+			return link
+		}
+		link = trimmed
+	}
+}
@@ -0,0 +1,121 @@
+package sheets
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/api/sheets/v4"
+)
+
+// manifestHeaders is the header row of the export manifest tab
+var manifestHeaders = []interface{}{
+	"記録日時", "チャンネルID", "シート名", "種類", "トレースID", "ツールバージョン", "パラメータ",
+	"最初のメッセージ", "最後のメッセージ", "今回の記録数", "シートの行数", "SHA-256",
+}
+
+// Manifest describes one completed export of a channel: what produced it and what its tab contained afterwards
+type Manifest struct {
+	ChannelID  string
+	SheetName  string
+	Kind       string // initial, reset, continuation...
+	TraceID    string
+	Version    string
+	Parameters string // JSON of the settings that shaped the rows
+	Recorded   int    // Rows written by this export
+	TabDigest
+}
+
+// TabDigest summarizes the data rows of a channel tab so its content can be verified later
+type TabDigest struct {
+	Rows        int
+	FirstPosted string
+	LastPosted  string
+	SHA256      string
+}
+
+// DigestChannelSheet reads a channel tab and returns its row count, the posted times of its oldest and newest
+// messages and the SHA-256 of its data rows. Each row is hashed as its displayed cells joined by tabs and
+// terminated by a newline, in sheet order (trailing empty cells are left out, as the Sheets API returns rows), so the
+// checksum can be recomputed from a download of the tab.
+func (c *Client) DigestChannelSheet(spreadsheetID, sheetName string) (TabDigest, error) {
+	sheetData, err := c.getSheetData(spreadsheetID, sheetName)
+	if err != nil {
+		return TabDigest{}, fmt.Errorf("failed to get sheet data: %v", err)
+	}
+
+	var digest TabDigest
+	hash := sha256.New()
+	oldest, newest := 0.0, 0.0
+	for i, row := range sheetData.Values {
+		if i == 0 || len(row) == 0 {
+			continue // Skip header and blank rows
+		}
+		digest.Rows++
+
+		cells := make([]string, len(row))
+		for j, cell := range row {
+			cells[j] = fmt.Sprintf("%v", cell)
+		}
+		hash.Write([]byte(strings.Join(cells, "\t") + "\n"))
+
+		if len(row) <= 6 {
+			continue
+		}
+		ts, err := strconv.ParseFloat(cells[6], 64)
+		if err != nil {
+			continue
+		}
+		if oldest == 0 || ts < oldest {
+			oldest, digest.FirstPosted = ts, cells[1]
+		}
+		if ts > newest {
+			newest, digest.LastPosted = ts, cells[1]
+		}
+	}
+	digest.SHA256 = hex.EncodeToString(hash.Sum(nil))
+	return digest, nil
+}
+
+// AppendManifestRow appends an export manifest to the manifest tab, creating the tab if missing
+func (c *Client) AppendManifestRow(spreadsheetID, sheetName string, manifest Manifest) error {
+	if _, err := c.ensureAuxiliarySheet(spreadsheetID, sheetName, manifestHeaders); err != nil {
+		return err
+	}
+
+	row := []interface{}{
+		time.Now().Format("2006-01-02 15:04:05"),
+		manifest.ChannelID,
+		manifest.SheetName,
+		manifest.Kind,
+		manifest.TraceID,
+		manifest.Version,
+		manifest.Parameters,
+		manifest.FirstPosted,
+		manifest.LastPosted,
+		manifest.Recorded,
+		manifest.Rows,
+		manifest.SHA256,
+	}
+	_, err := c.service.Spreadsheets.Values.Append(
+		spreadsheetID,
+		fmt.Sprintf("%s!A:%s", sheetName, columnLetter(len(manifestHeaders))),
+		&sheets.ValueRange{Values: [][]interface{}{row}},
+	).ValueInputOption("RAW").Do()
+	if err != nil {
+		return fmt.Errorf("unable to append manifest row: %v", err)
+	}
+	return nil
+}
+
+// ColumnHeaders returns the header row of channel tabs with the optional columns in use
+func (c *Client) ColumnHeaders() []string {
+	var columns []string
+	for _, header := range c.headers() {
+		columns = append(columns, fmt.Sprintf("%v", header))
+	}
+	return columns
+}
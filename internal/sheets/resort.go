@@ -0,0 +1,135 @@
+package sheets
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+
+	"google.golang.org/api/sheets/v4"
+)
+
+// resortRow is one data row of a channel tab with the keys used to put it back in order
+type resortRow struct {
+	values    []interface{}
+	oldNo     string
+	messageTS float64
+	postedAt  string
+}
+
+// cellString returns a cell value as text; whole numbers are printed without a decimal point
+func cellString(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case string:
+		return v
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// ResortSheet sorts the rows of a channel tab by message timestamp, renumbers the No. column and rewrites the
+// thread parent references to the new numbers. It returns the number of rows whose position or No. changed;
+// nothing is written when the tab is already in order.
+func (c *Client) ResortSheet(spreadsheetID, sheetName string) (int, error) {
+	// Unformatted values keep numbers numeric when the rows are written back with RAW
+	sheetData, err := c.service.Spreadsheets.Values.Get(spreadsheetID, c.columnRange(sheetName)).
+		ValueRenderOption("UNFORMATTED_VALUE").Do()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get sheet data: %v", err)
+	}
+	if len(sheetData.Values) <= 1 {
+		return 0, nil
+	}
+
+	var rows []*resortRow
+	for _, values := range sheetData.Values[1:] {
+		row := &resortRow{values: values}
+		if len(values) > 0 {
+			row.oldNo = cellString(values[0])
+		}
+		if len(values) > 1 {
+			row.postedAt = cellString(values[1])
+		}
+		if len(values) > 6 {
+			row.messageTS, _ = strconv.ParseFloat(cellString(values[6]), 64)
+		}
+		rows = append(rows, row)
+	}
+
+	// Message timestamps order rows exactly; rows without one fall back to the posted time column
+	sort.SliceStable(rows, func(i, j int) bool {
+		if rows[i].messageTS != 0 && rows[j].messageTS != 0 {
+			return rows[i].messageTS < rows[j].messageTS
+		}
+		return rows[i].postedAt < rows[j].postedAt
+	})
+
+	// Map every old No. to the new one (the first row wins if a No. was written twice)
+	newNos := make(map[string]int)
+	for i, row := range rows {
+		if _, exists := newNos[row.oldNo]; !exists && row.oldNo != "" {
+			newNos[row.oldNo] = i + 1
+		}
+	}
+
+	changed := 0
+	values := make([][]interface{}, len(rows))
+	for i, row := range rows {
+		rowValues := append([]interface{}{}, row.values...)
+		for len(rowValues) < 6 {
+			rowValues = append(rowValues, "")
+		}
+
+		rowValues[0] = i + 1
+		if parent := cellString(rowValues[5]); parent != "" {
+			if newNo, exists := newNos[parent]; exists {
+				rowValues[5] = strconv.Itoa(newNo)
+			}
+		}
+
+		if row.oldNo != strconv.Itoa(i+1) || cellString(rowValues[5]) != cellString(valueAt(row.values, 5)) {
+			changed++
+		}
+		values[i] = rowValues
+	}
+
+	if changed == 0 {
+		log.Printf("Sheet %s is already in chronological order", sheetName)
+		return 0, nil
+	}
+
+	// Rewrite the data rows in place chunk by chunk; rows appended meanwhile stay below the rewritten range
+	for start := 0; start < len(values); start += writeChunkSize {
+		end := start + writeChunkSize
+		if end > len(values) {
+			end = len(values)
+		}
+
+		err := retryWithBackoff(func() error {
+			_, err := c.service.Spreadsheets.Values.Update(
+				spreadsheetID,
+				fmt.Sprintf("%s!%s", sheetName, c.RowRange(start+2, end+1)),
+				&sheets.ValueRange{Values: values[start:end]},
+			).ValueInputOption("RAW").Do()
+			return err
+		}, fmt.Sprintf("rewrite sorted rows %d-%d of sheet %s", start+1, end, sheetName))
+		if err != nil {
+			return 0, fmt.Errorf("failed to rewrite sorted rows %d-%d: %v", start+1, end, err)
+		}
+	}
+
+	log.Printf("Resorted sheet %s: %d of %d rows changed", sheetName, changed, len(values))
+	return changed, nil
+}
+
+// valueAt returns the cell at index, or nil if the row is shorter
+func valueAt(row []interface{}, index int) interface{} {
+	if index < len(row) {
+		return row[index]
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package sheets
+
+import (
+	"fmt"
+
+	"google.golang.org/api/sheets/v4"
+)
+
+// AddRowNote attaches a note to the first cell of a row, e.g. to point out where the recording settings changed.
+// Unlike an extra row, a note does not disturb the numbering or the message lookups of the tab.
+func (c *Client) AddRowNote(spreadsheetID, sheetName string, row int, note string) error {
+	sheetID, err := c.GetSheetID(spreadsheetID, sheetName)
+	if err != nil {
+		return err
+	}
+
+	return retryWithBackoff(func() error {
+		request := &sheets.BatchUpdateSpreadsheetRequest{
+			Requests: []*sheets.Request{
+				{
+					UpdateCells: &sheets.UpdateCellsRequest{
+						Range: &sheets.GridRange{
+							SheetId:          sheetID,
+							StartRowIndex:    int64(row - 1),
+							EndRowIndex:      int64(row),
+							StartColumnIndex: 0,
+							EndColumnIndex:   1,
+						},
+						Rows:   []*sheets.RowData{{Values: []*sheets.CellData{{Note: note}}}},
+						Fields: "note",
+					},
+				},
+			},
+		}
+		if _, err := c.service.Spreadsheets.BatchUpdate(spreadsheetID, request).Do(); err != nil {
+			return fmt.Errorf("unable to add note to row %d of %s: %v", row, sheetName, err)
+		}
+		return nil
+	}, fmt.Sprintf("add note to row %d of %s", row, sheetName))
+}
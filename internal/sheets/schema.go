@@ -0,0 +1,360 @@
+package sheets
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"sync"
+
+	"google.golang.org/api/sheets/v4"
+)
+
+// schemaVersionKey is the developer metadata key of the schema version marker of a channel tab
+const schemaVersionKey = "slack_bot_schema_version"
+
+// migratedSheets holds the tabs ("<spreadsheet ID>/<sheet ID>") this process has already brought to its column
+// layout; migrationMutex keeps a startup migration and a first write from migrating the same tab twice
+var (
+	migratedSheets = make(map[string]bool)
+	migrationMutex sync.Mutex
+)
+
+// SchemaVersion identifies the column layout the client writes: a short hash of its header row, so enabling or
+// disabling an optional column, reordering them or changing the timestamp format gives a new version
+func (c *Client) SchemaVersion() string {
+	hash := sha256.New()
+	for _, header := range c.headers() {
+		fmt.Fprintf(hash, "%v\t", header)
+	}
+	return hex.EncodeToString(hash.Sum(nil))[:12]
+}
+
+// MigrateChannelSheets brings every channel tab of a spreadsheet to the client's column layout and returns how many
+// tabs were changed (see migrateSheet)
+func (c *Client) MigrateChannelSheets(spreadsheetID string) (int, error) {
+	spreadsheet, err := c.service.Spreadsheets.Get(spreadsheetID).Do()
+	if err != nil {
+		return 0, fmt.Errorf("unable to get spreadsheet: %v", err)
+	}
+	rememberSheetIDs(spreadsheetID, spreadsheet.Sheets)
+
+	migrated := 0
+	for _, sheet := range spreadsheet.Sheets {
+		if !isChannelSheet(sheet.Properties.Title) {
+			continue
+		}
+		changed, err := c.migrateSheetOnce(spreadsheetID, sheet)
+		if err != nil {
+			return migrated, err
+		}
+		if changed {
+			migrated++
+		}
+	}
+	return migrated, nil
+}
+
+// migrateSheetOnce migrates a tab the first time this process sees it
+func (c *Client) migrateSheetOnce(spreadsheetID string, sheet *sheets.Sheet) (bool, error) {
+	key := fmt.Sprintf("%s/%d", spreadsheetID, sheet.Properties.SheetId)
+
+	migrationMutex.Lock()
+	defer migrationMutex.Unlock()
+	if migratedSheets[key] {
+		return false, nil
+	}
+
+	changed, err := c.migrateSheet(spreadsheetID, sheet)
+	if err != nil {
+		return false, err
+	}
+	migratedSheets[key] = true
+	return changed, nil
+}
+
+// markSchemaVersion records the client's schema version on a tab it has just created with its headers
+func (c *Client) markSchemaVersion(spreadsheetID string, sheetID int64) {
+	migrationMutex.Lock()
+	defer migrationMutex.Unlock()
+	request := &sheets.BatchUpdateSpreadsheetRequest{Requests: []*sheets.Request{c.schemaVersionRequest(sheetID, nil)}}
+	if _, err := c.service.Spreadsheets.BatchUpdate(spreadsheetID, request).Do(); err != nil {
+		log.Printf("Warning: unable to mark schema version of sheet %d: %v", sheetID, err)
+		return
+	}
+	migratedSheets[fmt.Sprintf("%s/%d", spreadsheetID, sheetID)] = true
+}
+
+// migrateSheet moves the columns of a channel tab to the client's layout when the tab's schema version marker is
+// missing or differs. Columns are moved and inserted (MoveDimension / InsertDimension) rather than rewritten, so
+// values, formulas and notes travel with them; columns no longer enabled end up after the enabled ones and are kept.
+// New columns are backfilled from the other cells of each row when the column can derive its value from them, and
+// are left empty otherwise. Tabs that do not start with the base columns are left alone.
+func (c *Client) migrateSheet(spreadsheetID string, sheet *sheets.Sheet) (bool, error) {
+	sheetName := sheet.Properties.Title
+	sheetID := sheet.Properties.SheetId
+	marker := schemaMarker(sheet)
+	if marker != nil && marker.MetadataValue == c.SchemaVersion() {
+		return false, nil
+	}
+
+	var current []string
+	err := retryWithBackoff(func() error {
+		headerData, err := c.service.Spreadsheets.Values.Get(spreadsheetID, fmt.Sprintf("%s!1:1", sheetName)).Do()
+		if err != nil {
+			return fmt.Errorf("unable to read header of %s: %v", sheetName, err)
+		}
+		current = nil
+		if len(headerData.Values) > 0 {
+			for _, value := range headerData.Values[0] {
+				current = append(current, fmt.Sprint(value))
+			}
+		}
+		return nil
+	}, fmt.Sprintf("read header of %s", sheetName))
+	if err != nil {
+		return false, err
+	}
+	if len(current) > 0 && !hasBaseHeaders(current) {
+		log.Printf("Warning: sheet %s does not start with the bot's columns, not migrating it", sheetName)
+		return false, nil
+	}
+
+	target := c.headers()
+	requests, added := layoutRequests(sheetID, current, target)
+	if grid := sheet.Properties.GridProperties; grid != nil && int64(len(target)) > grid.ColumnCount+int64(countInserts(requests)) {
+		requests = append([]*sheets.Request{{
+			AppendDimension: &sheets.AppendDimensionRequest{
+				SheetId:         sheetID,
+				Dimension:       "COLUMNS",
+				Length:          int64(len(target)) - grid.ColumnCount - int64(countInserts(requests)),
+				ForceSendFields: []string{"SheetId"},
+			},
+		}}, requests...)
+	}
+
+	// Rows are read before the columns move, with the header row they were written under
+	var rows [][]interface{}
+	if len(current) > 0 && c.hasBackfill(added) {
+		err := retryWithBackoff(func() error {
+			data, err := c.service.Spreadsheets.Values.Get(spreadsheetID, sheetName).Do()
+			if err != nil {
+				return fmt.Errorf("unable to read %s: %v", sheetName, err)
+			}
+			rows = data.Values
+			return nil
+		}, fmt.Sprintf("read %s for migration", sheetName))
+		if err != nil {
+			return false, err
+		}
+	}
+
+	// The moves, the header row and the marker go in one batch, which Sheets applies entirely or not at all. It is
+	// not retried: a batch that succeeded but timed out would move the columns twice.
+	changed := len(requests) > 0 || len(added) > 0
+	requests = append(requests, headerRequest(sheetID, target), c.schemaVersionRequest(sheetID, marker))
+	request := &sheets.BatchUpdateSpreadsheetRequest{Requests: requests}
+	if _, err := c.service.Spreadsheets.BatchUpdate(spreadsheetID, request).Do(); err != nil {
+		return false, fmt.Errorf("unable to migrate columns of %s: %v", sheetName, err)
+	}
+
+	if len(rows) > 1 {
+		if err := c.backfillColumns(spreadsheetID, sheetName, current, rows[1:], added); err != nil {
+			log.Printf("Warning: %v", err)
+		}
+	}
+
+	if changed {
+		log.Printf("Migrated sheet %s to schema %s (%d new columns)", sheetName, c.SchemaVersion(), len(added))
+	}
+	return changed, nil
+}
+
+// schemaMarker returns the schema version marker of a tab, or nil if it has none
+func schemaMarker(sheet *sheets.Sheet) *sheets.DeveloperMetadata {
+	for _, metadata := range sheet.DeveloperMetadata {
+		if metadata.MetadataKey == schemaVersionKey {
+			return metadata
+		}
+	}
+	return nil
+}
+
+// hasBaseHeaders reports whether a header row starts with the base columns; the posted time column may carry
+// any of its headers
+func hasBaseHeaders(headers []string) bool {
+	if len(headers) < len(baseHeaders) {
+		return false
+	}
+	for i, expected := range baseHeaders {
+		if headers[i] == expected || (i == 1 && (headers[i] == postedAtISO8601Header || headers[i] == legacyPostedAtHeader)) {
+			continue
+		}
+		return false
+	}
+	return true
+}
+
+// layoutRequests returns the column moves and insertions that turn the current header row into target, and the
+// 0-based indexes in target of the columns the tab did not have. Columns are placed from left to right, so a column
+// found further right is moved into place and a missing one is inserted.
+func layoutRequests(sheetID int64, current []string, target []interface{}) ([]*sheets.Request, []int) {
+	var requests []*sheets.Request
+	var added []int
+	if len(current) == 0 {
+		return nil, nil
+	}
+
+	layout := append([]string{}, current...)
+	for j := len(baseHeaders); j < len(target); j++ {
+		header := fmt.Sprint(target[j])
+		i := -1
+		for k := j; k < len(layout); k++ {
+			if layout[k] == header {
+				i = k
+				break
+			}
+		}
+
+		switch {
+		case i == j:
+			continue
+		case i > j:
+			requests = append(requests, &sheets.Request{
+				MoveDimension: &sheets.MoveDimensionRequest{
+					Source:           columnRange(sheetID, i),
+					DestinationIndex: int64(j),
+				},
+			})
+			layout = append(layout[:i], layout[i+1:]...)
+		case j < len(layout):
+			requests = append(requests, &sheets.Request{
+				InsertDimension: &sheets.InsertDimensionRequest{Range: columnRange(sheetID, j)},
+			})
+			added = append(added, j)
+		default:
+			// Past the last header the column is empty already
+			added = append(added, j)
+		}
+		layout = append(layout[:j], append([]string{header}, layout[j:]...)...)
+	}
+	return requests, added
+}
+
+// columnRange returns the dimension range of a single column
+func columnRange(sheetID int64, index int) *sheets.DimensionRange {
+	return &sheets.DimensionRange{
+		SheetId:         sheetID,
+		Dimension:       "COLUMNS",
+		StartIndex:      int64(index),
+		EndIndex:        int64(index + 1),
+		ForceSendFields: []string{"SheetId", "StartIndex"},
+	}
+}
+
+// countInserts returns the number of columns the requests insert
+func countInserts(requests []*sheets.Request) int {
+	count := 0
+	for _, request := range requests {
+		if request.InsertDimension != nil {
+			count++
+		}
+	}
+	return count
+}
+
+// hasBackfill reports whether any of the added columns can derive its values from the other cells of a row
+func (c *Client) hasBackfill(added []int) bool {
+	for _, index := range added {
+		if column := c.extraColumns[index-len(baseHeaders)]; column.Backfill != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// backfillColumns fills the added columns that have a Backfill function for the data rows read before the migration
+func (c *Client) backfillColumns(spreadsheetID, sheetName string, headers []string, rows [][]interface{}, added []int) error {
+	for _, index := range added {
+		column := c.extraColumns[index-len(baseHeaders)]
+		if column.Backfill == nil {
+			continue
+		}
+
+		values := make([][]interface{}, len(rows))
+		for i, row := range rows {
+			cells := make(map[string]string, len(headers))
+			for k, header := range headers {
+				if k < len(row) {
+					cells[header] = fmt.Sprint(row[k])
+				}
+			}
+			values[i] = []interface{}{column.Backfill(cells)}
+		}
+
+		letter := columnLetter(index + 1)
+		backfillRange := fmt.Sprintf("%s!%s2:%s%d", sheetName, letter, letter, len(rows)+1)
+		err := retryWithBackoff(func() error {
+			if _, err := c.service.Spreadsheets.Values.Update(spreadsheetID, backfillRange, &sheets.ValueRange{Values: values}).ValueInputOption("RAW").Do(); err != nil {
+				return fmt.Errorf("unable to backfill %s of %s: %v", column.Header, sheetName, err)
+			}
+			return nil
+		}, fmt.Sprintf("backfill %s of %s", column.Header, sheetName))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// headerRequest writes a header row as plain strings
+func headerRequest(sheetID int64, headers []interface{}) *sheets.Request {
+	cells := make([]*sheets.CellData, len(headers))
+	for i, header := range headers {
+		text := fmt.Sprint(header)
+		cells[i] = &sheets.CellData{UserEnteredValue: &sheets.ExtendedValue{StringValue: &text}}
+	}
+	return &sheets.Request{
+		UpdateCells: &sheets.UpdateCellsRequest{
+			Range: &sheets.GridRange{
+				SheetId:          sheetID,
+				StartRowIndex:    0,
+				EndRowIndex:      1,
+				StartColumnIndex: 0,
+				EndColumnIndex:   int64(len(headers)),
+				ForceSendFields:  []string{"SheetId"},
+			},
+			Rows:   []*sheets.RowData{{Values: cells}},
+			Fields: "userEnteredValue",
+		},
+	}
+}
+
+// schemaVersionRequest writes the client's schema version to the marker of a tab, creating the marker when it is nil
+func (c *Client) schemaVersionRequest(sheetID int64, marker *sheets.DeveloperMetadata) *sheets.Request {
+	request := &sheets.Request{
+		CreateDeveloperMetadata: &sheets.CreateDeveloperMetadataRequest{
+			DeveloperMetadata: &sheets.DeveloperMetadata{
+				MetadataKey:   schemaVersionKey,
+				MetadataValue: c.SchemaVersion(),
+				Location: &sheets.DeveloperMetadataLocation{
+					SheetId:         sheetID,
+					ForceSendFields: []string{"SheetId"},
+				},
+				Visibility: "DOCUMENT",
+			},
+		},
+	}
+	if marker != nil {
+		request = &sheets.Request{
+			UpdateDeveloperMetadata: &sheets.UpdateDeveloperMetadataRequest{
+				DataFilters: []*sheets.DataFilter{{
+					DeveloperMetadataLookup: &sheets.DeveloperMetadataLookup{MetadataId: marker.MetadataId},
+				}},
+				DeveloperMetadata: &sheets.DeveloperMetadata{MetadataValue: c.SchemaVersion()},
+				Fields:            "metadataValue",
+			},
+		}
+	}
+	return request
+}
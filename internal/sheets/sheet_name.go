@@ -0,0 +1,70 @@
+package sheets
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// participantNamed holds the direct messages (IMs / MPIMs) whose tabs are named after their participants,
+// mapped to the last tab name used for them
+var (
+	participantNamed      = make(map[string]string)
+	participantNamedMutex sync.Mutex
+)
+
+// NameByParticipants names the tab of a direct message after its participants alone (e.g. "dm-alice-bob"),
+// without the "-<channel ID>" suffix channel tabs have
+func NameByParticipants(channelID string) {
+	participantNamedMutex.Lock()
+	defer participantNamedMutex.Unlock()
+	if _, exists := participantNamed[channelID]; !exists {
+		participantNamed[channelID] = ""
+	}
+}
+
+// SheetName returns the tab name of a conversation: "<channel name>-<channel ID>", or the participant-based
+// name for direct messages registered with NameByParticipants
+func SheetName(channelID, channelName string) string {
+	participantNamedMutex.Lock()
+	defer participantNamedMutex.Unlock()
+	if _, exists := participantNamed[channelID]; exists {
+		participantNamed[channelID] = channelName
+		return channelName
+	}
+	return fmt.Sprintf("%s-%s", channelName, channelID)
+}
+
+// isParticipantNamed reports whether a conversation's tab is named after its participants
+func isParticipantNamed(channelID string) bool {
+	participantNamedMutex.Lock()
+	defer participantNamedMutex.Unlock()
+	_, exists := participantNamed[channelID]
+	return exists
+}
+
+// isConversationSheet reports whether a tab title belongs to the given conversation
+func isConversationSheet(title, channelID string) bool {
+	if strings.HasSuffix(title, "-"+channelID) {
+		return true
+	}
+	participantNamedMutex.Lock()
+	defer participantNamedMutex.Unlock()
+	name, exists := participantNamed[channelID]
+	return exists && name != "" && title == name
+}
+
+// isChannelSheet reports whether a tab records a conversation (as opposed to the rollup, settings or audit tabs)
+func isChannelSheet(title string) bool {
+	if channelSheetPattern.MatchString(title) {
+		return true
+	}
+	participantNamedMutex.Lock()
+	defer participantNamedMutex.Unlock()
+	for _, name := range participantNamed {
+		if name != "" && name == title {
+			return true
+		}
+	}
+	return false
+}
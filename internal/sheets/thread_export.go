@@ -0,0 +1,55 @@
+package sheets
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"google.golang.org/api/sheets/v4"
+)
+
+// ThreadExportSheetName returns the tab a thread is exported to, e.g. "thread-general-1700000000_123456". It does
+// not end with the channel ID, so the tab is never taken for the channel's own tab.
+func ThreadExportSheetName(channelName, threadTS string) string {
+	return fmt.Sprintf("thread-%s-%s", channelName, strings.Replace(threadTS, ".", "_", 1))
+}
+
+// WriteThreadExport writes the parent and replies of a thread to their own tab with the columns of channel tabs,
+// replacing an earlier export of the same thread, and returns the tab's gid
+func (c *Client) WriteThreadExport(spreadsheetID, sheetName string, records []*MessageRecord) (int64, error) {
+	created, err := c.ensureAuxiliarySheet(spreadsheetID, sheetName, c.headers())
+	if err != nil {
+		return 0, err
+	}
+	if !created {
+		_, err := c.service.Spreadsheets.Values.Clear(spreadsheetID, fmt.Sprintf("%s!A2:%s", sheetName, c.lastColumn()), &sheets.ClearValuesRequest{}).Do()
+		if err != nil {
+			return 0, fmt.Errorf("unable to clear thread export sheet: %v", err)
+		}
+	}
+
+	var rows [][]interface{}
+	for i, record := range records {
+		// Replies point at the parent, which is the first row unless it was left out (e.g. by the opt-out policy)
+		threadParentNo := ""
+		if i > 0 && record.ThreadTS == records[0].MessageTS {
+			threadParentNo = "1"
+		}
+		rows = append(rows, c.buildRow(i+1, record, threadParentNo))
+	}
+
+	err = retryWithBackoff(func() error {
+		_, err := c.service.Spreadsheets.Values.Update(
+			spreadsheetID,
+			fmt.Sprintf("%s!A2:%s%d", sheetName, c.lastColumn(), len(rows)+1),
+			&sheets.ValueRange{Values: rows},
+		).ValueInputOption(c.rowInputOption()).Do()
+		return err
+	}, fmt.Sprintf("write thread export %s", sheetName))
+	if err != nil {
+		return 0, fmt.Errorf("unable to write thread export sheet: %v", err)
+	}
+
+	log.Printf("Exported %d messages to sheet %s", len(rows), sheetName)
+	return c.GetSheetID(spreadsheetID, sheetName)
+}
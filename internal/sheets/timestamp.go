@@ -0,0 +1,61 @@
+package sheets
+
+import (
+	"time"
+)
+
+// TimestampFormatISO8601 writes the posted time as RFC 3339 with the UTC offset (e.g. "2025-06-01T09:30:00+09:00")
+// and adds a separate date column, for downstream parsers that cannot assume the timezone
+const TimestampFormatISO8601 = "iso8601"
+
+// postedAtLayout is the default format of the posted time column, in the channel's timezone without an offset
+const postedAtLayout = "2006-01-02 15:04:05"
+
+// postedAtISO8601Header replaces the posted time header when timestamps are written as ISO 8601
+const postedAtISO8601Header = "投稿日時（ISO 8601）"
+
+// legacyPostedAtHeader is the posted time header of tabs created before the timezone became configurable per
+// channel; ensureCorrectHeader relabels it, since the column is written in each channel's own timezone
+const legacyPostedAtHeader = "投稿日時（JST）"
+
+// ColumnPostedDate records the posted date (YYYY-MM-DD) on its own, added with TimestampFormatISO8601
+var ColumnPostedDate = Column{
+	Header: "投稿日",
+	Value:  func(record *MessageRecord) interface{} { return record.Timestamp.Format("2006-01-02") },
+	Backfill: func(cells map[string]string) interface{} {
+		// Both posted time formats start with the date
+		postedAt := cells[postedAtISO8601Header]
+		if postedAt == "" {
+			postedAt = cells[baseHeaders[1].(string)]
+		}
+		if postedAt == "" {
+			postedAt = cells[legacyPostedAtHeader]
+		}
+		if len(postedAt) < len("2006-01-02") {
+			return ""
+		}
+		return postedAt[:len("2006-01-02")]
+	},
+}
+
+// UseISO8601Timestamps writes the posted time column as RFC 3339 with the UTC offset and adds the posted date column
+func (c *Client) UseISO8601Timestamps() {
+	c.iso8601Timestamps = true
+	c.AddColumns(ColumnPostedDate)
+}
+
+// formatPostedAt renders the posted time column
+func (c *Client) formatPostedAt(timestamp time.Time) string {
+	if c.iso8601Timestamps {
+		return timestamp.Format(time.RFC3339)
+	}
+	return timestamp.Format(postedAtLayout)
+}
+
+// parsePostedAt reads a posted time cell in either format; the default format is read in the given location
+func parsePostedAt(value string, location *time.Location) (time.Time, error) {
+	if timestamp, err := time.Parse(time.RFC3339, value); err == nil {
+		return timestamp, nil
+	}
+	return time.ParseInLocation(postedAtLayout, value, location)
+}
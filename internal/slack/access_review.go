@@ -0,0 +1,185 @@
+package slack
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"slack-to-google-sheets-bot/internal/audit"
+	"slack-to-google-sheets-bot/internal/config"
+	"slack-to-google-sheets-bot/internal/sheets"
+)
+
+// StartAccessReview posts a review of who the spreadsheets are shared with to the admin channel
+// on the first day of every quarter at 09:00 JST
+func StartAccessReview(cfg *config.Config) {
+	if !cfg.AccessReview {
+		return
+	}
+	if cfg.AdminChannelID == "" || cfg.GoogleSheetsCredentials == "" || cfg.SpreadsheetID == "" {
+		log.Printf("Warning: ACCESS_REVIEW is enabled but ADMIN_CHANNEL_ID or Google Sheets is not configured, review disabled")
+		return
+	}
+
+	go func() {
+		for {
+			time.Sleep(time.Until(nextAccessReviewTime(time.Now())))
+			sendAccessReview(cfg)
+		}
+	}()
+}
+
+// nextAccessReviewTime returns the next January, April, July or October 1st 09:00 JST after now
+func nextAccessReviewTime(now time.Time) time.Time {
+	local := now.In(jstLocation)
+	quarterMonth := time.Month((int(local.Month())-1)/3*3 + 1)
+	next := time.Date(local.Year(), quarterMonth, 1, 9, 0, 0, 0, jstLocation)
+	for !next.After(local) {
+		next = next.AddDate(0, 3, 0)
+	}
+	return next
+}
+
+// accessReviewResult is the review of one spreadsheet
+type accessReviewResult struct {
+	spreadsheetID string
+	grants        int
+	external      []string
+	stale         []string
+}
+
+// reviewPermissions flags grants to external domains, public links, deleted accounts and "show me" grants
+// older than staleAfter (lastShared maps lowercased emails to their latest grant)
+func reviewPermissions(spreadsheetID string, permissions []sheets.Permission, internalDomains []string, lastShared map[string]time.Time, staleAfter time.Duration, now time.Time) accessReviewResult {
+	result := accessReviewResult{spreadsheetID: spreadsheetID, grants: len(permissions)}
+
+	for _, permission := range permissions {
+		who := permission.EmailAddress
+		if who == "" {
+			who = permission.Domain
+		}
+		label := fmt.Sprintf("%s (%s)", who, permission.Role)
+
+		switch {
+		case permission.Type == "anyone":
+			result.external = append(result.external, fmt.Sprintf("リンクを知っている全員 (%s)", permission.Role))
+		case strings.HasSuffix(permission.EmailAddress, ".gserviceaccount.com"):
+			// The service accounts of this bot own or write the spreadsheet
+		case len(internalDomains) > 0 && !isInternalDomain(grantDomain(permission), internalDomains):
+			result.external = append(result.external, label)
+		}
+
+		if permission.Deleted {
+			result.stale = append(result.stale, label+" - 削除済みアカウント")
+			continue
+		}
+		if sharedAt, exists := lastShared[strings.ToLower(permission.EmailAddress)]; exists && now.Sub(sharedAt) > staleAfter {
+			result.stale = append(result.stale, fmt.Sprintf("%s - %s に show me で付与", label, sharedAt.In(jstLocation).Format("2006-01-02")))
+		}
+	}
+	return result
+}
+
+// grantDomain returns the domain a grant belongs to
+func grantDomain(permission sheets.Permission) string {
+	if permission.Domain != "" {
+		return strings.ToLower(permission.Domain)
+	}
+	if _, domain, found := strings.Cut(permission.EmailAddress, "@"); found {
+		return strings.ToLower(domain)
+	}
+	return ""
+}
+
+// isInternalDomain reports whether a domain is one of the internal domains (or a subdomain of one)
+func isInternalDomain(domain string, internalDomains []string) bool {
+	for _, internal := range internalDomains {
+		internal = strings.ToLower(internal)
+		if domain == internal || strings.HasSuffix(domain, "."+internal) {
+			return true
+		}
+	}
+	return false
+}
+
+// lastSharedTimes returns when each email was last granted access with "show me", from the audit log
+func lastSharedTimes() map[string]time.Time {
+	lastShared := make(map[string]time.Time)
+	entries, err := auditLogger.Entries("share")
+	if err != nil {
+		log.Printf("Warning: Could not read share grants from the audit log: %v", err)
+		return lastShared
+	}
+	for _, entry := range entries {
+		email := strings.ToLower(entry.Detail)
+		if entry.Time.After(lastShared[email]) {
+			lastShared[email] = entry.Time
+		}
+	}
+	return lastShared
+}
+
+// buildAccessReview renders the review of every managed spreadsheet
+func buildAccessReview(results []accessReviewResult, failed []string, cfg *config.Config) string {
+	lines := []string{"🔐 四半期アクセスレビュー", ""}
+	if len(cfg.AccessReviewInternalDomains) == 0 {
+		lines = append(lines, "ℹ️ ACCESS_REVIEW_INTERNAL_DOMAINS が未設定のため、外部ドメインの判定はリンク共有のみです", "")
+	}
+
+	for _, result := range results {
+		lines = append(lines, fmt.Sprintf("*https://docs.google.com/spreadsheets/d/%s* : 共有 %d件", result.spreadsheetID, result.grants))
+		for _, grant := range result.external {
+			lines = append(lines, "• ⚠️ 外部: "+grant)
+		}
+		for _, grant := range result.stale {
+			lines = append(lines, "• 🕰️ 見直し候補: "+grant)
+		}
+		if len(result.external) == 0 && len(result.stale) == 0 {
+			lines = append(lines, "• ✅ 指摘事項はありません")
+		}
+	}
+	for _, spreadsheetID := range failed {
+		lines = append(lines, fmt.Sprintf("*%s* : ❌ 共有設定を取得できませんでした", spreadsheetID))
+	}
+
+	lines = append(lines, "", fmt.Sprintf("（%d日以上前に show me で付与された権限を見直し候補としています）", cfg.AccessReviewStaleDays))
+	return strings.Join(lines, "\n")
+}
+
+// sendAccessReview reviews the permissions of every managed spreadsheet and posts the result to the admin channel
+func sendAccessReview(cfg *config.Config) {
+	sheetsClient, err := newSheetsClient(cfg)
+	if err != nil {
+		log.Printf("Error creating Google Sheets client for the access review: %v", err)
+		return
+	}
+
+	now := time.Now()
+	lastShared := lastSharedTimes()
+	staleAfter := time.Duration(cfg.AccessReviewStaleDays) * 24 * time.Hour
+
+	var results []accessReviewResult
+	var failed []string
+	for _, spreadsheetID := range allSpreadsheetIDs(cfg) {
+		permissions, err := sheetsClient.ListPermissions(spreadsheetID)
+		if err != nil {
+			log.Printf("Error listing permissions of %s: %v", spreadsheetID, err)
+			failed = append(failed, spreadsheetID)
+			continue
+		}
+		result := reviewPermissions(spreadsheetID, permissions, cfg.AccessReviewInternalDomains, lastShared, staleAfter, now)
+		results = append(results, result)
+
+		recordAudit(cfg, audit.Entry{
+			Action: "access_review",
+			Detail: fmt.Sprintf("%s: %d grants, %d external, %d stale", spreadsheetID, result.grants, len(result.external), len(result.stale)),
+		})
+	}
+
+	if err := newClient(cfg).SendMessage(cfg.AdminChannelID, buildAccessReview(results, failed, cfg)); err != nil {
+		log.Printf("Error sending access review: %v", err)
+		return
+	}
+	log.Printf("Access review of %d spreadsheets sent to %s", len(results), cfg.AdminChannelID)
+}
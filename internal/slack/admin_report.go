@@ -0,0 +1,91 @@
+package slack
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"slack-to-google-sheets-bot/internal/config"
+	"slack-to-google-sheets-bot/internal/latency"
+	"slack-to-google-sheets-bot/internal/quota"
+)
+
+// StartWeeklyAdminReport posts a usage report to the admin channel every Monday at 09:00 JST
+func StartWeeklyAdminReport(cfg *config.Config) {
+	if !cfg.WeeklyAdminReport {
+		return
+	}
+	if cfg.AdminChannelID == "" {
+		log.Printf("Warning: WEEKLY_ADMIN_REPORT is enabled but ADMIN_CHANNEL_ID is not set, report disabled")
+		return
+	}
+
+	go func() {
+		for {
+			time.Sleep(time.Until(nextWeeklyReportTime(time.Now())))
+			sendWeeklyAdminReport(cfg)
+		}
+	}()
+}
+
+// nextWeeklyReportTime returns the next Monday 09:00 JST after now
+func nextWeeklyReportTime(now time.Time) time.Time {
+	local := now.In(jstLocation)
+	next := time.Date(local.Year(), local.Month(), local.Day(), 9, 0, 0, 0, jstLocation)
+	for next.Weekday() != time.Monday || !next.After(local) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next
+}
+
+// buildWeeklyAdminReport renders the weekly report sections
+func buildWeeklyAdminReport(now time.Time) string {
+	snapshot := quota.Default().Snapshot(now)
+
+	lines := []string{
+		"📊 週次レポート",
+		"",
+		"*Google Sheets API 使用量*",
+		fmt.Sprintf("クォータ: 読み取り %d/分・書き込み %d/分", snapshot.ReadQuotaPerMinute, snapshot.WriteQuotaPerMinute),
+	}
+	for _, day := range snapshot.Days {
+		lines = append(lines, fmt.Sprintf("• %s: 読み取り %d回（最大 %d/分）・書き込み %d回（最大 %d/分）",
+			day.Date, day.Reads, day.PeakReadsPerMinute, day.Writes, day.PeakWritesPerMinute))
+	}
+	if len(snapshot.Days) == 0 {
+		lines = append(lines, "• 今週の API 呼び出しはありません")
+	}
+
+	lines = append(lines, "", "*記録レイテンシ*（イベント受信からシート書き込みまで）")
+	latencySnapshot := latency.Default().Snapshot(now)
+	for _, day := range latencySnapshot.Days {
+		lines = append(lines, fmt.Sprintf("• %s: %d件 p50 %.1f秒・p95 %.1f秒・p99 %.1f秒（最大 %.1f秒）",
+			day.Date, day.Count, day.P50, day.P95, day.P99, day.Max))
+	}
+	if len(latencySnapshot.Days) == 0 {
+		lines = append(lines, "• 今週の記録はありません")
+	}
+	if latencySnapshot.SLOSeconds > 0 {
+		lines = append(lines, fmt.Sprintf("SLO: p95 %.0f秒以内", latencySnapshot.SLOSeconds))
+	}
+
+	if len(snapshot.Warnings) > 0 {
+		lines = append(lines, "", "⚠️ *クォータ警告*")
+		for _, warning := range snapshot.Warnings {
+			lines = append(lines, "• "+warning)
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// sendWeeklyAdminReport posts the weekly report to the admin channel
+func sendWeeklyAdminReport(cfg *config.Config) {
+	report := buildWeeklyAdminReport(time.Now())
+	if err := newClient(cfg).SendMessage(cfg.AdminChannelID, report); err != nil {
+		log.Printf("Error sending weekly admin report: %v", err)
+		return
+	}
+	log.Printf("Weekly admin report sent to %s", cfg.AdminChannelID)
+}
@@ -0,0 +1,40 @@
+package slack
+
+import (
+	"log"
+	"time"
+
+	"slack-to-google-sheets-bot/internal/audit"
+	"slack-to-google-sheets-bot/internal/config"
+)
+
+// recordAudit writes an entry to the local audit log and, when enabled, to the audit tab of the spreadsheet
+func recordAudit(cfg *config.Config, entry audit.Entry) {
+	if entry.Time.IsZero() {
+		entry.Time = time.Now()
+	}
+	if err := auditLogger.Record(entry); err != nil {
+		log.Printf("Warning: Could not write audit entry: %v", err)
+	}
+
+	if !cfg.AuditSheetEnabled || cfg.GoogleSheetsCredentials == "" || cfg.SpreadsheetID == "" {
+		return
+	}
+
+	sheetsClient, err := newSheetsClient(cfg)
+	if err != nil {
+		log.Printf("Warning: Could not create Google Sheets client for the audit sheet: %v", err)
+		return
+	}
+
+	row := []interface{}{
+		entry.Time.In(jstLocation).Format("2006-01-02 15:04:05"),
+		entry.Action,
+		entry.ChannelID,
+		entry.User,
+		entry.Detail,
+	}
+	if err := sheetsClient.AppendAuditRow(cfg.SpreadsheetID, cfg.AuditSheetName, row); err != nil {
+		log.Printf("Warning: Could not write audit entry to the audit sheet: %v", err)
+	}
+}
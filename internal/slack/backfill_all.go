@@ -0,0 +1,163 @@
+package slack
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"slack-to-google-sheets-bot/internal/audit"
+	"slack-to-google-sheets-bot/internal/config"
+)
+
+// backfillAllCommandPattern matches "@bot backfill all"
+var backfillAllCommandPattern = regexp.MustCompile(`(?i)^backfill\s+all\b`)
+
+// bulkBackfillPause spaces out the channels of a bulk backfill so their history requests do not run into Slack's
+// per-method rate limits back to back
+const bulkBackfillPause = 30 * time.Second
+
+// bulkBackfillRunning keeps a second "backfill all" from starting while one is running
+var (
+	bulkBackfillRunning bool
+	bulkBackfillMutex   sync.Mutex
+)
+
+// handleBackfillAllCommand retrieves the history of every channel the bot is a member of that has no tab yet, one
+// channel after another; admins only
+func handleBackfillAllCommand(cfg *config.Config, slackClient *Client, event *Event) error {
+	channelID, userID := event.Event.Channel, event.Event.User
+	reply := func(message string) error {
+		if err := slackClient.SendMessage(channelID, message); err != nil {
+			log.Printf("Error sending backfill all reply: %v", err)
+		}
+		return nil
+	}
+
+	if !cfg.IsAdmin(userID) {
+		return reply("⚠️ 「backfill all」は管理者のみ実行できます。")
+	}
+	if cfg.GoogleSheetsCredentials == "" || cfg.SpreadsheetID == "" {
+		return reply("⚠️ Google Sheetsの設定が完了していません。")
+	}
+
+	bulkBackfillMutex.Lock()
+	if bulkBackfillRunning {
+		bulkBackfillMutex.Unlock()
+		return reply("⏳ 全チャンネルの履歴取得はすでに実行中です。完了までお待ちください。")
+	}
+	bulkBackfillRunning = true
+	bulkBackfillMutex.Unlock()
+
+	channels, err := unrecordedMemberChannels(cfg, slackClient)
+	if err != nil {
+		log.Printf("Error listing channels for backfill all: %v", err)
+		finishBulkBackfill()
+		return reply("❌ チャンネル一覧の取得に失敗しました。")
+	}
+	if len(channels) == 0 {
+		finishBulkBackfill()
+		return reply("✅ bot が参加しているチャンネルはすべて記録済みです。")
+	}
+
+	names := make([]string, 0, len(channels))
+	for _, channel := range channels {
+		names = append(names, "#"+channel.Name)
+	}
+	recordAudit(cfg, audit.Entry{
+		Action:    "backfill_all",
+		ChannelID: channelID,
+		User:      userID,
+		Detail:    fmt.Sprintf("%d channels: %s", len(channels), strings.Join(names, ", ")),
+	})
+	reply(fmt.Sprintf("🚀 未記録の %d チャンネルの履歴を1チャンネルずつ取得します（見込み: %d分以上）。\n%s\n"+
+		"各チャンネルの進捗はそれぞれのチャンネルに表示されます。完了したらここでお知らせします。",
+		len(channels), len(channels)*int(bulkBackfillPause/time.Minute+1), strings.Join(names, " ")))
+
+	go func() {
+		defer finishBulkBackfill()
+		runBulkBackfill(cfg, event, channels)
+	}()
+	return nil
+}
+
+// finishBulkBackfill allows the next "backfill all"
+func finishBulkBackfill() {
+	bulkBackfillMutex.Lock()
+	bulkBackfillRunning = false
+	bulkBackfillMutex.Unlock()
+}
+
+// unrecordedMemberChannels lists the channels the bot is a member of that have no tab yet
+func unrecordedMemberChannels(cfg *config.Config, slackClient *Client) ([]*ChannelInfo, error) {
+	members, err := slackClient.GetMemberChannels()
+	if err != nil {
+		return nil, err
+	}
+	sheetsClient, err := newSheetsClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	var channels []*ChannelInfo
+	for _, channel := range members {
+		if refusesSharedChannel(cfg, channel) {
+			continue
+		}
+		exists, err := sheetsClient.HasChannelSheet(channelSpreadsheetID(cfg, sheetsClient, channel.ID), channel.ID)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			channels = append(channels, channel)
+		}
+	}
+	return channels, nil
+}
+
+// runBulkBackfill retrieves the history of the channels sequentially and reports the result to the channel the
+// command was run in
+func runBulkBackfill(cfg *config.Config, event *Event, channels []*ChannelInfo) {
+	slackClient := newClient(cfg)
+	startedAt := time.Now()
+	var failed []string
+	completed := 0
+
+	for i, channel := range channels {
+		if i > 0 {
+			time.Sleep(bulkBackfillPause)
+		}
+		if channelRemoved(channel.ID) {
+			continue
+		}
+		historyProgressMutex.Lock()
+		inProgress := historyInProgress[channel.ID]
+		historyProgressMutex.Unlock()
+		if inProgress {
+			log.Printf("Backfill all: history retrieval already running for #%s, skipping", channel.Name)
+			continue
+		}
+
+		log.Printf("Backfill all: retrieving history of #%s (%s), %d/%d", channel.Name, channel.ID, i+1, len(channels))
+		channelEvent := commandEvent("backfill_all", event.TeamID, "", channel.ID, event.Event.User, "")
+		channelEvent.TraceID = event.TraceID
+		announceInitialBackfill(slackClient, channelEvent, channel)
+		if err := performHistoryRetrieval(cfg, slackClient, channelEvent, channel, true); err != nil {
+			log.Printf("Backfill all: error retrieving history of #%s: %v", channel.Name, err)
+			failed = append(failed, "#"+channel.Name)
+			continue
+		}
+		completed++
+	}
+
+	message := fmt.Sprintf("✅ 全チャンネルの履歴取得が完了しました（%d/%d チャンネル、所要時間: %s）。",
+		completed, len(channels), time.Since(startedAt).Round(time.Minute))
+	if len(failed) > 0 {
+		message += fmt.Sprintf("\n❌ 失敗したチャンネル: %s\nそれぞれのチャンネルで「Reset!」とメンションすると取得し直せます。", strings.Join(failed, " "))
+	}
+	if err := slackClient.SendMessage(event.Event.Channel, message); err != nil {
+		log.Printf("Error sending backfill all result: %v", err)
+	}
+}
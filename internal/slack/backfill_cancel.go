@@ -0,0 +1,116 @@
+package slack
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// forceResetCancelTimeout is how long "Reset! force" waits for the running history retrieval to stop, e.g. while
+// it finishes writing a chunk
+const forceResetCancelTimeout = 2 * time.Minute
+
+// errBackfillCancelled stops a history retrieval that a "Reset! force" replaced
+var errBackfillCancelled = errors.New("history retrieval was cancelled")
+
+// backfillRun is a running history retrieval of a channel
+type backfillRun struct {
+	cancel     chan struct{} // Closed to ask the run to stop
+	cancelOnce sync.Once
+	done       chan struct{} // Closed once the run has exited
+}
+
+var (
+	// backfillRuns are the running history retrievals by channel ID, guarded by historyProgressMutex
+	backfillRuns = make(map[string]*backfillRun)
+	// backfillGenerations count the cancellations per channel, so rate-limit retries scheduled by a cancelled run
+	// are dropped; guarded by historyProgressMutex
+	backfillGenerations = make(map[string]int)
+)
+
+// startBackfillRun marks a history retrieval of a channel as in progress; call it with historyProgressMutex held
+// and finish the run when the retrieval exits
+func startBackfillRun(channelID string, startedAt time.Time) *backfillRun {
+	run := &backfillRun{cancel: make(chan struct{}), done: make(chan struct{})}
+	backfillRuns[channelID] = run
+	historyInProgress[channelID] = true
+	historyStartTime[channelID] = startedAt
+	return run
+}
+
+// finishBackfillRun clears the in-progress flag of a channel, unless another run has taken over since
+func finishBackfillRun(channelID string, run *backfillRun) {
+	historyProgressMutex.Lock()
+	if backfillRuns[channelID] == run {
+		delete(backfillRuns, channelID)
+		delete(historyInProgress, channelID)
+		delete(historyStartTime, channelID)
+	}
+	historyProgressMutex.Unlock()
+	close(run.done)
+}
+
+// cancelBackfillRun stops the running history retrieval of a channel, if any, and waits up to timeout for it to
+// exit; it reports whether no run is left writing to the channel's tab
+func cancelBackfillRun(channelID string, timeout time.Duration) bool {
+	historyProgressMutex.Lock()
+	backfillGenerations[channelID]++
+	run := backfillRuns[channelID]
+	historyProgressMutex.Unlock()
+	if run == nil {
+		return true
+	}
+
+	run.cancelOnce.Do(func() { close(run.cancel) })
+	select {
+	case <-run.done:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
+
+// backfillGeneration returns the number of cancellations of a channel's history retrievals so far
+func backfillGeneration(channelID string) int {
+	historyProgressMutex.Lock()
+	defer historyProgressMutex.Unlock()
+	return backfillGenerations[channelID]
+}
+
+// backfillCancelled reports whether the running history retrieval of a channel was asked to stop
+func backfillCancelled(channelID string) bool {
+	historyProgressMutex.Lock()
+	run := backfillRuns[channelID]
+	historyProgressMutex.Unlock()
+	if run == nil {
+		return false
+	}
+	select {
+	case <-run.cancel:
+		return true
+	default:
+		return false
+	}
+}
+
+// historyStopped returns why paging through a channel's history must stop (the bot was removed, or the retrieval
+// was cancelled), or nil
+func historyStopped(channelID string) error {
+	if channelRemoved(channelID) {
+		return errChannelRemoved
+	}
+	if backfillCancelled(channelID) {
+		return errBackfillCancelled
+	}
+	return nil
+}
+
+// waitUnlessCancelled sleeps for d and reports whether the run was not cancelled in the meantime
+func (run *backfillRun) waitUnlessCancelled(d time.Duration) bool {
+	select {
+	case <-run.cancel:
+		return false
+	case <-time.After(d):
+		return true
+	}
+}
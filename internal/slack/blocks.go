@@ -0,0 +1,90 @@
+package slack
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Block is a Slack Block Kit block
+type Block map[string]interface{}
+
+// progressBarWidth is the number of cells in a text progress bar
+const progressBarWidth = 20
+
+// sectionBlock builds a section block with markdown text
+func sectionBlock(text string) Block {
+	return Block{
+		"type": "section",
+		"text": Block{"type": "mrkdwn", "text": text},
+	}
+}
+
+// fieldsBlock builds a section block laid out as two-column markdown fields
+func fieldsBlock(fields ...string) Block {
+	var items []Block
+	for _, field := range fields {
+		items = append(items, Block{"type": "mrkdwn", "text": field})
+	}
+	return Block{
+		"type":   "section",
+		"fields": items,
+	}
+}
+
+// contextBlock builds a context block with small markdown text
+func contextBlock(text string) Block {
+	return Block{
+		"type":     "context",
+		"elements": []Block{{"type": "mrkdwn", "text": text}},
+	}
+}
+
+// linkButtonBlock builds an actions block with a single button that opens a URL
+func linkButtonBlock(actionID, label, url string) Block {
+	return Block{
+		"type": "actions",
+		"elements": []Block{{
+			"type":      "button",
+			"action_id": actionID,
+			"text":      Block{"type": "plain_text", "text": label},
+			"url":       url,
+		}},
+	}
+}
+
+// actionButton builds a button that sends a block_actions payload to /slack/interactivity;
+// style is "primary", "danger" or empty
+func actionButton(actionID, label, value, style string) Block {
+	button := Block{
+		"type":      "button",
+		"action_id": actionID,
+		"text":      Block{"type": "plain_text", "text": label},
+		"value":     value,
+	}
+	if style != "" {
+		button["style"] = style
+	}
+	return button
+}
+
+// actionsBlock builds an actions block with the given buttons
+func actionsBlock(buttons ...Block) Block {
+	return Block{
+		"type":     "actions",
+		"elements": buttons,
+	}
+}
+
+// progressBar renders a text progress bar such as "▓▓▓▓▓░░░░░ 50% (5/10)"
+func progressBar(done, total int) string {
+	if total <= 0 {
+		return strings.Repeat("░", progressBarWidth)
+	}
+	if done > total {
+		done = total
+	}
+	filled := done * progressBarWidth / total
+	return fmt.Sprintf("%s%s %d%% (%d/%d)",
+		strings.Repeat("▓", filled), strings.Repeat("░", progressBarWidth-filled),
+		done*100/total, done, total)
+}
@@ -0,0 +1,39 @@
+package slack
+
+import (
+	"fmt"
+	"log"
+
+	"slack-to-google-sheets-bot/internal/budget"
+	"slack-to-google-sheets-bot/internal/config"
+)
+
+var budgetMgr = budget.NewManager()
+
+// trackMessageBudget counts a live-recorded message against the channel's monthly budget
+// and notifies the admin channel the first time the threshold is exceeded in a month
+func trackMessageBudget(cfg *config.Config, slackClient *Client, channelID, channelName string) {
+	threshold := cfg.MessageAlertThreshold(channelID)
+
+	total, crossed, err := budgetMgr.RecordMessages(channelID, 1, threshold)
+	if err != nil {
+		log.Printf("Warning: Could not update message budget for channel %s: %v", channelID, err)
+		return
+	}
+	if !crossed {
+		return
+	}
+
+	log.Printf("Channel %s exceeded its monthly message budget: %d/%d", channelID, total, threshold)
+
+	if cfg.AdminChannelID == "" {
+		log.Printf("ADMIN_CHANNEL_ID not configured, budget alert for channel %s not sent", channelID)
+		return
+	}
+
+	alertMessage := fmt.Sprintf("⚠️ #%s の今月の記録メッセージ数が %d 件に達しました（しきい値: %d 件）。\n"+
+		"チャンネルの分割やシートのローテーションを検討してください。", channelName, total, threshold)
+	if err := slackClient.SendMessage(cfg.AdminChannelID, alertMessage); err != nil {
+		log.Printf("Error sending budget alert for channel %s: %v", channelID, err)
+	}
+}
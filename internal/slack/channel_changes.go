@@ -0,0 +1,60 @@
+package slack
+
+import (
+	"log"
+
+	"slack-to-google-sheets-bot/internal/config"
+	"slack-to-google-sheets-bot/internal/sheets"
+)
+
+// Labels of the rows recording channel topic and purpose changes
+const (
+	topicChangedLabel   = "[トピック変更] "
+	purposeChangedLabel = "[説明変更] "
+	emptySettingLabel   = "（なし）"
+)
+
+// channelChangeText replaces the text of a channel_topic / channel_purpose message ("set the channel topic: ...")
+// with a label and the new value, so these governance changes stand out as system rows; other messages keep text
+func channelChangeText(subtype, text, topic, purpose string) string {
+	label, value := "", ""
+	switch subtype {
+	case "channel_topic":
+		label, value = topicChangedLabel, topic
+	case "channel_purpose":
+		label, value = purposeChangedLabel, purpose
+	default:
+		return text
+	}
+	if value == "" {
+		value = emptySettingLabel
+	}
+	return label + value
+}
+
+// updateChannelInfo keeps the topic and purpose of a channel current in CHANNEL_INFO_SHEET_NAME after a
+// channel_topic / channel_purpose message was recorded
+func updateChannelInfo(cfg *config.Config, slackClient *Client, sheetsClient *sheets.Client, event *Event, record *sheets.MessageRecord) {
+	if cfg.ChannelInfoSheetName == "" {
+		return
+	}
+
+	var field sheets.ChannelInfoField
+	var value string
+	switch event.Event.Subtype {
+	case "channel_topic":
+		field, value = sheets.ChannelInfoTopic, event.Event.Topic
+	case "channel_purpose":
+		field, value = sheets.ChannelInfoPurpose, event.Event.Purpose
+	default:
+		return
+	}
+
+	spreadsheetID := channelSpreadsheetID(cfg, sheetsClient, record.Channel)
+	changedAt := record.Timestamp.Format("2006-01-02 15:04:05")
+	err := sheetsClient.UpdateChannelInfoRow(spreadsheetID, cfg.ChannelInfoSheetName, record.Channel, record.ChannelName,
+		field, slackClient.FormatMessageText(value), changedAt, record.UserHandle)
+	if err != nil {
+		log.Printf("Error updating channel info of %s in %s: %v", record.Channel, cfg.ChannelInfoSheetName, err)
+	}
+}
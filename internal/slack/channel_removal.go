@@ -0,0 +1,92 @@
+package slack
+
+import (
+	"errors"
+	"log"
+	"strings"
+	"sync"
+
+	"slack-to-google-sheets-bot/internal/audit"
+	"slack-to-google-sheets-bot/internal/config"
+	"slack-to-google-sheets-bot/internal/progress"
+)
+
+// errChannelRemoved stops history retrieval for a channel the bot was removed from
+var errChannelRemoved = errors.New("bot was removed from the channel")
+
+// removedChannels are the channels the bot was removed from and has not rejoined
+var (
+	removedChannels      = make(map[string]bool)
+	removedChannelsMutex sync.Mutex
+)
+
+// markChannelRemoved records that the bot can no longer read a channel
+func markChannelRemoved(channelID string) {
+	removedChannelsMutex.Lock()
+	defer removedChannelsMutex.Unlock()
+	removedChannels[channelID] = true
+}
+
+// clearChannelRemoved forgets a removal when the bot rejoins the channel
+func clearChannelRemoved(channelID string) {
+	removedChannelsMutex.Lock()
+	defer removedChannelsMutex.Unlock()
+	delete(removedChannels, channelID)
+}
+
+// channelRemoved reports whether the bot was removed from a channel
+func channelRemoved(channelID string) bool {
+	removedChannelsMutex.Lock()
+	defer removedChannelsMutex.Unlock()
+	return removedChannels[channelID]
+}
+
+// isChannelAccessError reports whether a Slack API error means the bot cannot read the channel anymore
+func isChannelAccessError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, errChannelRemoved) {
+		return true
+	}
+	message := err.Error()
+	return strings.Contains(message, "not_in_channel") ||
+		strings.Contains(message, "channel_not_found") ||
+		strings.Contains(message, "is_archived")
+}
+
+// handleMemberLeft stops work on a channel when the member who left is the bot itself
+func handleMemberLeft(cfg *config.Config, event *Event) error {
+	slackClient := newClient(cfg)
+	botID, err := slackClient.BotUserID()
+	if err != nil {
+		log.Printf("Error resolving bot user ID for member_left_channel: %v", err)
+		return err
+	}
+	if event.Event.User != botID {
+		return nil
+	}
+
+	handleBotRemoved(cfg, event.Event.Channel)
+	return nil
+}
+
+// handleBotRemoved cancels the in-progress backfill, scheduled retries and pending thread notes of a channel
+// and deletes its progress file
+func handleBotRemoved(cfg *config.Config, channelID string) {
+	log.Printf("Bot was removed from channel %s, stopping all processing for it", channelID)
+	markChannelRemoved(channelID)
+
+	if err := progress.NewManager().DeleteProgress(channelID); err != nil {
+		log.Printf("Warning: Could not delete progress for removed channel %s: %v", channelID, err)
+	}
+	endStatusThread(channelID)
+	stopThreadMirrors(channelID)
+	stopPendingEdits(channelID)
+
+	recordAudit(cfg, audit.Entry{
+		Action:    "bot_removed",
+		ChannelID: channelID,
+		Detail:    "stopped history retrieval and scheduled retries",
+	})
+}
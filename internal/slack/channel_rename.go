@@ -0,0 +1,65 @@
+package slack
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"slack-to-google-sheets-bot/internal/config"
+)
+
+// channelRenames records when each channel was last renamed so cached channel info can be invalidated
+var (
+	channelRenames      = make(map[string]time.Time)
+	channelRenamesMutex sync.Mutex
+)
+
+// invalidateChannel marks the cached info of a channel as stale in every client
+func invalidateChannel(channelID string) {
+	channelRenamesMutex.Lock()
+	defer channelRenamesMutex.Unlock()
+	channelRenames[channelID] = time.Now()
+}
+
+// channelRenamedSince reports whether the channel was renamed after the given time
+func channelRenamedSince(channelID string, since time.Time) bool {
+	channelRenamesMutex.Lock()
+	defer channelRenamesMutex.Unlock()
+	renamedAt, exists := channelRenames[channelID]
+	return exists && renamedAt.After(since)
+}
+
+// handleChannelRename renames the channel's tab as soon as the channel is renamed
+func handleChannelRename(cfg *config.Config, event *Event) error {
+	renamed := event.Event.RenamedChannel
+	if renamed == nil || renamed.ID == "" || renamed.Name == "" {
+		log.Printf("Ignoring %s event without channel", event.Event.Type)
+		return nil
+	}
+
+	invalidateChannel(renamed.ID)
+
+	if cfg.GoogleSheetsCredentials == "" || cfg.SpreadsheetID == "" {
+		return nil
+	}
+
+	sheetsClient, err := newSheetsClient(cfg)
+	if err != nil {
+		log.Printf("Error creating Google Sheets client: %v", err)
+		return err
+	}
+
+	// Look in every spreadsheet so renaming a channel that is not recorded yet does not assign it one
+	for _, spreadsheetID := range allSpreadsheetIDs(cfg) {
+		oldName, err := sheetsClient.RenameChannelSheet(spreadsheetID, renamed.ID, renamed.Name)
+		if err != nil {
+			log.Printf("Error renaming sheet for channel %s: %v", renamed.ID, err)
+			return err
+		}
+		if oldName != "" {
+			log.Printf("Renamed sheet '%s' to '%s-%s' after channel rename", oldName, renamed.Name, renamed.ID)
+			return nil
+		}
+	}
+	return nil
+}
@@ -2,6 +2,7 @@ package slack
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
@@ -9,34 +10,73 @@ import (
 	"regexp"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
+	"slack-to-google-sheets-bot/internal/config"
 	"slack-to-google-sheets-bot/internal/progress"
 	"slack-to-google-sheets-bot/internal/sheets"
 )
 
 type Client struct {
-	token        string
-	httpClient   *http.Client
-	userCache    map[string]*UserInfo
-	channelCache map[string]*ChannelInfo
-	botCache     map[string]*BotInfo
+	token             string
+	teamID            string // Workspace sent with workspace-scoped methods on Enterprise Grid (see WithTeam)
+	recordMembership  bool   // Record channel_join / channel_leave messages (RECORD_MEMBERSHIP_MESSAGES)
+	httpClient        *http.Client
+	userCache         map[string]*UserInfo
+	userFetchedAt     map[string]time.Time // By user ID (users.info) and by profile field cache key
+	channelCache      map[string]*ChannelInfo
+	channelFetchedAt  map[string]time.Time
+	botCache          map[string]*BotInfo
+	profileFieldCache map[string]string
+	teamNameCache     map[string]string // Team ID -> name, from team.info
 }
 
 type UserInfo struct {
-	ID       string `json:"id"`
-	Name     string `json:"name"`
-	RealName string `json:"real_name"`
+	ID       string      `json:"id"`
+	Name     string      `json:"name"`
+	RealName string      `json:"real_name"`
+	Locale   string      `json:"locale,omitempty"` // Only returned with include_locale=true
+	IsBot    bool        `json:"is_bot,omitempty"`
+	Profile  UserProfile `json:"profile"`
+	TeamID   string      `json:"team_id,omitempty"` // Home workspace; another organization's for Slack Connect users
+
+	EnterpriseUser *EnterpriseUser `json:"enterprise_user,omitempty"`
+}
+
+// UserProfile contains the profile fields of a Slack user that the bot records
+type UserProfile struct {
+	Title  string                      `json:"title"`
+	Fields map[string]UserProfileField `json:"fields,omitempty"`
+}
+
+// UserProfileField is a custom profile field value
+type UserProfileField struct {
+	Value string `json:"value"`
+}
+
+// UserProfileResponse is the response of users.profile.get
+type UserProfileResponse struct {
+	OK      bool        `json:"ok"`
+	Profile UserProfile `json:"profile"`
 }
 
 type ChannelInfo struct {
-	ID   string `json:"id"`
-	Name string `json:"name"`
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	IsIM      bool   `json:"is_im,omitempty"`
+	IsMpim    bool   `json:"is_mpim,omitempty"`
+	IsPrivate bool   `json:"is_private,omitempty"`
+	IMUser    string `json:"user,omitempty"` // The other member of a direct message
+	Creator   string `json:"creator,omitempty"`
+
+	IsExtShared bool `json:"is_ext_shared,omitempty"` // Shared with another organization (Slack Connect)
 }
 
 type BotInfo struct {
-	ID   string `json:"id"`
-	Name string `json:"name"`
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	UserID string `json:"user_id,omitempty"` // The app's bot user, if any
 }
 
 type UserResponse struct {
@@ -54,21 +94,71 @@ type BotResponse struct {
 	Bot BotInfo `json:"bot"`
 }
 
+// apiBaseURL is the Slack Web API base URL; it can be pointed at a fake server for load tests
+var apiBaseURL = "https://slack.com/api/"
+
+// SetAPIBaseURL overrides the Slack Web API base URL (it must end with a slash)
+func SetAPIBaseURL(baseURL string) {
+	if !strings.HasSuffix(baseURL, "/") {
+		baseURL += "/"
+	}
+	apiBaseURL = baseURL
+}
+
+// newClient creates a client for the bot token and workspace of a configuration
+func newClient(cfg *config.Config) *Client {
+	client := NewClient(cfg.SlackBotToken).WithTeam(cfg.SlackTeamID)
+	client.recordMembership = cfg.RecordMembershipMessages
+	return client
+}
+
+// WithTeam makes the client send team_id with the methods that list a workspace's channels, user groups and
+// emoji, which org-wide tokens of Enterprise Grid require; it returns the client
+func (c *Client) WithTeam(teamID string) *Client {
+	c.teamID = teamID
+	c.httpClient.Transport = newRateLimitTransport(teamID)
+	return c
+}
+
+// workspaceKey identifies the token and workspace of the client, for caches that outlive a client
+func (c *Client) workspaceKey() string {
+	return c.token + "/" + c.teamID
+}
+
+// withTeam appends the client's team_id to the URL of a workspace-scoped method
+func (c *Client) withTeam(url string) string {
+	if c.teamID == "" {
+		return url
+	}
+	separator := "?"
+	if strings.Contains(url, "?") {
+		separator = "&"
+	}
+	return url + separator + "team_id=" + c.teamID
+}
+
 func NewClient(token string) *Client {
 	return &Client{
-		token:        token,
-		httpClient:   &http.Client{},
-		userCache:    make(map[string]*UserInfo),
-		channelCache: make(map[string]*ChannelInfo),
-		botCache:     make(map[string]*BotInfo),
+		token:             token,
+		httpClient:        &http.Client{Transport: newRateLimitTransport("")},
+		userCache:         make(map[string]*UserInfo),
+		userFetchedAt:     make(map[string]time.Time),
+		channelCache:      make(map[string]*ChannelInfo),
+		channelFetchedAt:  make(map[string]time.Time),
+		botCache:          make(map[string]*BotInfo),
+		profileFieldCache: make(map[string]string),
+		teamNameCache:     make(map[string]string),
 	}
 }
 
 const maxRetryAttempts = 4
 
-// retryWithBackoff executes a function with exponential backoff retry logic
+// retryWithBackoff executes a function with exponential backoff retry logic.
+// Rate limited attempts wait for the Retry-After Slack sent and do not count as attempts, until they have waited
+// maxRateLimitWait in total; the *RateLimitError is then returned so the caller can reschedule the work.
 func retryWithBackoff(operation func() error, description string) error {
 	var lastErr error
+	var rateLimitWaited time.Duration
 
 	for attempt := 1; attempt <= maxRetryAttempts; attempt++ {
 		lastErr = operation()
@@ -81,6 +171,35 @@ func retryWithBackoff(operation func() error, description string) error {
 
 		log.Printf("Attempt %d failed for %s: %v", attempt, description, lastErr)
 
+		// Users of other organizations stay invisible to users.info
+		if isUserNotFoundError(lastErr) {
+			log.Printf("Not retrying %s: the user is not visible to the bot", description)
+			return lastErr
+		}
+
+		// Retrying cannot help once the bot has lost access to the channel
+		if isChannelAccessError(lastErr) {
+			log.Printf("Not retrying %s: the bot can no longer access the channel", description)
+			return lastErr
+		}
+		if scope := missingScope(lastErr); scope != "" {
+			log.Printf("Not retrying %s: the bot token lacks the %s scope", description, scope)
+			return lastErr
+		}
+
+		var rateLimitErr *RateLimitError
+		if errors.As(lastErr, &rateLimitErr) {
+			if rateLimitWaited+rateLimitErr.RetryAfter > maxRateLimitWait {
+				log.Printf("Giving up %s for now: rate limited for %v in total", description, rateLimitWaited)
+				return lastErr
+			}
+			rateLimitWaited += rateLimitErr.RetryAfter
+			attempt--
+			log.Printf("Retrying %s in %v as requested by Slack's Retry-After...", description, rateLimitErr.RetryAfter)
+			time.Sleep(rateLimitErr.RetryAfter)
+			continue
+		}
+
 		// If this was the last attempt, don't sleep
 		if attempt == maxRetryAttempts {
 			break
@@ -97,17 +216,18 @@ func retryWithBackoff(operation func() error, description string) error {
 }
 
 func (c *Client) GetUserInfo(userID string) (*UserInfo, error) {
-	// Check cache first
-	if user, exists := c.userCache[userID]; exists {
+	// Check cache first, unless the user changed their profile after it was cached
+	if user, exists := c.userCache[userID]; exists && !userChangedSince(userID, c.userFetchedAt[userID]) {
 		return user, nil
 	}
+	fetchedAt := time.Now()
 
 	var result *UserInfo
 	err := retryWithBackoff(func() error {
 		// Rate limiting: small delay between API calls
 		time.Sleep(100 * time.Millisecond)
 
-		url := fmt.Sprintf("https://slack.com/api/users.info?user=%s", userID)
+		url := apiBaseURL + fmt.Sprintf("users.info?user=%s&include_locale=true", userID)
 
 		req, err := http.NewRequest("GET", url, nil)
 		if err != nil {
@@ -146,22 +266,24 @@ func (c *Client) GetUserInfo(userID string) (*UserInfo, error) {
 
 	// Cache the result
 	c.userCache[userID] = result
+	c.userFetchedAt[userID] = fetchedAt
 
 	return result, nil
 }
 
 func (c *Client) GetChannelInfo(channelID string) (*ChannelInfo, error) {
-	// Check cache first
-	if channel, exists := c.channelCache[channelID]; exists {
+	// Check cache first, unless the channel was renamed after it was cached
+	if channel, exists := c.channelCache[channelID]; exists && !channelRenamedSince(channelID, c.channelFetchedAt[channelID]) {
 		return channel, nil
 	}
+	fetchedAt := time.Now()
 
 	var result *ChannelInfo
 	err := retryWithBackoff(func() error {
 		// Rate limiting: small delay between API calls
 		time.Sleep(100 * time.Millisecond)
 
-		url := fmt.Sprintf("https://slack.com/api/conversations.info?channel=%s", channelID)
+		url := apiBaseURL + fmt.Sprintf("conversations.info?channel=%s", channelID)
 
 		req, err := http.NewRequest("GET", url, nil)
 		if err != nil {
@@ -198,9 +320,63 @@ func (c *Client) GetChannelInfo(channelID string) (*ChannelInfo, error) {
 		return nil, err
 	}
 
+	// Direct messages have no (or no human-friendly) name, so derive one from the members
+	if result.IsIM || result.IsMpim {
+		result.Name = c.directMessageName(result)
+		// Members of a DM never change, so the participant-based name is stable enough to identify its tab
+		sheets.NameByParticipants(channelID)
+	}
+
 	// Cache the result
 	c.channelCache[channelID] = result
+	c.channelFetchedAt[channelID] = fetchedAt
+
+	return result, nil
+}
+
+// GetFileInfo fetches the details of a shared file (files.info)
+func (c *Client) GetFileInfo(fileID string) (*FileInfo, error) {
+	var result *FileInfo
+	err := retryWithBackoff(func() error {
+		url := apiBaseURL + fmt.Sprintf("files.info?file=%s", fileID)
 
+		req, err := http.NewRequest("GET", url, nil)
+		if err != nil {
+			return err
+		}
+
+		req.Header.Set("Authorization", "Bearer "+c.token)
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return err
+		}
+
+		var fileResp struct {
+			OK   bool     `json:"ok"`
+			File FileInfo `json:"file"`
+		}
+		if err := json.Unmarshal(body, &fileResp); err != nil {
+			return err
+		}
+
+		if !fileResp.OK {
+			return fmt.Errorf("slack API error: %s", string(body))
+		}
+
+		result = &fileResp.File
+		return nil
+	}, fmt.Sprintf("get file info for %s", fileID))
+
+	if err != nil {
+		return nil, err
+	}
 	return result, nil
 }
 
@@ -223,7 +399,7 @@ func (c *Client) GetBotInfo(botID string) (*BotInfo, error) {
 		// Rate limiting: small delay between API calls
 		time.Sleep(100 * time.Millisecond)
 
-		url := fmt.Sprintf("https://slack.com/api/bots.info?bot=%s", botID)
+		url := apiBaseURL + fmt.Sprintf("bots.info?bot=%s", botID)
 
 		req, err := http.NewRequest("GET", url, nil)
 		if err != nil {
@@ -266,14 +442,420 @@ func (c *Client) GetBotInfo(botID string) (*BotInfo, error) {
 	return result, nil
 }
 
+// AuthTestResponse is the response of auth.test
+type AuthTestResponse struct {
+	OK     bool   `json:"ok"`
+	UserID string `json:"user_id"`
+	BotID  string `json:"bot_id"`
+	URL    string `json:"url"` // Workspace URL such as "https://example.slack.com/"
+	TeamID string `json:"team_id"`
+	Error  string `json:"error,omitempty"`
+
+	EnterpriseID string `json:"enterprise_id,omitempty"` // Set on Enterprise Grid
+}
+
+// scopesCheckInterval is how long the granted scopes from auth.test are trusted before they are checked again
+const scopesCheckInterval = 10 * time.Minute
+
+// botIdentity is what auth.test reported for a bot token
+type botIdentity struct {
+	userID        string
+	workspaceURL  string
+	teamID        string
+	enterpriseID  string
+	scopes        []string
+	scopesChecked time.Time
+}
+
+// botIdentities caches auth.test per bot token, since each installed workspace has its own bot user
+var (
+	botIdentities      = make(map[string]*botIdentity)
+	botIdentitiesMutex sync.Mutex
+)
+
+// BotUserID returns the bot's own user ID from auth.test, cached for the lifetime of the process
+func (c *Client) BotUserID() (string, error) {
+	botIdentitiesMutex.Lock()
+	defer botIdentitiesMutex.Unlock()
+	if identity, exists := botIdentities[c.token]; exists && identity.userID != "" {
+		return identity.userID, nil
+	}
+
+	if err := c.authTest(); err != nil {
+		return "", err
+	}
+	return botIdentities[c.token].userID, nil
+}
+
+// WorkspaceURL returns the URL of the bot's workspace from auth.test (e.g. "https://example.slack.com/"), cached like
+// the bot user ID
+func (c *Client) WorkspaceURL() (string, error) {
+	botIdentitiesMutex.Lock()
+	defer botIdentitiesMutex.Unlock()
+	if identity, exists := botIdentities[c.token]; exists && identity.workspaceURL != "" {
+		return identity.workspaceURL, nil
+	}
+
+	if err := c.authTest(); err != nil {
+		return "", err
+	}
+	workspaceURL := botIdentities[c.token].workspaceURL
+	if workspaceURL == "" {
+		return "", fmt.Errorf("auth.test did not report the workspace URL")
+	}
+	return workspaceURL, nil
+}
+
+// homeTeam returns the workspace and Enterprise Grid organization of the bot token from auth.test, cached like the
+// bot user ID
+func (c *Client) homeTeam() (teamID, enterpriseID string, err error) {
+	botIdentitiesMutex.Lock()
+	defer botIdentitiesMutex.Unlock()
+	if identity, exists := botIdentities[c.token]; exists {
+		return identity.teamID, identity.enterpriseID, nil
+	}
+
+	if err := c.authTest(); err != nil {
+		return "", "", err
+	}
+	identity := botIdentities[c.token]
+	return identity.teamID, identity.enterpriseID, nil
+}
+
+// ResolveBotUserID calls auth.test at startup so mentions of this bot can be told apart from mentions of other users;
+// on failure the ID is looked up again on the next event
+func ResolveBotUserID(token string) {
+	userID, err := NewClient(token).BotUserID()
+	if err != nil {
+		log.Printf("Warning: Could not resolve the bot user ID via auth.test: %v", err)
+		return
+	}
+	log.Printf("  Bot user ID: %s", userID)
+}
+
+// HasScope reports whether the bot token was granted an OAuth scope (e.g. groups:history).
+// Scopes are re-read periodically so a reinstalled app with added scopes is picked up without a restart.
+func (c *Client) HasScope(scope string) (bool, error) {
+	botIdentitiesMutex.Lock()
+	defer botIdentitiesMutex.Unlock()
+	identity, exists := botIdentities[c.token]
+	if !exists || time.Since(identity.scopesChecked) >= scopesCheckInterval {
+		if err := c.authTest(); err != nil {
+			return false, err
+		}
+		identity = botIdentities[c.token]
+	}
+
+	if len(identity.scopes) == 0 {
+		return false, fmt.Errorf("auth.test did not report the granted scopes")
+	}
+	for _, granted := range identity.scopes {
+		if granted == scope {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// authTest calls auth.test and caches the bot user ID and the granted scopes; callers must hold botIdentitiesMutex
+func (c *Client) authTest() error {
+	return retryWithBackoff(func() error {
+		req, err := http.NewRequest("GET", apiBaseURL+"auth.test", nil)
+		if err != nil {
+			return err
+		}
+
+		req.Header.Set("Authorization", "Bearer "+c.token)
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return err
+		}
+
+		var authResp AuthTestResponse
+		if err := json.Unmarshal(body, &authResp); err != nil {
+			return err
+		}
+
+		if !authResp.OK {
+			return fmt.Errorf("slack API error: %s", string(body))
+		}
+
+		workspaceURL := authResp.URL
+		if workspaceURL != "" && !strings.HasSuffix(workspaceURL, "/") {
+			workspaceURL += "/"
+		}
+		identity := &botIdentity{
+			userID:        authResp.UserID,
+			workspaceURL:  workspaceURL,
+			teamID:        authResp.TeamID,
+			enterpriseID:  authResp.EnterpriseID,
+			scopesChecked: time.Now(),
+		}
+		for _, scope := range strings.Split(resp.Header.Get("X-OAuth-Scopes"), ",") {
+			if scope = strings.TrimSpace(scope); scope != "" {
+				identity.scopes = append(identity.scopes, scope)
+			}
+		}
+		botIdentities[c.token] = identity
+		return nil
+	}, "auth.test")
+}
+
+// messageAuthor resolves who posted a message. Bot names fall back from the message's bot_profile
+// to bots.info and then to users.info of the app's bot user, so integrations are not all recorded as "Bot".
+// Users of other organizations (Slack Connect) fall back to the message's user_profile when users.info fails, and
+// their real name is labeled with their organization.
+func (c *Client) messageAuthor(userID, botID, username, userTeam string, botProfile *BotProfile, userProfile *MessageUserProfile) *UserInfo {
+	if userID != "" {
+		userInfo, err := c.GetUserInfo(userID)
+		if err != nil {
+			log.Printf("Error getting user info for %s: %v", userID, err)
+			if userProfile == nil && userTeam == "" {
+				return &UserInfo{ID: userID, Name: "Unknown", RealName: "Unknown"}
+			}
+			userInfo = externalAuthor(userID, userTeam, userProfile)
+			if userProfile != nil {
+				// users.info will not see the user on later messages either
+				c.userCache[userID] = userInfo
+				c.userFetchedAt[userID] = time.Now()
+			}
+		} else if userInfo.TeamID == "" && userTeam != "" {
+			withTeam := *userInfo
+			withTeam.TeamID = userTeam
+			userInfo = &withTeam
+		}
+		if userInfo.IsBot && userInfo.RealName == "" {
+			// App bot users often have no real name
+			return &UserInfo{ID: userInfo.ID, Name: userInfo.Name, RealName: userInfo.Name, IsBot: true}
+		}
+		return c.labelExternalUser(userInfo)
+	}
+
+	if botID == "" && username == "" && botProfile == nil {
+		return &UserInfo{ID: "", Name: "System", RealName: "System"}
+	}
+
+	botName := c.resolveBotName(botID, username, botProfile)
+	return &UserInfo{ID: botID, Name: botName, RealName: botName, IsBot: true}
+}
+
+// resolveBotName returns the display name of a bot: bot_profile → bots.info → users.info of the bot user → username → "Bot"
+func (c *Client) resolveBotName(botID, username string, botProfile *BotProfile) string {
+	if botProfile != nil && botProfile.Name != "" {
+		return botProfile.Name
+	}
+
+	if botID != "" {
+		botInfo, err := c.GetBotInfo(botID)
+		if err != nil {
+			log.Printf("Could not get bot info for %s: %v", botID, err)
+		} else if botInfo.Name != "" {
+			return botInfo.Name
+		} else if botInfo.UserID != "" {
+			// bots.info succeeded without a name; the app's bot user usually has one
+			if userInfo, err := c.GetUserInfo(botInfo.UserID); err == nil && userInfo.IsBot {
+				if userInfo.RealName != "" {
+					return userInfo.RealName
+				}
+				if userInfo.Name != "" {
+					return userInfo.Name
+				}
+			} else if err != nil {
+				log.Printf("Could not get bot user info for %s: %v", botInfo.UserID, err)
+			}
+		}
+	}
+
+	if username != "" {
+		return username
+	}
+	return "Bot"
+}
+
+// GetUserCustomField retrieves the value of a custom profile field for a user via users.profile.get
+func (c *Client) GetUserCustomField(userID, fieldID string) (string, error) {
+	cacheKey := userID + "_" + fieldID
+	if value, exists := c.profileFieldCache[cacheKey]; exists && !userChangedSince(userID, c.userFetchedAt[cacheKey]) {
+		return value, nil
+	}
+	fetchedAt := time.Now()
+
+	var result string
+	err := retryWithBackoff(func() error {
+		// Rate limiting: small delay between API calls
+		time.Sleep(100 * time.Millisecond)
+
+		url := apiBaseURL + fmt.Sprintf("users.profile.get?user=%s", userID)
+
+		req, err := http.NewRequest("GET", url, nil)
+		if err != nil {
+			return err
+		}
+
+		req.Header.Set("Authorization", "Bearer "+c.token)
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return err
+		}
+
+		var profileResp UserProfileResponse
+		if err := json.Unmarshal(body, &profileResp); err != nil {
+			return err
+		}
+
+		if !profileResp.OK {
+			return fmt.Errorf("slack API error: %s", string(body))
+		}
+
+		result = profileResp.Profile.Fields[fieldID].Value
+		return nil
+	}, fmt.Sprintf("get profile field %s for %s", fieldID, userID))
+
+	if err != nil {
+		return "", err
+	}
+
+	// Cache the result
+	c.profileFieldCache[cacheKey] = result
+	c.userFetchedAt[cacheKey] = fetchedAt
+
+	return result, nil
+}
+
 func (c *Client) SendMessage(channel, text string) error {
+	payload := map[string]interface{}{
+		"channel": channel,
+		"text":    text,
+	}
+	return c.postAPI("chat.postMessage", payload, fmt.Sprintf("send message to channel %s", channel))
+}
+
+// PostMessage sends a message to a channel and returns its timestamp so it can be threaded on or updated
+func (c *Client) PostMessage(channel, text string) (string, error) {
+	payload := map[string]interface{}{
+		"channel": channel,
+		"text":    text,
+	}
+	response, err := c.callAPI("chat.postMessage", payload, fmt.Sprintf("post message to channel %s", channel))
+	if err != nil {
+		return "", err
+	}
+	ts, _ := response["ts"].(string)
+	return ts, nil
+}
+
+// PostBlocks sends a Block Kit message (text is the notification fallback) and returns its timestamp
+func (c *Client) PostBlocks(channel, text string, blocks []Block) (string, error) {
+	payload := map[string]interface{}{
+		"channel": channel,
+		"text":    text,
+		"blocks":  blocks,
+	}
+	response, err := c.callAPI("chat.postMessage", payload, fmt.Sprintf("post blocks to channel %s", channel))
+	if err != nil {
+		return "", err
+	}
+	ts, _ := response["ts"].(string)
+	return ts, nil
+}
+
+// UpdateBlocks replaces the content of a previously posted message via chat.update
+func (c *Client) UpdateBlocks(channel, ts, text string, blocks []Block) error {
+	payload := map[string]interface{}{
+		"channel": channel,
+		"ts":      ts,
+		"text":    text,
+		"blocks":  blocks,
+	}
+	return c.postAPI("chat.update", payload, fmt.Sprintf("update message %s in channel %s", ts, channel))
+}
+
+// SendThreadReply posts a message as a reply in the thread started by threadTS
+func (c *Client) SendThreadReply(channel, threadTS, text string) error {
+	payload := map[string]interface{}{
+		"channel":   channel,
+		"thread_ts": threadTS,
+		"text":      text,
+	}
+	return c.postAPI("chat.postMessage", payload, fmt.Sprintf("send thread reply to %s in channel %s", threadTS, channel))
+}
+
+// AddReaction adds an emoji reaction to a message, treating "already_reacted" as success
+func (c *Client) AddReaction(channel, timestamp, emoji string) error {
+	payload := map[string]interface{}{
+		"channel":   channel,
+		"timestamp": timestamp,
+		"name":      emoji,
+	}
+	err := c.postAPI("reactions.add", payload, fmt.Sprintf("add reaction %s to %s in channel %s", emoji, timestamp, channel))
+	if err != nil && strings.Contains(err.Error(), "already_reacted") {
+		return nil
+	}
+	return err
+}
+
+// PostResponse sends a delayed response to a slash command's response_url; ephemeral responses are only shown
+// to the user who ran the command
+func (c *Client) PostResponse(responseURL, text string, ephemeral bool) error {
+	responseType := "in_channel"
+	if ephemeral {
+		responseType = "ephemeral"
+	}
+	return c.postResponseURL(responseURL, map[string]interface{}{"response_type": responseType, "text": text})
+}
+
+// ReplaceOriginal replaces the message an interaction came from (e.g. to remove its buttons) via response_url
+func (c *Client) ReplaceOriginal(responseURL, text string) error {
+	return c.postResponseURL(responseURL, map[string]interface{}{"replace_original": true, "text": text})
+}
+
+// postResponseURL sends a payload to a slash command's or interaction's response_url with retries
+func (c *Client) postResponseURL(responseURL string, payload map[string]interface{}) error {
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
 	return retryWithBackoff(func() error {
-		url := "https://slack.com/api/chat.postMessage"
+		resp, err := c.httpClient.Post(responseURL, "application/json", strings.NewReader(string(jsonData)))
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
 
-		payload := map[string]interface{}{
-			"channel": channel,
-			"text":    text,
+		if resp.StatusCode != http.StatusOK {
+			body, _ := io.ReadAll(resp.Body)
+			return fmt.Errorf("response_url returned %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
 		}
+		return nil
+	}, "post to response_url")
+}
+
+// postAPI sends a JSON payload to a Slack Web API method with retry logic
+func (c *Client) postAPI(method string, payload map[string]interface{}, description string) error {
+	_, err := c.callAPI(method, payload, description)
+	return err
+}
+
+// callAPI POSTs a JSON payload to a Slack Web API method with retries and returns the decoded response
+func (c *Client) callAPI(method string, payload map[string]interface{}, description string) (map[string]interface{}, error) {
+	var response map[string]interface{}
+	err := retryWithBackoff(func() error {
+		url := apiBaseURL + method
 
 		jsonData, err := json.Marshal(payload)
 		if err != nil {
@@ -299,7 +881,7 @@ func (c *Client) SendMessage(channel, text string) error {
 			return err
 		}
 
-		var response map[string]interface{}
+		response = nil
 		if err := json.Unmarshal(body, &response); err != nil {
 			return err
 		}
@@ -309,7 +891,8 @@ func (c *Client) SendMessage(channel, text string) error {
 		}
 
 		return nil
-	}, fmt.Sprintf("send message to channel %s", channel))
+	}, description)
+	return response, err
 }
 
 type HistoryResponse struct {
@@ -324,15 +907,31 @@ type ResponseMetadata struct {
 }
 
 type HistoryMessage struct {
-	Type        string       `json:"type"`
-	User        string       `json:"user"`
-	Text        string       `json:"text"`
-	Timestamp   string       `json:"ts"`
-	ThreadTS    string       `json:"thread_ts,omitempty"`
-	BotID       string       `json:"bot_id,omitempty"`
-	Username    string       `json:"username,omitempty"`
-	Attachments []Attachment `json:"attachments,omitempty"`
-	Files       []FileInfo   `json:"files,omitempty"`
+	Type        string        `json:"type"`
+	User        string        `json:"user"`
+	Text        string        `json:"text"`
+	Timestamp   string        `json:"ts"`
+	ThreadTS    string        `json:"thread_ts,omitempty"`
+	Subtype     string        `json:"subtype,omitempty"`
+	Topic       string        `json:"topic,omitempty"`   // For channel_topic messages
+	Purpose     string        `json:"purpose,omitempty"` // For channel_purpose messages
+	Room        *HuddleRoom   `json:"room,omitempty"`    // For huddle_thread messages
+	Team        string        `json:"team,omitempty"`    // Workspace the message was posted from
+	BotID       string        `json:"bot_id,omitempty"`
+	Username    string        `json:"username,omitempty"`
+	AppID       string        `json:"app_id,omitempty"`
+	BotProfile  *BotProfile   `json:"bot_profile,omitempty"`
+	Edited      *EditInfo     `json:"edited,omitempty"`
+	Attachments []Attachment  `json:"attachments,omitempty"`
+	Files       []FileInfo    `json:"files,omitempty"`
+	Blocks      MessageBlocks `json:"blocks,omitempty"`
+	Reactions   []Reaction    `json:"reactions,omitempty"`
+	PinnedTo    []string      `json:"pinned_to,omitempty"` // Channels the message is pinned to
+	ReplyCount  int           `json:"reply_count,omitempty"`
+	UserTeam    string        `json:"user_team,omitempty"` // Home workspace of the author
+	Inviter     string        `json:"inviter,omitempty"`   // Who added the user of a channel_join message
+
+	UserProfile *MessageUserProfile `json:"user_profile,omitempty"`
 }
 
 func (c *Client) GetChannelHistory(channelID string, limit int) ([]HistoryMessage, error) {
@@ -343,13 +942,17 @@ func (c *Client) GetChannelHistory(channelID string, limit int) ([]HistoryMessag
 	log.Printf("Starting to retrieve channel history for %s (limit: %d)", channelID, limit)
 
 	for {
+		// Stop paging as soon as the bot is removed from the channel or the retrieval is cancelled
+		if err := historyStopped(channelID); err != nil {
+			return nil, err
+		}
 		var historyResp HistoryResponse
 		err := retryWithBackoff(func() error {
 			var url string
 			if cursor == "" {
-				url = fmt.Sprintf("https://slack.com/api/conversations.history?channel=%s&limit=%d", channelID, pageLimit)
+				url = apiBaseURL + fmt.Sprintf("conversations.history?channel=%s&limit=%d", channelID, pageLimit)
 			} else {
-				url = fmt.Sprintf("https://slack.com/api/conversations.history?channel=%s&limit=%d&cursor=%s", channelID, pageLimit, cursor)
+				url = apiBaseURL + fmt.Sprintf("conversations.history?channel=%s&limit=%d&cursor=%s", channelID, pageLimit, cursor)
 			}
 
 			req, err := http.NewRequest("GET", url, nil)
@@ -433,18 +1036,40 @@ func (c *Client) GetChannelHistory(channelID string, limit int) ([]HistoryMessag
 }
 
 func (c *Client) getThreadReplies(channelID, threadTS string) ([]HistoryMessage, error) {
+	messages, err := c.getThreadMessages(channelID, threadTS)
+	if err != nil {
+		return nil, err
+	}
+
 	var allReplies []HistoryMessage
+	for _, message := range messages {
+		// The parent is in the channel history already, and so are replies also sent to the channel
+		if message.Timestamp == threadTS || message.Subtype == "thread_broadcast" {
+			continue
+		}
+		allReplies = append(allReplies, message)
+	}
+	return allReplies, nil
+}
+
+// getThreadMessages returns the parent and every reply of a thread in posting order (conversations.replies)
+func (c *Client) getThreadMessages(channelID, threadTS string) ([]HistoryMessage, error) {
+	var allMessages []HistoryMessage
 	cursor := ""
 	pageLimit := 200 // Maximum per page
 
 	for {
+		// Stop paging as soon as the bot is removed from the channel or the retrieval is cancelled
+		if err := historyStopped(channelID); err != nil {
+			return nil, err
+		}
 		var repliesResp HistoryResponse
 		err := retryWithBackoff(func() error {
 			var url string
 			if cursor == "" {
-				url = fmt.Sprintf("https://slack.com/api/conversations.replies?channel=%s&ts=%s&limit=%d", channelID, threadTS, pageLimit)
+				url = apiBaseURL + fmt.Sprintf("conversations.replies?channel=%s&ts=%s&limit=%d", channelID, threadTS, pageLimit)
 			} else {
-				url = fmt.Sprintf("https://slack.com/api/conversations.replies?channel=%s&ts=%s&limit=%d&cursor=%s", channelID, threadTS, pageLimit, cursor)
+				url = apiBaseURL + fmt.Sprintf("conversations.replies?channel=%s&ts=%s&limit=%d&cursor=%s", channelID, threadTS, pageLimit, cursor)
 			}
 
 			req, err := http.NewRequest("GET", url, nil)
@@ -480,9 +1105,12 @@ func (c *Client) getThreadReplies(channelID, threadTS string) ([]HistoryMessage,
 			return nil, err
 		}
 
-		// Skip the first message as it's the parent (already included in main messages)
-		if len(repliesResp.Messages) > 1 {
-			allReplies = append(allReplies, repliesResp.Messages[1:]...)
+		// Every page starts with the parent; keep it once
+		for i, message := range repliesResp.Messages {
+			if i == 0 && len(allMessages) > 0 && message.Timestamp == allMessages[0].Timestamp {
+				continue
+			}
+			allMessages = append(allMessages, message)
 		}
 
 		// Check if we have more pages
@@ -499,7 +1127,7 @@ func (c *Client) getThreadReplies(channelID, threadTS string) ([]HistoryMessage,
 		time.Sleep(150 * time.Millisecond)
 	}
 
-	return allReplies, nil
+	return allMessages, nil
 }
 
 // GetChannelHistoryWithProgress retrieves channel history with progress tracking and resumption capability
@@ -507,7 +1135,7 @@ func (c *Client) GetChannelHistoryWithProgress(channelID, channelName string, li
 	// Check for existing progress
 	existingProgress, err := progressMgr.LoadProgress(channelID)
 	if err != nil {
-		log.Printf("Error loading progress: %v", err)
+		log.Printf("Error loading progress, retrieving the history of %s from the start: %v", channelID, err)
 		existingProgress = nil
 	}
 
@@ -549,13 +1177,17 @@ func (c *Client) GetChannelHistoryWithProgress(channelID, channelName string, li
 	messageCount := 0
 
 	for {
+		// Stop paging as soon as the bot is removed from the channel or the retrieval is cancelled
+		if err := historyStopped(channelID); err != nil {
+			return nil, err
+		}
 		var historyResp HistoryResponse
 		err := retryWithBackoff(func() error {
 			var url string
 			if cursor == "" {
-				url = fmt.Sprintf("https://slack.com/api/conversations.history?channel=%s&limit=%d", channelID, pageLimit)
+				url = apiBaseURL + fmt.Sprintf("conversations.history?channel=%s&limit=%d", channelID, pageLimit)
 			} else {
-				url = fmt.Sprintf("https://slack.com/api/conversations.history?channel=%s&limit=%d&cursor=%s", channelID, pageLimit, cursor)
+				url = apiBaseURL + fmt.Sprintf("conversations.history?channel=%s&limit=%d&cursor=%s", channelID, pageLimit, cursor)
 			}
 
 			req, err := http.NewRequest("GET", url, nil)
@@ -596,62 +1228,34 @@ func (c *Client) GetChannelHistoryWithProgress(channelID, channelName string, li
 		// Convert messages to MessageRecord format and add to collection
 		var pageRecords []*sheets.MessageRecord
 		for _, msg := range historyResp.Messages {
-			if msg.Type == "message" {
+			if msg.Type == "message" && !c.skipsMessage(msg.Subtype) {
 				// Get user info (handle both human users and bots)
-				var userInfo *UserInfo
-				if msg.User != "" {
-					// Human user message
-					var err error
-					userInfo, err = c.GetUserInfo(msg.User)
-					if err != nil {
-						log.Printf("Error getting user info for %s: %v", msg.User, err)
-						userInfo = &UserInfo{ID: msg.User, Name: "Unknown", RealName: "Unknown"}
-					}
-				} else if msg.BotID != "" || msg.Username != "" {
-					// Bot message - try to get bot information from API
-					botName := msg.Username
-					if msg.BotID != "" {
-						// Try to get actual bot name from API
-						if botInfo, err := c.GetBotInfo(msg.BotID); err == nil {
-							botName = botInfo.Name
-						} else {
-							log.Printf("Could not get bot info for %s: %v", msg.BotID, err)
-							// Fallback to username or "Bot"
-							if msg.Username != "" {
-								botName = msg.Username
-							} else {
-								botName = "Bot"
-							}
-						}
-					} else if botName == "" {
-						botName = "Bot"
-					}
-					userInfo = &UserInfo{
-						ID:       msg.BotID,
-						Name:     botName,
-						RealName: botName,
-					}
-				} else {
-					// System message or unknown
-					userInfo = &UserInfo{ID: "", Name: "System", RealName: "System"}
-				}
+				userInfo := c.messageAuthor(msg.User, msg.BotID, msg.Username, msg.UserTeam, msg.BotProfile, msg.UserProfile)
 
 				// Parse timestamp and convert to JST
 				timestamp := convertSlackTimestampToJST(msg.Timestamp)
 
 				// Format message text including attachments
-				formattedText := c.FormatMessageWithAttachments(msg.Text, msg.Attachments, msg.Files)
+				formattedText := c.FormatMessageWithAttachments(msg.Subtype, messageText(systemMessageText(msg.Subtype, msg.Text, msg.Topic, msg.Purpose, msg.User, msg.Inviter, msg.Room), msg.Blocks), msg.Attachments, msg.Files)
 
 				record := &sheets.MessageRecord{
-					Timestamp:    timestamp,
-					Channel:      channelID,
-					ChannelName:  channelName,
-					User:         msg.User,
-					UserHandle:   userInfo.Name,
-					UserRealName: userInfo.RealName,
-					Text:         formattedText,
-					ThreadTS:     msg.ThreadTS,
-					MessageTS:    msg.Timestamp,
+					Timestamp:     timestamp,
+					Channel:       channelID,
+					ChannelName:   channelName,
+					User:          msg.User,
+					UserHandle:    userInfo.Name,
+					UserRealName:  userInfo.RealName,
+					Text:          formattedText,
+					ThreadTS:      msg.ThreadTS,
+					MessageTS:     msg.Timestamp,
+					AppSource:     appSource(msg.AppID, msg.BotProfile),
+					EditorID:      editorID(msg.Edited),
+					Reactions:     formatReactions(msg.Reactions),
+					ReactionCount: reactionCount(msg.Reactions),
+					ReplyCount:    msg.ReplyCount,
+					Files:         fileRecords(msg.Files),
+					Pinned:        len(msg.PinnedTo) > 0,
+					TeamID:        msg.Team,
 				}
 
 				pageRecords = append(pageRecords, record)
@@ -671,60 +1275,32 @@ func (c *Client) GetChannelHistoryWithProgress(channelID, channelName string, li
 
 				// Convert thread replies to MessageRecord format
 				for _, reply := range threadReplies {
-					if reply.Type == "message" {
+					if reply.Type == "message" && !c.skipsMessage(reply.Subtype) {
 						// Get user info (handle both human users and bots)
-						var userInfo *UserInfo
-						if reply.User != "" {
-							// Human user message
-							var err error
-							userInfo, err = c.GetUserInfo(reply.User)
-							if err != nil {
-								log.Printf("Error getting user info for %s: %v", reply.User, err)
-								userInfo = &UserInfo{ID: reply.User, Name: "Unknown", RealName: "Unknown"}
-							}
-						} else if reply.BotID != "" || reply.Username != "" {
-							// Bot message - try to get bot information from API
-							botName := reply.Username
-							if reply.BotID != "" {
-								// Try to get actual bot name from API
-								if botInfo, err := c.GetBotInfo(reply.BotID); err == nil {
-									botName = botInfo.Name
-								} else {
-									log.Printf("Could not get bot info for %s: %v", reply.BotID, err)
-									// Fallback to username or "Bot"
-									if reply.Username != "" {
-										botName = reply.Username
-									} else {
-										botName = "Bot"
-									}
-								}
-							} else if botName == "" {
-								botName = "Bot"
-							}
-							userInfo = &UserInfo{
-								ID:       reply.BotID,
-								Name:     botName,
-								RealName: botName,
-							}
-						} else {
-							// System message or unknown
-							userInfo = &UserInfo{ID: "", Name: "System", RealName: "System"}
-						}
+						userInfo := c.messageAuthor(reply.User, reply.BotID, reply.Username, reply.UserTeam, reply.BotProfile, reply.UserProfile)
 
 						timestamp := convertSlackTimestampToJST(reply.Timestamp)
 
-						formattedText := c.FormatMessageWithAttachments(reply.Text, reply.Attachments, reply.Files)
+						formattedText := c.FormatMessageWithAttachments(reply.Subtype, messageText(systemMessageText(reply.Subtype, reply.Text, reply.Topic, reply.Purpose, reply.User, reply.Inviter, reply.Room), reply.Blocks), reply.Attachments, reply.Files)
 
 						record := &sheets.MessageRecord{
-							Timestamp:    timestamp,
-							Channel:      channelID,
-							ChannelName:  channelName,
-							User:         reply.User,
-							UserHandle:   userInfo.Name,
-							UserRealName: userInfo.RealName,
-							Text:         formattedText,
-							ThreadTS:     reply.ThreadTS,
-							MessageTS:    reply.Timestamp,
+							Timestamp:     timestamp,
+							Channel:       channelID,
+							ChannelName:   channelName,
+							User:          reply.User,
+							UserHandle:    userInfo.Name,
+							UserRealName:  userInfo.RealName,
+							Text:          formattedText,
+							ThreadTS:      reply.ThreadTS,
+							MessageTS:     reply.Timestamp,
+							AppSource:     appSource(reply.AppID, reply.BotProfile),
+							EditorID:      editorID(reply.Edited),
+							Reactions:     formatReactions(reply.Reactions),
+							ReactionCount: reactionCount(reply.Reactions),
+							ReplyCount:    reply.ReplyCount,
+							Files:         fileRecords(reply.Files),
+							Pinned:        len(reply.PinnedTo) > 0,
+							TeamID:        reply.Team,
 						}
 
 						pageRecords = append(pageRecords, record)
@@ -812,6 +1388,18 @@ func (c *Client) FormatMessageText(text string) string {
 		return match // Keep original if failed to resolve
 	})
 
+	// Convert user group mentions: <!subteam^S123456|@group> -> @group-handle
+	text = c.formatUserGroupMentions(text)
+
+	// Convert special mentions and dates: <!here> -> @here, <!date^1392734382^{date_num}|...> -> 2014-02-18
+	text = formatSpecialMentions(text)
+
+	// Unwrap links: <https://example.com|Example> -> Example (https://example.com)
+	text = unwrapLinks(text)
+
+	// Convert emoji shortcodes: :tada: -> 🎉
+	text = c.formatEmoji(text)
+
 	// Convert channel mentions: <#C123456|general> -> #general
 	channelMentionRe := regexp.MustCompile(`<#[CD][A-Z0-9]+\|([^>]+)>`)
 	text = channelMentionRe.ReplaceAllString(text, "#$1")
@@ -834,9 +1422,13 @@ func (c *Client) FormatMessageText(text string) string {
 	return text
 }
 
-// FormatMessageWithAttachments formats message text including attachments and files
-func (c *Client) FormatMessageWithAttachments(text string, attachments []Attachment, files []FileInfo) string {
+// FormatMessageWithAttachments formats message text including attachments and files.
+// /me messages are italicized and file shares are listed as "[file: name]" so they are readable without the text.
+func (c *Client) FormatMessageWithAttachments(subtype, text string, attachments []Attachment, files []FileInfo) string {
 	formattedText := c.FormatMessageText(text)
+	if subtype == "me_message" && formattedText != "" {
+		formattedText = "_" + formattedText + "_"
+	}
 
 	var parts []string
 	if formattedText != "" {
@@ -844,12 +1436,14 @@ func (c *Client) FormatMessageWithAttachments(text string, attachments []Attachm
 	}
 
 	// Add attachment content
-	if attachmentText := formatAttachments(attachments); attachmentText != "" {
+	if attachmentText := c.formatAttachments(attachments); attachmentText != "" {
 		parts = append(parts, attachmentText)
 	}
 
 	// Add file content
-	if fileText := formatFiles(files); fileText != "" {
+	if subtype == "file_share" {
+		parts = append(parts, formatSharedFiles(files))
+	} else if fileText := formatFiles(files); fileText != "" {
 		parts = append(parts, fileText)
 	}
 
@@ -866,14 +1460,18 @@ func (c *Client) getMessagesAfterTime(channelID, channelName string, afterTime t
 	log.Printf("Getting messages after %v for channel %s (optimized approach)", afterTime, channelID)
 
 	for {
+		// Stop paging as soon as the bot is removed from the channel or the retrieval is cancelled
+		if err := historyStopped(channelID); err != nil {
+			return nil, err
+		}
 		var historyResp HistoryResponse
 		err := retryWithBackoff(func() error {
 			var url string
 			if cursor == "" {
-				url = fmt.Sprintf("https://slack.com/api/conversations.history?channel=%s&limit=%d&oldest=%f",
+				url = apiBaseURL + fmt.Sprintf("conversations.history?channel=%s&limit=%d&oldest=%f",
 					channelID, pageLimit, float64(afterTime.Unix()))
 			} else {
-				url = fmt.Sprintf("https://slack.com/api/conversations.history?channel=%s&limit=%d&oldest=%f&cursor=%s",
+				url = apiBaseURL + fmt.Sprintf("conversations.history?channel=%s&limit=%d&oldest=%f&cursor=%s",
 					channelID, pageLimit, float64(afterTime.Unix()), cursor)
 			}
 
@@ -915,7 +1513,7 @@ func (c *Client) getMessagesAfterTime(channelID, channelName string, afterTime t
 		var pageRecords []*sheets.MessageRecord
 
 		for _, msg := range historyResp.Messages {
-			if msg.Type == "message" {
+			if msg.Type == "message" && !c.skipsMessage(msg.Subtype) {
 				// Parse timestamp and convert to JST
 				msgTime := convertSlackTimestampToJST(msg.Timestamp)
 
@@ -927,51 +1525,28 @@ func (c *Client) getMessagesAfterTime(channelID, channelName string, afterTime t
 				}
 
 				// Get user info (handle both human users and bots)
-				var userInfo *UserInfo
-				if msg.User != "" {
-					var err error
-					userInfo, err = c.GetUserInfo(msg.User)
-					if err != nil {
-						log.Printf("Error getting user info for %s: %v", msg.User, err)
-						userInfo = &UserInfo{ID: msg.User, Name: "Unknown", RealName: "Unknown"}
-					}
-				} else if msg.BotID != "" || msg.Username != "" {
-					botName := msg.Username
-					if msg.BotID != "" {
-						if botInfo, err := c.GetBotInfo(msg.BotID); err == nil {
-							botName = botInfo.Name
-						} else {
-							log.Printf("Could not get bot info for %s: %v", msg.BotID, err)
-							if msg.Username != "" {
-								botName = msg.Username
-							} else {
-								botName = "Bot"
-							}
-						}
-					} else if botName == "" {
-						botName = "Bot"
-					}
-					userInfo = &UserInfo{
-						ID:       msg.BotID,
-						Name:     botName,
-						RealName: botName,
-					}
-				} else {
-					userInfo = &UserInfo{ID: "", Name: "System", RealName: "System"}
-				}
+				userInfo := c.messageAuthor(msg.User, msg.BotID, msg.Username, msg.UserTeam, msg.BotProfile, msg.UserProfile)
 
-				formattedText := c.FormatMessageWithAttachments(msg.Text, msg.Attachments, msg.Files)
+				formattedText := c.FormatMessageWithAttachments(msg.Subtype, messageText(systemMessageText(msg.Subtype, msg.Text, msg.Topic, msg.Purpose, msg.User, msg.Inviter, msg.Room), msg.Blocks), msg.Attachments, msg.Files)
 
 				record := &sheets.MessageRecord{
-					Timestamp:    msgTime,
-					Channel:      channelID,
-					ChannelName:  channelName,
-					User:         msg.User,
-					UserHandle:   userInfo.Name,
-					UserRealName: userInfo.RealName,
-					Text:         formattedText,
-					ThreadTS:     msg.ThreadTS,
-					MessageTS:    msg.Timestamp,
+					Timestamp:     msgTime,
+					Channel:       channelID,
+					ChannelName:   channelName,
+					User:          msg.User,
+					UserHandle:    userInfo.Name,
+					UserRealName:  userInfo.RealName,
+					Text:          formattedText,
+					ThreadTS:      msg.ThreadTS,
+					MessageTS:     msg.Timestamp,
+					AppSource:     appSource(msg.AppID, msg.BotProfile),
+					EditorID:      editorID(msg.Edited),
+					Reactions:     formatReactions(msg.Reactions),
+					ReactionCount: reactionCount(msg.Reactions),
+					ReplyCount:    msg.ReplyCount,
+					Files:         fileRecords(msg.Files),
+					Pinned:        len(msg.PinnedTo) > 0,
+					TeamID:        msg.Team,
 				}
 
 				pageRecords = append(pageRecords, record)
@@ -1003,7 +1578,7 @@ func (c *Client) getMessagesAfterTime(channelID, channelName string, afterTime t
 
 					// Process thread replies, filtering by afterTime
 					for _, reply := range threadReplies {
-						if reply.Type == "message" {
+						if reply.Type == "message" && !c.skipsMessage(reply.Subtype) {
 							replyTime := convertSlackTimestampToJST(reply.Timestamp)
 
 							// Only include thread replies that are newer than afterTime
@@ -1011,52 +1586,29 @@ func (c *Client) getMessagesAfterTime(channelID, channelName string, afterTime t
 								continue
 							}
 
-							// Get user info for thread reply
-							var userInfo *UserInfo
-							if reply.User != "" {
-								var err error
-								userInfo, err = c.GetUserInfo(reply.User)
-								if err != nil {
-									log.Printf("Error getting user info for %s: %v", reply.User, err)
-									userInfo = &UserInfo{ID: reply.User, Name: "Unknown", RealName: "Unknown"}
-								}
-							} else if reply.BotID != "" || reply.Username != "" {
-								botName := reply.Username
-								if reply.BotID != "" {
-									if botInfo, err := c.GetBotInfo(reply.BotID); err == nil {
-										botName = botInfo.Name
-									} else {
-										log.Printf("Could not get bot info for %s: %v", reply.BotID, err)
-										if reply.Username != "" {
-											botName = reply.Username
-										} else {
-											botName = "Bot"
-										}
-									}
-								} else if botName == "" {
-									botName = "Bot"
-								}
-								userInfo = &UserInfo{
-									ID:       reply.BotID,
-									Name:     botName,
-									RealName: botName,
-								}
-							} else {
-								userInfo = &UserInfo{ID: "", Name: "System", RealName: "System"}
-							}
+							// Get user info (handle both human users and bots)
+							userInfo := c.messageAuthor(reply.User, reply.BotID, reply.Username, reply.UserTeam, reply.BotProfile, reply.UserProfile)
 
-							formattedText := c.FormatMessageWithAttachments(reply.Text, reply.Attachments, reply.Files)
+							formattedText := c.FormatMessageWithAttachments(reply.Subtype, messageText(systemMessageText(reply.Subtype, reply.Text, reply.Topic, reply.Purpose, reply.User, reply.Inviter, reply.Room), reply.Blocks), reply.Attachments, reply.Files)
 
 							replyRecord := &sheets.MessageRecord{
-								Timestamp:    replyTime,
-								Channel:      channelID,
-								ChannelName:  channelName,
-								User:         reply.User,
-								UserHandle:   userInfo.Name,
-								UserRealName: userInfo.RealName,
-								Text:         formattedText,
-								ThreadTS:     reply.ThreadTS,
-								MessageTS:    reply.Timestamp,
+								Timestamp:     replyTime,
+								Channel:       channelID,
+								ChannelName:   channelName,
+								User:          reply.User,
+								UserHandle:    userInfo.Name,
+								UserRealName:  userInfo.RealName,
+								Text:          formattedText,
+								ThreadTS:      reply.ThreadTS,
+								MessageTS:     reply.Timestamp,
+								AppSource:     appSource(reply.AppID, reply.BotProfile),
+								EditorID:      editorID(reply.Edited),
+								Reactions:     formatReactions(reply.Reactions),
+								ReactionCount: reactionCount(reply.Reactions),
+								ReplyCount:    reply.ReplyCount,
+								Files:         fileRecords(reply.Files),
+								Pinned:        len(reply.PinnedTo) > 0,
+								TeamID:        reply.Team,
 							}
 
 							allRecords = append(allRecords, replyRecord)
@@ -1094,8 +1646,8 @@ func (c *Client) getMessagesAfterTime(channelID, channelName string, afterTime t
 	return allRecords, nil
 }
 
-// formatAttachments converts attachments to readable text format
-func formatAttachments(attachments []Attachment) string {
+// formatAttachments converts attachments (link unfurls, forwarded messages, bot posts) to readable text format
+func (c *Client) formatAttachments(attachments []Attachment) string {
 	if len(attachments) == 0 {
 		return ""
 	}
@@ -1105,7 +1657,12 @@ func formatAttachments(attachments []Attachment) string {
 		var attParts []string
 
 		if att.Pretext != "" {
-			attParts = append(attParts, att.Pretext)
+			attParts = append(attParts, c.FormatMessageText(att.Pretext))
+		}
+
+		// Forwarded messages and unfurled message links carry the original poster
+		if att.AuthorName != "" {
+			attParts = append(attParts, att.AuthorName)
 		}
 
 		if att.Title != "" {
@@ -1117,7 +1674,10 @@ func formatAttachments(attachments []Attachment) string {
 		}
 
 		if att.Text != "" {
-			attParts = append(attParts, att.Text)
+			attParts = append(attParts, c.FormatMessageText(att.Text))
+		} else if att.Fallback != "" && att.Title == "" {
+			// Without a title or text the fallback is the only readable content
+			attParts = append(attParts, c.FormatMessageText(att.Fallback))
 		}
 
 		// Add fields
@@ -1138,10 +1698,6 @@ func formatAttachments(attachments []Attachment) string {
 			}
 		}
 
-		if att.Fallback != "" && len(attParts) == 0 {
-			attParts = append(attParts, att.Fallback)
-		}
-
 		if len(attParts) > 0 {
 			parts = append(parts, "[attachment] "+strings.Join(attParts, " | "))
 		}
@@ -1178,11 +1734,7 @@ func formatFiles(files []FileInfo) string {
 
 		// Add preview for text files
 		if file.Preview != "" {
-			preview := file.Preview
-			if len(preview) > 200 {
-				preview = preview[:200] + "..."
-			}
-			fileParts = append(fileParts, fmt.Sprintf("Body: %s", preview))
+			fileParts = append(fileParts, fmt.Sprintf("Body: %s", truncateText(file.Preview, 200)))
 		}
 
 		if file.Permalink != "" {
@@ -1196,3 +1748,28 @@ func formatFiles(files []FileInfo) string {
 
 	return strings.Join(parts, "\n\n")
 }
+
+// formatSharedFiles renders the files of a file_share message as "[file: name]" lines, with the link when available
+func formatSharedFiles(files []FileInfo) string {
+	if len(files) == 0 {
+		return "[file]" // Files can be hidden (e.g. from external workspaces)
+	}
+
+	var lines []string
+	for _, file := range files {
+		name := file.Name
+		if file.Title != "" {
+			name = file.Title
+		}
+		if name == "" {
+			name = file.ID
+		}
+
+		line := fmt.Sprintf("[file: %s]", name)
+		if file.Permalink != "" {
+			line += " " + file.Permalink
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
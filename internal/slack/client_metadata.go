@@ -0,0 +1,29 @@
+package slack
+
+// appSource describes the app or integration that posted a message as "name (app ID)", or "" for ordinary user posts
+func appSource(appID string, botProfile *BotProfile) string {
+	name := ""
+	if botProfile != nil {
+		name = botProfile.Name
+		if appID == "" {
+			appID = botProfile.AppID
+		}
+	}
+
+	switch {
+	case name != "" && appID != "":
+		return name + " (" + appID + ")"
+	case name != "":
+		return name
+	default:
+		return appID
+	}
+}
+
+// editorID returns the user ID of a message's last editor, or "" if it was never edited
+func editorID(edited *EditInfo) string {
+	if edited == nil {
+		return ""
+	}
+	return edited.User
+}
@@ -0,0 +1,30 @@
+package slack
+
+import (
+	"regexp"
+	"strings"
+)
+
+// leadingMentionPattern matches the user mention a message starts with (e.g. "<@U0123ABCD> " or "<@U0123ABCD|bot>: ")
+var leadingMentionPattern = regexp.MustCompile(`^\s*<@([A-Z0-9]+)(?:\|[^>]*)?>[\s:,]*`)
+
+// commandText strips the bot mention a message starts with and returns the rest, which is where commands are
+// read from. It reports false when the message does not start with the bot mention (e.g. "he said <@bot> reset"),
+// so mentions in the middle of a sentence are recorded like any other message instead of running a command.
+// An empty botUserID accepts any leading mention, for when auth.test is unavailable.
+func commandText(text, botUserID string) (string, bool) {
+	matches := leadingMentionPattern.FindStringSubmatch(text)
+	if matches == nil || (botUserID != "" && matches[1] != botUserID) {
+		return "", false
+	}
+	return strings.TrimSpace(text[len(matches[0]):]), true
+}
+
+// mentionsBot reports whether a message mentions the bot, in which case Slack also sends an app_mention event
+// that records it. Without a bot user ID every mention counts, so no message is recorded twice.
+func mentionsBot(text, botUserID string) bool {
+	if botUserID == "" {
+		return strings.Contains(text, "<@")
+	}
+	return strings.Contains(text, "<@"+botUserID+">") || strings.Contains(text, "<@"+botUserID+"|")
+}
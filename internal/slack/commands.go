@@ -0,0 +1,192 @@
+package slack
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"slack-to-google-sheets-bot/internal/config"
+	"slack-to-google-sheets-bot/internal/i18n"
+)
+
+// commandInvocation is one run of a bot command, from a mention or from the slash command
+type commandInvocation struct {
+	cfg         *config.Config
+	slackClient *Client
+	event       *Event
+	channelInfo *ChannelInfo
+	text        string // Command text without the bot mention or the slash command name
+}
+
+// botCommand is a command users can run by mentioning the bot or with the slash command
+type botCommand struct {
+	name    string
+	matches func(text string) bool
+	run     func(inv *commandInvocation) error
+}
+
+// commandRegistry lists every command; mentions and slash commands are dispatched through it so both behave the same
+var commandRegistry = []botCommand{
+	{
+		name:    "show me",
+		matches: func(text string) bool { return strings.HasPrefix(strings.ToLower(text), "show me") },
+		run: func(inv *commandInvocation) error {
+			return handleShowMeCommand(inv.cfg, inv.slackClient, inv.event, inv.channelInfo, extractEmailFromShowMe(inv.text))
+		},
+	},
+	{
+		name:    "search",
+		matches: searchCommandPattern.MatchString,
+		run: func(inv *commandInvocation) error {
+			return handleSearchCommand(inv.cfg, inv.slackClient, inv.event, inv.channelInfo, extractSearchQuery(inv.text))
+		},
+	},
+	{
+		name:    "find",
+		matches: findCommandPattern.MatchString,
+		run: func(inv *commandInvocation) error {
+			return handleFindCommand(inv.cfg, inv.slackClient, inv.event, inv.text)
+		},
+	},
+	{
+		name:    "settings",
+		matches: settingsCommandPattern.MatchString,
+		run: func(inv *commandInvocation) error {
+			return handleSettingsCommand(inv.cfg, inv.slackClient, inv.event, inv.channelInfo, inv.text)
+		},
+	},
+	{
+		name:    "resort",
+		matches: resortCommandPattern.MatchString,
+		run: func(inv *commandInvocation) error {
+			return handleResortCommand(inv.cfg, inv.slackClient, inv.event, inv.channelInfo)
+		},
+	},
+	{
+		name: "legal hold",
+		matches: func(text string) bool {
+			return holdCommandPattern.MatchString(text) || releaseHoldCommandPattern.MatchString(text)
+		},
+		run: func(inv *commandInvocation) error {
+			return handleHoldCommand(inv.cfg, inv.slackClient, inv.event, inv.channelInfo, inv.text)
+		},
+	},
+	{
+		name:    "backfill all",
+		matches: backfillAllCommandPattern.MatchString,
+		run: func(inv *commandInvocation) error {
+			return handleBackfillAllCommand(inv.cfg, inv.slackClient, inv.event)
+		},
+	},
+	{
+		name:    "reset",
+		matches: resetCommandPattern.MatchString,
+		run: func(inv *commandInvocation) error {
+			return handleResetCommand(inv.cfg, inv.slackClient, inv.event, inv.channelInfo, inv.text)
+		},
+	},
+}
+
+// lookupCommand returns the registered command matching the text, or nil
+func lookupCommand(text string) *botCommand {
+	for i := range commandRegistry {
+		if commandRegistry[i].matches(text) {
+			return &commandRegistry[i]
+		}
+	}
+	return nil
+}
+
+// runCommand runs the command in the text, replying with the help message when no command matches
+func runCommand(inv *commandInvocation) error {
+	command := lookupCommand(inv.text)
+	if command == nil {
+		helpMessage := i18n.T(replyLanguage(inv.cfg, inv.slackClient, inv.event.Event.User), i18n.KeyHelp)
+		if err := inv.slackClient.SendMessage(inv.event.Event.Channel, helpMessage); err != nil {
+			log.Printf("Error sending help message: %v", err)
+		}
+		return nil
+	}
+
+	tracef(inv.event, "Running %q command for %s in %s", command.name, inv.event.Event.User, inv.event.Event.Channel)
+	return command.run(inv)
+}
+
+// commandEvent builds the event commands expect from a mention for an invocation without a message (a slash command
+// or a button); the time of the invocation stands in for the message ts
+func commandEvent(eventType, teamID, triggerID, channelID, userID, text string) *Event {
+	now := time.Now()
+	return &Event{
+		Type:    eventType,
+		TeamID:  teamID,
+		EventID: triggerID,
+		TraceID: NewTraceID(),
+		Event: EventData{
+			Type:    eventType,
+			Channel: channelID,
+			User:    userID,
+			Text:    text,
+			EventTS: fmt.Sprintf("%d.%06d", now.Unix(), now.Nanosecond()/1000),
+		},
+	}
+}
+
+// slashCommandAliases maps the hyphenated spellings that suit slash commands to the mention commands
+var slashCommandAliases = map[string]string{
+	"show-me":       "show me",
+	"reset-preview": "reset preview",
+	"reset-confirm": "reset confirm",
+	"reset-force":   "reset force",
+}
+
+// slashCommandText turns the text of a slash command into the command text a mention would have
+func slashCommandText(text string) string {
+	text = strings.TrimSpace(text)
+	first, rest, _ := strings.Cut(text, " ")
+	if alias, exists := slashCommandAliases[strings.ToLower(first)]; exists {
+		return strings.TrimSpace(alias + " " + rest)
+	}
+	return text
+}
+
+// SlashCommandAck is the immediate response to a slash command, shown only to the user who ran it;
+// Slack requires a response within 3 seconds, so the command itself runs afterwards
+func SlashCommandAck(slashCommand *SlashCommand) string {
+	text := slashCommandText(slashCommand.Text)
+	if command := lookupCommand(text); command != nil {
+		return fmt.Sprintf("⏳ %s %s を受け付けました", slashCommand.Command, text)
+	}
+	return ""
+}
+
+// HandleSlashCommand runs a slash command (e.g. "/sheetbot reset") exactly like the same command after a bot mention
+// and reports the outcome to the user through the command's response_url
+func HandleSlashCommand(cfg *config.Config, slashCommand *SlashCommand) error {
+	cfg, err := configForTeam(cfg, slashCommand.TeamID, slashCommand.EnterpriseID)
+	if err != nil {
+		return fmt.Errorf("refusing slash command from workspace %s: %v", slashCommand.TeamID, err)
+	}
+	text := slashCommandText(slashCommand.Text)
+	event := commandEvent("slash_command", slashCommand.TeamID, slashCommand.TriggerID, slashCommand.ChannelID, slashCommand.UserID, text)
+	tracef(event, "Received slash command %s %q from %s in %s", slashCommand.Command, slashCommand.Text, slashCommand.UserID, slashCommand.ChannelID)
+
+	slackClient := newClient(cfg)
+	channelInfo, err := slackClient.GetChannelInfo(slashCommand.ChannelID)
+	if err != nil {
+		log.Printf("Error getting channel info for slash command: %v", err)
+		channelInfo = &ChannelInfo{ID: slashCommand.ChannelID, Name: "Unknown"}
+	}
+
+	commandErr := runCommand(&commandInvocation{cfg: cfg, slackClient: slackClient, event: event, channelInfo: channelInfo, text: text})
+
+	// Replies are posted to the channel like replies to mentions; the delayed response only tells the user it failed,
+	// e.g. because the bot is not a member of the channel
+	if commandErr != nil && slashCommand.ResponseURL != "" {
+		message := fmt.Sprintf("❌ %s %s を実行できませんでした: %v%s", slashCommand.Command, text, commandErr, traceSuffix(event))
+		if err := slackClient.PostResponse(slashCommand.ResponseURL, message, true); err != nil {
+			log.Printf("Error sending slash command response: %v", err)
+		}
+	}
+	return commandErr
+}
@@ -0,0 +1,38 @@
+package slack
+
+import (
+	"log"
+
+	"slack-to-google-sheets-bot/internal/completeness"
+	"slack-to-google-sheets-bot/internal/config"
+)
+
+// writeCompletenessSheet appends the received vs recorded counts of one day (YYYY-MM-DD) to the completeness tab
+func writeCompletenessSheet(cfg *config.Config, date string) {
+	if cfg.CompletenessSheetName == "" {
+		return
+	}
+
+	counts := completeness.Default().Day(date)
+	if len(counts) == 0 {
+		return
+	}
+
+	var rows [][]interface{}
+	for _, count := range counts {
+		rows = append(rows, []interface{}{
+			count.Date, count.ChannelID, count.Received, count.Recorded, count.Skipped, count.Failed, count.Completeness,
+		})
+	}
+
+	sheetsClient, err := newSheetsClient(cfg)
+	if err != nil {
+		log.Printf("Error creating Google Sheets client for completeness counts: %v", err)
+		return
+	}
+	if err := sheetsClient.AppendCompletenessRows(cfg.SpreadsheetID, cfg.CompletenessSheetName, rows); err != nil {
+		log.Printf("Error writing completeness counts for %s: %v", date, err)
+		return
+	}
+	log.Printf("Wrote completeness counts of %d channels for %s", len(rows), date)
+}
@@ -0,0 +1,60 @@
+package slack
+
+import (
+	"log"
+	"time"
+
+	"slack-to-google-sheets-bot/internal/config"
+)
+
+// StartDailyRollup rebuilds the daily rollup tab, rearranges the tabs and writes yesterday's completeness counts
+// every day shortly after midnight JST
+func StartDailyRollup(cfg *config.Config) {
+	if !cfg.DailyRollupEnabled && !cfg.ArrangeTabs && cfg.CompletenessSheetName == "" {
+		return
+	}
+	if cfg.GoogleSheetsCredentials == "" || cfg.SpreadsheetID == "" {
+		return
+	}
+
+	go func() {
+		for {
+			time.Sleep(time.Until(nextDailyRollupTime(time.Now())))
+			refreshDailyRollup(cfg)
+			arrangeSheetTabs(cfg)
+			writeCompletenessSheet(cfg, time.Now().In(jstLocation).AddDate(0, 0, -1).Format("2006-01-02"))
+		}
+	}()
+}
+
+// nextDailyRollupTime returns the next 00:05 JST after now
+func nextDailyRollupTime(now time.Time) time.Time {
+	local := now.In(jstLocation)
+	next := time.Date(local.Year(), local.Month(), local.Day(), 0, 5, 0, 0, jstLocation)
+	if !next.After(local) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next
+}
+
+// refreshDailyRollup rebuilds the rollup tab from all channel tabs
+func refreshDailyRollup(cfg *config.Config) {
+	if !cfg.DailyRollupEnabled {
+		return
+	}
+
+	sheetsClient, err := newSheetsClient(cfg)
+	if err != nil {
+		log.Printf("Error creating Google Sheets client for daily rollup: %v", err)
+		return
+	}
+
+	for _, spreadsheetID := range allSpreadsheetIDs(cfg) {
+		rows, err := sheetsClient.BuildDailyRollup(spreadsheetID, cfg.DailyRollupSheetName)
+		if err != nil {
+			log.Printf("Error building daily rollup for spreadsheet %s: %v", spreadsheetID, err)
+			continue
+		}
+		log.Printf("Daily rollup of spreadsheet %s rebuilt with %d rows in sheet %s", spreadsheetID, rows, cfg.DailyRollupSheetName)
+	}
+}
@@ -0,0 +1,67 @@
+package slack
+
+import (
+	"log"
+	"time"
+
+	"slack-to-google-sheets-bot/internal/config"
+	"slack-to-google-sheets-bot/internal/sheets"
+)
+
+// deletedStatus is written to the status column of deleted messages, followed by when they were deleted
+const deletedStatus = "deleted"
+
+// handleMessageDeleted marks the row of a deleted message in the status column, keeping the row so auditors can see
+// the message existed, and drops the message from the local message store's queries and search. It handles
+// message_deleted events and the tombstones Slack leaves of deleted thread parents.
+func handleMessageDeleted(cfg *config.Config, event *Event, messageTS string) error {
+	if messageTS == "" {
+		return nil
+	}
+	channelID := event.Event.Channel
+
+	deletedAt := time.Now()
+	if event.Event.EventTS != "" {
+		deletedAt = convertSlackTimestampToJST(event.Event.EventTS)
+	}
+	status := deletedStatus + " " + deletedAt.In(cfg.Location(channelID)).Format("2006-01-02 15:04:05")
+
+	// The store serves the query API and search, so it forgets deleted messages even when the sheet keeps them live
+	markRecordDeleted(cfg, channelID, messageTS, status)
+
+	if !cfg.RecordDeletions || cfg.GoogleSheetsCredentials == "" || cfg.SpreadsheetID == "" {
+		return nil
+	}
+
+	slackClient := newClient(cfg)
+	channelInfo, err := slackClient.GetChannelInfo(channelID)
+	if err != nil {
+		log.Printf("Error getting channel info for message deletion: %v", err)
+		return err
+	}
+
+	sheetsClient, err := newSheetsClient(cfg)
+	if err != nil {
+		log.Printf("Error creating Google Sheets client for message deletion: %v", err)
+		return err
+	}
+
+	sheetName := sheets.SheetName(channelID, channelInfo.Name)
+	row, err := sheetsClient.UpdateStatus(channelSpreadsheetID(cfg, sheetsClient, channelID), sheetName, messageTS, status)
+	if err != nil {
+		log.Printf("Error marking %s as deleted in sheet %s: %v", messageTS, sheetName, err)
+		return err
+	}
+	if row < 0 {
+		log.Printf("Message %s not recorded in sheet %s, ignoring its deletion", messageTS, sheetName)
+		return nil
+	}
+
+	mirrorWrite(cfg, "deletion of "+messageTS, func(sheetsClient *sheets.Client, spreadsheetID string) error {
+		_, err := sheetsClient.UpdateStatus(spreadsheetID, sheetName, messageTS, status)
+		return err
+	})
+
+	log.Printf("Marked %s as %s", buildSheetRangeURL(cfg, sheetsClient, channelID, channelInfo.Name, row, row), status)
+	return nil
+}
@@ -0,0 +1,257 @@
+package slack
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"slack-to-google-sheets-bot/internal/config"
+	"slack-to-google-sheets-bot/internal/sheets"
+)
+
+// ConversationsListResponse is the response of conversations.list
+type ConversationsListResponse struct {
+	OK               bool             `json:"ok"`
+	Channels         []ListedChannel  `json:"channels"`
+	ResponseMetadata ResponseMetadata `json:"response_metadata"`
+}
+
+// ListedChannel is a channel in conversations.list with the flags used to pick the ones to record
+type ListedChannel struct {
+	ChannelInfo
+	IsMember   bool `json:"is_member"`
+	IsArchived bool `json:"is_archived"`
+}
+
+// GetMemberChannels lists the public and private channels the bot is a member of (conversations.list)
+func (c *Client) GetMemberChannels() ([]*ChannelInfo, error) {
+	var channels []*ChannelInfo
+	cursor := ""
+
+	for {
+		var listResp ConversationsListResponse
+		err := retryWithBackoff(func() error {
+			url := c.withTeam(apiBaseURL + "conversations.list?types=public_channel,private_channel&exclude_archived=true&limit=200")
+			if cursor != "" {
+				url += "&cursor=" + cursor
+			}
+
+			req, err := http.NewRequest("GET", url, nil)
+			if err != nil {
+				return err
+			}
+
+			req.Header.Set("Authorization", "Bearer "+c.token)
+
+			resp, err := c.httpClient.Do(req)
+			if err != nil {
+				return err
+			}
+			defer resp.Body.Close()
+
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				return err
+			}
+
+			listResp = ConversationsListResponse{}
+			if err := json.Unmarshal(body, &listResp); err != nil {
+				return err
+			}
+
+			if !listResp.OK {
+				return fmt.Errorf("slack API error: %s", string(body))
+			}
+
+			return nil
+		}, "list channels")
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range listResp.Channels {
+			listed := &listResp.Channels[i]
+			if listed.IsMember && !listed.IsArchived {
+				channel := listed.ChannelInfo
+				channels = append(channels, &channel)
+			}
+		}
+
+		cursor = listResp.ResponseMetadata.NextCursor
+		if cursor == "" {
+			return channels, nil
+		}
+
+		// Add rate limiting between requests
+		time.Sleep(150 * time.Millisecond)
+	}
+}
+
+// listMemberChannels lists the channels the bot is a member of, in each of SLACK_TEAM_IDS on Enterprise Grid. A
+// channel shared by several of the workspaces is listed once.
+func listMemberChannels(cfg *config.Config, slackClient *Client) ([]*ChannelInfo, error) {
+	if len(cfg.SlackTeamIDs) == 0 {
+		return slackClient.GetMemberChannels()
+	}
+
+	var channels []*ChannelInfo
+	listed := make(map[string]bool)
+	for _, teamID := range cfg.SlackTeamIDs {
+		teamChannels, err := NewClient(slackClient.token).WithTeam(teamID).GetMemberChannels()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list the channels of workspace %s: %v", teamID, err)
+		}
+		for _, channel := range teamChannels {
+			if !listed[channel.ID] {
+				listed[channel.ID] = true
+				channels = append(channels, channel)
+			}
+		}
+	}
+	return channels, nil
+}
+
+// discoveryMutex keeps sync rounds from overlapping when one takes longer than the interval
+var discoveryMutex sync.Mutex
+
+// StartChannelDiscovery lists every channel the bot is a member of at startup and every CHANNEL_SYNC_INTERVAL_MINUTES,
+// starts the initial backfill of channels without a tab and appends the messages the others missed (e.g. while the
+// bot was down), so recording does not depend on member_joined_channel or mention events alone
+func StartChannelDiscovery(cfg *config.Config) {
+	if !cfg.AutoDiscoverChannels {
+		return
+	}
+	if cfg.SlackBotToken == "" || cfg.GoogleSheetsCredentials == "" || cfg.SpreadsheetID == "" {
+		log.Printf("Channel discovery needs SLACK_BOT_TOKEN and Google Sheets to be configured, not starting it")
+		return
+	}
+
+	interval := time.Duration(cfg.ChannelSyncIntervalMinutes) * time.Minute
+	if interval <= 0 {
+		interval = time.Hour
+	}
+
+	go func() {
+		for {
+			syncDiscoveredChannels(cfg)
+			time.Sleep(interval)
+		}
+	}()
+}
+
+// syncDiscoveredChannels runs one discovery round over every channel the bot is a member of
+func syncDiscoveredChannels(cfg *config.Config) {
+	discoveryMutex.Lock()
+	defer discoveryMutex.Unlock()
+
+	slackClient := newClient(cfg)
+	channels, err := listMemberChannels(cfg, slackClient)
+	if err != nil {
+		log.Printf("Error listing channels for discovery: %v", err)
+		return
+	}
+
+	sheetsClient, err := newSheetsClient(cfg)
+	if err != nil {
+		log.Printf("Error creating Google Sheets client for channel discovery: %v", err)
+		return
+	}
+
+	log.Printf("Channel discovery: the bot is a member of %d channels", len(channels))
+	for _, channel := range channels {
+		if channelRemoved(channel.ID) {
+			continue
+		}
+		historyProgressMutex.Lock()
+		inProgress := historyInProgress[channel.ID]
+		historyProgressMutex.Unlock()
+		if inProgress {
+			continue
+		}
+
+		if err := syncDiscoveredChannel(cfg, slackClient, sheetsClient, channel); err != nil {
+			log.Printf("Error syncing discovered channel %s (#%s): %v", channel.ID, channel.Name, err)
+		}
+	}
+}
+
+// syncDiscoveredChannel backfills a channel that has no tab yet, or appends the messages posted since its last
+// recorded one
+func syncDiscoveredChannel(cfg *config.Config, slackClient *Client, sheetsClient *sheets.Client, channel *ChannelInfo) error {
+	if refusesSharedChannel(cfg, channel) {
+		return nil
+	}
+
+	spreadsheetID := channelSpreadsheetID(cfg, sheetsClient, channel.ID)
+	exists, err := sheetsClient.HasChannelSheet(spreadsheetID, channel.ID)
+	if err != nil {
+		return err
+	}
+
+	// A channel never recorded gets the same initial backfill as when the bot is invited
+	if !exists {
+		log.Printf("Channel discovery: starting initial backfill of #%s (%s)", channel.Name, channel.ID)
+		event := announceInitialBackfill(slackClient, commandEvent("channel_discovered", "", "", channel.ID, "", ""), channel)
+		// The backfill runs on its own client; the client caches are not safe for concurrent use
+		go func() {
+			if err := performHistoryRetrieval(cfg, newClient(cfg), event, channel, true); err != nil {
+				log.Printf("Error backfilling discovered channel %s: %v", channel.ID, err)
+			}
+		}()
+		return nil
+	}
+
+	appended, err := appendMissedMessages(cfg, slackClient, sheetsClient, spreadsheetID, channel)
+	if appended > 0 {
+		log.Printf("Channel discovery: appended %d missed messages to #%s (%s)", appended, channel.Name, channel.ID)
+	}
+	return err
+}
+
+// announceInitialBackfill opens the status thread of an initial backfill the bot starts on its own and returns event
+func announceInitialBackfill(slackClient *Client, event *Event, channel *ChannelInfo) *Event {
+	message := fmt.Sprintf("🚀 初回の記録を開始します...\n"+
+		"このチャンネル (#%s) のメッセージをGoogle Sheetsに記録します。\n"+
+		"%s", channel.Name, recordingNotice)
+	if err := startStatusThread(slackClient, channel.ID, message); err != nil {
+		log.Printf("Error sending initial message: %v", err)
+	}
+	return event
+}
+
+// appendMissedMessages appends the messages posted after the newest recorded one of a channel's tab and returns
+// how many were written; rows that fail to write go to the retry spool
+func appendMissedMessages(cfg *config.Config, slackClient *Client, sheetsClient *sheets.Client, spreadsheetID string, channel *ChannelInfo) (int, error) {
+	if refusesSharedChannel(cfg, channel) {
+		return 0, nil
+	}
+	if err := sheetsClient.EnsureChannelSheetExists(spreadsheetID, channel.ID, channel.Name); err != nil {
+		return 0, err
+	}
+	_, lastTS, err := sheetsClient.LastMessageTS(spreadsheetID, sheets.SheetName(channel.ID, channel.Name))
+	if err != nil || lastTS == "" {
+		return 0, err
+	}
+
+	records, err := messagesAfterTS(cfg, slackClient, channel.ID, channel.Name, lastTS)
+	if err != nil || len(records) == 0 {
+		return 0, err
+	}
+
+	if err := sheetsClient.WriteBatchMessages(spreadsheetID, records); err != nil {
+		failed := spoolFailedRecords(channel.ID, err)
+		if failed == nil {
+			return 0, err
+		}
+		records = excludeRecords(records, failed)
+	}
+	annotateSettingChangesAt(cfg, sheetsClient, spreadsheetID, records)
+	runRecordHooks(cfg, sheetsClient, records, nil, false)
+	persistRecords(cfg, records)
+	mirrorRecords(cfg, records)
+	return len(records), nil
+}
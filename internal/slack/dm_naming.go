@@ -0,0 +1,143 @@
+package slack
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// maxDirectMessageNameLength keeps derived names well below the 100-character sheet title limit once "-<channel ID>" is appended
+const maxDirectMessageNameLength = 80
+
+// MembersResponse is the response of conversations.members
+type MembersResponse struct {
+	OK               bool             `json:"ok"`
+	Members          []string         `json:"members"`
+	ResponseMetadata ResponseMetadata `json:"response_metadata"`
+}
+
+// getConversationMembers returns the user IDs of a conversation's members
+func (c *Client) getConversationMembers(channelID string) ([]string, error) {
+	var members []string
+	cursor := ""
+	for {
+		var membersResp MembersResponse
+		err := retryWithBackoff(func() error {
+			// Rate limiting: small delay between API calls
+			time.Sleep(100 * time.Millisecond)
+
+			url := apiBaseURL + fmt.Sprintf("conversations.members?channel=%s&limit=200", channelID)
+			if cursor != "" {
+				url += "&cursor=" + cursor
+			}
+
+			req, err := http.NewRequest("GET", url, nil)
+			if err != nil {
+				return err
+			}
+
+			req.Header.Set("Authorization", "Bearer "+c.token)
+
+			resp, err := c.httpClient.Do(req)
+			if err != nil {
+				return err
+			}
+			defer resp.Body.Close()
+
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				return err
+			}
+
+			membersResp = MembersResponse{}
+			if err := json.Unmarshal(body, &membersResp); err != nil {
+				return err
+			}
+
+			if !membersResp.OK {
+				return fmt.Errorf("slack API error: %s", string(body))
+			}
+
+			return nil
+		}, fmt.Sprintf("get members of %s", channelID))
+		if err != nil {
+			return nil, err
+		}
+
+		members = append(members, membersResp.Members...)
+		cursor = membersResp.ResponseMetadata.NextCursor
+		if cursor == "" {
+			return members, nil
+		}
+	}
+}
+
+// isConversationMember reports whether a user is a member of a conversation
+func (c *Client) isConversationMember(channelID, userID string) (bool, error) {
+	members, err := c.getConversationMembers(channelID)
+	if err != nil {
+		return false, err
+	}
+	for _, member := range members {
+		if member == userID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// directMessageName derives a sheet-friendly name such as "dm-alice-bob" from the human members of an IM or MPIM.
+// It is recomputed on every lookup, so the sheet is renamed when the membership changes. On Enterprise Grid the
+// workspace ID comes first ("dm-t0123abcd-alice-bob"), since the same handles may exist in several workspaces.
+func (c *Client) directMessageName(channel *ChannelInfo) string {
+	memberIDs, err := c.getConversationMembers(channel.ID)
+	if err != nil {
+		log.Printf("Error getting members of %s: %v", channel.ID, err)
+		memberIDs = nil
+	}
+	if len(memberIDs) == 0 && channel.IMUser != "" {
+		memberIDs = []string{channel.IMUser}
+	}
+
+	var handles []string
+	for _, memberID := range memberIDs {
+		userInfo, err := c.GetUserInfo(memberID)
+		if err != nil {
+			handles = append(handles, memberID)
+			continue
+		}
+		if userInfo.IsBot {
+			continue // The bot itself is a member of every conversation it records
+		}
+		handles = append(handles, userInfo.Name)
+	}
+	sort.Strings(handles)
+
+	prefix := "dm-"
+	if c.teamID != "" {
+		prefix += c.teamID + "-"
+	}
+	return sanitizeSheetName(prefix + strings.Join(handles, "-"))
+}
+
+// sanitizeSheetName replaces characters that are not allowed (or awkward) in sheet titles and A1 ranges, and caps the length
+func sanitizeSheetName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		switch r {
+		case '[', ']', '*', '?', '/', '\\', ':', '\'', '!', ' ', '\t', '\n':
+			return '_'
+		}
+		return r
+	}, strings.ToLower(name))
+
+	name = strings.TrimRight(name, "-")
+	if runes := []rune(name); len(runes) > maxDirectMessageNameLength {
+		name = string(runes[:maxDirectMessageNameLength])
+	}
+	return name
+}
@@ -0,0 +1,164 @@
+package slack
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"slack-to-google-sheets-bot/internal/config"
+	"slack-to-google-sheets-bot/internal/sheets"
+)
+
+// pendingEdits holds, per channel, the latest edit of each message waiting to be written; a burst of edits to the
+// same message then costs one row update, and the edits of a channel share one sheet read and one batch update
+var (
+	pendingEdits      = make(map[string]map[string]*pendingEdit) // channel ID -> MessageTS -> latest edit
+	pendingEditTimers = make(map[string]*time.Timer)             // channel ID -> flush timer
+	pendingEditsMutex sync.Mutex
+)
+
+// editedMarker is appended to the text of messages first recorded from an edit, like Slack's own "(edited)" label
+const editedMarker = "（編集済み）"
+
+// pendingEdit is the newest known edit of a message
+type pendingEdit struct {
+	record   *sheets.MessageRecord
+	editedTS string // "ts" of the edit, to keep the newest when Slack delivers edits out of order
+}
+
+// queueEdit schedules the row update of an edited message. Edits arriving within EDIT_DEBOUNCE_SECONDS of the
+// first pending edit of the channel are written together, and only the latest edit of each message is kept.
+func queueEdit(cfg *config.Config, record *sheets.MessageRecord, editedTS string) {
+	window := time.Duration(cfg.EditDebounceSeconds) * time.Second
+	if window <= 0 {
+		flushEdits(cfg, []*sheets.MessageRecord{record})
+		return
+	}
+
+	pendingEditsMutex.Lock()
+	defer pendingEditsMutex.Unlock()
+
+	channelEdits, exists := pendingEdits[record.Channel]
+	if !exists {
+		channelEdits = make(map[string]*pendingEdit)
+		pendingEdits[record.Channel] = channelEdits
+	}
+	if previous, exists := channelEdits[record.MessageTS]; exists {
+		log.Printf("Squashing edit of message %s in channel %s into the pending one", record.MessageTS, record.Channel)
+		if previous.editedTS > editedTS {
+			return
+		}
+	}
+	channelEdits[record.MessageTS] = &pendingEdit{record: record, editedTS: editedTS}
+
+	if _, scheduled := pendingEditTimers[record.Channel]; scheduled {
+		return
+	}
+	channelID := record.Channel
+	pendingEditTimers[channelID] = time.AfterFunc(window, func() {
+		flushEdits(cfg, takePendingEdits(channelID))
+	})
+}
+
+// takePendingEdits returns and removes the pending edits of a channel
+func takePendingEdits(channelID string) []*sheets.MessageRecord {
+	pendingEditsMutex.Lock()
+	defer pendingEditsMutex.Unlock()
+
+	var records []*sheets.MessageRecord
+	for _, edit := range pendingEdits[channelID] {
+		records = append(records, edit.record)
+	}
+	delete(pendingEdits, channelID)
+	delete(pendingEditTimers, channelID)
+	return records
+}
+
+// stopPendingEdits drops the pending edits of a channel, e.g. when the bot is removed from it
+func stopPendingEdits(channelID string) {
+	pendingEditsMutex.Lock()
+	defer pendingEditsMutex.Unlock()
+
+	if timer, exists := pendingEditTimers[channelID]; exists {
+		timer.Stop()
+	}
+	delete(pendingEdits, channelID)
+	delete(pendingEditTimers, channelID)
+}
+
+// flushEdits rewrites the rows of edited messages of one channel with one sheet read and one batch update
+func flushEdits(cfg *config.Config, records []*sheets.MessageRecord) {
+	if len(records) == 0 {
+		return
+	}
+
+	sheetsClient, err := newSheetsClient(cfg)
+	if err != nil {
+		log.Printf("Error creating Google Sheets client for message edits: %v", err)
+		return
+	}
+
+	channelID, channelName := records[0].Channel, records[0].ChannelName
+	rows, err := sheetsClient.UpdateMessages(channelSpreadsheetID(cfg, sheetsClient, channelID), records)
+	if err != nil {
+		log.Printf("Error updating %d edited messages in Google Sheets (%s): %v",
+			len(records), buildSheetURLWithGID(cfg, sheetsClient, channelID, channelName), err)
+		return
+	}
+
+	var updated, missing []*sheets.MessageRecord
+	for _, record := range records {
+		row, exists := rows[record.MessageTS]
+		if !exists {
+			missing = append(missing, record)
+			continue
+		}
+		updated = append(updated, record)
+		log.Printf("✅ Message edit recorded in #%s by %s: %s (%s)",
+			record.ChannelName, record.UserHandle,
+			truncateText(record.Text, 50),
+			buildSheetRangeURL(cfg, sheetsClient, record.Channel, record.ChannelName, row, row))
+	}
+	insertMissingEdits(cfg, sheetsClient, missing)
+	if len(updated) == 0 {
+		return
+	}
+
+	runRecordHooks(cfg, sheetsClient, updated, rows, true)
+	persistRecords(cfg, updated)
+	mirrorWrite(cfg, "update edited messages", func(sheetsClient *sheets.Client, spreadsheetID string) error {
+		_, err := sheetsClient.UpdateMessages(spreadsheetID, updated)
+		return err
+	})
+}
+
+// insertMissingEdits records edited messages that are not in the sheet yet (e.g. posted while the bot was down)
+// as new rows flagged as edited, placed among the other rows by their timestamp
+func insertMissingEdits(cfg *config.Config, sheetsClient *sheets.Client, records []*sheets.MessageRecord) {
+	if len(records) == 0 {
+		return
+	}
+
+	for _, record := range records {
+		record.Text += editedMarker
+	}
+	channelID, channelName := records[0].Channel, records[0].ChannelName
+	err := sheetsClient.MergeMessages(channelSpreadsheetID(cfg, sheetsClient, channelID), records, nil)
+	if err != nil {
+		failed := spoolFailedRecords(channelID, err)
+		if failed == nil {
+			log.Printf("Error inserting %d edited messages missing from Google Sheets (%s): %v",
+				len(records), buildSheetURLWithGID(cfg, sheetsClient, channelID, channelName), err)
+			return
+		}
+		records = excludeRecords(records, failed)
+	}
+
+	for _, record := range records {
+		log.Printf("✅ Edited message %s was not recorded yet, inserted it in #%s by %s: %s",
+			record.MessageTS, record.ChannelName, record.UserHandle, truncateText(record.Text, 50))
+	}
+	runRecordHooks(cfg, sheetsClient, records, nil, false)
+	persistRecords(cfg, records)
+	mirrorRecords(cfg, records)
+}
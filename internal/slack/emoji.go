@@ -0,0 +1,163 @@
+package slack
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// customEmojiCacheTTL is how long the custom emoji from emoji.list are used before the list is fetched again
+const customEmojiCacheTTL = time.Hour
+
+// maxShortcodeLength bounds how far a colon is matched with the next one, so ordinary text with colons stays fast
+const maxShortcodeLength = 100
+
+// customEmojiListing is the cached emoji.list of one token and workspace
+type customEmojiListing struct {
+	emoji     map[string]string // Custom emoji name -> image URL or "alias:<name>"
+	fetchedAt time.Time
+}
+
+var (
+	// customEmojiListings caches emoji.list by workspaceKey; it outlives clients, which are created per event
+	customEmojiListings      = make(map[string]*customEmojiListing)
+	customEmojiListingsMutex sync.Mutex
+)
+
+// EmojiListResponse is the response of emoji.list
+type EmojiListResponse struct {
+	OK    bool              `json:"ok"`
+	Emoji map[string]string `json:"emoji"` // Name -> image URL, or "alias:<name>"
+}
+
+// formatEmoji replaces emoji shortcodes such as :tada: with Unicode emoji. Custom emoji have no Unicode form and
+// keep their :name:, except aliases of standard emoji, which are resolved. Code spans are left as they are, as Slack
+// shows them.
+func (c *Client) formatEmoji(text string) string {
+	if !strings.Contains(text, ":") {
+		return text
+	}
+
+	// Odd parts of the text split at backticks are inside `code` or ```code blocks```
+	parts := strings.Split(text, "`")
+	for i := 0; i < len(parts); i += 2 {
+		parts[i] = c.replaceShortcodes(parts[i])
+	}
+	return strings.Join(parts, "`")
+}
+
+// replaceShortcodes scans text for :name: and replaces the names that resolve to an emoji; a colon that does not
+// start a known shortcode can still end one, so "10:30 :tada:" works
+func (c *Client) replaceShortcodes(text string) string {
+	var builder strings.Builder
+	for {
+		start := strings.IndexByte(text, ':')
+		if start < 0 {
+			builder.WriteString(text)
+			return builder.String()
+		}
+		builder.WriteString(text[:start])
+		text = text[start:]
+
+		end := strings.IndexByte(text[1:], ':') + 1
+		if end > 1 && end <= maxShortcodeLength {
+			if emoji, found := c.resolveEmoji(text[1:end]); found {
+				builder.WriteString(emoji)
+				text = text[end+1:]
+				continue
+			}
+		}
+		builder.WriteByte(':')
+		text = text[1:]
+	}
+}
+
+// resolveEmoji returns the Unicode form of a standard emoji or of a custom alias of one
+func (c *Client) resolveEmoji(name string) (string, bool) {
+	if strings.ContainsAny(name, " \t\n") {
+		return "", false
+	}
+	if emoji, exists := standardEmoji[name]; exists {
+		return emoji, true
+	}
+
+	// Only names that are not standard emoji cost a look at the custom emoji
+	target, isAlias := strings.CutPrefix(c.customEmoji()[name], "alias:")
+	if !isAlias {
+		return "", false
+	}
+	emoji, exists := standardEmoji[target]
+	if !exists {
+		// An alias of another custom emoji is shown by the name it points to
+		return ":" + target + ":", true
+	}
+	return emoji, true
+}
+
+// customEmoji returns the workspace's custom emoji, fetching emoji.list when the cached list is stale
+func (c *Client) customEmoji() map[string]string {
+	customEmojiListingsMutex.Lock()
+	defer customEmojiListingsMutex.Unlock()
+
+	listing, exists := customEmojiListings[c.workspaceKey()]
+	if !exists {
+		listing = &customEmojiListing{}
+		customEmojiListings[c.workspaceKey()] = listing
+	}
+	if time.Since(listing.fetchedAt) >= customEmojiCacheTTL {
+		// Failures are not retried until the TTL has passed, so a missing scope does not cost a call per message
+		listing.fetchedAt = time.Now()
+		emoji, err := c.listCustomEmoji()
+		if err != nil {
+			log.Printf("Warning: Could not list custom emoji: %v", err)
+		} else {
+			listing.emoji = emoji
+		}
+	}
+	// The map is replaced, never modified, so it can be read after the lock is released
+	return listing.emoji
+}
+
+// listCustomEmoji returns the custom emoji of the workspace (emoji.list)
+func (c *Client) listCustomEmoji() (map[string]string, error) {
+	var listResp EmojiListResponse
+	err := retryWithBackoff(func() error {
+		req, err := http.NewRequest("GET", c.withTeam(apiBaseURL+"emoji.list"), nil)
+		if err != nil {
+			return err
+		}
+
+		req.Header.Set("Authorization", "Bearer "+c.token)
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return err
+		}
+
+		listResp = EmojiListResponse{}
+		if err := json.Unmarshal(body, &listResp); err != nil {
+			return err
+		}
+
+		if !listResp.OK {
+			return fmt.Errorf("slack API error: %s", string(body))
+		}
+
+		return nil
+	}, "list custom emoji")
+	if err != nil {
+		return nil, err
+	}
+	return listResp.Emoji, nil
+}
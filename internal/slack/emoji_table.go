@@ -0,0 +1,651 @@
+package slack
+
+// standardEmoji maps the shortcodes of Slack's standard emoji that are common in messages to their Unicode form;
+// skin tone modifiers follow the emoji they apply to, as in ":+1::skin-tone-2:"
+var standardEmoji = map[string]string{
+	"+1":                         "👍",
+	"-1":                         "👎",
+	"100":                        "💯",
+	"abc":                        "🔤",
+	"airplane":                   "✈️",
+	"alarm_clock":                "⏰",
+	"alien":                      "👽",
+	"ambulance":                  "🚑",
+	"anchor":                     "⚓",
+	"anger":                      "💢",
+	"angry":                      "😠",
+	"anguished":                  "😧",
+	"apple":                      "🍎",
+	"arrow_backward":             "◀️",
+	"arrow_down":                 "⬇️",
+	"arrow_forward":              "▶️",
+	"arrow_heading_down":         "⤵️",
+	"arrow_heading_up":           "⤴️",
+	"arrow_left":                 "⬅️",
+	"arrow_lower_left":           "↙️",
+	"arrow_lower_right":          "↘️",
+	"arrow_right":                "➡️",
+	"arrow_right_hook":           "↪️",
+	"arrow_up":                   "⬆️",
+	"arrow_up_down":              "↕️",
+	"arrow_upper_left":           "↖️",
+	"arrow_upper_right":          "↗️",
+	"arrows_clockwise":           "🔃",
+	"arrows_counterclockwise":    "🔄",
+	"art":                        "🎨",
+	"astonished":                 "😲",
+	"avocado":                    "🥑",
+	"back":                       "🔙",
+	"bacon":                      "🥓",
+	"balloon":                    "🎈",
+	"ballot_box_with_check":      "☑️",
+	"banana":                     "🍌",
+	"bangbang":                   "‼️",
+	"bar_chart":                  "📊",
+	"baseball":                   "⚾",
+	"basketball":                 "🏀",
+	"bathtub":                    "🛁",
+	"battery":                    "🔋",
+	"bear":                       "🐻",
+	"bed":                        "🛏️",
+	"bee":                        "🐝",
+	"beer":                       "🍺",
+	"beers":                      "🍻",
+	"beginner":                   "🔰",
+	"bell":                       "🔔",
+	"bento":                      "🍱",
+	"bike":                       "🚲",
+	"bird":                       "🐦",
+	"birthday":                   "🎂",
+	"black_circle":               "⚫",
+	"black_flag":                 "🏴",
+	"black_heart":                "🖤",
+	"black_large_square":         "⬛",
+	"black_nib":                  "✒️",
+	"black_square_for_stop":      "⏹️",
+	"blue_book":                  "📘",
+	"blue_heart":                 "💙",
+	"blush":                      "😊",
+	"boat":                       "⛵",
+	"book":                       "📖",
+	"bookmark":                   "🔖",
+	"bookmark_tabs":              "📑",
+	"books":                      "📚",
+	"boom":                       "💥",
+	"bouquet":                    "💐",
+	"bow":                        "🙇",
+	"bowling":                    "🎳",
+	"brain":                      "🧠",
+	"bread":                      "🍞",
+	"briefcase":                  "💼",
+	"broken_heart":               "💔",
+	"brown_heart":                "🤎",
+	"bug":                        "🐛",
+	"bulb":                       "💡",
+	"bullettrain_front":          "🚅",
+	"bullettrain_side":           "🚄",
+	"burrito":                    "🌯",
+	"bus":                        "🚌",
+	"butterfly":                  "🦋",
+	"cactus":                     "🌵",
+	"cake":                       "🍰",
+	"calendar":                   "📆",
+	"call_me_hand":               "🤙",
+	"camera":                     "📷",
+	"camera_with_flash":          "📸",
+	"candle":                     "🕯️",
+	"candy":                      "🍬",
+	"car":                        "🚗",
+	"card_file_box":              "🗃️",
+	"card_index":                 "📇",
+	"card_index_dividers":        "🗂️",
+	"cat":                        "🐱",
+	"cd":                         "💿",
+	"chains":                     "⛓️",
+	"champagne":                  "🍾",
+	"chart":                      "💹",
+	"chart_with_downwards_trend": "📉",
+	"chart_with_upwards_trend":   "📈",
+	"checkered_flag":             "🏁",
+	"cheese_wedge":               "🧀",
+	"cherries":                   "🍒",
+	"cherry_blossom":             "🌸",
+	"chicken":                    "🐔",
+	"chocolate_bar":              "🍫",
+	"christmas_tree":             "🎄",
+	"clap":                       "👏",
+	"clinking_glasses":           "🥂",
+	"clipboard":                  "📋",
+	"clock1":                     "🕐",
+	"clock12":                    "🕛",
+	"clock3":                     "🕒",
+	"closed_book":                "📕",
+	"closed_lock_with_key":       "🔐",
+	"cloud":                      "☁️",
+	"clown_face":                 "🤡",
+	"cn":                         "🇨🇳",
+	"cocktail":                   "🍸",
+	"coffee":                     "☕",
+	"cold_face":                  "🥶",
+	"cold_sweat":                 "😰",
+	"collision":                  "💥",
+	"computer":                   "💻",
+	"confetti_ball":              "🎊",
+	"confounded":                 "😖",
+	"confused":                   "😕",
+	"construction":               "🚧",
+	"convenience_store":          "🏪",
+	"cookie":                     "🍪",
+	"cool":                       "🆒",
+	"copyright":                  "©️",
+	"corn":                       "🌽",
+	"cow":                        "🐮",
+	"crab":                       "🦀",
+	"credit_card":                "💳",
+	"crescent_moon":              "🌙",
+	"crossed_fingers":            "🤞",
+	"crown":                      "👑",
+	"cry":                        "😢",
+	"cup_with_straw":             "🥤",
+	"cupid":                      "💘",
+	"curry":                      "🍛",
+	"dancer":                     "💃",
+	"dango":                      "🍡",
+	"dart":                       "🎯",
+	"dash":                       "💨",
+	"date":                       "📅",
+	"de":                         "🇩🇪",
+	"deciduous_tree":             "🌳",
+	"desktop_computer":           "🖥️",
+	"disappointed":               "😞",
+	"disappointed_relieved":      "😥",
+	"dizzy":                      "💫",
+	"dizzy_face":                 "😵",
+	"dog":                        "🐶",
+	"dollar":                     "💵",
+	"dolphin":                    "🐬",
+	"door":                       "🚪",
+	"double_vertical_bar":        "⏸️",
+	"doughnut":                   "🍩",
+	"drooling_face":              "🤤",
+	"droplet":                    "💧",
+	"dvd":                        "📀",
+	"e-mail":                     "📧",
+	"earth_africa":               "🌍",
+	"earth_americas":             "🌎",
+	"earth_asia":                 "🌏",
+	"egg":                        "🥚",
+	"eggplant":                   "🍆",
+	"eight":                      "8️⃣",
+	"electric_plug":              "🔌",
+	"email":                      "📧",
+	"end":                        "🔚",
+	"envelope":                   "✉️",
+	"envelope_with_arrow":        "📩",
+	"euro":                       "💶",
+	"evergreen_tree":             "🌲",
+	"exclamation":                "❗",
+	"exploding_head":             "🤯",
+	"expressionless":             "😑",
+	"eye":                        "👁️",
+	"eyes":                       "👀",
+	"face_palm":                  "🤦",
+	"face_vomiting":              "🤮",
+	"face_with_cowboy_hat":       "🤠",
+	"face_with_head_bandage":     "🤕",
+	"face_with_monocle":          "🧐",
+	"face_with_raised_eyebrow":   "🤨",
+	"face_with_rolling_eyes":     "🙄",
+	"face_with_symbols_on_mouth": "🤬",
+	"face_with_thermometer":      "🤒",
+	"facepunch":                  "👊",
+	"factory":                    "🏭",
+	"fallen_leaf":                "🍂",
+	"fast_forward":               "⏩",
+	"fax":                        "📠",
+	"fearful":                    "😨",
+	"file_cabinet":               "🗄️",
+	"file_folder":                "📁",
+	"film_frames":                "🎞️",
+	"fire":                       "🔥",
+	"fire_engine":                "🚒",
+	"fireworks":                  "🎆",
+	"first_place_medal":          "🥇",
+	"fish":                       "🐟",
+	"fish_cake":                  "🍥",
+	"fist":                       "✊",
+	"five":                       "5️⃣",
+	"flag-cn":                    "🇨🇳",
+	"flag-de":                    "🇩🇪",
+	"flag-fr":                    "🇫🇷",
+	"flag-gb":                    "🇬🇧",
+	"flag-jp":                    "🇯🇵",
+	"flag-kr":                    "🇰🇷",
+	"flag-us":                    "🇺🇸",
+	"flashlight":                 "🔦",
+	"floppy_disk":                "💾",
+	"flushed":                    "😳",
+	"football":                   "🏈",
+	"fork_and_knife":             "🍴",
+	"four":                       "4️⃣",
+	"four_leaf_clover":           "🍀",
+	"fox_face":                   "🦊",
+	"fr":                         "🇫🇷",
+	"free":                       "🆓",
+	"fried_egg":                  "🍳",
+	"fried_shrimp":               "🍤",
+	"fries":                      "🍟",
+	"frog":                       "🐸",
+	"frowning":                   "😦",
+	"full_moon":                  "🌕",
+	"game_die":                   "🎲",
+	"gb":                         "🇬🇧",
+	"gear":                       "⚙️",
+	"gem":                        "💎",
+	"ghost":                      "👻",
+	"gift":                       "🎁",
+	"gift_heart":                 "💝",
+	"globe_with_meridians":       "🌐",
+	"golf":                       "⛳",
+	"grapes":                     "🍇",
+	"green_apple":                "🍏",
+	"green_book":                 "📗",
+	"green_heart":                "💚",
+	"grey_exclamation":           "❕",
+	"grey_question":              "❔",
+	"grimacing":                  "😬",
+	"grin":                       "😁",
+	"grinning":                   "😀",
+	"guitar":                     "🎸",
+	"hamburger":                  "🍔",
+	"hammer":                     "🔨",
+	"hammer_and_pick":            "⚒️",
+	"hammer_and_wrench":          "🛠️",
+	"hamster":                    "🐹",
+	"hand":                       "✋",
+	"hand_with_index_and_middle_fingers_crossed": "🤞",
+	"handshake":                             "🤝",
+	"hankey":                                "💩",
+	"hash":                                  "#️⃣",
+	"hatching_chick":                        "🐣",
+	"headphones":                            "🎧",
+	"hear_no_evil":                          "🙉",
+	"heart":                                 "❤️",
+	"heart_eyes":                            "😍",
+	"heart_eyes_cat":                        "😻",
+	"heartbeat":                             "💓",
+	"heartpulse":                            "💗",
+	"heavy_check_mark":                      "✔️",
+	"heavy_division_sign":                   "➗",
+	"heavy_exclamation_mark":                "❗",
+	"heavy_heart_exclamation_mark_ornament": "❣️",
+	"heavy_minus_sign":                      "➖",
+	"heavy_multiplication_x":                "✖️",
+	"heavy_plus_sign":                       "➕",
+	"herb":                                  "🌿",
+	"hibiscus":                              "🌺",
+	"honeybee":                              "🐝",
+	"horse":                                 "🐴",
+	"hospital":                              "🏥",
+	"hot_face":                              "🥵",
+	"hot_pepper":                            "🌶️",
+	"hotdog":                                "🌭",
+	"hotel":                                 "🏨",
+	"hourglass":                             "⌛",
+	"hourglass_flowing_sand":                "⏳",
+	"house":                                 "🏠",
+	"house_with_garden":                     "🏡",
+	"hugging_face":                          "🤗",
+	"hugs":                                  "🤗",
+	"hushed":                                "😯",
+	"ice_cream":                             "🍨",
+	"icecream":                              "🍦",
+	"id":                                    "🆔",
+	"imp":                                   "👿",
+	"inbox_tray":                            "📥",
+	"incoming_envelope":                     "📨",
+	"information_desk_person":               "💁",
+	"information_source":                    "ℹ️",
+	"innocent":                              "😇",
+	"interrobang":                           "⁉️",
+	"iphone":                                "📱",
+	"jack_o_lantern":                        "🎃",
+	"japan":                                 "🗾",
+	"jigsaw":                                "🧩",
+	"joy":                                   "😂",
+	"joy_cat":                               "😹",
+	"jp":                                    "🇯🇵",
+	"key":                                   "🔑",
+	"keyboard":                              "⌨️",
+	"keycap_star":                           "*️⃣",
+	"keycap_ten":                            "🔟",
+	"kissing":                               "😗",
+	"kissing_heart":                         "😘",
+	"knife_fork_plate":                      "🍽️",
+	"koala":                                 "🐨",
+	"kr":                                    "🇰🇷",
+	"label":                                 "🏷️",
+	"large_blue_circle":                     "🔵",
+	"large_blue_diamond":                    "🔷",
+	"large_blue_square":                     "🟦",
+	"large_brown_circle":                    "🟤",
+	"large_green_circle":                    "🟢",
+	"large_green_square":                    "🟩",
+	"large_orange_circle":                   "🟠",
+	"large_orange_diamond":                  "🔶",
+	"large_orange_square":                   "🟧",
+	"large_purple_circle":                   "🟣",
+	"large_purple_square":                   "🟪",
+	"large_red_square":                      "🟥",
+	"large_yellow_circle":                   "🟡",
+	"large_yellow_square":                   "🟨",
+	"laughing":                              "😆",
+	"ledger":                                "📒",
+	"left_right_arrow":                      "↔️",
+	"left_speech_bubble":                    "🗨️",
+	"leftwards_arrow_with_hook":             "↩️",
+	"lemon":                                 "🍋",
+	"link":                                  "🔗",
+	"lion_face":                             "🦁",
+	"lock":                                  "🔒",
+	"lock_with_ink_pen":                     "🔏",
+	"lollipop":                              "🍭",
+	"loud_sound":                            "🔊",
+	"loudspeaker":                           "📢",
+	"lying_face":                            "🤥",
+	"mag":                                   "🔍",
+	"mag_right":                             "🔎",
+	"magnet":                                "🧲",
+	"mailbox":                               "📫",
+	"man-facepalming":                       "🤦‍♂️",
+	"man-shrugging":                         "🤷‍♂️",
+	"man_dancing":                           "🕺",
+	"maple_leaf":                            "🍁",
+	"mask":                                  "😷",
+	"medal":                                 "🏅",
+	"mega":                                  "📣",
+	"memo":                                  "📝",
+	"metal":                                 "🤘",
+	"microphone":                            "🎤",
+	"microscope":                            "🔬",
+	"money_mouth_face":                      "🤑",
+	"moneybag":                              "💰",
+	"monkey_face":                           "🐵",
+	"mount_fuji":                            "🗻",
+	"mouse":                                 "🐭",
+	"movie_camera":                          "🎥",
+	"moyai":                                 "🗿",
+	"muscle":                                "💪",
+	"mushroom":                              "🍄",
+	"musical_note":                          "🎵",
+	"mute":                                  "🔇",
+	"nauseated_face":                        "🤢",
+	"negative_squared_cross_mark":           "❎",
+	"nerd_face":                             "🤓",
+	"neutral_face":                          "😐",
+	"new":                                   "🆕",
+	"new_moon":                              "🌑",
+	"newspaper":                             "📰",
+	"ng":                                    "🆖",
+	"nine":                                  "9️⃣",
+	"no_bell":                               "🔕",
+	"no_entry":                              "⛔",
+	"no_entry_sign":                         "🚫",
+	"no_good":                               "🙅",
+	"no_mouth":                              "😶",
+	"notebook":                              "📓",
+	"notes":                                 "🎶",
+	"nut_and_bolt":                          "🔩",
+	"o":                                     "⭕",
+	"ocean":                                 "🌊",
+	"octopus":                               "🐙",
+	"oden":                                  "🍢",
+	"office":                                "🏢",
+	"ok":                                    "🆗",
+	"ok_hand":                               "👌",
+	"ok_woman":                              "🙆",
+	"old_key":                               "🗝️",
+	"on":                                    "🔛",
+	"one":                                   "1️⃣",
+	"open_book":                             "📖",
+	"open_file_folder":                      "📂",
+	"open_hands":                            "👐",
+	"open_mouth":                            "😮",
+	"orange_book":                           "📙",
+	"orange_heart":                          "🧡",
+	"outbox_tray":                           "📤",
+	"owl":                                   "🦉",
+	"package":                               "📦",
+	"page_facing_up":                        "📄",
+	"page_with_curl":                        "📃",
+	"pager":                                 "📟",
+	"palm_tree":                             "🌴",
+	"palms_up_together":                     "🤲",
+	"panda_face":                            "🐼",
+	"paperclip":                             "📎",
+	"partly_sunny":                          "⛅",
+	"partying_face":                         "🥳",
+	"peach":                                 "🍑",
+	"pencil":                                "📝",
+	"pencil2":                               "✏️",
+	"penguin":                               "🐧",
+	"pensive":                               "😔",
+	"persevere":                             "😣",
+	"person_frowning":                       "🙍",
+	"phone":                                 "☎️",
+	"pig":                                   "🐷",
+	"pill":                                  "💊",
+	"pizza":                                 "🍕",
+	"pleading_face":                         "🥺",
+	"point_down":                            "👇",
+	"point_left":                            "👈",
+	"point_right":                           "👉",
+	"point_up":                              "☝️",
+	"point_up_2":                            "👆",
+	"police_car":                            "🚓",
+	"poop":                                  "💩",
+	"popcorn":                               "🍿",
+	"postbox":                               "📮",
+	"pound":                                 "💷",
+	"pray":                                  "🙏",
+	"printer":                               "🖨️",
+	"punch":                                 "👊",
+	"purple_heart":                          "💜",
+	"pushpin":                               "📌",
+	"question":                              "❓",
+	"rabbit":                                "🐰",
+	"radio":                                 "📻",
+	"rage":                                  "😡",
+	"rainbow":                               "🌈",
+	"rainbow-flag":                          "🏳️‍🌈",
+	"raised_hand":                           "✋",
+	"raised_hands":                          "🙌",
+	"raising_hand":                          "🙋",
+	"ramen":                                 "🍜",
+	"recycle":                               "♻️",
+	"red_car":                               "🚗",
+	"red_circle":                            "🔴",
+	"registered":                            "®️",
+	"relaxed":                               "☺️",
+	"relieved":                              "😌",
+	"repeat":                                "🔁",
+	"revolving_hearts":                      "💞",
+	"rewind":                                "⏪",
+	"ribbon":                                "🎀",
+	"rice":                                  "🍚",
+	"rice_ball":                             "🍙",
+	"rice_cracker":                          "🍘",
+	"robot_face":                            "🤖",
+	"rocket":                                "🚀",
+	"roll_eyes":                             "🙄",
+	"rolling_on_the_floor_laughing":         "🤣",
+	"rose":                                  "🌹",
+	"rotating_light":                        "🚨",
+	"round_pushpin":                         "📍",
+	"runner":                                "🏃",
+	"running":                               "🏃",
+	"sake":                                  "🍶",
+	"santa":                                 "🎅",
+	"satellite_antenna":                     "📡",
+	"satisfied":                             "😆",
+	"school":                                "🏫",
+	"scissors":                              "✂️",
+	"scream":                                "😱",
+	"scream_cat":                            "🙀",
+	"second_place_medal":                    "🥈",
+	"see_no_evil":                           "🙈",
+	"seedling":                              "🌱",
+	"seven":                                 "7️⃣",
+	"shark":                                 "🦈",
+	"ship":                                  "🚢",
+	"shit":                                  "💩",
+	"shopping_trolley":                      "🛒",
+	"shower":                                "🚿",
+	"shrug":                                 "🤷",
+	"shushing_face":                         "🤫",
+	"sign_of_the_horns":                     "🤘",
+	"six":                                   "6️⃣",
+	"skin-tone-2":                           "🏻",
+	"skin-tone-3":                           "🏼",
+	"skin-tone-4":                           "🏽",
+	"skin-tone-5":                           "🏾",
+	"skin-tone-6":                           "🏿",
+	"skull":                                 "💀",
+	"sleeping":                              "😴",
+	"sleepy":                                "😪",
+	"slightly_frowning_face":                "🙁",
+	"slightly_smiling_face":                 "🙂",
+	"sloth":                                 "🦥",
+	"small_blue_diamond":                    "🔹",
+	"small_orange_diamond":                  "🔸",
+	"small_red_triangle":                    "🔺",
+	"small_red_triangle_down":               "🔻",
+	"smile":                                 "😄",
+	"smile_cat":                             "😸",
+	"smiley":                                "😃",
+	"smiling_face_with_3_hearts":            "🥰",
+	"smiling_imp":                           "😈",
+	"smirk":                                 "😏",
+	"smoking":                               "🚬",
+	"snail":                                 "🐌",
+	"snake":                                 "🐍",
+	"sneezing_face":                         "🤧",
+	"snowflake":                             "❄️",
+	"snowman":                               "⛄",
+	"sob":                                   "😭",
+	"soccer":                                "⚽",
+	"soon":                                  "🔜",
+	"sos":                                   "🆘",
+	"sound":                                 "🔉",
+	"spaghetti":                             "🍝",
+	"sparkler":                              "🎇",
+	"sparkles":                              "✨",
+	"sparkling_heart":                       "💖",
+	"speak_no_evil":                         "🙊",
+	"speaker":                               "🔈",
+	"speech_balloon":                        "💬",
+	"spiral_calendar_pad":                   "🗓️",
+	"sports_medal":                          "🏅",
+	"stadium":                               "🏟️",
+	"star":                                  "⭐",
+	"star-struck":                           "🤩",
+	"star2":                                 "🌟",
+	"station":                               "🚉",
+	"stopwatch":                             "⏱️",
+	"straight_ruler":                        "📏",
+	"strawberry":                            "🍓",
+	"stuck_out_tongue":                      "😛",
+	"stuck_out_tongue_closed_eyes":          "😝",
+	"stuck_out_tongue_winking_eye":          "😜",
+	"sunflower":                             "🌻",
+	"sunglasses":                            "😎",
+	"sunny":                                 "☀️",
+	"sushi":                                 "🍣",
+	"sweat":                                 "😓",
+	"sweat_drops":                           "💦",
+	"sweat_smile":                           "😅",
+	"syringe":                               "💉",
+	"taco":                                  "🌮",
+	"tada":                                  "🎉",
+	"tangerine":                             "🍊",
+	"taxi":                                  "🚕",
+	"tea":                                   "🍵",
+	"telephone":                             "☎️",
+	"telephone_receiver":                    "📞",
+	"telescope":                             "🔭",
+	"tennis":                                "🎾",
+	"tent":                                  "⛺",
+	"the_horns":                             "🤘",
+	"thinking":                              "🤔",
+	"thinking_face":                         "🤔",
+	"third_place_medal":                     "🥉",
+	"thought_balloon":                       "💭",
+	"three":                                 "3️⃣",
+	"thumbsdown":                            "👎",
+	"thumbsup":                              "👍",
+	"tiger":                                 "🐯",
+	"timer_clock":                           "⏲️",
+	"tired_face":                            "😫",
+	"tm":                                    "™️",
+	"toilet":                                "🚽",
+	"tokyo_tower":                           "🗼",
+	"tomato":                                "🍅",
+	"toolbox":                               "🧰",
+	"top":                                   "🔝",
+	"train":                                 "🚆",
+	"triangular_flag_on_post":               "🚩",
+	"triangular_ruler":                      "📐",
+	"triumph":                               "😤",
+	"trophy":                                "🏆",
+	"tropical_drink":                        "🍹",
+	"tropical_fish":                         "🐠",
+	"truck":                                 "🚚",
+	"tulip":                                 "🌷",
+	"turtle":                                "🐢",
+	"tv":                                    "📺",
+	"two":                                   "2️⃣",
+	"two_hearts":                            "💕",
+	"umbrella":                              "☔",
+	"unamused":                              "😒",
+	"unicorn_face":                          "🦄",
+	"unlock":                                "🔓",
+	"up":                                    "🆙",
+	"upside_down_face":                      "🙃",
+	"us":                                    "🇺🇸",
+	"v":                                     "✌️",
+	"video_camera":                          "📹",
+	"video_game":                            "🎮",
+	"vs":                                    "🆚",
+	"walking":                               "🚶",
+	"warning":                               "⚠️",
+	"wastebasket":                           "🗑️",
+	"watch":                                 "⌚",
+	"watermelon":                            "🍉",
+	"wave":                                  "👋",
+	"weary":                                 "😩",
+	"whale":                                 "🐳",
+	"white_check_mark":                      "✅",
+	"white_circle":                          "⚪",
+	"white_flag":                            "🏳️",
+	"white_frowning_face":                   "☹️",
+	"white_heart":                           "🤍",
+	"white_large_square":                    "⬜",
+	"wine_glass":                            "🍷",
+	"wink":                                  "😉",
+	"woman-facepalming":                     "🤦‍♀️",
+	"woman-shrugging":                       "🤷‍♀️",
+	"woozy_face":                            "🥴",
+	"worried":                               "😟",
+	"wrench":                                "🔧",
+	"writing_hand":                          "✍️",
+	"x":                                     "❌",
+	"yawning_face":                          "🥱",
+	"yellow_heart":                          "💛",
+	"yen":                                   "💴",
+	"yum":                                   "😋",
+	"zany_face":                             "🤪",
+	"zap":                                   "⚡",
+	"zero":                                  "0️⃣",
+	"zipper_mouth_face":                     "🤐",
+	"zzz":                                   "💤",
+}
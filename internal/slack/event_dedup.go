@@ -0,0 +1,62 @@
+package slack
+
+import (
+	"sync"
+	"time"
+)
+
+// eventDedupTTL is how long a handled event_id is remembered; Slack gives up redelivering well within an hour
+const eventDedupTTL = time.Hour
+
+// handledEvents remembers event IDs that were handled (or are being handled) so redeliveries are dropped
+var (
+	handledEvents      = make(map[string]time.Time) // event_id -> expiry
+	handledEventsMutex sync.Mutex
+	handledEventsPrune time.Time
+)
+
+// claimEvent reports whether the event was not seen within eventDedupTTL and marks it as handled.
+// Events without an ID (e.g. synthetic ones) are always claimed.
+func claimEvent(eventID string) bool {
+	if eventID == "" {
+		return true
+	}
+
+	handledEventsMutex.Lock()
+	defer handledEventsMutex.Unlock()
+
+	now := time.Now()
+	if expiry, exists := handledEvents[eventID]; exists && now.Before(expiry) {
+		return false
+	}
+	// Expired entries are dropped once a minute rather than on every event
+	if now.Sub(handledEventsPrune) >= time.Minute {
+		for id, expiry := range handledEvents {
+			if now.After(expiry) {
+				delete(handledEvents, id)
+			}
+		}
+		handledEventsPrune = now
+	}
+	handledEvents[eventID] = now.Add(eventDedupTTL)
+	return true
+}
+
+// EventHandled reports whether an event was handled (or is being handled) within eventDedupTTL
+func EventHandled(eventID string) bool {
+	if eventID == "" {
+		return false
+	}
+
+	handledEventsMutex.Lock()
+	defer handledEventsMutex.Unlock()
+	expiry, exists := handledEvents[eventID]
+	return exists && time.Now().Before(expiry)
+}
+
+// releaseEvent forgets an event whose handling failed so a redelivery can try again
+func releaseEvent(eventID string) {
+	handledEventsMutex.Lock()
+	defer handledEventsMutex.Unlock()
+	delete(handledEvents, eventID)
+}
@@ -0,0 +1,54 @@
+package slack
+
+import (
+	"log"
+
+	"slack-to-google-sheets-bot/internal/sheets"
+)
+
+// fileRecords converts the files of a message to the file columns, skipping files Slack hides from the bot
+func fileRecords(files []FileInfo) []sheets.FileRecord {
+	var records []sheets.FileRecord
+	for _, file := range files {
+		if file.Mode == "tombstone" || file.Mode == "hidden_by_limit" {
+			continue
+		}
+
+		name := file.Name
+		if name == "" {
+			name = file.Title
+		}
+		fileType := file.Filetype
+		if fileType == "" {
+			fileType = file.Mimetype
+		}
+		permalink := file.Permalink
+		if permalink == "" {
+			permalink = file.URLPrivate
+		}
+
+		records = append(records, sheets.FileRecord{Name: name, Type: fileType, Size: file.Size, Permalink: permalink})
+	}
+	return records
+}
+
+// completeSharedFiles looks up files that a file_share event only carries as IDs, so their name, type and link can be recorded.
+// Files that cannot be looked up are kept as they are.
+func (c *Client) completeSharedFiles(files []FileInfo) []FileInfo {
+	completed := make([]FileInfo, 0, len(files))
+	for _, file := range files {
+		if file.ID == "" || (file.FileAccess != "check_file_info" && file.Name != "" && file.Permalink != "") {
+			completed = append(completed, file)
+			continue
+		}
+
+		info, err := c.GetFileInfo(file.ID)
+		if err != nil {
+			log.Printf("Error getting file info for %s: %v", file.ID, err)
+			completed = append(completed, file)
+			continue
+		}
+		completed = append(completed, *info)
+	}
+	return completed
+}
@@ -0,0 +1,95 @@
+package slack
+
+import (
+	"log"
+	"regexp"
+	"strings"
+
+	"slack-to-google-sheets-bot/internal/config"
+	"slack-to-google-sheets-bot/internal/i18n"
+	"slack-to-google-sheets-bot/internal/sheets"
+)
+
+var (
+	findCommandPattern = regexp.MustCompile(`(?i)^find\s+<?(https://\S+?)(?:\|[^>]*)?>?(?:\s|$)`)
+	// permalinkPattern matches https://<workspace>.slack.com/archives/<channel>/p<ts without dot>
+	permalinkPattern = regexp.MustCompile(`/archives/([CDG][A-Z0-9]+)/p(\d{10})(\d{6})`)
+)
+
+// parsePermalink extracts the channel ID and message timestamp from a Slack message permalink
+func parsePermalink(permalink string) (channelID, messageTS string, ok bool) {
+	matches := permalinkPattern.FindStringSubmatch(permalink)
+	if len(matches) != 4 {
+		return "", "", false
+	}
+	return matches[1], matches[2] + "." + matches[3], true
+}
+
+// handleFindCommand replies with a link to the sheet row that recorded the message behind a permalink. Links to
+// other channels are only answered for members of the linked channel, by DM, so the command does not reveal the
+// names of private channels to the channel it is run in.
+func handleFindCommand(cfg *config.Config, slackClient *Client, event *Event, command string) error {
+	lang := replyLanguage(cfg, slackClient, event.Event.User)
+	destination := event.Event.Channel
+	reply := func(message string) error {
+		if err := slackClient.SendMessage(destination, message); err != nil {
+			log.Printf("Error sending find reply: %v", err)
+		}
+		return nil
+	}
+
+	matches := findCommandPattern.FindStringSubmatch(command)
+	if len(matches) < 2 {
+		return reply(i18n.T(lang, i18n.KeyFindUsage))
+	}
+
+	channelID, messageTS, ok := parsePermalink(matches[1])
+	if !ok {
+		return reply(i18n.T(lang, i18n.KeyFindInvalidLink))
+	}
+
+	if channelID != event.Event.Channel {
+		member, err := slackClient.isConversationMember(channelID, event.Event.User)
+		if err != nil {
+			log.Printf("Error checking membership of %s in %s for find: %v", event.Event.User, channelID, err)
+		}
+		// Not telling a missing channel from one the user is not in keeps channel IDs from being probed
+		if !member {
+			return reply(i18n.T(lang, i18n.KeyFindNotMember))
+		}
+		if !strings.HasPrefix(event.Event.Channel, "D") {
+			reply(i18n.T(lang, i18n.KeyFindSentByDM))
+			// chat.postMessage with a user ID delivers the message to the bot's DM with that user
+			destination = event.Event.User
+		}
+	}
+
+	if cfg.GoogleSheetsCredentials == "" || cfg.SpreadsheetID == "" {
+		return reply(i18n.T(lang, i18n.KeySheetsNotConfigured))
+	}
+
+	channelInfo, err := slackClient.GetChannelInfo(channelID)
+	if err != nil {
+		log.Printf("Error getting channel info for find: %v", err)
+		return reply(i18n.T(lang, i18n.KeyFindChannelFailed))
+	}
+
+	sheetsClient, err := newSheetsClient(cfg)
+	if err != nil {
+		log.Printf("Error creating Google Sheets client for find: %v", err)
+		return reply(i18n.T(lang, i18n.KeySheetsConnectFailed))
+	}
+
+	sheetName := sheets.SheetName(channelID, channelInfo.Name)
+	row, err := sheetsClient.FindMessageRow(channelSpreadsheetID(cfg, sheetsClient, channelID), sheetName, messageTS)
+	if err != nil {
+		log.Printf("Error finding message %s in sheet %s: %v", messageTS, sheetName, err)
+		return reply(i18n.T(lang, i18n.KeyFindReadFailed))
+	}
+	if row < 0 {
+		return reply(i18n.T(lang, i18n.KeyFindNotRecorded, sheetName))
+	}
+
+	sheetURL := buildSheetRangeURL(cfg, sheetsClient, channelID, channelInfo.Name, row, row)
+	return reply(i18n.T(lang, i18n.KeyFindFound, sheetURL, sheetName, row))
+}
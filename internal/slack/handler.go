@@ -1,21 +1,32 @@
 package slack
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
 
+	"slack-to-google-sheets-bot/internal/audit"
+	"slack-to-google-sheets-bot/internal/completeness"
 	"slack-to-google-sheets-bot/internal/config"
+	boterrors "slack-to-google-sheets-bot/internal/errors"
+	"slack-to-google-sheets-bot/internal/i18n"
+	"slack-to-google-sheets-bot/internal/latency"
 	"slack-to-google-sheets-bot/internal/progress"
 	"slack-to-google-sheets-bot/internal/sheets"
 )
 
 const (
 	MaxFailureCount = 3
+
+	// maxReportedMissingMessages is how many missing message timestamps the backfill verification lists in Slack;
+	// the log has all of them
+	maxReportedMissingMessages = 10
 )
 
 var (
@@ -46,8 +57,36 @@ var (
 
 func HandleEvent(cfg *config.Config, event *Event) error {
 	// Log all incoming events for debugging
-	log.Printf("Received event: type=%s, user=%s, text=%s, timestamp=%s",
-		event.Event.Type, event.Event.User, event.Event.Text, event.Event.Timestamp)
+	if event.TraceID == "" {
+		event.TraceID = NewTraceID()
+	}
+	tracef(event, "Received event: type=%s, channel=%s, user=%s, text=%s, timestamp=%s",
+		event.Event.Type, event.Event.Channel, event.Event.User, event.Event.Text, event.Event.Timestamp)
+
+	// Slack redelivers events it believes were not acknowledged; an event handled before is not written twice
+	if !claimEvent(event.EventID) {
+		tracef(event, "Event was already handled, skipping redelivery (retry %d)", event.RetryNum)
+		return nil
+	}
+	if err := handleEvent(cfg, event); err != nil {
+		releaseEvent(event.EventID)
+		return err
+	}
+	return nil
+}
+
+// handleEvent dispatches an event that was not handled before
+func handleEvent(cfg *config.Config, event *Event) error {
+	// Workspaces installed through the OAuth flow are handled with their own bot token
+	if event.Event.Type == "app_uninstalled" || event.Event.Type == "tokens_revoked" {
+		return handleAppUninstalled(event)
+	}
+	cfg, err := configForTeam(cfg, event.TeamID, event.EnterpriseID)
+	if err != nil {
+		// Slack would only deliver the event again, so it is dropped
+		log.Printf("Refusing %s event from workspace %s: %v", event.Event.Type, event.TeamID, err)
+		return nil
+	}
 
 	// Handle member joined channel event
 	if event.Event.Type == "member_joined_channel" {
@@ -125,6 +164,61 @@ func HandleEvent(cfg *config.Config, event *Event) error {
 		return handleAppMention(cfg, event)
 	}
 
+	// Direct messages are commands to the bot (opt out / opt in) and are only recorded when listed in RECORD_DM_CHANNELS
+	if event.Event.Type == "message" && (event.Event.ChannelType == "im" || event.Event.ChannelType == "mpim") {
+		if !cfg.RecordsDirectMessages(event.Event.Channel) {
+			if event.Event.ChannelType == "im" {
+				return handleDirectMessage(cfg, event)
+			}
+			return nil
+		}
+		if event.Event.ChannelType == "im" && isOptCommand(event.Event.Text) {
+			return handleDirectMessage(cfg, event)
+		}
+	}
+
+	// Pick up renamed users and profile changes in later rows
+	if event.Event.Type == "user_change" {
+		return handleUserChange(event)
+	}
+
+	// Rename the channel's tab right away instead of on the next message
+	if event.Event.Type == "channel_rename" || event.Event.Type == "group_rename" {
+		return handleChannelRename(cfg, event)
+	}
+
+	// Stop all work on channels the bot was removed from
+	if event.Event.Type == "member_left_channel" {
+		return handleMemberLeft(cfg, event)
+	}
+	if event.Event.Type == "channel_left" || event.Event.Type == "group_left" {
+		handleBotRemoved(cfg, event.Event.Channel)
+		return nil
+	}
+
+	// Handle reaction events
+	if event.Event.Type == "reaction_added" || event.Event.Type == "reaction_removed" {
+		if isThreadExportReaction(cfg, event) {
+			if err := handleThreadExportReaction(cfg, event); err != nil {
+				log.Printf("Error exporting thread %s: %v", event.Event.Item.Timestamp, err)
+			}
+		}
+		return handleReactionChanged(cfg, event)
+	}
+
+	// Handle pin events
+	if event.Event.Type == "pin_added" || event.Event.Type == "pin_removed" {
+		return handlePinChanged(cfg, event)
+	}
+
+	// Handle deleted messages; a deleted thread parent with replies stays as a tombstone
+	if event.Event.Type == "message" && event.Event.Subtype == "message_deleted" {
+		return handleMessageDeleted(cfg, event, event.Event.DeletedTS)
+	}
+	if event.Event.Type == "message" && event.Event.Subtype == "message_changed" && event.Event.Message != nil && event.Event.Message.Subtype == "tombstone" {
+		return handleMessageDeleted(cfg, event, event.Event.Message.Timestamp)
+	}
+
 	// Handle message changed events (edits)
 	if event.Event.Type == "message" && event.Event.Subtype == "message_changed" {
 		log.Printf("Processing message_changed event for channel: %s", event.Event.Channel)
@@ -137,32 +231,57 @@ func HandleEvent(cfg *config.Config, event *Event) error {
 		return nil
 	}
 
-	// Skip messages without text (but allow bot messages)
-	if event.Event.Text == "" {
+	// Replies posted with "also send to channel" are recorded once, as replies linked to their thread parent
+	if event.Event.Subtype == "thread_broadcast" && event.Event.ThreadTS == "" && event.Event.Root != nil {
+		event.Event.ThreadTS = event.Event.Root.Timestamp
+	}
+
+	// Skip messages without any content (attachment-only and Block Kit-only posts such as app messages, and huddles,
+	// are recorded)
+	if event.Event.Text == "" && event.Event.Subtype != "file_share" && len(event.Event.Attachments) == 0 && len(event.Event.Files) == 0 &&
+		len(event.Event.Blocks) == 0 && event.Event.Room == nil {
 		return nil
 	}
 
+	// Joins and leaves are only recorded with RECORD_MEMBERSHIP_MESSAGES=true
+	if isMembershipMessage(event.Event.Subtype) && !cfg.RecordMembershipMessages {
+		return nil
+	}
+
+	completeness.Default().Received(event.Event.Channel, time.Now())
+
 	// Skip message recording if history retrieval is in progress for this channel
 	historyProgressMutex.Lock()
 	if historyInProgress[event.Event.Channel] {
 		historyProgressMutex.Unlock()
-		log.Printf("Skipping message recording for channel %s - history retrieval in progress", event.Event.Channel)
+		tracef(event, "Skipping message recording for channel %s - history retrieval in progress", event.Event.Channel)
+		completeness.Default().Skipped(event.Event.Channel, time.Now())
 		return nil
 	}
 	historyProgressMutex.Unlock()
 
-	// Skip messages that are app mentions to avoid duplicate processing
-	// (app_mention events are already handled above)
-	// Only skip if this message mentions our bot specifically
-	if strings.Contains(event.Event.Text, "<@") {
-		// Check if this is an app mention to our bot by looking for bot mention patterns
-		// This is a simplified check - in a real implementation you'd want to check the actual bot user ID
-		log.Printf("Skipping message event that contains mentions to avoid duplicate processing")
+	// Create Slack client
+	slackClient := newClient(cfg)
+
+	// Skip messages mentioning this bot to avoid duplicate processing
+	// (app_mention events are already handled above); mentions of other users are recorded as usual
+	botUserID, err := slackClient.BotUserID()
+	if err != nil {
+		log.Printf("Warning: Could not resolve the bot user ID, skipping every message with a mention: %v", err)
+	}
+	if mentionsBot(event.Event.Text, botUserID) {
+		tracef(event, "Skipping message event that mentions the bot to avoid duplicate processing")
+		completeness.Default().Skipped(event.Event.Channel, time.Now())
 		return nil
 	}
 
-	// Create Slack client
-	slackClient := NewClient(cfg.SlackBotToken)
+	// The bot's own posts (status thread updates, thread mirror notes) are not recorded live: a recorded note would
+	// re-arm the thread mirror, which would post another note once the thread went quiet again, forever
+	if botUserID != "" && event.Event.User == botUserID {
+		tracef(event, "Skipping the bot's own message %s", event.Event.Timestamp)
+		completeness.Default().Skipped(event.Event.Channel, time.Now())
+		return nil
+	}
 
 	// Get channel information
 	channelInfo, err := slackClient.GetChannelInfo(event.Event.Channel)
@@ -175,26 +294,33 @@ func HandleEvent(cfg *config.Config, event *Event) error {
 }
 
 func recordSingleMessage(cfg *config.Config, slackClient *Client, event *Event, channelInfo *ChannelInfo) error {
-	// Get user information (handle both human users and bots)
-	var userInfo *UserInfo
-	if event.Event.User != "" {
-		// Human user message
-		var err error
-		userInfo, err = slackClient.GetUserInfo(event.Event.User)
-		if err != nil {
-			log.Printf("Error getting user info for %s: %v", event.Event.User, err)
-			userInfo = &UserInfo{ID: event.Event.User, Name: "Unknown", RealName: "Unknown"}
-		}
-	} else {
-		// Bot message or system message - create a placeholder user info
-		userInfo = &UserInfo{ID: "", Name: "Bot", RealName: "Bot"}
+	if refusesSharedChannel(cfg, channelInfo) {
+		completeness.Default().Skipped(event.Event.Channel, time.Now())
+		return nil
 	}
 
+	// Get user information (handle both human users and bots)
+	userInfo := slackClient.messageAuthor(event.Event.User, event.Event.BotID, event.Event.Username, event.Event.UserTeam, event.Event.BotProfile, event.Event.UserProfile)
+
 	// Parse timestamp and convert to JST
 	timestamp := convertSlackTimestampToJST(event.Event.Timestamp)
 
+	// Respect the channel's recording schedule (quiet hours)
+	if !isRecordingAllowed(cfg, event.Event.Channel, timestamp) {
+		tracef(event, "Skipping message %s in channel %s - outside recording schedule", event.Event.Timestamp, event.Event.Channel)
+		noteScheduleSkip(event.Event.Channel, timestamp)
+		completeness.Default().Skipped(event.Event.Channel, time.Now())
+		return nil
+	}
+	flushScheduleSkip(event.Event.Channel)
+
+	// Shared files are recorded with their caption as the text; events may carry only the file IDs
+	if event.Event.Subtype == "file_share" {
+		event.Event.Files = slackClient.completeSharedFiles(event.Event.Files)
+	}
+
 	// Format message text including attachments (convert mentions and channels)
-	formattedText := slackClient.FormatMessageWithAttachments(event.Event.Text, event.Event.Attachments, event.Event.Files)
+	formattedText := slackClient.FormatMessageWithAttachments(event.Event.Subtype, messageText(systemMessageText(event.Event.Subtype, event.Event.Text, event.Event.Topic, event.Event.Purpose, event.Event.User, event.Event.Inviter, event.Event.Room), event.Event.Blocks), event.Event.Attachments, event.Event.Files)
 
 	// Create message record
 	record := sheets.MessageRecord{
@@ -207,14 +333,26 @@ func recordSingleMessage(cfg *config.Config, slackClient *Client, event *Event,
 		Text:         formattedText,
 		ThreadTS:     event.Event.ThreadTS,
 		MessageTS:    event.Event.Timestamp,
+		AppSource:    appSource(event.Event.AppID, event.Event.BotProfile),
+		Files:        fileRecords(event.Event.Files),
+		TraceID:      event.TraceID,
+		TeamID:       event.Event.Team,
+	}
+
+	// Respect users who opted out of recording
+	if !applyOptOutPolicy(cfg, &record) {
+		tracef(event, "Skipping message %s in channel %s - user opted out", record.MessageTS, record.Channel)
+		completeness.Default().Skipped(record.Channel, time.Now())
+		return nil
 	}
+	enrichRecords(cfg, slackClient, []*sheets.MessageRecord{&record})
 
 	// Write to Google Sheets
 	if cfg.GoogleSheetsCredentials != "" && cfg.SpreadsheetID != "" {
 		log.Printf("Creating Google Sheets client with credentials length: %d", len(cfg.GoogleSheetsCredentials))
-		sheetsClient, err := sheets.NewClient(cfg.GoogleSheetsCredentials)
+		sheetsClient, err := newSheetsClient(cfg)
 		if err != nil {
-			log.Printf("Error creating Google Sheets client: %v", err)
+			tracef(event, "Error creating Google Sheets client: %v", err)
 			preview := cfg.GoogleSheetsCredentials
 			if len(preview) > 100 {
 				preview = preview[:100]
@@ -223,29 +361,53 @@ func recordSingleMessage(cfg *config.Config, slackClient *Client, event *Event,
 			log.Printf("Credentials starts with: %c", cfg.GoogleSheetsCredentials[0])
 			log.Printf("Is it a file path? Contains '.json': %t", strings.Contains(cfg.GoogleSheetsCredentials, ".json"))
 
-			// Send error notification to Slack
-			errorMessage := fmt.Sprintf("❌ Google Sheetsへの接続に失敗しました。\n"+
-				"エラー: %v\n"+
-				"管理者にお問い合わせください。", err)
+			// Send error notification to Slack; the cause goes to the admin channel only
+			failure := boterrors.New(boterrors.SheetsConnectFailed, err)
+			errorMessage := failure.UserMessage(replyLanguage(cfg, slackClient, event.Event.User)) + traceSuffix(event)
 			if err := slackClient.SendMessage(event.Event.Channel, errorMessage); err != nil {
 				log.Printf("Error sending failure notification: %v", err)
 			}
+			reportFailureToAdmins(cfg, slackClient, event, failure)
 
+			completeness.Default().Failed(record.Channel, time.Now())
 			return err
 		}
 
-		if err := sheetsClient.WriteMessage(cfg.SpreadsheetID, &record); err != nil {
-			log.Printf("Error writing message to Google Sheets (channel: %s, user: %s): %v",
-				record.ChannelName, record.UserHandle, err)
+		spreadsheetID := channelSpreadsheetID(cfg, sheetsClient, record.Channel)
+		row, err := sheetsClient.WriteMessage(spreadsheetID, &record)
+		if err != nil {
+			tracef(event, "Error writing message to Google Sheets (channel: %s, user: %s, sheet: %s): %v",
+				record.ChannelName, record.UserHandle,
+				buildSheetURLWithGID(cfg, sheetsClient, record.Channel, record.ChannelName), err)
 
 			// For individual message failures, only log the error (don't spam the channel)
 			// Only send notification for critical failures
+			completeness.Default().Failed(record.Channel, time.Now())
 			return err
 		}
 
-		log.Printf("✅ Message auto-recorded in #%s by %s: %s",
-			record.ChannelName, record.UserHandle,
-			truncateText(record.Text, 50))
+		tracef(event, "✅ Message %s auto-recorded in #%s by %s: %s (%s)",
+			record.MessageTS, record.ChannelName, record.UserHandle,
+			truncateText(record.Text, 50),
+			buildSheetRangeURL(cfg, sheetsClient, record.Channel, record.ChannelName, row, row))
+
+		annotateSettingChanges(cfg, sheetsClient, spreadsheetID, record.Channel, sheets.SheetName(record.Channel, record.ChannelName), row)
+		completeness.Default().Recorded(record.Channel, time.Now())
+		if !event.ReceivedAt.IsZero() {
+			latency.Default().Record(time.Since(event.ReceivedAt), time.Now())
+		}
+		updateChannelInfo(cfg, slackClient, sheetsClient, event, &record)
+		runRecordHooks(cfg, sheetsClient, []*sheets.MessageRecord{&record}, map[string]int{record.MessageTS: row}, false)
+		persistRecords(cfg, []*sheets.MessageRecord{&record})
+		mirrorRecords(cfg, []*sheets.MessageRecord{&record})
+		trackMessageBudget(cfg, slackClient, record.Channel, record.ChannelName)
+		retrySpooledRecords(cfg, sheetsClient, record.Channel)
+
+		// Track thread activity so a summary link can be mirrored back once the thread goes quiet
+		if record.ThreadTS != "" && record.ThreadTS != record.MessageTS {
+			scheduleThreadMirror(cfg, record.Channel, record.ChannelName, record.ThreadTS)
+			refreshReplyCount(cfg, sheetsClient, &record)
+		}
 	} else {
 		log.Printf("Google Sheets not configured, message logged: %s in #%s by %s", record.Text, record.ChannelName, record.UserHandle)
 	}
@@ -253,12 +415,13 @@ func recordSingleMessage(cfg *config.Config, slackClient *Client, event *Event,
 	return nil
 }
 
-// truncateText truncates text to the specified length with ellipsis
+// truncateText truncates text to the specified number of characters (runes, so multi-byte text stays valid) with ellipsis
 func truncateText(text string, maxLength int) string {
-	if len(text) <= maxLength {
+	runes := []rune(text)
+	if len(runes) <= maxLength {
 		return text
 	}
-	return text[:maxLength] + "..."
+	return string(runes[:maxLength]) + "..."
 }
 
 // extractEmailFromShowMe extracts email address from "show me" command
@@ -283,16 +446,26 @@ func isRateLimitError(err error) bool {
 	if err == nil {
 		return false
 	}
-	return strings.Contains(err.Error(), "ratelimited")
+	var rateLimitErr *RateLimitError
+	return errors.As(err, &rateLimitErr) || strings.Contains(err.Error(), "ratelimited")
 }
 
 // scheduleHistoryRetry schedules a retry of history retrieval after specified duration
 // Preserves the original start time to ensure new messages are properly captured
 func scheduleHistoryRetry(cfg *config.Config, channelID, channelName string, isInitialRecording bool, originalStartTime time.Time, retryDelay time.Duration) {
 	log.Printf("Scheduling history retry for channel %s in %v due to rate limit (preserving start time: %v)", channelID, retryDelay, originalStartTime)
+	generation := backfillGeneration(channelID)
 
 	go func() {
 		time.Sleep(retryDelay)
+		if channelRemoved(channelID) {
+			log.Printf("Dropping scheduled history retry for channel %s - bot was removed", channelID)
+			return
+		}
+		if backfillGeneration(channelID) != generation {
+			log.Printf("Dropping scheduled history retry for channel %s - a reset replaced it", channelID)
+			return
+		}
 		log.Printf("Retrying history retrieval for channel %s after %v delay", channelID, retryDelay)
 
 		// Create a mock event for retry
@@ -316,7 +489,7 @@ func scheduleHistoryRetry(cfg *config.Config, channelID, channelName string, isI
 
 // retryMemberJoinedHistoryWithStartTime retries the member joined history retrieval with preserved start time
 func retryMemberJoinedHistoryWithStartTime(cfg *config.Config, event *Event, channelName string, originalStartTime time.Time) error {
-	slackClient := NewClient(cfg.SlackBotToken)
+	slackClient := newClient(cfg)
 
 	// Get channel information
 	channelInfo := &ChannelInfo{ID: event.Event.Channel, Name: channelName}
@@ -332,7 +505,7 @@ func retryMemberJoinedHistoryWithStartTime(cfg *config.Config, event *Event, cha
 
 // retryAppMentionHistoryWithStartTime retries the app mention history retrieval with preserved start time
 func retryAppMentionHistoryWithStartTime(cfg *config.Config, event *Event, channelName string, originalStartTime time.Time) error {
-	slackClient := NewClient(cfg.SlackBotToken)
+	slackClient := newClient(cfg)
 
 	// Get channel information
 	channelInfo := &ChannelInfo{ID: event.Event.Channel, Name: channelName}
@@ -355,41 +528,49 @@ func performHistoryRetrieval(cfg *config.Config, slackClient *Client, event *Eve
 func performHistoryRetrievalWithStartTime(cfg *config.Config, slackClient *Client, event *Event, channelInfo *ChannelInfo, isInitialRecording bool, originalStartTime time.Time) error {
 	// Check if Google Sheets is configured
 	if cfg.GoogleSheetsCredentials == "" || cfg.SpreadsheetID == "" {
-		configMessage := "⚠️ Google Sheetsの設定が完了していません。管理者にお問い合わせください。"
-		slackClient.SendMessage(event.Event.Channel, configMessage)
+		notifyFailure(cfg, slackClient, event, boterrors.New(boterrors.SheetsNotConfigured, nil))
 		return nil
 	}
 
 	// Create Google Sheets client
-	sheetsClient, err := sheets.NewClient(cfg.GoogleSheetsCredentials)
+	sheetsClient, err := newSheetsClient(cfg)
 	if err != nil {
 		log.Printf("Error creating Google Sheets client: %v", err)
-		errorMessage := "❌ Google Sheetsへの接続に失敗しました。"
-		slackClient.SendMessage(event.Event.Channel, errorMessage)
+		notifyFailure(cfg, slackClient, event, boterrors.New(boterrors.SheetsConnectFailed, err))
+		failStatusMessage(slackClient, event.Event.Channel)
 		return err
 	}
 
+	// Private channels need groups:history; say so instead of failing halfway through the history
+	if scope := privateChannelMissingScope(slackClient, channelInfo); scope != "" {
+		log.Printf("Cannot record private channel %s - missing scope %s", event.Event.Channel, scope)
+		notifyJobResult(cfg, slackClient, event, true, missingScopeMessage(scope))
+		failStatusMessage(slackClient, event.Event.Channel)
+		return nil
+	}
+
+	// Slack Connect channels are not recorded at all with RECORD_SHARED_CHANNELS=false
+	if refusesSharedChannel(cfg, channelInfo) {
+		notifyJobResult(cfg, slackClient, event, true, sharedChannelRefusedMessage)
+		failStatusMessage(slackClient, event.Event.Channel)
+		return nil
+	}
+
 	// Ensure channel-specific sheet exists
-	if err := sheetsClient.EnsureChannelSheetExists(cfg.SpreadsheetID, event.Event.Channel, channelInfo.Name); err != nil {
+	if err := sheetsClient.EnsureChannelSheetExists(channelSpreadsheetID(cfg, sheetsClient, event.Event.Channel), event.Event.Channel, channelInfo.Name); err != nil {
 		log.Printf("Error ensuring channel sheet exists: %v", err)
-		errorMessage := "❌ スプレッドシートの初期化に失敗しました。"
-		slackClient.SendMessage(event.Event.Channel, errorMessage)
+		notifyFailure(cfg, slackClient, event, boterrors.New(boterrors.SheetInitFailed, err))
+		failStatusMessage(slackClient, event.Event.Channel)
 		return err
 	}
 
 	// Set history retrieval in progress flag with original start time
 	historyProgressMutex.Lock()
-	historyInProgress[event.Event.Channel] = true
-	historyStartTime[event.Event.Channel] = originalStartTime
+	run := startBackfillRun(event.Event.Channel, originalStartTime)
 	historyProgressMutex.Unlock()
 
 	// Ensure flag is cleared when function exits
-	defer func() {
-		historyProgressMutex.Lock()
-		delete(historyInProgress, event.Event.Channel)
-		delete(historyStartTime, event.Event.Channel)
-		historyProgressMutex.Unlock()
-	}()
+	defer finishBackfillRun(event.Event.Channel, run)
 
 	// Get channel history with progress tracking
 	progressMgr := progress.NewManager()
@@ -399,42 +580,105 @@ func performHistoryRetrievalWithStartTime(cfg *config.Config, slackClient *Clien
 		log.Printf("Found existing progress for channel %s, resuming...", event.Event.Channel)
 	}
 
+	updateStatusProgress(slackClient, event.Event.Channel, "メッセージ履歴を取得中", 0, 0)
 	records, err := slackClient.GetChannelHistoryWithProgress(event.Event.Channel, channelInfo.Name, 0, progressMgr)
 	if err != nil {
 		log.Printf("Error getting channel history: %v", err)
 
+		// A "Reset! force" replaced this run and is waiting for it to exit
+		if errors.Is(err, errBackfillCancelled) {
+			log.Printf("Stopping history retrieval for channel %s - cancelled", event.Event.Channel)
+			return nil
+		}
+
+		// The bot was removed from the channel; there is nobody to notify
+		if isChannelAccessError(err) {
+			log.Printf("Stopping history retrieval for channel %s - bot can no longer access it", event.Event.Channel)
+			return nil
+		}
+
+		// The app was installed without the scope this channel type needs
+		if scope := missingScope(err); scope != "" {
+			notifyJobResult(cfg, slackClient, event, true, missingScopeMessage(scope))
+			failStatusMessage(slackClient, event.Event.Channel)
+			return nil
+		}
+
 		// Check if this is a rate limit error
 		if isRateLimitError(err) {
-			// Schedule retry after 3 minutes with preserved original start time
-			scheduleHistoryRetry(cfg, event.Event.Channel, channelInfo.Name, isInitialRecording, originalStartTime, 3*time.Minute)
+			// Schedule retry after Slack's Retry-After (at least 3 minutes) with preserved original start time
+			retryDelay := rateLimitDelay(err, 3*time.Minute)
+			if retryDelay < 3*time.Minute {
+				retryDelay = 3 * time.Minute
+			}
+			scheduleHistoryRetry(cfg, event.Event.Channel, channelInfo.Name, isInitialRecording, originalStartTime, retryDelay)
+			updateStatusProgress(slackClient, event.Event.Channel, fmt.Sprintf("API制限のため%d分後に再開します", int(retryDelay.Round(time.Minute).Minutes())), 0, 0)
 			return nil // Don't return error, let the retry handle it
 		}
 
-		errorMessage := "❌ チャンネル履歴の取得に失敗しました。"
-		slackClient.SendMessage(event.Event.Channel, errorMessage)
+		notifyFailure(cfg, slackClient, event, boterrors.New(boterrors.HistoryFetchFailed, err))
+		failStatusMessage(slackClient, event.Event.Channel)
 		return err
 	}
 
+	records = filterRecordsBySchedule(cfg, event.Event.Channel, records)
+	records = filterRecordsByOptOut(cfg, records)
+	enrichRecords(cfg, slackClient, records)
+	for _, record := range records {
+		record.TraceID = event.TraceID
+	}
+
 	if len(records) == 0 {
 		noMessagesMsg := "ℹ️ 記録するメッセージが見つかりませんでした。"
-		slackClient.SendMessage(event.Event.Channel, noMessagesMsg)
+		notifyJobResult(cfg, slackClient, event, false, noMessagesMsg)
+		finishStatusMessage(slackClient, event.Event.Channel, noMessagesMsg, nil, "")
+		endStatusThread(event.Event.Channel)
+		recordCompletedRun(event, isInitialRecording, originalStartTime, 0)
+		return nil
+	}
+
+	// Resolving users can take a while; a cancelled run must not write into the tab a reset is about to clear
+	if backfillCancelled(event.Event.Channel) {
+		log.Printf("Stopping history retrieval for channel %s before writing - cancelled", event.Event.Channel)
 		return nil
 	}
 
+	// The whole history is written below, so previously spooled records are superseded
+	if err := retrySpool.Clear(event.Event.Channel); err != nil {
+		log.Printf("Warning: Could not clear retry spool: %v", err)
+	}
+
 	// Write messages to spreadsheet
-	// Use WriteBatchMessagesFromRow2 for initial recording and reset operations
-	// to ensure data starts from row 2 regardless of existing content
-	if err := sheetsClient.WriteBatchMessagesFromRow2(cfg.SpreadsheetID, records); err != nil {
+	// A reset clears the tab first, so the history is written from row 2; otherwise (e.g. a re-invited bot)
+	// it is merged with the rows recorded live so far instead of overwriting them
+	updateStatusProgress(slackClient, event.Event.Channel, "シートに書き込み中", 0, len(records))
+	writeProgress := func(written, total int) {
+		updateStatusProgress(slackClient, event.Event.Channel, "シートに書き込み中", written, total)
+	}
+	if err := sheetsClient.MergeMessages(channelSpreadsheetID(cfg, sheetsClient, event.Event.Channel), records, writeProgress); err != nil {
 		log.Printf("Error writing batch messages to sheets after retries: %v", err)
-		errorMessage := fmt.Sprintf("❌ スプレッドシートへの記録に失敗しました（4回試行後）\n"+
-			"エラー: %v\n"+
-			"ネットワークまたはAPI制限の問題の可能性があります。\n"+
-			"しばらく時間をおいてから再度お試しください。", err)
-		if notifyErr := slackClient.SendMessage(event.Event.Channel, errorMessage); notifyErr != nil {
-			log.Printf("Error sending failure notification after retries: %v", notifyErr)
+		failed := spoolFailedRecords(event.Event.Channel, err)
+		if failed == nil || len(failed) == len(records) {
+			targetURL := buildSheetRangeURL(cfg, sheetsClient, event.Event.Channel, channelInfo.Name, 2, len(records)+1)
+			notifyFailure(cfg, slackClient, event, boterrors.New(boterrors.HistoryWriteFailed, err, targetURL, 2, len(records)+1))
+			failStatusMessage(slackClient, event.Event.Channel)
+			return err
+		}
+
+		// Partial failure: report the counts and continue with the records that were written
+		records = excludeRecords(records, failed)
+		if notifyErr := notifyJobResult(cfg, slackClient, event, true, partialFailureMessage(len(records), len(failed))); notifyErr != nil {
+			log.Printf("Error sending partial failure notification: %v", notifyErr)
 		}
-		return err
 	}
+	// The whole tab now follows the current settings, so there is no change to point out
+	rememberRecordingSettings(cfg, sheetsClient, event.Event.Channel)
+	runRecordHooks(cfg, sheetsClient, records, nil, false)
+	persistRecords(cfg, records)
+	mirrored := append([]*sheets.MessageRecord(nil), records...)
+	mirrorWrite(cfg, "merge channel history", func(sheetsClient *sheets.Client, spreadsheetID string) error {
+		return sheetsClient.MergeMessages(spreadsheetID, mirrored, nil)
+	})
 
 	// Mark progress as completed and clean up
 	if err := progressMgr.UpdatePhase(event.Event.Channel, "completed"); err != nil {
@@ -453,37 +697,57 @@ func performHistoryRetrievalWithStartTime(cfg *config.Config, slackClient *Clien
 
 	log.Printf("Checking for new messages after original start time: %v (channel: %s)", startTime, event.Event.Channel)
 	log.Printf("Wait for 5 minutes before checking for new messages to avoid rate limits")
-	time.Sleep(5 * time.Minute) // Wait to avoid rate limits
+	updateStatusProgress(slackClient, event.Event.Channel, "処理中に投稿された新着メッセージを確認中（約5分）", len(records), len(records))
+	// Wait to avoid rate limits
+	if !run.waitUnlessCancelled(5 * time.Minute) {
+		log.Printf("Skipping new message check for channel %s - cancelled", event.Event.Channel)
+		return nil
+	}
+	if channelRemoved(event.Event.Channel) {
+		log.Printf("Skipping new message check for channel %s - bot was removed", event.Event.Channel)
+		return nil
+	}
 	newMessages, err := slackClient.getMessagesAfterTime(event.Event.Channel, channelInfo.Name, startTime)
 
 	if err != nil {
 		log.Printf("Error: Could not get new messages after history retrieval: %v", err)
 
 		// For non-rate-limit errors, send error message but continue
-		errorMessage := "⚠️ 処理中の新着メッセージ取得に失敗しました。一部のメッセージが記録されていない可能性があります。"
-		if err := slackClient.SendMessage(event.Event.Channel, errorMessage); err != nil {
-			log.Printf("Error sending new messages error notification: %v", err)
-		}
-	} else if len(newMessages) > 0 {
+		notifyFailure(cfg, slackClient, event, boterrors.New(boterrors.NewMessagesFetchFailed, err))
+	} else if newMessages = filterRecordsByOptOut(cfg, filterRecordsBySchedule(cfg, event.Event.Channel, newMessages)); len(newMessages) > 0 {
+		enrichRecords(cfg, slackClient, newMessages)
 		log.Printf("Found %d new messages during history retrieval, adding them", len(newMessages))
-		if err := sheetsClient.WriteBatchMessages(cfg.SpreadsheetID, newMessages); err != nil {
+		if err := sheetsClient.WriteBatchMessages(channelSpreadsheetID(cfg, sheetsClient, event.Event.Channel), newMessages); err != nil {
 			log.Printf("Error: Could not write new messages after history retrieval: %v", err)
 
-			// Critical failure - unable to write new messages
-			errorMessage := "❌ 処理中の新着メッセージの記録に失敗しました。再度実行してください。"
-			if err := slackClient.SendMessage(event.Event.Channel, errorMessage); err != nil {
-				log.Printf("Error sending write failure notification: %v", err)
+			failed := spoolFailedRecords(event.Event.Channel, err)
+			if failed == nil || len(failed) == len(newMessages) {
+				// Critical failure - unable to write new messages
+				sheetURL := buildSheetURLWithGID(cfg, sheetsClient, event.Event.Channel, channelInfo.Name)
+				notifyFailure(cfg, slackClient, event, boterrors.New(boterrors.NewMessagesWriteFailed, err, sheetURL))
+				failStatusMessage(slackClient, event.Event.Channel)
+				return err
+			}
+
+			// Partial failure: report the counts and continue with the records that were written
+			newMessages = excludeRecords(newMessages, failed)
+			runRecordHooks(cfg, sheetsClient, newMessages, nil, false)
+			persistRecords(cfg, newMessages)
+			mirrorRecords(cfg, newMessages)
+			if notifyErr := notifyJobResult(cfg, slackClient, event, true, partialFailureMessage(len(newMessages), len(failed))); notifyErr != nil {
+				log.Printf("Error sending partial failure notification: %v", notifyErr)
 			}
-			return err
 		} else {
 			log.Printf("Successfully added %d new messages after history retrieval", len(newMessages))
+			runRecordHooks(cfg, sheetsClient, newMessages, nil, false)
+			persistRecords(cfg, newMessages)
+			mirrorRecords(cfg, newMessages)
 		}
 	} else {
 		log.Printf("No new messages found during history retrieval period")
 	}
 
 	// Send completion message
-	sheetURL := buildSheetURLWithGID(cfg, sheetsClient, event.Event.Channel, channelInfo.Name)
 	var completionMessage string
 
 	totalRecorded := len(records)
@@ -491,6 +755,9 @@ func performHistoryRetrievalWithStartTime(cfg *config.Config, slackClient *Clien
 		totalRecorded += len(newMessages)
 	}
 
+	// Link to the rows written by this run (history starts at row 2, new messages follow)
+	sheetURL := buildSheetRangeURL(cfg, sheetsClient, event.Event.Channel, channelInfo.Name, 2, totalRecorded+1)
+
 	if isInitialRecording {
 		if len(newMessages) > 0 {
 			completionMessage = fmt.Sprintf("✅ 初回のメッセージ履歴記録が完了しました！\n"+
@@ -517,29 +784,123 @@ func performHistoryRetrievalWithStartTime(cfg *config.Config, slackClient *Clien
 		}
 	}
 
-	if err := slackClient.SendMessage(event.Event.Channel, completionMessage); err != nil {
+	completionMessage += verifyBackfillCount(cfg, sheetsClient, event.Event.Channel, channelInfo.Name, records, newMessages)
+
+	finishStatusMessage(slackClient, event.Event.Channel, strings.SplitN(completionMessage, "\n", 2)[0], []string{
+		fmt.Sprintf("*履歴メッセージ数*\n%d件", len(records)),
+		fmt.Sprintf("*処理中の新着メッセージ数*\n%d件", len(newMessages)),
+		fmt.Sprintf("*合計記録数*\n%d件", totalRecorded),
+	}, sheetURL)
+
+	if err := notifyJobResult(cfg, slackClient, event, false, completionMessage); err != nil {
 		log.Printf("Error sending completion message: %v", err)
 	}
+	endStatusThread(event.Event.Channel)
+	recordCompletedRun(event, isInitialRecording, originalStartTime, totalRecorded)
+	manifestKind := manifestKindFull
+	if isInitialRecording {
+		manifestKind = manifestKindInitial
+	}
+	recordManifest(cfg, sheetsClient, event, channelInfo, manifestKind, totalRecorded)
+	refreshDailyRollup(cfg)
+	arrangeSheetTabs(cfg)
 
 	return nil
 }
 
+// verifyBackfillCount checks that every fetched record has a row in the sheet and returns a note for the completion
+// message when the counts differ, listing the messages that silent write failures left out
+func verifyBackfillCount(cfg *config.Config, sheetsClient *sheets.Client, channelID, channelName string, recordGroups ...[]*sheets.MessageRecord) string {
+	expected := make(map[string]bool)
+	for _, records := range recordGroups {
+		for _, record := range records {
+			expected[record.MessageTS] = true
+		}
+	}
+
+	sheetName := sheets.SheetName(channelID, channelName)
+	recorded, err := sheetsClient.MessageTimestamps(channelSpreadsheetID(cfg, sheetsClient, channelID), sheetName)
+	if err != nil {
+		log.Printf("Warning: could not verify recorded message count for %s: %v", sheetName, err)
+		return "\n⚠️ 記録件数の検証に失敗しました。シートの内容をご確認ください。"
+	}
+
+	var missing []string
+	for messageTS := range expected {
+		if !recorded[messageTS] {
+			missing = append(missing, messageTS)
+		}
+	}
+	sort.Strings(missing)
+
+	if len(missing) > 0 {
+		log.Printf("Backfill verification failed for %s: %d of %d fetched messages are not in the sheet: %s",
+			sheetName, len(missing), len(expected), strings.Join(missing, ", "))
+		shown := missing
+		if len(shown) > maxReportedMissingMessages {
+			shown = shown[:maxReportedMissingMessages]
+		}
+		list := strings.Join(shown, ", ")
+		if len(missing) > len(shown) {
+			list += fmt.Sprintf(" ほか%d件", len(missing)-len(shown))
+		}
+		return fmt.Sprintf("\n⚠️ 取得したメッセージ %d件 のうち %d件 がシートに記録されていません（投稿ID: %s）。\n"+
+			"書き込みに失敗した可能性があります。再度実行してください。", len(expected), len(missing), list)
+	}
+
+	if len(recorded) > len(expected) {
+		log.Printf("Backfill verification passed for %s: %d messages, sheet has %d", sheetName, len(expected), len(recorded))
+		return fmt.Sprintf("\nℹ️ シートには取得件数（%d件）より多い %d件 のメッセージが記録されています"+
+			"（処理中に自動記録されたメッセージを含みます）。", len(expected), len(recorded))
+	}
+	log.Printf("Backfill verification passed for %s: %d messages", sheetName, len(expected))
+	return ""
+}
+
 func handleMemberJoined(cfg *config.Config, event *Event) error {
 	// Check if the bot itself was added to the channel
-	slackClient := NewClient(cfg.SlackBotToken)
+	slackClient := newClient(cfg)
+	clearChannelRemoved(event.Event.Channel)
 
 	// Get channel information
 	channelInfo, err := slackClient.GetChannelInfo(event.Event.Channel)
 	if err != nil {
 		log.Printf("Error getting channel info for member join: %v", err)
+
+		// Private channels cannot even be looked up without groups:read
+		if scope := missingScope(err); scope != "" {
+			if err := slackClient.SendMessage(event.Event.Channel, missingScopeMessage(scope)); err != nil {
+				log.Printf("Error sending missing scope message: %v", err)
+			}
+			return nil
+		}
 		channelInfo = &ChannelInfo{ID: event.Event.Channel, Name: "Unknown"}
 	}
 
+	// Slack Connect channels are refused before anything is retrieved when RECORD_SHARED_CHANNELS=false
+	if refusesSharedChannel(cfg, channelInfo) {
+		if err := slackClient.SendMessage(event.Event.Channel, sharedChannelRefusedMessage); err != nil {
+			log.Printf("Error sending shared channel message: %v", err)
+		}
+		return nil
+	}
+
+	// Re-invites (e.g. after a kick) do not pull the whole history again within the cooldown
+	if skipIfInCooldown(cfg, slackClient, event, channelInfo) {
+		return nil
+	}
+
+	// A channel recorded before the bot was removed can continue from its last recorded message
+	if offerContinuation(cfg, slackClient, event, channelInfo) {
+		return nil
+	}
+
 	// Send initial message
 	message := fmt.Sprintf("🚀 初回の記録を開始します...\n"+
-		"このチャンネル (#%s) のメッセージをGoogle Sheetsに記録します。", channelInfo.Name)
+		"このチャンネル (#%s) のメッセージをGoogle Sheetsに記録します。\n"+
+		"%s", channelInfo.Name, recordingNotice)
 
-	if err := slackClient.SendMessage(event.Event.Channel, message); err != nil {
+	if err := startStatusThread(slackClient, event.Event.Channel, message); err != nil {
 		log.Printf("Error sending initial message: %v", err)
 	}
 
@@ -548,7 +909,7 @@ func handleMemberJoined(cfg *config.Config, event *Event) error {
 }
 
 func handleAppMention(cfg *config.Config, event *Event) error {
-	slackClient := NewClient(cfg.SlackBotToken)
+	slackClient := newClient(cfg)
 
 	// Get channel information
 	channelInfo, err := slackClient.GetChannelInfo(event.Event.Channel)
@@ -557,86 +918,142 @@ func handleAppMention(cfg *config.Config, event *Event) error {
 		channelInfo = &ChannelInfo{ID: event.Event.Channel, Name: "Unknown"}
 	}
 
-	// Check if this is a reset request
-	isResetRequest := strings.Contains(strings.ToLower(event.Event.Text), "reset")
-
-	// Check if this is a "show me" command
-	isShowMeCmd := strings.Contains(strings.ToLower(event.Event.Text), "show me")
-	var extractedEmail string
-	if isShowMeCmd {
-		extractedEmail = extractEmailFromShowMe(event.Event.Text)
+	// Commands are read only from the words right after a leading bot mention
+	botUserID, err := slackClient.BotUserID()
+	if err != nil {
+		log.Printf("Warning: Could not resolve the bot user ID, accepting any leading mention: %v", err)
 	}
+	command, isCommand := commandText(event.Event.Text, botUserID)
 
 	// First, record the mention message itself
 	if err := recordSingleMessage(cfg, slackClient, event, channelInfo); err != nil {
 		log.Printf("Error recording mention message: %v", err)
 	}
 
-	// Handle "show me" command
-	if isShowMeCmd {
-		return handleShowMeCommand(cfg, slackClient, event, channelInfo, extractedEmail)
+	// A mention in the middle of a message is an ordinary message, so it is only recorded
+	if !isCommand {
+		tracef(event, "Mention is not at the start of the message, recorded without running a command")
+		return nil
 	}
 
-	// If not a reset request, just respond with instruction and return
-	if !isResetRequest {
-		ackMessage := "🔗 ユーザーにスプレッドシート閲覧権限を付与するには「show me <メールアドレス>」とメンションしてください\n" +
-			"🤖 このチャンネルの記録を取得し直すには「Reset!」とメンションしてください\n"
+	return runCommand(&commandInvocation{cfg: cfg, slackClient: slackClient, event: event, channelInfo: channelInfo, text: command})
+}
 
-		if err := slackClient.SendMessage(event.Event.Channel, ackMessage); err != nil {
-			log.Printf("Error sending acknowledgment message: %v", err)
-		}
+// handleResetCommand clears the channel's tab and retrieves the history again ("Reset!"), or previews the reset
+func handleResetCommand(cfg *config.Config, slackClient *Client, event *Event, channelInfo *ChannelInfo, command string) error {
+	isForceReset := forceResetPattern.MatchString(command)
+	isResetPreview := resetPreviewPattern.MatchString(command)
+	isResetConfirm := resetConfirmPattern.MatchString(command)
+
+	// "Reset! preview" only shows what would be deleted
+	if isResetPreview {
+		return handleResetPreview(cfg, slackClient, event, channelInfo, false)
+	}
+
+	// Channels under legal hold keep their recorded data until an admin releases the hold
+	if blockedByLegalHold(cfg, slackClient, event.Event.Channel, event.Event.User, "リセット") {
 		return nil
 	}
 
+	// A plain "Reset!" shows the preview first and runs only after "Reset! confirm" from the same user
+	if cfg.ResetConfirmation && !isForceReset {
+		if !isResetConfirm {
+			return handleResetPreview(cfg, slackClient, event, channelInfo, true)
+		}
+		if !takeResetConfirmation(event.Event.Channel, event.Event.User) {
+			message := i18n.T(replyLanguage(cfg, slackClient, event.Event.User), i18n.KeyResetNoPending)
+			if err := slackClient.SendMessage(event.Event.Channel, message); err != nil {
+				log.Printf("Error sending reset confirmation reply: %v", err)
+			}
+			return nil
+		}
+	}
+
+	return runReset(cfg, slackClient, event, channelInfo, isForceReset)
+}
+
+// runReset clears the channel's tab and retrieves the history again once the reset has been confirmed
+func runReset(cfg *config.Config, slackClient *Client, event *Event, channelInfo *ChannelInfo, isForceReset bool) error {
+	lang := replyLanguage(cfg, slackClient, event.Event.User)
+	if isForceReset {
+		// "Reset! force" skips the duplicate and in-progress guards below, so it is limited to admins and audited
+		if !cfg.IsAdmin(event.Event.User) {
+			message := i18n.T(lang, i18n.KeyResetForceAdminOnly)
+			if err := slackClient.SendMessage(event.Event.Channel, message); err != nil {
+				log.Printf("Error sending force reset rejection: %v", err)
+			}
+			return nil
+		}
+		recordAudit(cfg, audit.Entry{
+			Action:    "force_reset",
+			ChannelID: event.Event.Channel,
+			User:      event.Event.User,
+			Detail:    fmt.Sprintf("#%s: bypassed run history, in-progress and cooldown guards", channelInfo.Name),
+		})
+
+		// A running backfill would keep writing into the cleared tab, so it is stopped before anything is cleared
+		if !cancelBackfillRun(event.Event.Channel, forceResetCancelTimeout) {
+			message := i18n.T(lang, i18n.KeyResetForceStopFailed)
+			if err := slackClient.SendMessage(event.Event.Channel, message); err != nil {
+				log.Printf("Error sending force reset failure: %v", err)
+			}
+			return nil
+		}
+	} else {
+		// Slack redelivers slow mentions; answer from the run history instead of backfilling twice
+		if replyIfAlreadyCompleted(slackClient, event, lang) {
+			return nil
+		}
+		if replyIfBackfillRunning(slackClient, event, lang) {
+			return nil
+		}
+	}
+
 	// Send acknowledgment message for reset request
-	ackMessage := fmt.Sprintf("🔄 シートをリセットして過去のメッセージ履歴を再取得しています... (#%s)", channelInfo.Name)
-	if err := slackClient.SendMessage(event.Event.Channel, ackMessage); err != nil {
+	ackMessage := i18n.T(lang, i18n.KeyResetStarted, channelInfo.Name)
+	if err := startStatusThread(slackClient, event.Event.Channel, ackMessage); err != nil {
 		log.Printf("Error sending acknowledgment message: %v", err)
 	}
 
 	// Check if Google Sheets is configured
 	if cfg.GoogleSheetsCredentials == "" || cfg.SpreadsheetID == "" {
-		configMessage := "⚠️ Google Sheetsの設定が完了していません。管理者にお問い合わせください。"
-		slackClient.SendMessage(event.Event.Channel, configMessage)
+		notifyFailure(cfg, slackClient, event, boterrors.New(boterrors.SheetsNotConfigured, nil))
 		return nil
 	}
 
 	// Create Google Sheets client
-	sheetsClient, err := sheets.NewClient(cfg.GoogleSheetsCredentials)
+	sheetsClient, err := newSheetsClient(cfg)
 	if err != nil {
 		log.Printf("Error creating Google Sheets client: %v", err)
-		errorMessage := "❌ Google Sheetsへの接続に失敗しました。"
-		slackClient.SendMessage(event.Event.Channel, errorMessage)
+		notifyFailure(cfg, slackClient, event, boterrors.New(boterrors.SheetsConnectFailed, err))
 		return err
 	}
 
-	// Handle reset request - clear existing data
-	if isResetRequest {
-		sheetName := fmt.Sprintf("%s-%s", channelInfo.Name, event.Event.Channel)
+	// Clear existing data of the reset channel
+	sheetName := sheets.SheetName(event.Event.Channel, channelInfo.Name)
 
-		// Ensure the sheet exists first
-		if err := sheetsClient.EnsureChannelSheetExists(cfg.SpreadsheetID, event.Event.Channel, channelInfo.Name); err != nil {
-			log.Printf("Error ensuring sheet exists for reset: %v", err)
-			errorMessage := "❌ シートの確認に失敗しました。"
-			slackClient.SendMessage(event.Event.Channel, errorMessage)
-			return err
-		}
+	// Ensure the sheet exists first
+	if err := sheetsClient.EnsureChannelSheetExists(channelSpreadsheetID(cfg, sheetsClient, event.Event.Channel), event.Event.Channel, channelInfo.Name); err != nil {
+		log.Printf("Error ensuring sheet exists for reset: %v", err)
+		notifyFailure(cfg, slackClient, event, boterrors.New(boterrors.SheetCheckFailed, err))
+		return err
+	}
 
-		// Clear existing data
-		if err := sheetsClient.ClearSheetData(cfg.SpreadsheetID, sheetName); err != nil {
-			log.Printf("Error clearing sheet data: %v", err)
-			errorMessage := "❌ シートのクリアに失敗しました。"
-			slackClient.SendMessage(event.Event.Channel, errorMessage)
-			return err
-		}
+	// Clear existing data
+	if err := sheetsClient.ClearSheetData(channelSpreadsheetID(cfg, sheetsClient, event.Event.Channel), sheetName); err != nil {
+		log.Printf("Error clearing sheet data: %v", err)
+		notifyFailure(cfg, slackClient, event, boterrors.New(boterrors.SheetClearFailed, err))
+		return err
+	}
 
-		log.Printf("Sheet reset completed for channel %s", channelInfo.Name)
+	// The mirror is not cleared: it is the backup that a reset of the primary must not wipe, and the history
+	// retrieved below is merged into it without duplicating the rows it already has
+	log.Printf("Sheet reset completed for channel %s", channelInfo.Name)
 
-		// Clean up any existing progress for reset
-		progressMgr := progress.NewManager()
-		if err := progressMgr.DeleteProgress(event.Event.Channel); err != nil {
-			log.Printf("Warning: Could not clean up existing progress: %v", err)
-		}
+	// Clean up any existing progress for reset
+	progressMgr := progress.NewManager()
+	if err := progressMgr.DeleteProgress(event.Event.Channel); err != nil {
+		log.Printf("Warning: Could not clean up existing progress: %v", err)
 	}
 
 	// Use the common history retrieval function
@@ -666,7 +1083,7 @@ func handleMessageChanged(cfg *config.Config, event *Event) error {
 	}
 
 	// Create Slack client
-	slackClient := NewClient(cfg.SlackBotToken)
+	slackClient := newClient(cfg)
 
 	// Get channel information
 	channelInfo, err := slackClient.GetChannelInfo(event.Event.Channel)
@@ -676,62 +1093,53 @@ func handleMessageChanged(cfg *config.Config, event *Event) error {
 	}
 
 	// Get user information for the edited message
-	var userInfo *UserInfo
-	if changedMessage.User != "" {
-		userInfo, err = slackClient.GetUserInfo(changedMessage.User)
-		if err != nil {
-			log.Printf("Error getting user info for edited message: %v", err)
-			userInfo = &UserInfo{ID: changedMessage.User, Name: "Unknown", RealName: "Unknown"}
-		}
-	} else {
-		userInfo = &UserInfo{ID: "", Name: "Bot", RealName: "Bot"}
-	}
+	userInfo := slackClient.messageAuthor(changedMessage.User, changedMessage.BotID, changedMessage.Username, changedMessage.UserTeam, changedMessage.BotProfile, changedMessage.UserProfile)
 
 	// Parse timestamp and convert to JST
 	timestamp := convertSlackTimestampToJST(changedMessage.Timestamp)
 
 	// Format message text including attachments
-	formattedText := slackClient.FormatMessageWithAttachments(changedMessage.Text, changedMessage.Attachments, changedMessage.Files)
+	formattedText := slackClient.FormatMessageWithAttachments(changedMessage.Subtype, messageText(changedMessage.Text, changedMessage.Blocks), changedMessage.Attachments, changedMessage.Files)
 
 	// Create message record for the edited message
 	record := sheets.MessageRecord{
-		Timestamp:    timestamp,
-		Channel:      event.Event.Channel,
-		ChannelName:  channelInfo.Name,
-		User:         changedMessage.User,
-		UserHandle:   userInfo.Name,
-		UserRealName: userInfo.RealName,
-		Text:         formattedText,
-		ThreadTS:     changedMessage.ThreadTS,
-		MessageTS:    changedMessage.Timestamp,
-	}
-
-	// Create Google Sheets client and update the message
-	sheetsClient, err := sheets.NewClient(cfg.GoogleSheetsCredentials)
-	if err != nil {
-		log.Printf("Error creating Google Sheets client for message edit: %v", err)
-		return err
+		Timestamp:     timestamp,
+		Channel:       event.Event.Channel,
+		ChannelName:   channelInfo.Name,
+		User:          changedMessage.User,
+		UserHandle:    userInfo.Name,
+		UserRealName:  userInfo.RealName,
+		Text:          formattedText,
+		ThreadTS:      changedMessage.ThreadTS,
+		MessageTS:     changedMessage.Timestamp,
+		AppSource:     appSource(changedMessage.AppID, changedMessage.BotProfile),
+		EditorID:      editorID(changedMessage.Edited),
+		Reactions:     formatReactions(changedMessage.Reactions),
+		ReactionCount: reactionCount(changedMessage.Reactions),
+		ReplyCount:    changedMessage.ReplyCount,
+		Files:         fileRecords(changedMessage.Files),
+		Pinned:        len(changedMessage.PinnedTo) > 0,
 	}
 
-	// Update the message in the sheet
-	if err := sheetsClient.UpdateMessage(cfg.SpreadsheetID, &record); err != nil {
-		log.Printf("Error updating edited message in Google Sheets: %v", err)
-		return err
+	// Respect users who opted out of recording
+	if !applyOptOutPolicy(cfg, &record) {
+		log.Printf("Skipping edit of message %s in channel %s - user opted out", record.MessageTS, record.Channel)
+		return nil
 	}
+	enrichRecords(cfg, slackClient, []*sheets.MessageRecord{&record})
 
-	log.Printf("✅ Message edit recorded in #%s by %s: %s",
-		record.ChannelName, record.UserHandle,
-		truncateText(record.Text, 50))
-
+	// Rapid successive edits are squashed and written together with the other edits of the channel
+	queueEdit(cfg, &record, changedMessage.Edited.Timestamp)
 	return nil
 }
 
 // handleShowMeCommand handles the "show me" command to grant spreadsheet access
 func handleShowMeCommand(cfg *config.Config, slackClient *Client, event *Event, channelInfo *ChannelInfo, email string) error {
+	lang := replyLanguage(cfg, slackClient, event.Event.User)
+
 	// Validate email
 	if email == "" {
-		errorMessage := "❌ 有効なメールアドレスが見つかりませんでした。\n" +
-			"使用例: `@bot show me test@example.com`"
+		errorMessage := i18n.T(lang, i18n.KeyShowMeInvalidEmail)
 		if err := slackClient.SendMessage(event.Event.Channel, errorMessage); err != nil {
 			log.Printf("Error sending invalid email message: %v", err)
 		}
@@ -740,7 +1148,7 @@ func handleShowMeCommand(cfg *config.Config, slackClient *Client, event *Event,
 
 	// Check if Google Sheets is configured
 	if cfg.GoogleSheetsCredentials == "" || cfg.SpreadsheetID == "" {
-		configMessage := "⚠️ Google Sheetsの設定が完了していません。管理者にお問い合わせください。"
+		configMessage := i18n.T(lang, i18n.KeySheetsNotConfigured)
 		if err := slackClient.SendMessage(event.Event.Channel, configMessage); err != nil {
 			log.Printf("Error sending config message: %v", err)
 		}
@@ -748,29 +1156,40 @@ func handleShowMeCommand(cfg *config.Config, slackClient *Client, event *Event,
 	}
 
 	// Create Google Sheets client
-	sheetsClient, err := sheets.NewClient(cfg.GoogleSheetsCredentials)
+	sheetsClient, err := newSheetsClient(cfg)
 	if err != nil {
 		log.Printf("Error creating Google Sheets client for sharing: %v", err)
-		errorMessage := "❌ Google Sheetsへの接続に失敗しました。"
+		errorMessage := i18n.T(lang, i18n.KeySheetsConnectFailed)
 		if err := slackClient.SendMessage(event.Event.Channel, errorMessage); err != nil {
 			log.Printf("Error sending connection error message: %v", err)
 		}
 		return err
 	}
 
-	// Share the spreadsheet
-	if err := sheetsClient.ShareSpreadsheet(cfg.SpreadsheetID, email); err != nil {
+	// Share the spreadsheet (and its continuations when it has been split), or add the person to its Google Group
+	groups, shared, err := grantReadAccess(cfg, sheetsClient, email)
+	if err != nil {
 		log.Printf("Error sharing spreadsheet with %s: %v", email, err)
-		errorMessage := fmt.Sprintf("❌ %s への権限付与に失敗しました（エラー: %v）", email, err)
+		errorMessage := i18n.T(lang, i18n.KeyShowMeShareFailed, email, err)
 		if err := slackClient.SendMessage(event.Event.Channel, errorMessage); err != nil {
 			log.Printf("Error sending share error message: %v", err)
 		}
 		return err
 	}
 
+	// The grant time lets the quarterly access review flag grants nobody renewed; group members are not grants
+	// of the spreadsheet and are reviewed in the group
+	if len(groups) > 0 {
+		recordAudit(cfg, audit.Entry{Action: "group_member_added", ChannelID: event.Event.Channel, User: event.Event.User,
+			Detail: fmt.Sprintf("%s to %s", email, strings.Join(groups, ", "))})
+	}
+	if shared {
+		recordAudit(cfg, audit.Entry{Action: "share", ChannelID: event.Event.Channel, User: event.Event.User, Detail: email})
+	}
+
 	// Send success message
 	sheetURL := buildSheetURLWithGID(cfg, sheetsClient, event.Event.Channel, channelInfo.Name)
-	successMessage := fmt.Sprintf("✅ %s に<%s|スプレッドシート>の閲覧権限を付与しました。", email, sheetURL)
+	successMessage := i18n.T(lang, i18n.KeyShowMeShared, email, sheetURL)
 	if err := slackClient.SendMessage(event.Event.Channel, successMessage); err != nil {
 		log.Printf("Error sending success message: %v", err)
 	}
@@ -781,13 +1200,14 @@ func handleShowMeCommand(cfg *config.Config, slackClient *Client, event *Event,
 
 // buildSheetURLWithGID builds a Google Sheets URL with specific sheet ID (gid) parameter
 func buildSheetURLWithGID(cfg *config.Config, sheetsClient *sheets.Client, channelID, channelName string) string {
-	baseURL := fmt.Sprintf("https://docs.google.com/spreadsheets/d/%s", cfg.SpreadsheetID)
+	spreadsheetID := channelSpreadsheetID(cfg, sheetsClient, channelID)
+	baseURL := fmt.Sprintf("https://docs.google.com/spreadsheets/d/%s", spreadsheetID)
 
 	// Generate sheet name to match the one used in ensureChannelSheetExists
-	sheetName := fmt.Sprintf("%s-%s", channelName, channelID)
+	sheetName := sheets.SheetName(channelID, channelName)
 
 	// Try to get the sheet ID (gid)
-	if sheetID, err := sheetsClient.GetSheetID(cfg.SpreadsheetID, sheetName); err == nil {
+	if sheetID, err := sheetsClient.GetSheetID(spreadsheetID, sheetName); err == nil {
 		// Return URL with gid parameter for direct navigation to the specific sheet
 		return fmt.Sprintf("%s/edit?gid=%d#gid=%d", baseURL, sheetID, sheetID)
 	} else {
@@ -797,6 +1217,18 @@ func buildSheetURLWithGID(cfg *config.Config, sheetsClient *sheets.Client, chann
 	}
 }
 
+// buildSheetRangeURL builds a Google Sheets URL that opens the channel sheet with the given rows selected
+func buildSheetRangeURL(cfg *config.Config, sheetsClient *sheets.Client, channelID, channelName string, firstRow, lastRow int) string {
+	sheetURL := buildSheetURLWithGID(cfg, sheetsClient, channelID, channelName)
+	if !strings.Contains(sheetURL, "#gid=") || firstRow <= 0 {
+		return sheetURL
+	}
+	if lastRow < firstRow {
+		lastRow = firstRow
+	}
+	return fmt.Sprintf("%s&range=%s", sheetURL, sheetsClient.RowRange(firstRow, lastRow))
+}
+
 // convertSlackTimestampToJST converts a Slack timestamp string to JST time
 func convertSlackTimestampToJST(timestampStr string) time.Time {
 	ts, err := strconv.ParseFloat(timestampStr, 64)
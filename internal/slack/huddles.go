@@ -0,0 +1,52 @@
+package slack
+
+import (
+	"fmt"
+	"time"
+)
+
+// HuddleRoom is the huddle of a huddle_thread message
+type HuddleRoom struct {
+	ID                 string   `json:"id"`
+	CreatedBy          string   `json:"created_by"`
+	DateStart          int64    `json:"date_start"`
+	DateEnd            int64    `json:"date_end"`
+	HasEnded           bool     `json:"has_ended"`
+	ParticipantHistory []string `json:"participant_history"`
+}
+
+// callText is the text of a message sharing a call (a "call" block), whose text field is empty
+const callText = "📞 通話が共有されました"
+
+// huddleText describes a huddle as a system row, with Slack user markup that FormatMessageText resolves. Messages
+// recorded live only see the start; a backfill also sees the participants and length of huddles that ended.
+func huddleText(room *HuddleRoom) string {
+	text := fmt.Sprintf("🎧 <@%s> がハドルを開始しました", room.CreatedBy)
+	if !room.HasEnded {
+		return text
+	}
+
+	text = fmt.Sprintf("🎧 <@%s> が開始したハドル（終了）", room.CreatedBy)
+	if room.DateEnd > room.DateStart && room.DateStart > 0 {
+		text += fmt.Sprintf("\n時間: %d分", int(time.Duration(room.DateEnd-room.DateStart)*time.Second/time.Minute))
+	}
+	if len(room.ParticipantHistory) > 0 {
+		text += "\n参加者:"
+		for _, userID := range room.ParticipantHistory {
+			text += fmt.Sprintf(" <@%s>", userID)
+		}
+	}
+	return text
+}
+
+// systemMessageText returns the text recorded for messages that Slack sends with a system subtype and little or no
+// text (channel topic and purpose changes, huddles, joins and leaves); other messages keep their text
+func systemMessageText(subtype, text, topic, purpose, user, inviter string, room *HuddleRoom) string {
+	if room != nil && room.CreatedBy != "" && subtype == "huddle_thread" {
+		return huddleText(room)
+	}
+	if isMembershipMessage(subtype) {
+		return membershipText(subtype, text, user, inviter)
+	}
+	return channelChangeText(subtype, text, topic, purpose)
+}
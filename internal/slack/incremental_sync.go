@@ -0,0 +1,83 @@
+package slack
+
+import (
+	"log"
+	"time"
+
+	"slack-to-google-sheets-bot/internal/config"
+)
+
+// StartIncrementalSync appends, every INCREMENTAL_SYNC_INTERVAL_MINUTES, the messages posted after the newest
+// recorded one of every channel tab, so events lost while the bot was down or unreachable heal on their own.
+// Unlike AUTO_DISCOVER_CHANNELS it only looks at channels that already have a tab and never starts a backfill.
+func StartIncrementalSync(cfg *config.Config) {
+	if cfg.IncrementalSyncMinutes <= 0 {
+		return
+	}
+	if cfg.SlackBotToken == "" || cfg.GoogleSheetsCredentials == "" || cfg.SpreadsheetID == "" {
+		log.Printf("Incremental sync needs SLACK_BOT_TOKEN and Google Sheets to be configured, not starting it")
+		return
+	}
+
+	interval := time.Duration(cfg.IncrementalSyncMinutes) * time.Minute
+	go func() {
+		for {
+			time.Sleep(interval)
+			runIncrementalSync(cfg)
+		}
+	}()
+}
+
+// runIncrementalSync runs one sync round over the channel tabs of every spreadsheet
+func runIncrementalSync(cfg *config.Config) {
+	// Shares the lock with channel discovery, which appends missed messages the same way
+	discoveryMutex.Lock()
+	defer discoveryMutex.Unlock()
+
+	sheetsClient, err := newSheetsClient(cfg)
+	if err != nil {
+		log.Printf("Error creating Google Sheets client for incremental sync: %v", err)
+		return
+	}
+	slackClient := newClient(cfg)
+
+	channels, appended := 0, 0
+	for _, spreadsheetID := range allSpreadsheetIDs(cfg) {
+		channelSheets, err := sheetsClient.ChannelSheets(spreadsheetID)
+		if err != nil {
+			log.Printf("Error listing channel tabs of spreadsheet %s for incremental sync: %v", spreadsheetID, err)
+			continue
+		}
+
+		for channelID := range channelSheets {
+			// A channel whose tab lives in another spreadsheet now is synced there
+			if channelSpreadsheetID(cfg, sheetsClient, channelID) != spreadsheetID || channelRemoved(channelID) {
+				continue
+			}
+			historyProgressMutex.Lock()
+			inProgress := historyInProgress[channelID]
+			historyProgressMutex.Unlock()
+			if inProgress {
+				continue
+			}
+
+			channelInfo, err := slackClient.GetChannelInfo(channelID)
+			if err != nil {
+				log.Printf("Incremental sync: skipping channel %s: %v", channelID, err)
+				continue
+			}
+
+			count, err := appendMissedMessages(cfg, slackClient, sheetsClient, spreadsheetID, channelInfo)
+			if err != nil {
+				log.Printf("Error syncing channel %s (#%s): %v", channelID, channelInfo.Name, err)
+				continue
+			}
+			channels++
+			if count > 0 {
+				appended += count
+				log.Printf("Incremental sync: appended %d missed messages to #%s (%s)", count, channelInfo.Name, channelID)
+			}
+		}
+	}
+	log.Printf("Incremental sync: checked %d channels, appended %d missed messages", channels, appended)
+}
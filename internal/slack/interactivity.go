@@ -0,0 +1,117 @@
+package slack
+
+import (
+	"fmt"
+	"log"
+
+	"slack-to-google-sheets-bot/internal/config"
+	"slack-to-google-sheets-bot/internal/i18n"
+)
+
+// Action IDs of the buttons the bot posts
+const (
+	actionResetConfirm = "reset_confirm"
+	actionResetCancel  = "reset_cancel"
+
+	actionReinviteContinue = "reinvite_continue"
+	actionReinviteFull     = "reinvite_full"
+)
+
+// interactionHandler handles a clicked button; the event carries the channel and the user who clicked
+type interactionHandler func(cfg *config.Config, slackClient *Client, event *Event, payload *InteractionPayload, action BlockAction) error
+
+// interactionHandlers routes block_actions by action ID
+var interactionHandlers = map[string]interactionHandler{
+	actionResetConfirm: handleResetConfirmButton,
+	actionResetCancel:  handleResetCancelButton,
+
+	actionReinviteContinue: handleReinviteContinueButton,
+	actionReinviteFull:     handleReinviteFullButton,
+}
+
+// HandleInteraction routes a block_actions payload from /slack/interactivity to the handler of each clicked action
+func HandleInteraction(cfg *config.Config, payload *InteractionPayload) error {
+	if payload.Type != "block_actions" {
+		log.Printf("Ignoring unsupported interaction type %s", payload.Type)
+		return nil
+	}
+
+	cfg, err := configForTeam(cfg, payload.Team.ID, payload.Enterprise.ID)
+	if err != nil {
+		return fmt.Errorf("refusing interaction from workspace %s: %v", payload.Team.ID, err)
+	}
+	slackClient := newClient(cfg)
+	for _, action := range payload.Actions {
+		handler, exists := interactionHandlers[action.ActionID]
+		if !exists {
+			log.Printf("Ignoring unknown action %s", action.ActionID)
+			continue
+		}
+
+		// Buttons carry their channel in the value, which also covers messages without a channel in the payload
+		channelID := payload.Channel.ID
+		if action.Value != "" {
+			channelID = action.Value
+		}
+		event := commandEvent("block_actions", payload.Team.ID, payload.TriggerID, channelID, payload.User.ID, "")
+		tracef(event, "Received %s from %s in %s", action.ActionID, payload.User.ID, channelID)
+
+		if err := handler(cfg, slackClient, event, payload, action); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// replyToClicker tells only the user who clicked a button why nothing happened
+func replyToClicker(slackClient *Client, payload *InteractionPayload, message string) {
+	if payload.ResponseURL == "" {
+		return
+	}
+	if err := slackClient.PostResponse(payload.ResponseURL, message, true); err != nil {
+		log.Printf("Error sending interaction response: %v", err)
+	}
+}
+
+// replaceButtons replaces the message with the buttons so they cannot be clicked twice
+func replaceButtons(slackClient *Client, payload *InteractionPayload, message string) {
+	if payload.ResponseURL == "" {
+		return
+	}
+	if err := slackClient.ReplaceOriginal(payload.ResponseURL, message); err != nil {
+		log.Printf("Error replacing interaction message: %v", err)
+	}
+}
+
+// handleResetConfirmButton runs a previewed reset when the user who asked for it clicks "リセットを実行"
+func handleResetConfirmButton(cfg *config.Config, slackClient *Client, event *Event, payload *InteractionPayload, action BlockAction) error {
+	if blockedByLegalHold(cfg, slackClient, event.Event.Channel, event.Event.User, "リセット") {
+		cancelResetConfirmation(event.Event.Channel, event.Event.User)
+		replaceButtons(slackClient, payload, i18n.T(replyLanguage(cfg, slackClient, event.Event.User), i18n.KeyResetHeldNotRun))
+		return nil
+	}
+	lang := replyLanguage(cfg, slackClient, event.Event.User)
+	if !takeResetConfirmation(event.Event.Channel, event.Event.User) {
+		replyToClicker(slackClient, payload, i18n.T(lang, i18n.KeyResetButtonNoPending))
+		return nil
+	}
+	replaceButtons(slackClient, payload, i18n.T(lang, i18n.KeyResetConfirmed, event.Event.User))
+
+	channelInfo, err := slackClient.GetChannelInfo(event.Event.Channel)
+	if err != nil {
+		log.Printf("Error getting channel info for reset button: %v", err)
+		channelInfo = &ChannelInfo{ID: event.Event.Channel, Name: "Unknown"}
+	}
+	return runReset(cfg, slackClient, event, channelInfo, false)
+}
+
+// handleResetCancelButton drops a previewed reset when the user who asked for it clicks "キャンセル"
+func handleResetCancelButton(cfg *config.Config, slackClient *Client, event *Event, payload *InteractionPayload, action BlockAction) error {
+	lang := replyLanguage(cfg, slackClient, event.Event.User)
+	if !cancelResetConfirmation(event.Event.Channel, event.Event.User) {
+		replyToClicker(slackClient, payload, i18n.T(lang, i18n.KeyResetCancelNotRequester))
+		return nil
+	}
+	replaceButtons(slackClient, payload, i18n.T(lang, i18n.KeyResetCancelled, event.Event.User))
+	return nil
+}
@@ -0,0 +1,67 @@
+package slack
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"slack-to-google-sheets-bot/internal/config"
+	"slack-to-google-sheets-bot/internal/latency"
+)
+
+const (
+	// latencyCheckInterval is how often the last hour's p95 is compared against the SLO
+	latencyCheckInterval = 5 * time.Minute
+	// minLatencySamples keeps a handful of slow messages in a quiet hour from raising an alert
+	minLatencySamples = 20
+)
+
+// StartLatencySLOMonitor alerts the admin channel when the p95 from event receipt to sheet write over the last hour
+// exceeds LATENCY_SLO_P95_SECONDS, once per breach, and again when it is back within the SLO
+func StartLatencySLOMonitor(cfg *config.Config) {
+	if cfg.LatencySLOP95Seconds <= 0 {
+		return
+	}
+	if cfg.AdminChannelID == "" {
+		log.Printf("Warning: LATENCY_SLO_P95_SECONDS is set but ADMIN_CHANNEL_ID is not, latency alerts disabled")
+		return
+	}
+
+	go func() {
+		breached := false
+		for {
+			time.Sleep(latencyCheckInterval)
+			breached = checkLatencySLO(cfg, breached)
+		}
+	}()
+}
+
+// checkLatencySLO posts an alert or a recovery message when the breach state changes and returns the new state
+func checkLatencySLO(cfg *config.Config, breached bool) bool {
+	slo := float64(cfg.LatencySLOP95Seconds)
+	lastHour := latency.Default().Snapshot(time.Now()).LastHour
+	if lastHour.Count < minLatencySamples {
+		return breached
+	}
+
+	var message string
+	switch {
+	case !breached && lastHour.P95 > slo:
+		log.Printf("Recording latency p95 %.1fs exceeds the SLO of %.0fs (%d messages in the last hour)", lastHour.P95, slo, lastHour.Count)
+		message = fmt.Sprintf("⚠️ 直近1時間の記録レイテンシ（イベント受信からシート書き込みまで）の p95 が %.1f秒 となり、SLO（%.0f秒）を超えました。\n"+
+			"対象: %d件（p50 %.1f秒・p99 %.1f秒・最大 %.1f秒）\n"+
+			"Google Sheets API のクォータや Slack API のレート制限を確認してください。",
+			lastHour.P95, slo, lastHour.Count, lastHour.P50, lastHour.P99, lastHour.Max)
+	case breached && lastHour.P95 <= slo:
+		log.Printf("Recording latency p95 %.1fs is back within the SLO of %.0fs", lastHour.P95, slo)
+		message = fmt.Sprintf("✅ 直近1時間の記録レイテンシの p95 が %.1f秒 となり、SLO（%.0f秒）以内に戻りました。", lastHour.P95, slo)
+	default:
+		return breached
+	}
+
+	if err := newClient(cfg).SendMessage(cfg.AdminChannelID, message); err != nil {
+		log.Printf("Error sending latency alert: %v", err)
+		return breached
+	}
+	return !breached
+}
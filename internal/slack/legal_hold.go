@@ -0,0 +1,89 @@
+package slack
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+
+	"slack-to-google-sheets-bot/internal/audit"
+	"slack-to-google-sheets-bot/internal/config"
+	"slack-to-google-sheets-bot/internal/legalhold"
+)
+
+var legalHolds = legalhold.NewRegistry("legal-hold")
+
+// SetLegalHoldDir sets the data directory of the legal hold registry; call it once at startup
+func SetLegalHoldDir(dir string) {
+	legalHolds = legalhold.NewRegistry(dir)
+}
+
+var (
+	// holdCommandPattern matches the admin-only "hold [reason]", which places the channel under legal hold
+	holdCommandPattern = regexp.MustCompile(`(?is)^hold\b\s*(.*?)\s*$`)
+	// releaseHoldCommandPattern matches the admin-only "release hold", which lifts the legal hold
+	releaseHoldCommandPattern = regexp.MustCompile(`(?i)^release\s+hold\b`)
+)
+
+// blockedByLegalHold refuses an operation that would delete a held channel's recorded data, logging the attempt
+// to the audit log and telling the channel; it reports whether the operation must stop
+func blockedByLegalHold(cfg *config.Config, slackClient *Client, channelID, userID, operation string) bool {
+	hold, held := legalHolds.HoldOf(channelID)
+	if !held {
+		return false
+	}
+
+	recordAudit(cfg, audit.Entry{
+		Action:    "legal_hold_blocked",
+		ChannelID: channelID,
+		User:      userID,
+		Detail:    fmt.Sprintf("%s refused, hold placed by %s on %s", operation, hold.PlacedBy, hold.PlacedAt.In(jstLocation).Format("2006-01-02")),
+	})
+
+	message := fmt.Sprintf("🔒 このチャンネルはリーガルホールド中のため、%sは実行できません。解除は管理者が「release hold」とメンションしてください。", operation)
+	if err := slackClient.SendMessage(channelID, message); err != nil {
+		log.Printf("Error sending legal hold notice: %v", err)
+	}
+	return true
+}
+
+// handleHoldCommand places the channel under legal hold ("hold [reason]") or lifts it ("release hold"); admins only
+func handleHoldCommand(cfg *config.Config, slackClient *Client, event *Event, channelInfo *ChannelInfo, command string) error {
+	channelID, userID := event.Event.Channel, event.Event.User
+	release := releaseHoldCommandPattern.MatchString(command)
+
+	reply := func(message string) error {
+		if err := slackClient.SendMessage(channelID, message); err != nil {
+			log.Printf("Error sending legal hold reply: %v", err)
+		}
+		return nil
+	}
+
+	if !cfg.IsAdmin(userID) {
+		recordAudit(cfg, audit.Entry{Action: "legal_hold_denied", ChannelID: channelID, User: userID, Detail: command})
+		return reply("⚠️ リーガルホールドの設定・解除は管理者のみ実行できます。")
+	}
+
+	if release {
+		released, err := legalHolds.Release(channelID)
+		if err != nil {
+			log.Printf("Error releasing legal hold of %s: %v", channelID, err)
+			return reply("❌ リーガルホールドの解除に失敗しました。")
+		}
+		if !released {
+			return reply("ℹ️ このチャンネルはリーガルホールド中ではありません。")
+		}
+		recordAudit(cfg, audit.Entry{Action: "legal_hold_released", ChannelID: channelID, User: userID, Detail: "#" + channelInfo.Name})
+		return reply(fmt.Sprintf("🔓 #%s のリーガルホールドを解除しました。", channelInfo.Name))
+	}
+
+	reason := ""
+	if matches := holdCommandPattern.FindStringSubmatch(command); len(matches) > 1 {
+		reason = matches[1]
+	}
+	if err := legalHolds.Place(channelID, userID, reason); err != nil {
+		log.Printf("Error placing legal hold on %s: %v", channelID, err)
+		return reply("❌ リーガルホールドの設定に失敗しました。")
+	}
+	recordAudit(cfg, audit.Entry{Action: "legal_hold_placed", ChannelID: channelID, User: userID, Detail: fmt.Sprintf("#%s: %s", channelInfo.Name, reason)})
+	return reply(fmt.Sprintf("🔒 #%s をリーガルホールドにしました。解除されるまでリセットなど記録データを削除する操作は実行できません。", channelInfo.Name))
+}
@@ -0,0 +1,30 @@
+package slack
+
+import (
+	"regexp"
+	"strings"
+)
+
+// linkMarkupRe matches Slack link markup such as <https://example.com|example.com>, <https://example.com> and
+// <mailto:someone@example.com|someone@example.com>; mentions (<@...>, <#...>, <!...>) start with a symbol instead
+var linkMarkupRe = regexp.MustCompile(`<([A-Za-z][A-Za-z0-9+.\-]*:[^|>]+)(?:\|([^>]*))?>`)
+
+// unwrapLinks replaces link markup with the URL, or with "label (URL)" when the label says something the URL does
+// not; mailto links keep only the address
+func unwrapLinks(text string) string {
+	if !strings.Contains(text, "<") {
+		return text
+	}
+	return linkMarkupRe.ReplaceAllStringFunc(text, func(match string) string {
+		groups := linkMarkupRe.FindStringSubmatch(match)
+		target, label := groups[1], strings.TrimSpace(groups[2])
+
+		if address, isMail := strings.CutPrefix(target, "mailto:"); isMail {
+			target = address
+		}
+		if label == "" || label == target || label == strings.TrimPrefix(strings.TrimPrefix(target, "https://"), "http://") {
+			return target
+		}
+		return label + " (" + target + ")"
+	})
+}
@@ -0,0 +1,31 @@
+package slack
+
+import (
+	"log"
+
+	"slack-to-google-sheets-bot/internal/config"
+	"slack-to-google-sheets-bot/internal/i18n"
+)
+
+// replyLanguage picks the language for replies to a user from their Slack locale,
+// falling back to the configured default language
+func replyLanguage(cfg *config.Config, slackClient *Client, userID string) string {
+	defaultLang, ok := i18n.FromLocale(cfg.DefaultLanguage)
+	if !ok {
+		defaultLang = i18n.Japanese
+	}
+	if userID == "" {
+		return defaultLang
+	}
+
+	userInfo, err := slackClient.GetUserInfo(userID)
+	if err != nil {
+		log.Printf("Warning: Could not get locale of user %s: %v", userID, err)
+		return defaultLang
+	}
+
+	if lang, ok := i18n.FromLocale(userInfo.Locale); ok {
+		return lang
+	}
+	return defaultLang
+}
@@ -0,0 +1,80 @@
+package slack
+
+import (
+	"encoding/json"
+	"log"
+
+	"slack-to-google-sheets-bot/internal/buildinfo"
+	"slack-to-google-sheets-bot/internal/config"
+	"slack-to-google-sheets-bot/internal/sheets"
+)
+
+// Kinds of export written to the manifest
+const (
+	manifestKindInitial      = "initial"
+	manifestKindFull         = "full"
+	manifestKindContinuation = "continuation"
+)
+
+// manifestParameters are the settings that shaped the rows of an export, recorded as JSON in the manifest
+type manifestParameters struct {
+	Columns           []string `json:"columns"`
+	TimestampFormat   string   `json:"timestamp_format"`
+	RecordingSchedule string   `json:"recording_schedule,omitempty"`
+	MetadataOnly      bool     `json:"metadata_only"`
+	Encrypted         bool     `json:"encrypted"`
+	NormalizeText     bool     `json:"normalize_recorded_text"`
+	OptOutPolicy      string   `json:"opt_out_policy"`
+}
+
+// recordManifest appends a manifest row for a completed export of a channel when MANIFEST_SHEET_NAME is set: the
+// tool version, the parameters, the period and row count of the channel tab and the checksum of its rows
+func recordManifest(cfg *config.Config, sheetsClient *sheets.Client, event *Event, channelInfo *ChannelInfo, kind string, recorded int) {
+	if cfg.ManifestSheetName == "" {
+		return
+	}
+
+	channelID := channelInfo.ID
+	if channelID == "" {
+		channelID = event.Event.Channel
+	}
+	spreadsheetID := channelSpreadsheetID(cfg, sheetsClient, channelID)
+	sheetName := sheets.SheetName(channelID, channelInfo.Name)
+	digest, err := sheetsClient.DigestChannelSheet(spreadsheetID, sheetName)
+	if err != nil {
+		log.Printf("Warning: Could not digest sheet %s for the manifest: %v", sheetName, err)
+		return
+	}
+
+	timestampFormat := cfg.TimestampFormat
+	if timestampFormat == "" {
+		timestampFormat = "default"
+	}
+	parameters, err := json.Marshal(manifestParameters{
+		Columns:           sheetsClient.ColumnHeaders(),
+		TimestampFormat:   timestampFormat,
+		RecordingSchedule: cfg.RecordingSchedule(channelID),
+		MetadataOnly:      cfg.RecordsMetadataOnly(channelID),
+		Encrypted:         cfg.RecordsEncrypted(channelID),
+		NormalizeText:     cfg.NormalizeRecordedText,
+		OptOutPolicy:      cfg.OptOutPolicy,
+	})
+	if err != nil {
+		log.Printf("Warning: Could not encode manifest parameters: %v", err)
+		return
+	}
+
+	manifest := sheets.Manifest{
+		ChannelID:  channelID,
+		SheetName:  sheetName,
+		Kind:       kind,
+		TraceID:    event.TraceID,
+		Version:    buildinfo.String(),
+		Parameters: string(parameters),
+		Recorded:   recorded,
+		TabDigest:  digest,
+	}
+	if err := sheetsClient.AppendManifestRow(spreadsheetID, cfg.ManifestSheetName, manifest); err != nil {
+		log.Printf("Warning: Could not append manifest row for channel %s: %v", channelID, err)
+	}
+}
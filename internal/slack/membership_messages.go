@@ -0,0 +1,36 @@
+package slack
+
+import "fmt"
+
+// isMembershipMessage reports whether a message subtype is one Slack posts when someone joins or leaves a channel
+func isMembershipMessage(subtype string) bool {
+	switch subtype {
+	case "channel_join", "channel_leave", "group_join", "group_leave":
+		return true
+	}
+	return false
+}
+
+// skipsMessage reports whether a message of this subtype is left out of the sheet: join/leave messages are only
+// recorded with RECORD_MEMBERSHIP_MESSAGES=true
+func (c *Client) skipsMessage(subtype string) bool {
+	return isMembershipMessage(subtype) && !c.recordMembership
+}
+
+// membershipText describes a join or leave as a system row, with Slack user markup that FormatMessageText resolves;
+// other messages keep their text
+func membershipText(subtype, text, user, inviter string) string {
+	if user == "" {
+		return text
+	}
+	switch subtype {
+	case "channel_join", "group_join":
+		if inviter != "" {
+			return fmt.Sprintf("➡️ <@%s> がチャンネルに参加しました（<@%s> が招待）", user, inviter)
+		}
+		return fmt.Sprintf("➡️ <@%s> がチャンネルに参加しました", user)
+	case "channel_leave", "group_leave":
+		return fmt.Sprintf("⬅️ <@%s> がチャンネルから退出しました", user)
+	}
+	return text
+}
@@ -0,0 +1,170 @@
+package slack
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// MessageBlocks are the Block Kit blocks of a received message. Blocks that do not decode (e.g. interactive ones
+// whose elements carry text objects) are dropped instead of failing the whole event.
+type MessageBlocks []MessageBlock
+
+// MessageBlock is a block of a received message; only the parts that carry text are decoded
+type MessageBlock struct {
+	Type     string         `json:"type"`
+	Text     *TextObject    `json:"text,omitempty"`     // section, header
+	Fields   []TextObject   `json:"fields,omitempty"`   // section
+	Elements []BlockElement `json:"elements,omitempty"` // rich_text, context
+	AltText  string         `json:"alt_text,omitempty"` // image
+}
+
+// TextObject is a plain_text or mrkdwn text object
+type TextObject struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// BlockElement is an element of a rich_text or context block, or a nested rich text element
+type BlockElement struct {
+	Type        string         `json:"type"`
+	Text        string         `json:"text,omitempty"`
+	Elements    []BlockElement `json:"elements,omitempty"`
+	Style       interface{}    `json:"style,omitempty"` // "bullet" / "ordered" for lists, an object for text
+	Indent      int            `json:"indent,omitempty"`
+	URL         string         `json:"url,omitempty"`
+	UserID      string         `json:"user_id,omitempty"`
+	ChannelID   string         `json:"channel_id,omitempty"`
+	UsergroupID string         `json:"usergroup_id,omitempty"`
+	Range       string         `json:"range,omitempty"` // here, channel, everyone
+	Name        string         `json:"name,omitempty"`  // emoji
+	Timestamp   int64          `json:"timestamp,omitempty"`
+	Format      string         `json:"format,omitempty"`
+	Fallback    string         `json:"fallback,omitempty"`
+	Value       string         `json:"value,omitempty"` // color
+	AltText     string         `json:"alt_text,omitempty"`
+}
+
+// UnmarshalJSON decodes the blocks one by one, skipping those that do not fit MessageBlock
+func (b *MessageBlocks) UnmarshalJSON(data []byte) error {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil // Not a list of blocks; the message text is used as it is
+	}
+	for _, item := range raw {
+		var block MessageBlock
+		if err := json.Unmarshal(item, &block); err == nil {
+			*b = append(*b, block)
+		}
+	}
+	return nil
+}
+
+// messageText returns the text of a message, rebuilt from its blocks when the text field is empty, as for messages
+// of apps that post Block Kit only. Mentions and links are rebuilt as Slack markup, so FormatMessageText resolves them.
+func messageText(text string, blocks MessageBlocks) string {
+	if strings.TrimSpace(text) != "" || len(blocks) == 0 {
+		return text
+	}
+	return blocks.Text()
+}
+
+// Text renders the blocks that carry text (header, section, context, rich_text, image alt text, calls), one per line
+func (b MessageBlocks) Text() string {
+	var lines []string
+	for _, block := range b {
+		var text string
+		switch block.Type {
+		case "header", "section":
+			var parts []string
+			if block.Text != nil && block.Text.Text != "" {
+				parts = append(parts, block.Text.Text)
+			}
+			for _, field := range block.Fields {
+				if field.Text != "" {
+					parts = append(parts, field.Text)
+				}
+			}
+			text = strings.Join(parts, "\n")
+		case "context":
+			var parts []string
+			for _, element := range block.Elements {
+				if element.Text != "" {
+					parts = append(parts, element.Text)
+				} else if element.AltText != "" {
+					parts = append(parts, element.AltText)
+				}
+			}
+			text = strings.Join(parts, " ")
+		case "rich_text":
+			var parts []string
+			for _, element := range block.Elements {
+				parts = append(parts, richTextElement(element))
+			}
+			text = strings.Join(parts, "\n")
+		case "image":
+			text = block.AltText
+		case "call":
+			text = callText
+		}
+		if text = strings.TrimRight(text, "\n"); text != "" {
+			lines = append(lines, text)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// richTextElement renders a top-level element of a rich_text block: a section, list, quote or code block
+func richTextElement(element BlockElement) string {
+	switch element.Type {
+	case "rich_text_list":
+		indent := strings.Repeat("    ", element.Indent)
+		var items []string
+		for i, item := range element.Elements {
+			marker := "•"
+			if element.Style == "ordered" {
+				marker = fmt.Sprintf("%d.", i+1)
+			}
+			items = append(items, fmt.Sprintf("%s%s %s", indent, marker, richTextInline(item.Elements)))
+		}
+		return strings.Join(items, "\n")
+	case "rich_text_quote":
+		return "> " + strings.ReplaceAll(richTextInline(element.Elements), "\n", "\n> ")
+	case "rich_text_preformatted":
+		return "```\n" + strings.TrimRight(richTextInline(element.Elements), "\n") + "\n```"
+	default:
+		return strings.TrimRight(richTextInline(element.Elements), "\n")
+	}
+}
+
+// richTextInline renders the inline elements of a rich text section as Slack markup
+func richTextInline(elements []BlockElement) string {
+	var builder strings.Builder
+	for _, element := range elements {
+		switch element.Type {
+		case "text":
+			builder.WriteString(element.Text)
+		case "link":
+			if element.Text != "" {
+				builder.WriteString("<" + element.URL + "|" + element.Text + ">")
+			} else {
+				builder.WriteString("<" + element.URL + ">")
+			}
+		case "user":
+			builder.WriteString("<@" + element.UserID + ">")
+		case "channel":
+			builder.WriteString("<#" + element.ChannelID + ">")
+		case "usergroup":
+			builder.WriteString("<!subteam^" + element.UsergroupID + ">")
+		case "broadcast":
+			builder.WriteString("<!" + element.Range + ">")
+		case "emoji":
+			builder.WriteString(":" + element.Name + ":")
+		case "date":
+			builder.WriteString(fmt.Sprintf("<!date^%d^%s|%s>", element.Timestamp, element.Format, element.Fallback))
+		case "color":
+			builder.WriteString(element.Value)
+		}
+	}
+	return builder.String()
+}
@@ -0,0 +1,41 @@
+package slack
+
+import (
+	"log"
+
+	"slack-to-google-sheets-bot/internal/config"
+	"slack-to-google-sheets-bot/internal/search"
+	"slack-to-google-sheets-bot/internal/sheets"
+	"slack-to-google-sheets-bot/internal/store"
+)
+
+// persistRecords saves successfully written records to the local message store and search index when enabled
+func persistRecords(cfg *config.Config, records []*sheets.MessageRecord) {
+	if !cfg.MessageStoreEnabled {
+		return
+	}
+
+	if err := store.Default().Save(records); err != nil {
+		log.Printf("Warning: Could not persist %d records to message store: %v", len(records), err)
+		return
+	}
+
+	search.Default().Add(records)
+}
+
+// markRecordDeleted records a deleted message's status in the local message store, which drops it from API query
+// results, and removes it from the search index
+func markRecordDeleted(cfg *config.Config, channelID, messageTS, status string) {
+	if !cfg.MessageStoreEnabled {
+		return
+	}
+
+	record, err := store.Default().MarkDeleted(channelID, messageTS, status)
+	if err != nil {
+		log.Printf("Warning: Could not mark %s of channel %s as deleted in message store: %v", messageTS, channelID, err)
+		return
+	}
+	if record != nil {
+		search.Default().Add([]*sheets.MessageRecord{record})
+	}
+}
@@ -0,0 +1,73 @@
+package slack
+
+import (
+	"log"
+	"sync"
+
+	"slack-to-google-sheets-bot/internal/config"
+	"slack-to-google-sheets-bot/internal/sheets"
+)
+
+// mirrorQueueSize is how many mirror writes can wait before new ones are dropped
+const mirrorQueueSize = 1000
+
+// mirrorJob is one write replayed against the mirror spreadsheet
+type mirrorJob struct {
+	description string
+	write       func(sheetsClient *sheets.Client, spreadsheetID string) error
+}
+
+var (
+	mirrorQueue     chan mirrorJob
+	mirrorQueueOnce sync.Once
+)
+
+// mirrorWrite replays a successful write against the mirror spreadsheet in the background.
+// Jobs run one at a time in the order they were queued so the mirror sees the same sequence of writes.
+func mirrorWrite(cfg *config.Config, description string, write func(sheetsClient *sheets.Client, spreadsheetID string) error) {
+	if cfg.MirrorSpreadsheetID == "" || cfg.GoogleSheetsCredentials == "" {
+		return
+	}
+
+	mirrorQueueOnce.Do(func() {
+		mirrorQueue = make(chan mirrorJob, mirrorQueueSize)
+		go runMirrorWorker(cfg)
+	})
+
+	select {
+	case mirrorQueue <- mirrorJob{description: description, write: write}:
+	default:
+		log.Printf("Warning: Mirror queue is full, dropping mirror write: %s", description)
+	}
+}
+
+// runMirrorWorker applies queued mirror writes until the process exits
+func runMirrorWorker(cfg *config.Config) {
+	var sheetsClient *sheets.Client
+	for job := range mirrorQueue {
+		if sheetsClient == nil {
+			client, err := newSheetsClient(cfg)
+			if err != nil {
+				log.Printf("Error creating Google Sheets client for mirror, dropping mirror write (%s): %v", job.description, err)
+				continue
+			}
+			sheetsClient = client
+		}
+
+		if err := job.write(sheetsClient, cfg.MirrorSpreadsheetID); err != nil {
+			log.Printf("Error writing to mirror spreadsheet (%s): %v", job.description, err)
+		}
+	}
+}
+
+// mirrorRecords appends records to the mirror spreadsheet
+func mirrorRecords(cfg *config.Config, records []*sheets.MessageRecord) {
+	if len(records) == 0 {
+		return
+	}
+	// Copy the slice because the batch writers sort it in place
+	mirrored := append([]*sheets.MessageRecord(nil), records...)
+	mirrorWrite(cfg, "append records", func(sheetsClient *sheets.Client, spreadsheetID string) error {
+		return sheetsClient.WriteBatchMessages(spreadsheetID, mirrored)
+	})
+}
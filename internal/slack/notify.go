@@ -0,0 +1,113 @@
+package slack
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"slack-to-google-sheets-bot/internal/config"
+	boterrors "slack-to-google-sheets-bot/internal/errors"
+)
+
+const (
+	// NotificationTargetChannel posts job results in the channel the job ran for (default)
+	NotificationTargetChannel = "channel"
+	// NotificationTargetOps posts job results to the admin/ops channel
+	NotificationTargetOps = "ops"
+	// NotificationTargetDM sends job results as a DM to the person who triggered the job
+	NotificationTargetDM = "dm"
+
+	// NotificationVerbosityFull posts the complete messages (default)
+	NotificationVerbosityFull = "full"
+	// NotificationVerbosityBrief posts only the first line of each message
+	NotificationVerbosityBrief = "brief"
+	// NotificationVerbosityErrors posts error messages only
+	NotificationVerbosityErrors = "errors"
+	// NotificationVerbosityNone posts nothing; results are only logged
+	NotificationVerbosityNone = "none"
+)
+
+// notifyJobResult delivers a job's completion or error message according to the channel's
+// notification target and verbosity settings
+func notifyJobResult(cfg *config.Config, slackClient *Client, event *Event, isError bool, message string) error {
+	channelID := event.Event.Channel
+	if isError {
+		message += traceSuffix(event)
+	}
+
+	switch cfg.NotificationVerbosityFor(channelID) {
+	case NotificationVerbosityNone:
+		log.Printf("Notification suppressed for channel %s: %s", channelID, truncateText(message, 80))
+		return nil
+	case NotificationVerbosityErrors:
+		if !isError {
+			log.Printf("Notification suppressed for channel %s: %s", channelID, truncateText(message, 80))
+			return nil
+		}
+	case NotificationVerbosityBrief:
+		message = strings.SplitN(message, "\n", 2)[0]
+	}
+
+	destination := channelID
+	switch cfg.NotificationTarget(channelID) {
+	case NotificationTargetOps:
+		if cfg.AdminChannelID != "" {
+			destination = cfg.AdminChannelID
+		} else {
+			log.Printf("ADMIN_CHANNEL_ID not configured, notifying channel %s instead", channelID)
+		}
+	case NotificationTargetDM:
+		if triggeredBy := jobTriggeredBy(event); triggeredBy != "" {
+			// chat.postMessage with a user ID delivers the message to the bot's DM with that user
+			destination = triggeredBy
+		} else {
+			log.Printf("No user to DM for job in channel %s, notifying the channel instead", channelID)
+		}
+	}
+
+	if destination == channelID {
+		return sendStatusMessage(slackClient, channelID, message)
+	}
+
+	// Messages delivered outside the channel need to say which channel they are about
+	message = fmt.Sprintf("<#%s>\n%s", channelID, message)
+	return slackClient.SendMessage(destination, message)
+}
+
+// notifyFailure tells the channel's users what failed through notifyJobResult and sends the internal cause to the
+// admin channel; when job results already go to the admin channel, only the detailed message is sent there
+func notifyFailure(cfg *config.Config, slackClient *Client, event *Event, failure *boterrors.Error) {
+	tracef(event, "Job failed: %v", failure)
+	lang := replyLanguage(cfg, slackClient, jobTriggeredBy(event))
+
+	message := failure.UserMessage(lang)
+	toAdmins := cfg.NotificationTarget(event.Event.Channel) == NotificationTargetOps && cfg.AdminChannelID != ""
+	if toAdmins {
+		message = failure.AdminDetail(lang)
+	}
+	if err := notifyJobResult(cfg, slackClient, event, true, message); err != nil {
+		log.Printf("Error sending failure notification: %v", err)
+	}
+	if !toAdmins {
+		reportFailureToAdmins(cfg, slackClient, event, failure)
+	}
+}
+
+// reportFailureToAdmins posts the internal cause of a failure to ADMIN_CHANNEL_ID, if one is configured
+func reportFailureToAdmins(cfg *config.Config, slackClient *Client, event *Event, failure *boterrors.Error) {
+	if cfg.AdminChannelID == "" || cfg.AdminChannelID == event.Event.Channel || failure.Cause == nil {
+		return
+	}
+	message := fmt.Sprintf("<#%s>\n%s", event.Event.Channel, failure.AdminDetail(replyLanguage(cfg, slackClient, ""))) + traceSuffix(event)
+	if err := slackClient.SendMessage(cfg.AdminChannelID, message); err != nil {
+		log.Printf("Error sending failure detail to admin channel: %v", err)
+	}
+}
+
+// jobTriggeredBy returns the user who started a job: the inviter for joins, the mentioning user otherwise
+func jobTriggeredBy(event *Event) string {
+	if event.Event.Inviter != "" {
+		return event.Event.Inviter
+	}
+	return event.Event.User
+}
@@ -0,0 +1,248 @@
+package slack
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"slack-to-google-sheets-bot/internal/config"
+	"slack-to-google-sheets-bot/internal/installation"
+)
+
+// oauthBotScopes are the bot scopes requested on install; keep them in sync with slack-app-manifest.yml
+const oauthBotScopes = "app_mentions:read,channels:history,channels:read,chat:write,commands,emoji:read,files:read," +
+	"groups:history,groups:read,pins:read,im:history,im:read,mpim:history,mpim:read," +
+	"reactions:read,reactions:write,team:read,usergroups:read,users:read,users.profile:read"
+
+// oauthStateTTL is how long an install started at /slack/oauth/start may take to come back to the callback
+const oauthStateTTL = 10 * time.Minute
+
+var installations = installation.NewStore("installations")
+
+// SetInstallationDir sets the data directory of the bot tokens stored by the OAuth install flow; call it once at
+// startup
+func SetInstallationDir(dir string) {
+	installations = installation.NewStore(dir)
+}
+
+var (
+	oauthStates      = make(map[string]time.Time) // state -> expiry
+	oauthStatesMutex sync.Mutex
+)
+
+// OAuthAccessResponse is the response of oauth.v2.access
+type OAuthAccessResponse struct {
+	OK          bool   `json:"ok"`
+	Error       string `json:"error,omitempty"`
+	AccessToken string `json:"access_token"`
+	BotUserID   string `json:"bot_user_id"`
+	Team        struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"team"`
+	AuthedUser struct {
+		ID string `json:"id"`
+	} `json:"authed_user"`
+}
+
+// newOAuthState issues a one-time state value that ties the callback to an install started here
+func newOAuthState() (string, error) {
+	buffer := make([]byte, 16)
+	if _, err := rand.Read(buffer); err != nil {
+		return "", err
+	}
+	state := hex.EncodeToString(buffer)
+
+	oauthStatesMutex.Lock()
+	defer oauthStatesMutex.Unlock()
+	now := time.Now()
+	for issued, expiry := range oauthStates {
+		if now.After(expiry) {
+			delete(oauthStates, issued)
+		}
+	}
+	oauthStates[state] = now.Add(oauthStateTTL)
+	return state, nil
+}
+
+// takeOAuthState reports whether the state was issued and has not expired, consuming it
+func takeOAuthState(state string) bool {
+	oauthStatesMutex.Lock()
+	defer oauthStatesMutex.Unlock()
+	expiry, exists := oauthStates[state]
+	delete(oauthStates, state)
+	return exists && time.Now().Before(expiry)
+}
+
+// HandleOAuthStart redirects to Slack's authorize page to install the app into a workspace
+func HandleOAuthStart(cfg *config.Config) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !cfg.OAuthEnabled() {
+			http.Error(w, "OAuth install is not configured", http.StatusNotFound)
+			return
+		}
+
+		state, err := newOAuthState()
+		if err != nil {
+			log.Printf("Error generating OAuth state: %v", err)
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+
+		query := url.Values{
+			"client_id": {cfg.SlackClientID},
+			"scope":     {oauthBotScopes},
+			"state":     {state},
+		}
+		if cfg.SlackOAuthRedirectURL != "" {
+			query.Set("redirect_uri", cfg.SlackOAuthRedirectURL)
+		}
+		http.Redirect(w, r, "https://slack.com/oauth/v2/authorize?"+query.Encode(), http.StatusFound)
+	}
+}
+
+// HandleOAuthCallback exchanges the code Slack redirects back with for the workspace's bot token and stores it
+func HandleOAuthCallback(cfg *config.Config) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !cfg.OAuthEnabled() {
+			http.Error(w, "OAuth install is not configured", http.StatusNotFound)
+			return
+		}
+
+		query := r.URL.Query()
+		if errorCode := query.Get("error"); errorCode != "" {
+			log.Printf("OAuth install was not completed: %s", errorCode)
+			http.Error(w, "Installation was cancelled", http.StatusBadRequest)
+			return
+		}
+		if !takeOAuthState(query.Get("state")) {
+			log.Printf("OAuth callback with an unknown or expired state")
+			http.Error(w, "Invalid or expired state, please start the installation again", http.StatusBadRequest)
+			return
+		}
+
+		access, err := exchangeOAuthCode(cfg, query.Get("code"))
+		if err != nil {
+			log.Printf("Error exchanging OAuth code: %v", err)
+			http.Error(w, "Installation failed", http.StatusBadGateway)
+			return
+		}
+
+		installed := installation.Installation{
+			TeamID:      access.Team.ID,
+			TeamName:    access.Team.Name,
+			BotToken:    access.AccessToken,
+			BotUserID:   access.BotUserID,
+			InstalledBy: access.AuthedUser.ID,
+			InstalledAt: time.Now(),
+		}
+		if err := installations.Save(installed); err != nil {
+			log.Printf("Error saving installation of %s: %v", installed.TeamID, err)
+			http.Error(w, "Installation failed", http.StatusInternalServerError)
+			return
+		}
+
+		log.Printf("Installed into workspace %s (%s) by %s", installed.TeamName, installed.TeamID, installed.InstalledBy)
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		if cfg.TeamSpreadsheetIDs[installed.TeamID] == "" {
+			log.Printf("Workspace %s (%s) has no spreadsheet in TEAM_SPREADSHEET_IDS, its events are refused until one is set", installed.TeamName, installed.TeamID)
+			fmt.Fprintf(w, "インストールが完了しました（%s）。管理者が記録先のスプレッドシート（TEAM_SPREADSHEET_IDS）を設定するまで記録は開始されません。\n", installed.TeamName)
+			return
+		}
+		fmt.Fprintf(w, "インストールが完了しました（%s）。記録したいチャンネルに bot を招待してください。\n", installed.TeamName)
+	}
+}
+
+// exchangeOAuthCode calls oauth.v2.access with the code from the callback
+func exchangeOAuthCode(cfg *config.Config, code string) (*OAuthAccessResponse, error) {
+	form := url.Values{
+		"client_id":     {cfg.SlackClientID},
+		"client_secret": {cfg.SlackClientSecret},
+		"code":          {code},
+	}
+	if cfg.SlackOAuthRedirectURL != "" {
+		form.Set("redirect_uri", cfg.SlackOAuthRedirectURL)
+	}
+
+	resp, err := http.Post(apiBaseURL+"oauth.v2.access", "application/x-www-form-urlencoded", strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var access OAuthAccessResponse
+	if err := json.Unmarshal(body, &access); err != nil {
+		return nil, err
+	}
+	if !access.OK {
+		return nil, fmt.Errorf("slack API error: %s", access.Error)
+	}
+	if access.Team.ID == "" || access.AccessToken == "" {
+		return nil, fmt.Errorf("oauth.v2.access returned no team or bot token")
+	}
+	return &access, nil
+}
+
+// configForTeam returns the configuration for a workspace's events. With the OAuth install flow, workspaces
+// installed through it use their stored bot token and their own spreadsheet (TEAM_SPREADSHEET_IDS), and only the
+// workspace or organization of SLACK_BOT_TOKEN uses the configured token and spreadsheet; requests of any other
+// workspace, or when the installations cannot be read, are refused rather than handled with another workspace's
+// token and spreadsheet. enterpriseID is set for requests from an Enterprise Grid organization.
+func configForTeam(cfg *config.Config, teamID, enterpriseID string) (*config.Config, error) {
+	// On Enterprise Grid, API calls are made in the context of the workspace the request came from
+	if enterpriseID != "" && teamID != "" {
+		cfg = cfg.WithTeam(teamID)
+	}
+	if !cfg.OAuthEnabled() {
+		return cfg, nil
+	}
+	if teamID == "" {
+		return nil, fmt.Errorf("request has no workspace")
+	}
+
+	installed, exists, err := installations.Get(teamID)
+	if err != nil {
+		return nil, fmt.Errorf("could not read the installation of %s: %v", teamID, err)
+	}
+	if exists {
+		spreadsheetID := cfg.TeamSpreadsheetIDs[installed.TeamID]
+		if spreadsheetID == "" {
+			return nil, fmt.Errorf("workspace %s (%s) has no spreadsheet in TEAM_SPREADSHEET_IDS", installed.TeamName, teamID)
+		}
+		return cfg.WithBotToken(installed.BotToken).WithSpreadsheet(spreadsheetID), nil
+	}
+
+	if cfg.SlackBotToken == "" {
+		return nil, fmt.Errorf("workspace %s did not install the app", teamID)
+	}
+	homeTeamID, homeEnterpriseID, err := NewClient(cfg.SlackBotToken).homeTeam()
+	if err != nil {
+		return nil, fmt.Errorf("could not look up the workspace of SLACK_BOT_TOKEN: %v", err)
+	}
+	if teamID != homeTeamID && (enterpriseID == "" || enterpriseID != homeEnterpriseID) {
+		return nil, fmt.Errorf("workspace %s did not install the app", teamID)
+	}
+	return cfg, nil
+}
+
+// handleAppUninstalled forgets the bot token of a workspace that uninstalled the app or revoked its tokens
+func handleAppUninstalled(event *Event) error {
+	if err := installations.Delete(event.TeamID); err != nil {
+		return fmt.Errorf("failed to delete installation of %s: %v", event.TeamID, err)
+	}
+	log.Printf("Removed the installation of workspace %s (%s)", event.TeamID, event.Event.Type)
+	return nil
+}
@@ -0,0 +1,126 @@
+package slack
+
+import (
+	"log"
+	"strings"
+
+	"slack-to-google-sheets-bot/internal/audit"
+	"slack-to-google-sheets-bot/internal/config"
+	"slack-to-google-sheets-bot/internal/optout"
+	"slack-to-google-sheets-bot/internal/sheets"
+)
+
+const (
+	// OptOutPolicySkip drops messages by opted-out users entirely
+	OptOutPolicySkip = "skip"
+	// OptOutPlaceholder replaces the text of messages by opted-out users under the "redact" policy
+	OptOutPlaceholder = "[message by opted-out user]"
+
+	// recordingNotice is appended to the join message so channel members know how to opt out
+	recordingNotice = "📢 このチャンネルの投稿はスプレッドシートに記録されます。" +
+		"記録を希望しない場合は、この bot にDMで「opt out」と送ってください（「opt in」で再開できます）。"
+)
+
+var optOutRegistry = optout.NewRegistry()
+
+// applyOptOutPolicy applies the opt-out policy to a record in place and reports whether it should still be written
+func applyOptOutPolicy(cfg *config.Config, record *sheets.MessageRecord) bool {
+	if !optOutRegistry.IsOptedOut(record.User) {
+		return true
+	}
+
+	if cfg.OptOutPolicy == OptOutPolicySkip {
+		return false
+	}
+
+	// Every column carrying content is cleared; keep this in step when a content column is added. Attachment text
+	// is part of Text.
+	record.Text = OptOutPlaceholder
+	record.EditHistory = ""
+	record.Redacted = true
+	dropFileDetails(record)
+	return true
+}
+
+// filterRecordsByOptOut applies the opt-out policy to a batch of history records
+func filterRecordsByOptOut(cfg *config.Config, records []*sheets.MessageRecord) []*sheets.MessageRecord {
+	var kept []*sheets.MessageRecord
+	for _, record := range records {
+		if applyOptOutPolicy(cfg, record) {
+			kept = append(kept, record)
+		}
+	}
+	return kept
+}
+
+const (
+	optOutCommand = "opt out"
+	optInCommand  = "opt in"
+)
+
+// normalizedDMText lowercases a DM and collapses its whitespace so the opt out / opt in keywords can be matched
+func normalizedDMText(text string) string {
+	return strings.Join(strings.Fields(strings.ToLower(text)), " ")
+}
+
+// optCommand returns the opt out / opt in command a DM starts with, or "" when it is not one. Only a leading keyword
+// counts, so a sentence such as "I don't want to opt out" changes nothing.
+func optCommand(text string) string {
+	text = normalizedDMText(text)
+	for _, command := range []string{optOutCommand, optInCommand} {
+		rest, found := strings.CutPrefix(text, command)
+		if !found {
+			continue
+		}
+		if rest == "" || strings.ContainsRune(" .!。！", []rune(rest)[0]) {
+			return command
+		}
+	}
+	return ""
+}
+
+// isOptCommand reports whether a DM is an "opt out" or "opt in" request
+func isOptCommand(text string) bool {
+	return optCommand(text) != ""
+}
+
+// handleDirectMessage handles "opt out" / "opt in" requests sent to the bot via DM
+func handleDirectMessage(cfg *config.Config, event *Event) error {
+	// Ignore bot messages (including our own replies) and non-user subtypes
+	if event.Event.BotID != "" || event.Event.Subtype != "" || event.Event.User == "" {
+		return nil
+	}
+
+	slackClient := newClient(cfg)
+
+	var reply string
+	switch optCommand(event.Event.Text) {
+	case optOutCommand:
+		if err := optOutRegistry.OptOut(event.Event.User); err != nil {
+			log.Printf("Error registering opt-out for %s: %v", event.Event.User, err)
+			reply = "❌ オプトアウトの登録に失敗しました。時間をおいて再度お試しください。"
+			break
+		}
+		if err := auditLogger.Record(audit.Entry{Action: "opt_out", User: event.Event.User}); err != nil {
+			log.Printf("Warning: Could not write audit entry: %v", err)
+		}
+		reply = "✅ オプトアウトを登録しました。今後あなたの投稿は記録されません。「opt in」で再開できます。"
+	case optInCommand:
+		if err := optOutRegistry.OptIn(event.Event.User); err != nil {
+			log.Printf("Error removing opt-out for %s: %v", event.Event.User, err)
+			reply = "❌ オプトインの登録に失敗しました。時間をおいて再度お試しください。"
+			break
+		}
+		if err := auditLogger.Record(audit.Entry{Action: "opt_in", User: event.Event.User}); err != nil {
+			log.Printf("Warning: Could not write audit entry: %v", err)
+		}
+		reply = "✅ オプトアウトを解除しました。今後あなたの投稿は再び記録されます。"
+	default:
+		reply = "🤖 投稿の記録を停止するには「opt out」、再開するには「opt in」と送ってください。"
+	}
+
+	if err := slackClient.SendMessage(event.Event.Channel, reply); err != nil {
+		log.Printf("Error sending DM reply: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package slack
+
+import (
+	"log"
+
+	"slack-to-google-sheets-bot/internal/config"
+	"slack-to-google-sheets-bot/internal/sheets"
+)
+
+// handlePinChanged toggles the pinned cell of the message a pin_added / pin_removed event refers to
+func handlePinChanged(cfg *config.Config, event *Event) error {
+	if !cfg.RecordPins || cfg.GoogleSheetsCredentials == "" || cfg.SpreadsheetID == "" {
+		return nil
+	}
+
+	item := event.Event.Item
+	if item == nil || item.Type != "message" || item.Message == nil || item.Message.Timestamp == "" {
+		return nil // Pinned files and file comments are not recorded
+	}
+	channelID := item.Channel
+	if channelID == "" {
+		channelID = event.Event.ChannelID
+	}
+	messageTS := item.Message.Timestamp
+	pinned := event.Event.Type == "pin_added"
+
+	slackClient := newClient(cfg)
+
+	channelInfo, err := slackClient.GetChannelInfo(channelID)
+	if err != nil {
+		log.Printf("Error getting channel info for pin: %v", err)
+		return err
+	}
+
+	sheetsClient, err := newSheetsClient(cfg)
+	if err != nil {
+		log.Printf("Error creating Google Sheets client for pin: %v", err)
+		return err
+	}
+
+	sheetName := sheets.SheetName(channelID, channelInfo.Name)
+	row, err := sheetsClient.UpdatePinned(channelSpreadsheetID(cfg, sheetsClient, channelID), sheetName, messageTS, pinned)
+	if err != nil {
+		log.Printf("Error updating pinned status of %s in sheet %s: %v", messageTS, sheetName, err)
+		return err
+	}
+	if row < 0 {
+		log.Printf("Message %s not recorded in sheet %s, ignoring %s", messageTS, sheetName, event.Event.Type)
+		return nil
+	}
+
+	mirrorWrite(cfg, "pinned status of "+messageTS, func(sheetsClient *sheets.Client, spreadsheetID string) error {
+		_, err := sheetsClient.UpdatePinned(spreadsheetID, sheetName, messageTS, pinned)
+		return err
+	})
+
+	log.Printf("Updated pinned status of %s (%s): %t",
+		buildSheetRangeURL(cfg, sheetsClient, channelID, channelInfo.Name, row, row), event.Event.Type, pinned)
+	return nil
+}
@@ -0,0 +1,46 @@
+package slack
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+)
+
+// neededScopePattern extracts the scope Slack reports as missing from an API error body
+var neededScopePattern = regexp.MustCompile(`"needed"\s*:\s*"([^"]+)"`)
+
+// missingScope returns the scope(s) a missing_scope API error asks for, or "" for other errors
+func missingScope(err error) string {
+	if err == nil || !strings.Contains(err.Error(), "missing_scope") {
+		return ""
+	}
+	if matches := neededScopePattern.FindStringSubmatch(err.Error()); len(matches) == 2 {
+		return matches[1]
+	}
+	return "unknown"
+}
+
+// missingScopeMessage tells the channel which scope an administrator has to add to the Slack app
+func missingScopeMessage(scope string) string {
+	return fmt.Sprintf("🔒 このチャンネルを記録するには、Slack アプリに `%s` 権限が必要です。\n"+
+		"Slack ワークスペースの管理者に、アプリの OAuth & Permissions で権限を追加してアプリを再インストールするよう依頼してください。", scope)
+}
+
+// privateChannelMissingScope returns the scope the bot lacks to read a private channel's history, or "" if it can.
+// When the scopes cannot be checked, the history request itself reports a missing scope later.
+func privateChannelMissingScope(slackClient *Client, channelInfo *ChannelInfo) string {
+	if !channelInfo.IsPrivate || channelInfo.IsIM || channelInfo.IsMpim {
+		return ""
+	}
+
+	granted, err := slackClient.HasScope("groups:history")
+	if err != nil {
+		log.Printf("Warning: Could not check the granted scopes: %v", err)
+		return ""
+	}
+	if !granted {
+		return "groups:history"
+	}
+	return ""
+}
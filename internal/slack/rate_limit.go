@@ -0,0 +1,130 @@
+package slack
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"slack-to-google-sheets-bot/internal/chaos"
+)
+
+const (
+	// defaultRetryAfter is used when a 429 response has no usable Retry-After header
+	defaultRetryAfter = 30 * time.Second
+	// maxRateLimitWait caps how long one call keeps waiting out rate limits before the error is returned to the caller
+	maxRateLimitWait = 5 * time.Minute
+)
+
+// RateLimitError is returned when Slack answers 429; RetryAfter is the wait Slack asked for
+type RateLimitError struct {
+	Method     string
+	RetryAfter time.Duration
+}
+
+// Error mentions "ratelimited" like Slack's own error code
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("slack API error: ratelimited (%s, retry after %v)", e.Method, e.RetryAfter)
+}
+
+// rateLimitDelay returns the wait Slack asked for if err is a rate limit, or fallback otherwise
+func rateLimitDelay(err error, fallback time.Duration) time.Duration {
+	var rateLimitErr *RateLimitError
+	if errors.As(err, &rateLimitErr) && rateLimitErr.RetryAfter > 0 {
+		return rateLimitErr.RetryAfter
+	}
+	return fallback
+}
+
+// rateLimitedUntil holds, per token, workspace and Web API method (see rateLimitKey), when Slack allows calling the
+// method again; Slack limits each method separately for every app installation
+var (
+	rateLimitedUntil      = make(map[string]time.Time)
+	rateLimitedUntilMutex sync.Mutex
+)
+
+// apiMethod returns the Web API method of a request URL path such as "/api/conversations.history"
+func apiMethod(path string) string {
+	return path[strings.LastIndex(path, "/")+1:]
+}
+
+// rateLimitKey identifies what a 429 applies to: the method called with one token for one workspace. The token is
+// hashed so the map does not hold another copy of it. The workspace is the client's team (see WithTeam), since
+// POST methods carry team_id in the body rather than the URL.
+func rateLimitKey(req *http.Request, teamID, method string) string {
+	token := sha256.Sum256([]byte(req.Header.Get("Authorization")))
+	return hex.EncodeToString(token[:8]) + "/" + teamID + "/" + method
+}
+
+// parseRetryAfter reads a Retry-After header given in seconds
+func parseRetryAfter(value string) time.Duration {
+	seconds, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || seconds <= 0 {
+		return defaultRetryAfter
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// newRateLimitTransport returns the transport of a Slack API client calling on behalf of a workspace ("" when the
+// token is not org-wide)
+func newRateLimitTransport(teamID string) http.RoundTripper {
+	base := chaos.Transport(nil)
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &rateLimitTransport{base: base, teamID: teamID}
+}
+
+// rateLimitTransport turns 429 responses into a *RateLimitError and holds back further calls of a rate limited
+// method with the same token and workspace until its Retry-After has passed, so concurrent callers do not keep
+// hitting the limit
+type rateLimitTransport struct {
+	base   http.RoundTripper
+	teamID string
+}
+
+// RoundTrip waits out a known rate limit of the method, then sends the request
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	method := apiMethod(req.URL.Path)
+	key := rateLimitKey(req, t.teamID, method)
+
+	rateLimitedUntilMutex.Lock()
+	until := rateLimitedUntil[key]
+	rateLimitedUntilMutex.Unlock()
+	if wait := time.Until(until); wait > 0 {
+		log.Printf("Slack method %s is rate limited, waiting %v before calling it", method, wait.Round(time.Second))
+		select {
+		case <-time.After(wait):
+		case <-req.Context().Done():
+			if req.Body != nil {
+				req.Body.Close()
+			}
+			return nil, req.Context().Err()
+		}
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusTooManyRequests {
+		return resp, err
+	}
+
+	retryAfter := parseRetryAfter(resp.Header.Get("Retry-After"))
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	rateLimitedUntilMutex.Lock()
+	if next := time.Now().Add(retryAfter); next.After(rateLimitedUntil[key]) {
+		rateLimitedUntil[key] = next
+	}
+	rateLimitedUntilMutex.Unlock()
+
+	log.Printf("Slack rate limited %s, retry after %v", method, retryAfter)
+	return nil, &RateLimitError{Method: method, RetryAfter: retryAfter}
+}
@@ -0,0 +1,86 @@
+package slack
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestRateLimitKey(t *testing.T) {
+	request := func(method, url, token string) *http.Request {
+		req, err := http.NewRequest(method, url, strings.NewReader(""))
+		if err != nil {
+			t.Fatalf("NewRequest: %v", err)
+		}
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		return req
+	}
+	base := rateLimitKey(request("GET", "https://slack.com/api/conversations.history", "xoxb-1"), "T1", "conversations.history")
+
+	tests := []struct {
+		name     string
+		req      *http.Request
+		teamID   string
+		method   string
+		wantSame bool
+	}{
+		{"same token, team and method", request("GET", "https://slack.com/api/conversations.history?channel=C1", "xoxb-1"), "T1", "conversations.history", true},
+		{"team_id in the query is ignored", request("GET", "https://slack.com/api/conversations.history?team_id=T2", "xoxb-1"), "T1", "conversations.history", true},
+		{"POST without team_id in the URL", request("POST", "https://slack.com/api/conversations.history", "xoxb-1"), "T1", "conversations.history", true},
+		{"other team", request("GET", "https://slack.com/api/conversations.history", "xoxb-1"), "T2", "conversations.history", false},
+		{"no team", request("GET", "https://slack.com/api/conversations.history", "xoxb-1"), "", "conversations.history", false},
+		{"other token", request("GET", "https://slack.com/api/conversations.history", "xoxb-2"), "T1", "conversations.history", false},
+		{"other method", request("GET", "https://slack.com/api/conversations.replies", "xoxb-1"), "T1", "conversations.replies", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key := rateLimitKey(tt.req, tt.teamID, tt.method)
+			if (key == base) != tt.wantSame {
+				t.Fatalf("rateLimitKey = %q, base %q, want same: %v", key, base, tt.wantSame)
+			}
+			if strings.Contains(key, "xoxb-") {
+				t.Fatalf("rateLimitKey %q contains the token", key)
+			}
+		})
+	}
+}
+
+func TestRateLimitTransportUsesClientTeam(t *testing.T) {
+	tests := []struct {
+		name   string
+		client *Client
+		want   string
+	}{
+		{"single workspace", NewClient("xoxb-1"), ""},
+		{"Enterprise Grid workspace", NewClient("xoxb-1").WithTeam("T1"), "T1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transport, ok := tt.client.httpClient.Transport.(*rateLimitTransport)
+			if !ok {
+				t.Fatalf("transport is %T, want *rateLimitTransport", tt.client.httpClient.Transport)
+			}
+			if transport.teamID != tt.want {
+				t.Fatalf("transport team = %q, want %q", transport.teamID, tt.want)
+			}
+		})
+	}
+}
+
+func TestAPIMethod(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/api/conversations.history", "conversations.history"},
+		{"/api/chat.postMessage", "chat.postMessage"},
+		{"/custom/base/api/users.info", "users.info"},
+	}
+	for _, tt := range tests {
+		if got := apiMethod(tt.path); got != tt.want {
+			t.Errorf("apiMethod(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package slack
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+
+	"slack-to-google-sheets-bot/internal/config"
+	"slack-to-google-sheets-bot/internal/sheets"
+)
+
+// ReactionsResponse is the response of reactions.get
+type ReactionsResponse struct {
+	OK      bool `json:"ok"`
+	Message struct {
+		Reactions []Reaction `json:"reactions"`
+	} `json:"message"`
+}
+
+// formatReactions renders reactions as ":name: count" pairs in Slack's order
+func formatReactions(reactions []Reaction) string {
+	var parts []string
+	for _, reaction := range reactions {
+		parts = append(parts, fmt.Sprintf(":%s: %d", reaction.Name, reaction.Count))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// reactionCount returns the total number of reactions of all emoji
+func reactionCount(reactions []Reaction) int {
+	total := 0
+	for _, reaction := range reactions {
+		total += reaction.Count
+	}
+	return total
+}
+
+// GetReactions returns the current reactions of a message
+func (c *Client) GetReactions(channelID, messageTS string) ([]Reaction, error) {
+	var reactions []Reaction
+	err := retryWithBackoff(func() error {
+		url := apiBaseURL + fmt.Sprintf("reactions.get?channel=%s&timestamp=%s", channelID, messageTS)
+
+		req, err := http.NewRequest("GET", url, nil)
+		if err != nil {
+			return err
+		}
+
+		req.Header.Set("Authorization", "Bearer "+c.token)
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return err
+		}
+
+		var reactionsResp ReactionsResponse
+		if err := json.Unmarshal(body, &reactionsResp); err != nil {
+			return err
+		}
+
+		if !reactionsResp.OK {
+			return fmt.Errorf("slack API error: %s", string(body))
+		}
+
+		reactions = reactionsResp.Message.Reactions
+		return nil
+	}, fmt.Sprintf("get reactions of %s in channel %s", messageTS, channelID))
+
+	return reactions, err
+}
+
+// handleReactionChanged rewrites the reactions cell of the reacted message from its current reactions.
+// The counts are re-read from Slack rather than incremented, so out-of-order add/remove events cannot drift.
+func handleReactionChanged(cfg *config.Config, event *Event) error {
+	if (!cfg.RecordReactions && !cfg.RecordEngagement) || cfg.GoogleSheetsCredentials == "" || cfg.SpreadsheetID == "" {
+		return nil
+	}
+
+	item := event.Event.Item
+	if item == nil || item.Type != "message" || item.Channel == "" || item.Timestamp == "" {
+		return nil // Reactions to files and file comments are not recorded
+	}
+
+	slackClient := newClient(cfg)
+
+	channelInfo, err := slackClient.GetChannelInfo(item.Channel)
+	if err != nil {
+		log.Printf("Error getting channel info for reaction: %v", err)
+		return err
+	}
+
+	reactions, err := slackClient.GetReactions(item.Channel, item.Timestamp)
+	if err != nil {
+		log.Printf("Error getting reactions of %s: %v", item.Timestamp, err)
+		return err
+	}
+
+	sheetsClient, err := newSheetsClient(cfg)
+	if err != nil {
+		log.Printf("Error creating Google Sheets client for reaction: %v", err)
+		return err
+	}
+
+	sheetName := sheets.SheetName(item.Channel, channelInfo.Name)
+	summary, count := formatReactions(reactions), reactionCount(reactions)
+	updateCells := func(sheetsClient *sheets.Client, spreadsheetID string) (int, error) {
+		row := -1
+		if cfg.RecordReactions {
+			updated, err := sheetsClient.UpdateReactions(spreadsheetID, sheetName, item.Timestamp, summary)
+			if err != nil || updated < 0 {
+				return updated, err
+			}
+			row = updated
+		}
+		if cfg.RecordEngagement {
+			updated, err := sheetsClient.UpdateReactionCount(spreadsheetID, sheetName, item.Timestamp, count)
+			if err != nil || updated < 0 {
+				return updated, err
+			}
+			row = updated
+		}
+		return row, nil
+	}
+
+	row, err := updateCells(sheetsClient, channelSpreadsheetID(cfg, sheetsClient, item.Channel))
+	if err != nil {
+		log.Printf("Error updating reactions of %s in sheet %s: %v", item.Timestamp, sheetName, err)
+		return err
+	}
+	if row < 0 {
+		log.Printf("Message %s not recorded in sheet %s, ignoring %s", item.Timestamp, sheetName, event.Event.Type)
+		return nil
+	}
+
+	mirrorWrite(cfg, "reactions of "+item.Timestamp, func(sheetsClient *sheets.Client, spreadsheetID string) error {
+		_, err := updateCells(sheetsClient, spreadsheetID)
+		return err
+	})
+
+	log.Printf("Updated reactions of %s (%s :%s:): %s (%d in total)",
+		buildSheetRangeURL(cfg, sheetsClient, item.Channel, channelInfo.Name, row, row), event.Event.Type, event.Event.Reaction, summary, count)
+	return nil
+}
+
+// refreshReplyCount updates the reply count of the thread parent of a newly recorded reply when engagement is recorded
+func refreshReplyCount(cfg *config.Config, sheetsClient *sheets.Client, reply *sheets.MessageRecord) {
+	if !cfg.RecordEngagement {
+		return
+	}
+
+	sheetName := sheets.SheetName(reply.Channel, reply.ChannelName)
+	if _, err := sheetsClient.RefreshReplyCount(channelSpreadsheetID(cfg, sheetsClient, reply.Channel), sheetName, reply.ThreadTS); err != nil {
+		log.Printf("Error updating reply count of %s in sheet %s: %v", reply.ThreadTS, sheetName, err)
+		return
+	}
+	mirrorWrite(cfg, "reply count of "+reply.ThreadTS, func(sheetsClient *sheets.Client, spreadsheetID string) error {
+		_, err := sheetsClient.RefreshReplyCount(spreadsheetID, sheetName, reply.ThreadTS)
+		return err
+	})
+}
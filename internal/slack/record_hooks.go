@@ -0,0 +1,27 @@
+package slack
+
+import (
+	"slack-to-google-sheets-bot/internal/config"
+	"slack-to-google-sheets-bot/internal/hooks"
+	"slack-to-google-sheets-bot/internal/sheets"
+)
+
+// runRecordHooks passes records just written to the registered hooks; rows maps MessageTS to the 1-based row of
+// records whose row is known, and updated tells whether existing rows were rewritten
+func runRecordHooks(cfg *config.Config, sheetsClient *sheets.Client, records []*sheets.MessageRecord, rows map[string]int, updated bool) {
+	if !hooks.Enabled() || len(records) == 0 {
+		return
+	}
+
+	written := make([]hooks.Written, 0, len(records))
+	for _, record := range records {
+		written = append(written, hooks.Written{
+			MessageRecord: record,
+			SpreadsheetID: channelSpreadsheetID(cfg, sheetsClient, record.Channel),
+			SheetName:     sheets.SheetName(record.Channel, record.ChannelName),
+			Row:           rows[record.MessageTS],
+			Updated:       updated,
+		})
+	}
+	hooks.Run(written)
+}
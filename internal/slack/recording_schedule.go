@@ -0,0 +1,107 @@
+package slack
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"slack-to-google-sheets-bot/internal/audit"
+	"slack-to-google-sheets-bot/internal/config"
+	"slack-to-google-sheets-bot/internal/schedule"
+	"slack-to-google-sheets-bot/internal/sheets"
+)
+
+// skippedPeriod tracks a run of consecutive messages skipped because of a channel's recording schedule
+type skippedPeriod struct {
+	From  time.Time
+	To    time.Time
+	Count int
+}
+
+var (
+	auditLogger        = audit.NewLogger()
+	skippedPeriods     = make(map[string]*skippedPeriod)
+	skippedPeriodMutex = sync.Mutex{}
+)
+
+// isRecordingAllowed reports whether a message posted at t in the channel falls inside its recording schedule
+func isRecordingAllowed(cfg *config.Config, channelID string, t time.Time) bool {
+	spec := cfg.RecordingSchedule(channelID)
+	if spec == "" {
+		return true
+	}
+
+	s, err := schedule.Parse(spec)
+	if err != nil {
+		log.Printf("Warning: invalid recording schedule %q for channel %s, recording anyway: %v", spec, channelID, err)
+		return true
+	}
+
+	return s.Allows(t.In(jstLocation))
+}
+
+// noteScheduleSkip extends the channel's current skipped period with a message posted at t
+func noteScheduleSkip(channelID string, t time.Time) {
+	skippedPeriodMutex.Lock()
+	defer skippedPeriodMutex.Unlock()
+
+	period, exists := skippedPeriods[channelID]
+	if !exists {
+		period = &skippedPeriod{From: t}
+		skippedPeriods[channelID] = period
+	}
+	period.To = t
+	period.Count++
+}
+
+// flushScheduleSkip writes the channel's pending skipped period (if any) to the audit log
+func flushScheduleSkip(channelID string) {
+	skippedPeriodMutex.Lock()
+	period, exists := skippedPeriods[channelID]
+	delete(skippedPeriods, channelID)
+	skippedPeriodMutex.Unlock()
+
+	if !exists {
+		return
+	}
+
+	recordScheduleSkipAudit(channelID, period)
+}
+
+// recordScheduleSkipAudit writes a skipped period to the audit log
+func recordScheduleSkipAudit(channelID string, period *skippedPeriod) {
+	detail := fmt.Sprintf("skipped %d messages outside recording schedule from %s to %s",
+		period.Count, period.From.Format("2006-01-02 15:04:05"), period.To.Format("2006-01-02 15:04:05"))
+	if err := auditLogger.Record(audit.Entry{Action: "recording_skipped", ChannelID: channelID, Detail: detail}); err != nil {
+		log.Printf("Warning: Could not write audit entry: %v", err)
+	}
+}
+
+// filterRecordsBySchedule drops history records posted outside the channel's recording schedule
+func filterRecordsBySchedule(cfg *config.Config, channelID string, records []*sheets.MessageRecord) []*sheets.MessageRecord {
+	if cfg.RecordingSchedule(channelID) == "" {
+		return records
+	}
+
+	var allowed []*sheets.MessageRecord
+	var period *skippedPeriod
+	for _, record := range records {
+		if isRecordingAllowed(cfg, channelID, record.Timestamp) {
+			allowed = append(allowed, record)
+			continue
+		}
+		if period == nil {
+			period = &skippedPeriod{From: record.Timestamp}
+		}
+		period.To = record.Timestamp
+		period.Count++
+	}
+
+	if period != nil {
+		log.Printf("Filtered %d history messages outside recording schedule for channel %s", period.Count, channelID)
+		recordScheduleSkipAudit(channelID, period)
+	}
+
+	return allowed
+}
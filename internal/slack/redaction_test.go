@@ -0,0 +1,128 @@
+package slack
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"slack-to-google-sheets-bot/internal/config"
+	"slack-to-google-sheets-bot/internal/optout"
+	"slack-to-google-sheets-bot/internal/sheets"
+)
+
+const secretText = "給与テーブルの件、詳細はこちら"
+
+// secretRecord returns a record whose text, edit history and file details must not survive redaction
+func secretRecord(channelID, userID string) *sheets.MessageRecord {
+	return &sheets.MessageRecord{
+		Timestamp:   time.Date(2025, 6, 1, 9, 0, 0, 0, time.UTC),
+		Channel:     channelID,
+		ChannelName: "general",
+		User:        userID,
+		Text:        secretText,
+		MessageTS:   "1748768400.000100",
+		EditHistory: "[版1] 給与テーブルの件",
+		Files:       []sheets.FileRecord{{Name: "salaries.xlsx", Type: "xlsx", Size: 2048, Permalink: "https://example.slack.com/files/F1"}},
+	}
+}
+
+func TestApplyOptOutPolicy(t *testing.T) {
+	previous := optOutRegistry
+	optOutRegistry = optout.NewRegistryAt(t.TempDir())
+	t.Cleanup(func() { optOutRegistry = previous })
+	if err := optOutRegistry.OptOut("U0OPTOUT"); err != nil {
+		t.Fatalf("OptOut: %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		policy       string
+		user         string
+		wantKept     bool
+		wantRedacted bool
+	}{
+		{"opted-out user, redact policy", "redact", "U0OPTOUT", true, true},
+		{"opted-out user, skip policy", OptOutPolicySkip, "U0OPTOUT", false, false},
+		{"other user", "redact", "U0OTHER", true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			record := secretRecord("C0TEST", tt.user)
+			kept := applyOptOutPolicy(&config.Config{OptOutPolicy: tt.policy}, record)
+			if kept != tt.wantKept {
+				t.Fatalf("kept = %v, want %v", kept, tt.wantKept)
+			}
+			if !kept {
+				return
+			}
+			if tt.wantRedacted {
+				if record.Text != OptOutPlaceholder {
+					t.Fatalf("Text = %q, want %q", record.Text, OptOutPlaceholder)
+				}
+				assertRedacted(t, record)
+			} else {
+				assertUntouched(t, record)
+			}
+		})
+	}
+}
+
+func TestRedactionByRecordMode(t *testing.T) {
+	tests := []struct {
+		name         string
+		mode         string
+		wantText     string
+		wantRedacted bool
+	}{
+		{"full", "full", secretText, false},
+		{"metadata only", "metadata", fmt.Sprintf(metadataOnlyText, len([]rune(secretText))), true},
+		// Without an archive key the content cannot be sealed, so it is dropped rather than written in plain text
+		{"encrypted without an archive key", "encrypted", sealFailedText, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{RecordModes: map[string]string{"C0TEST": tt.mode}}
+			record := secretRecord("C0TEST", "U0TEST")
+
+			sealRecords(cfg, []*sheets.MessageRecord{record})
+			minimizeRecords(cfg, []*sheets.MessageRecord{record})
+
+			if record.Text != tt.wantText {
+				t.Fatalf("Text = %q, want %q", record.Text, tt.wantText)
+			}
+			if tt.wantRedacted {
+				assertRedacted(t, record)
+			} else {
+				assertUntouched(t, record)
+			}
+		})
+	}
+}
+
+// assertRedacted checks that nothing of the original content is left in a record, and that it is marked so that
+// UpdateMessages does not rebuild the edit history from the sheet
+func assertRedacted(t *testing.T, record *sheets.MessageRecord) {
+	t.Helper()
+	if !record.Redacted {
+		t.Fatalf("Redacted = false, want true")
+	}
+	if record.EditHistory != "" {
+		t.Fatalf("EditHistory = %q, want it cleared", record.EditHistory)
+	}
+	for _, file := range record.Files {
+		if file.Name != "" || file.Permalink != "" {
+			t.Fatalf("file details kept: %+v", file)
+		}
+		if file.Type == "" || file.Size == 0 {
+			t.Fatalf("file type and size dropped: %+v", file)
+		}
+	}
+}
+
+// assertUntouched checks that a record that is not redacted keeps its content
+func assertUntouched(t *testing.T, record *sheets.MessageRecord) {
+	t.Helper()
+	if record.Redacted || record.Text != secretText || record.EditHistory == "" || record.Files[0].Name == "" {
+		t.Fatalf("record was redacted: %+v", record)
+	}
+}
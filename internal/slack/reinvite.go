@@ -0,0 +1,238 @@
+package slack
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"slack-to-google-sheets-bot/internal/audit"
+	"slack-to-google-sheets-bot/internal/config"
+	boterrors "slack-to-google-sheets-bot/internal/errors"
+	"slack-to-google-sheets-bot/internal/sheets"
+)
+
+// reinviteOffers are re-invited channels waiting for the choice between continuing and a full backfill
+var (
+	reinviteOffers      = make(map[string]string) // channel ID -> newest recorded message TS
+	reinviteOffersMutex sync.Mutex
+)
+
+// takeReinviteOffer returns and removes the pending offer of a channel so it is answered only once
+func takeReinviteOffer(channelID string) (string, bool) {
+	reinviteOffersMutex.Lock()
+	defer reinviteOffersMutex.Unlock()
+	lastTS, exists := reinviteOffers[channelID]
+	delete(reinviteOffers, channelID)
+	return lastTS, exists
+}
+
+// offerContinuation asks a re-invited channel whose tab already has rows whether to continue from the last
+// recorded message or to retrieve the whole history again. It returns false when there is nothing recorded yet,
+// in which case the initial backfill runs as usual.
+func offerContinuation(cfg *config.Config, slackClient *Client, event *Event, channelInfo *ChannelInfo) bool {
+	if cfg.GoogleSheetsCredentials == "" || cfg.SpreadsheetID == "" {
+		return false
+	}
+
+	sheetsClient, err := newSheetsClient(cfg)
+	if err != nil {
+		log.Printf("Error creating Google Sheets client for re-invite check: %v", err)
+		return false
+	}
+	spreadsheetID := channelSpreadsheetID(cfg, sheetsClient, event.Event.Channel)
+	exists, err := sheetsClient.HasChannelSheet(spreadsheetID, event.Event.Channel)
+	if err != nil {
+		log.Printf("Warning: Could not check for an existing sheet of channel %s: %v", event.Event.Channel, err)
+		return false
+	}
+	if !exists {
+		return false
+	}
+
+	// The tab is renamed first if the channel was renamed while the bot was away
+	if err := sheetsClient.EnsureChannelSheetExists(spreadsheetID, event.Event.Channel, channelInfo.Name); err != nil {
+		log.Printf("Error ensuring channel sheet exists for re-invite: %v", err)
+		return false
+	}
+	rows, lastTS, err := sheetsClient.LastMessageTS(spreadsheetID, sheets.SheetName(event.Event.Channel, channelInfo.Name))
+	if err != nil {
+		log.Printf("Warning: Could not read the last recorded message of channel %s: %v", event.Event.Channel, err)
+		return false
+	}
+	if rows == 0 || lastTS == "" {
+		return false
+	}
+
+	reinviteOffersMutex.Lock()
+	reinviteOffers[event.Event.Channel] = lastTS
+	reinviteOffersMutex.Unlock()
+
+	lastRecorded := convertSlackTimestampToJST(lastTS).Format("2006-01-02 15:04")
+	log.Printf("Channel %s was recorded before (%d rows, last message %s), offering to continue", event.Event.Channel, rows, lastTS)
+	recordAudit(cfg, audit.Entry{
+		Action:    "bot_reinvited",
+		ChannelID: event.Event.Channel,
+		User:      event.Event.Inviter,
+		Detail:    fmt.Sprintf("#%s: %d rows recorded, last message at %s", channelInfo.Name, rows, lastRecorded),
+	})
+
+	message := fmt.Sprintf("👋 このチャンネル (#%s) には記録済みのデータがあります（%d行、最終記録: %s）。\n"+
+		"最後に記録したメッセージの続きから記録するか、履歴をすべて取得し直すかを選んでください。"+
+		"選ぶまでの間も新しいメッセージは記録されます。\n%s", channelInfo.Name, rows, lastRecorded, recordingNotice)
+	blocks := []Block{
+		sectionBlock(message),
+		actionsBlock(
+			actionButton(actionReinviteContinue, "続きから記録", event.Event.Channel, "primary"),
+			actionButton(actionReinviteFull, "履歴をすべて取得", event.Event.Channel, ""),
+		),
+	}
+	if _, err := slackClient.PostBlocks(event.Event.Channel, message, blocks); err != nil {
+		log.Printf("Error sending re-invite offer: %v", err)
+	}
+	return true
+}
+
+// handleReinviteContinueButton records the messages posted since the last recorded one when "続きから記録" is clicked
+func handleReinviteContinueButton(cfg *config.Config, slackClient *Client, event *Event, payload *InteractionPayload, action BlockAction) error {
+	lastTS, exists := takeReinviteOffer(event.Event.Channel)
+	if !exists {
+		replyToClicker(slackClient, payload, "ℹ️ この選択はすでに処理済みか、bot の再起動により無効になっています。")
+		return nil
+	}
+	replaceButtons(slackClient, payload, fmt.Sprintf("▶️ <@%s> が「続きから記録」を選びました。", event.Event.User))
+
+	channelInfo, err := slackClient.GetChannelInfo(event.Event.Channel)
+	if err != nil {
+		log.Printf("Error getting channel info for re-invite continuation: %v", err)
+		channelInfo = &ChannelInfo{ID: event.Event.Channel, Name: "Unknown"}
+	}
+	return continueFromLastRecorded(cfg, slackClient, event, channelInfo, lastTS)
+}
+
+// handleReinviteFullButton retrieves the whole history again when "履歴をすべて取得" is clicked; rows already
+// in the sheet are kept and merged with the history
+func handleReinviteFullButton(cfg *config.Config, slackClient *Client, event *Event, payload *InteractionPayload, action BlockAction) error {
+	if _, exists := takeReinviteOffer(event.Event.Channel); !exists {
+		replyToClicker(slackClient, payload, "ℹ️ この選択はすでに処理済みか、bot の再起動により無効になっています。")
+		return nil
+	}
+	replaceButtons(slackClient, payload, fmt.Sprintf("🔄 <@%s> が「履歴をすべて取得」を選びました。", event.Event.User))
+
+	channelInfo, err := slackClient.GetChannelInfo(event.Event.Channel)
+	if err != nil {
+		log.Printf("Error getting channel info for re-invite backfill: %v", err)
+		channelInfo = &ChannelInfo{ID: event.Event.Channel, Name: "Unknown"}
+	}
+	if err := startStatusThread(slackClient, event.Event.Channel, fmt.Sprintf("🔄 過去のメッセージ履歴を取得しています... (#%s)", channelInfo.Name)); err != nil {
+		log.Printf("Error sending re-invite backfill message: %v", err)
+	}
+	return performHistoryRetrieval(cfg, slackClient, event, channelInfo, false)
+}
+
+// continueFromLastRecorded appends the messages posted after lastTS, so numbering continues from the existing rows
+// and the history before it is not pulled again
+func continueFromLastRecorded(cfg *config.Config, slackClient *Client, event *Event, channelInfo *ChannelInfo, lastTS string) error {
+	channelID := event.Event.Channel
+	startedAt := time.Now()
+
+	if refusesSharedChannel(cfg, channelInfo) {
+		return slackClient.SendMessage(channelID, sharedChannelRefusedMessage)
+	}
+
+	historyProgressMutex.Lock()
+	if historyInProgress[channelID] {
+		historyProgressMutex.Unlock()
+		log.Printf("History retrieval already running for channel %s, ignoring continuation", channelID)
+		return nil
+	}
+	run := startBackfillRun(channelID, startedAt)
+	historyProgressMutex.Unlock()
+	defer finishBackfillRun(channelID, run)
+
+	if err := startStatusThread(slackClient, channelID, fmt.Sprintf("🔄 最後に記録したメッセージの続きから記録しています... (#%s)", channelInfo.Name)); err != nil {
+		log.Printf("Error sending continuation message: %v", err)
+	}
+
+	sheetsClient, err := newSheetsClient(cfg)
+	if err != nil {
+		log.Printf("Error creating Google Sheets client: %v", err)
+		notifyFailure(cfg, slackClient, event, boterrors.New(boterrors.SheetsConnectFailed, err))
+		failStatusMessage(slackClient, channelID)
+		return err
+	}
+
+	updateStatusProgress(slackClient, channelID, "前回の記録以降のメッセージを取得中", 0, 0)
+	records, err := messagesAfterTS(cfg, slackClient, channelID, channelInfo.Name, lastTS)
+	if err != nil {
+		log.Printf("Error getting messages after %s for channel %s: %v", lastTS, channelID, err)
+		if isChannelAccessError(err) {
+			return nil
+		}
+		notifyFailure(cfg, slackClient, event, boterrors.New(boterrors.ContinuationFetchFailed, err))
+		failStatusMessage(slackClient, channelID)
+		return err
+	}
+
+	for _, record := range records {
+		record.TraceID = event.TraceID
+	}
+
+	spreadsheetID := channelSpreadsheetID(cfg, sheetsClient, channelID)
+	if len(records) > 0 {
+		updateStatusProgress(slackClient, channelID, "シートに書き込み中", 0, len(records))
+		if err := sheetsClient.WriteBatchMessages(spreadsheetID, records); err != nil {
+			log.Printf("Error writing continued messages for channel %s: %v", channelID, err)
+			failed := spoolFailedRecords(channelID, err)
+			if failed == nil || len(failed) == len(records) {
+				notifyFailure(cfg, slackClient, event, boterrors.New(boterrors.ContinuationWriteFailed, err))
+				failStatusMessage(slackClient, channelID)
+				return err
+			}
+			records = excludeRecords(records, failed)
+			notifyJobResult(cfg, slackClient, event, true, partialFailureMessage(len(records), len(failed)))
+		}
+		annotateSettingChangesAt(cfg, sheetsClient, spreadsheetID, records)
+		runRecordHooks(cfg, sheetsClient, records, nil, false)
+		persistRecords(cfg, records)
+		mirrorRecords(cfg, records)
+	}
+
+	sheetURL := buildSheetURLWithGID(cfg, sheetsClient, channelID, channelInfo.Name)
+	completionMessage := fmt.Sprintf("✅ 前回の記録の続きから記録しました！\n"+
+		"追加したメッセージ数: %d件\n"+
+		"記録先: %s", len(records), sheetURL)
+	finishStatusMessage(slackClient, channelID, strings.SplitN(completionMessage, "\n", 2)[0], []string{
+		fmt.Sprintf("*追加したメッセージ数*\n%d件", len(records)),
+	}, sheetURL)
+	if err := notifyJobResult(cfg, slackClient, event, false, completionMessage); err != nil {
+		log.Printf("Error sending continuation completion message: %v", err)
+	}
+	endStatusThread(channelID)
+	recordCompletedRun(event, false, startedAt, len(records))
+	recordManifest(cfg, sheetsClient, event, channelInfo, manifestKindContinuation, len(records))
+	return nil
+}
+
+// messagesAfterTS fetches the messages of a channel posted after lastTS, filtered and enriched for recording
+func messagesAfterTS(cfg *config.Config, slackClient *Client, channelID, channelName, lastTS string) ([]*sheets.MessageRecord, error) {
+	// Slack's oldest parameter has second precision; the message at lastTS itself is dropped below
+	after := convertSlackTimestampToJST(lastTS).Add(-time.Second)
+	fetched, err := slackClient.getMessagesAfterTime(channelID, channelName, after)
+	if err != nil {
+		return nil, err
+	}
+
+	lastValue, _ := strconv.ParseFloat(lastTS, 64)
+	var records []*sheets.MessageRecord
+	for _, record := range fetched {
+		if ts, err := strconv.ParseFloat(record.MessageTS, 64); err == nil && ts > lastValue {
+			records = append(records, record)
+		}
+	}
+	records = filterRecordsByOptOut(cfg, filterRecordsBySchedule(cfg, channelID, records))
+	enrichRecords(cfg, slackClient, records)
+	return records, nil
+}
@@ -0,0 +1,131 @@
+package slack
+
+import (
+	"log"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"slack-to-google-sheets-bot/internal/config"
+	"slack-to-google-sheets-bot/internal/i18n"
+	"slack-to-google-sheets-bot/internal/sheets"
+)
+
+// resetConfirmationWindow is how long a "Reset!" preview waits for "Reset! confirm"
+const resetConfirmationWindow = 10 * time.Minute
+
+var (
+	// resetPreviewPattern matches "Reset! preview" / "Reset! dry-run", which only show what a reset would delete
+	resetPreviewPattern = regexp.MustCompile(`(?i)^reset!?\s+(?:preview|dry-?run)\b`)
+	// resetConfirmPattern matches "Reset! confirm", which runs a previewed reset
+	resetConfirmPattern = regexp.MustCompile(`(?i)^reset!?\s+confirm\b`)
+)
+
+// pendingReset is a previewed reset waiting for confirmation
+type pendingReset struct {
+	user      string
+	expiresAt time.Time
+}
+
+var (
+	pendingResets      = make(map[string]pendingReset) // Channel ID -> previewed reset
+	pendingResetsMutex sync.Mutex
+)
+
+// awaitResetConfirmation remembers that a user previewed a reset of a channel
+func awaitResetConfirmation(channelID, userID string) {
+	pendingResetsMutex.Lock()
+	defer pendingResetsMutex.Unlock()
+	pendingResets[channelID] = pendingReset{user: userID, expiresAt: time.Now().Add(resetConfirmationWindow)}
+}
+
+// takeResetConfirmation reports whether the user has an unexpired previewed reset of the channel, consuming it
+func takeResetConfirmation(channelID, userID string) bool {
+	pendingResetsMutex.Lock()
+	defer pendingResetsMutex.Unlock()
+
+	pending, exists := pendingResets[channelID]
+	if !exists || pending.user != userID || time.Now().After(pending.expiresAt) {
+		return false
+	}
+	delete(pendingResets, channelID)
+	return true
+}
+
+// cancelResetConfirmation drops the user's previewed reset of the channel, reporting whether there was one
+func cancelResetConfirmation(channelID, userID string) bool {
+	pendingResetsMutex.Lock()
+	defer pendingResetsMutex.Unlock()
+
+	pending, exists := pendingResets[channelID]
+	if !exists || pending.user != userID {
+		return false
+	}
+	delete(pendingResets, channelID)
+	return true
+}
+
+// buildResetPreview describes what a reset of the channel would delete and how the data comes back
+func buildResetPreview(cfg *config.Config, lang, channelID, channelName string) string {
+	sheetName := sheets.SheetName(channelID, channelName)
+	lines := []string{i18n.T(lang, i18n.KeyResetPreviewTitle, channelName)}
+
+	if cfg.GoogleSheetsCredentials == "" || cfg.SpreadsheetID == "" {
+		lines = append(lines, i18n.T(lang, i18n.KeySheetsNotConfigured))
+		return strings.Join(lines, "\n")
+	}
+
+	sheetsClient, err := newSheetsClient(cfg)
+	if err != nil {
+		log.Printf("Error creating Google Sheets client for reset preview: %v", err)
+		lines = append(lines, i18n.T(lang, i18n.KeyResetPreviewUnreadable))
+	} else if rows, first, last, err := sheetsClient.SheetSummary(channelSpreadsheetID(cfg, sheetsClient, channelID), sheetName); err != nil {
+		log.Printf("Error reading sheet %s for reset preview: %v", sheetName, err)
+		lines = append(lines, i18n.T(lang, i18n.KeyResetPreviewUnreadable))
+	} else if rows == 0 {
+		lines = append(lines, i18n.T(lang, i18n.KeyResetPreviewEmpty, sheetName))
+	} else {
+		lines = append(lines,
+			i18n.T(lang, i18n.KeyResetPreviewRows, rows, sheetName),
+			i18n.T(lang, i18n.KeyResetPreviewPeriod, first, last))
+	}
+
+	lines = append(lines,
+		i18n.T(lang, i18n.KeyResetPreviewRefetch),
+		i18n.T(lang, i18n.KeyResetPreviewLost))
+	if cfg.MirrorSpreadsheetID != "" {
+		lines = append(lines, i18n.T(lang, i18n.KeyResetPreviewMirrorKept))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// handleResetPreview replies with the reset preview; with askConfirmation the reset waits for "Reset! confirm"
+func handleResetPreview(cfg *config.Config, slackClient *Client, event *Event, channelInfo *ChannelInfo, askConfirmation bool) error {
+	lang := replyLanguage(cfg, slackClient, event.Event.User)
+	message := buildResetPreview(cfg, lang, event.Event.Channel, channelInfo.Name)
+	if askConfirmation {
+		awaitResetConfirmation(event.Event.Channel, event.Event.User)
+		message += "\n\n" + i18n.T(lang, i18n.KeyResetPreviewAskConfirm, int(resetConfirmationWindow.Minutes()))
+
+		// Buttons confirm or cancel without typing; only the user who asked for the reset can use them
+		blocks := []Block{
+			sectionBlock(message),
+			actionsBlock(
+				actionButton(actionResetConfirm, i18n.T(lang, i18n.KeyResetConfirmButton), event.Event.Channel, "danger"),
+				actionButton(actionResetCancel, i18n.T(lang, i18n.KeyResetCancelButton), event.Event.Channel, ""),
+			),
+		}
+		if _, err := slackClient.PostBlocks(event.Event.Channel, message, blocks); err != nil {
+			log.Printf("Error sending reset preview: %v", err)
+		}
+		return nil
+	}
+
+	message += "\n\n" + i18n.T(lang, i18n.KeyResetPreviewOnly)
+
+	if err := slackClient.SendMessage(event.Event.Channel, message); err != nil {
+		log.Printf("Error sending reset preview: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package slack
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+
+	"slack-to-google-sheets-bot/internal/audit"
+	"slack-to-google-sheets-bot/internal/config"
+	"slack-to-google-sheets-bot/internal/i18n"
+	"slack-to-google-sheets-bot/internal/sheets"
+)
+
+// resortCommandPattern matches "@bot resort"
+var resortCommandPattern = regexp.MustCompile(`(?i)^resort\b`)
+
+// handleResortCommand puts the channel's tab back in chronological order after live and backfill writes interleaved
+func handleResortCommand(cfg *config.Config, slackClient *Client, event *Event, channelInfo *ChannelInfo) error {
+	lang := replyLanguage(cfg, slackClient, event.Event.User)
+	reply := func(message string) error {
+		if err := slackClient.SendMessage(event.Event.Channel, message); err != nil {
+			log.Printf("Error sending resort reply: %v", err)
+		}
+		return nil
+	}
+
+	if cfg.GoogleSheetsCredentials == "" || cfg.SpreadsheetID == "" {
+		return reply(i18n.T(lang, i18n.KeySheetsNotConfigured))
+	}
+
+	// A running backfill rewrites the tab itself; sorting in between would be overwritten
+	historyProgressMutex.Lock()
+	inProgress := historyInProgress[event.Event.Channel]
+	historyProgressMutex.Unlock()
+	if inProgress {
+		return reply(i18n.T(lang, i18n.KeyResortBusy))
+	}
+
+	sheetsClient, err := newSheetsClient(cfg)
+	if err != nil {
+		log.Printf("Error creating Google Sheets client for resort: %v", err)
+		return reply(i18n.T(lang, i18n.KeySheetsConnectFailed))
+	}
+
+	sheetName := sheets.SheetName(event.Event.Channel, channelInfo.Name)
+	changed, err := sheetsClient.ResortSheet(channelSpreadsheetID(cfg, sheetsClient, event.Event.Channel), sheetName)
+	if err != nil {
+		log.Printf("Error resorting sheet %s: %v", sheetName, err)
+		return reply(i18n.T(lang, i18n.KeyResortFailed))
+	}
+	if changed == 0 {
+		return reply(i18n.T(lang, i18n.KeyResortAlreadySorted, sheetName))
+	}
+
+	recordAudit(cfg, audit.Entry{
+		Action:    "resort",
+		ChannelID: event.Event.Channel,
+		User:      event.Event.User,
+		Detail:    fmt.Sprintf("#%s: %d rows renumbered", channelInfo.Name, changed),
+	})
+	mirrorWrite(cfg, "resort "+sheetName, func(sheetsClient *sheets.Client, spreadsheetID string) error {
+		_, err := sheetsClient.ResortSheet(spreadsheetID, sheetName)
+		return err
+	})
+
+	return reply(i18n.T(lang, i18n.KeyResortDone, sheetName, changed))
+}
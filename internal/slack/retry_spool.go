@@ -0,0 +1,94 @@
+package slack
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"slack-to-google-sheets-bot/internal/config"
+	"slack-to-google-sheets-bot/internal/sheets"
+	"slack-to-google-sheets-bot/internal/spool"
+)
+
+var retrySpool = spool.NewSpool()
+
+// spoolFailedRecords saves the records a batch write could not write to the retry spool
+// and returns them; it returns nil if the error does not carry per-record detail
+func spoolFailedRecords(channelID string, err error) []*sheets.MessageRecord {
+	var partialErr *sheets.PartialWriteError
+	if !errors.As(err, &partialErr) {
+		return nil
+	}
+
+	if spoolErr := retrySpool.Add(channelID, partialErr.Failed); spoolErr != nil {
+		log.Printf("Warning: Could not spool %d failed records for channel %s: %v", len(partialErr.Failed), channelID, spoolErr)
+	}
+	for _, record := range partialErr.Failed {
+		log.Printf("Record not written (channel: %s, ts: %s), spooled for retry", channelID, record.MessageTS)
+	}
+	return partialErr.Failed
+}
+
+// excludeRecords returns the records that are not contained in the excluded list (by MessageTS)
+func excludeRecords(records, excluded []*sheets.MessageRecord) []*sheets.MessageRecord {
+	if len(excluded) == 0 {
+		return records
+	}
+
+	skip := make(map[string]bool)
+	for _, record := range excluded {
+		skip[record.MessageTS] = true
+	}
+
+	var result []*sheets.MessageRecord
+	for _, record := range records {
+		if !skip[record.MessageTS] {
+			result = append(result, record)
+		}
+	}
+	return result
+}
+
+// partialFailureMessage describes a partially failed write for the Slack report
+func partialFailureMessage(written, failed int) string {
+	return fmt.Sprintf("⚠️ 一部のメッセージの記録に失敗しました。\n"+
+		"記録成功: %d件 / 記録失敗: %d件\n"+
+		"失敗したメッセージは再試行キューに保存し、次回の記録時に再度書き込みます。", written, failed)
+}
+
+// retrySpooledRecords writes a channel's spooled records and removes the ones that succeeded
+func retrySpooledRecords(cfg *config.Config, sheetsClient *sheets.Client, channelID string) {
+	if !retrySpool.HasPending(channelID) {
+		return
+	}
+
+	pending, err := retrySpool.Pending(channelID)
+	if err != nil {
+		log.Printf("Warning: Could not load spooled records for channel %s: %v", channelID, err)
+		return
+	}
+	if len(pending) == 0 {
+		return
+	}
+
+	log.Printf("Retrying %d spooled records for channel %s", len(pending), channelID)
+	err = sheetsClient.WriteBatchMessages(channelSpreadsheetID(cfg, sheetsClient, channelID), pending)
+	var partialErr *sheets.PartialWriteError
+	if err != nil && !errors.As(err, &partialErr) {
+		log.Printf("Warning: Could not write spooled records for channel %s: %v", channelID, err)
+		return
+	}
+
+	written := pending
+	if partialErr != nil {
+		written = excludeRecords(pending, partialErr.Failed)
+	}
+	if err := retrySpool.Remove(channelID, written); err != nil {
+		log.Printf("Warning: Could not update retry spool for channel %s: %v", channelID, err)
+	}
+	runRecordHooks(cfg, sheetsClient, written, nil, false)
+	persistRecords(cfg, written)
+	mirrorRecords(cfg, written)
+
+	log.Printf("Wrote %d/%d spooled records for channel %s", len(written), len(pending), channelID)
+}
@@ -0,0 +1,118 @@
+package slack
+
+import (
+	"log"
+	"regexp"
+	"time"
+
+	"slack-to-google-sheets-bot/internal/config"
+	"slack-to-google-sheets-bot/internal/i18n"
+	"slack-to-google-sheets-bot/internal/runhistory"
+)
+
+var runHistory = runhistory.NewStore()
+
+var (
+	// resetCommandPattern matches a command starting with "Reset!" (with or without the exclamation mark)
+	resetCommandPattern = regexp.MustCompile(`(?i)^reset!?(?:\s|$)`)
+	// forceResetPattern matches the admin-only "Reset! force" modifier
+	forceResetPattern = regexp.MustCompile(`(?i)^reset!?\s+force\b`)
+)
+
+// runTriggerTS returns the timestamp identifying the event that started a backfill
+func runTriggerTS(event *Event) string {
+	if event.Event.Timestamp != "" {
+		return event.Event.Timestamp
+	}
+	return event.Event.EventTS
+}
+
+// recordCompletedRun stores a finished backfill in the run history
+func recordCompletedRun(event *Event, isInitialRecording bool, startedAt time.Time, rows int) {
+	run := runhistory.Run{
+		ChannelID:   event.Event.Channel,
+		TriggerTS:   runTriggerTS(event),
+		Initial:     isInitialRecording,
+		StartedAt:   startedAt,
+		CompletedAt: time.Now(),
+		Rows:        rows,
+	}
+	if err := runHistory.RecordCompleted(run); err != nil {
+		log.Printf("Warning: Could not record completed run for channel %s: %v", event.Event.Channel, err)
+	}
+}
+
+// replyIfAlreadyCompleted answers a redelivered "Reset!" mention whose backfill already finished
+// instead of starting a second one, and reports whether it did so
+func replyIfAlreadyCompleted(slackClient *Client, event *Event, lang string) bool {
+	run, completed, err := runHistory.Completed(event.Event.Channel, runTriggerTS(event))
+	if err != nil {
+		log.Printf("Warning: Could not read run history: %v", err)
+		return false
+	}
+	if !completed {
+		return false
+	}
+
+	log.Printf("Backfill for mention %s in channel %s already completed at %s (retry num: %d, reason: %s), not starting again",
+		event.Event.Timestamp, event.Event.Channel, run.CompletedAt.Format(time.RFC3339), event.RetryNum, event.RetryReason)
+
+	message := i18n.T(lang, i18n.KeyResetAlreadyCompleted, run.CompletedAt.In(jstLocation).Format("2006-01-02 15:04"), run.Rows)
+	if err := slackClient.SendMessage(event.Event.Channel, message); err != nil {
+		log.Printf("Error sending already-completed message: %v", err)
+	}
+	return true
+}
+
+// replyIfBackfillRunning answers a "Reset!" while the channel is already being backfilled, and reports whether it did so
+func replyIfBackfillRunning(slackClient *Client, event *Event, lang string) bool {
+	historyProgressMutex.Lock()
+	inProgress := historyInProgress[event.Event.Channel]
+	historyProgressMutex.Unlock()
+	if !inProgress {
+		return false
+	}
+
+	log.Printf("History retrieval already running for channel %s, ignoring reset request", event.Event.Channel)
+	message := i18n.T(lang, i18n.KeyResetBackfillRunning)
+	if err := slackClient.SendMessage(event.Event.Channel, message); err != nil {
+		log.Printf("Error sending in-progress message: %v", err)
+	}
+	return true
+}
+
+// skipIfInCooldown answers a re-invite instead of starting another full backfill when the channel is
+// being backfilled right now or was backfilled within the cooldown, and reports whether it did so.
+// "Reset!" is not affected, so a fresh import can still be requested explicitly.
+func skipIfInCooldown(cfg *config.Config, slackClient *Client, event *Event, channelInfo *ChannelInfo) bool {
+	historyProgressMutex.Lock()
+	inProgress := historyInProgress[event.Event.Channel]
+	historyProgressMutex.Unlock()
+	if inProgress {
+		log.Printf("History retrieval already running for channel %s, ignoring re-invite", event.Event.Channel)
+		return true
+	}
+
+	if cfg.BackfillCooldownHours <= 0 {
+		return false
+	}
+
+	run, exists, err := runHistory.Latest(event.Event.Channel)
+	if err != nil {
+		log.Printf("Warning: Could not read run history: %v", err)
+		return false
+	}
+	if !exists || time.Since(run.CompletedAt) >= time.Duration(cfg.BackfillCooldownHours)*time.Hour {
+		return false
+	}
+
+	log.Printf("Channel %s was backfilled at %s, within the %dh cooldown; skipping initial backfill",
+		event.Event.Channel, run.CompletedAt.Format(time.RFC3339), cfg.BackfillCooldownHours)
+
+	message := i18n.T(replyLanguage(cfg, slackClient, jobTriggeredBy(event)), i18n.KeyBackfillCooldown,
+		channelInfo.Name, run.CompletedAt.In(jstLocation).Format("2006-01-02 15:04"))
+	if err := slackClient.SendMessage(event.Event.Channel, message); err != nil {
+		log.Printf("Error sending cooldown message: %v", err)
+	}
+	return true
+}
@@ -0,0 +1,39 @@
+package slack
+
+import (
+	"fmt"
+	"log"
+
+	"slack-to-google-sheets-bot/internal/audit"
+	"slack-to-google-sheets-bot/internal/config"
+)
+
+// MigrateSheetSchemas brings the channel tabs of every spreadsheet to the current column layout at startup, in the
+// background; a tab written to before it is reached is migrated by that write instead
+func MigrateSheetSchemas(cfg *config.Config) {
+	if cfg.GoogleSheetsCredentials == "" || cfg.SpreadsheetID == "" {
+		return
+	}
+
+	go func() {
+		sheetsClient, err := newSheetsClient(cfg)
+		if err != nil {
+			log.Printf("Error creating Google Sheets client for schema migration: %v", err)
+			return
+		}
+		for _, spreadsheetID := range allSpreadsheetIDs(cfg) {
+			migrated, err := sheetsClient.MigrateChannelSheets(spreadsheetID)
+			if err != nil {
+				log.Printf("Error migrating the sheets of spreadsheet %s: %v", spreadsheetID, err)
+			}
+			if migrated == 0 {
+				continue
+			}
+			log.Printf("Migrated %d sheets of spreadsheet %s to schema %s", migrated, spreadsheetID, sheetsClient.SchemaVersion())
+			recordAudit(cfg, audit.Entry{
+				Action: "schema_migrated",
+				Detail: fmt.Sprintf("%d tabs of %s migrated to schema %s", migrated, spreadsheetID, sheetsClient.SchemaVersion()),
+			})
+		}
+	}()
+}
@@ -0,0 +1,106 @@
+package slack
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+	"unicode/utf8"
+
+	"slack-to-google-sheets-bot/internal/config"
+	"slack-to-google-sheets-bot/internal/sealedexport"
+	"slack-to-google-sheets-bot/internal/sheets"
+)
+
+const (
+	// sealedText replaces the message text in channels whose content goes to the encrypted archive
+	sealedText = "[本文は暗号化して保存されました: %d文字]"
+	// sealFailedText replaces the message text when the encrypted archive could not be written; the content is
+	// dropped rather than written in plain text
+	sealFailedText = "[本文は記録されません: 暗号化アーカイブへの保存に失敗しました]"
+)
+
+// sealedRecord is one line of the encrypted archive
+type sealedRecord struct {
+	Channel      string              `json:"channel"`
+	ChannelName  string              `json:"channel_name"`
+	MessageTS    string              `json:"ts"`
+	ThreadTS     string              `json:"thread_ts,omitempty"`
+	PostedAt     string              `json:"posted_at"`
+	User         string              `json:"user,omitempty"`
+	UserHandle   string              `json:"user_name"`
+	UserRealName string              `json:"user_real_name"`
+	Text         string              `json:"text"`
+	Files        []sheets.FileRecord `json:"files,omitempty"`
+	Edited       bool                `json:"edited,omitempty"`
+}
+
+// sealRecords writes the content of records in "encrypted" channels to the encrypted archive and leaves only their
+// metadata in the records: the text is replaced by its length, and shared files keep their type and size but not
+// their name or link
+func sealRecords(cfg *config.Config, records []*sheets.MessageRecord) {
+	byChannel := make(map[string][]*sheets.MessageRecord)
+	var channels []string
+	for _, record := range records {
+		if !cfg.RecordsEncrypted(record.Channel) {
+			continue
+		}
+		if _, exists := byChannel[record.Channel]; !exists {
+			channels = append(channels, record.Channel)
+		}
+		byChannel[record.Channel] = append(byChannel[record.Channel], record)
+	}
+
+	for _, channelID := range channels {
+		sealed := byChannel[channelID]
+		err := writeSealedRecords(channelID, sealed)
+		if err != nil {
+			log.Printf("Error writing %d messages of channel %s to the encrypted archive, recording metadata only: %v", len(sealed), channelID, err)
+		}
+		for _, record := range sealed {
+			if err != nil {
+				record.Text = sealFailedText
+			} else {
+				record.Text = fmt.Sprintf(sealedText, utf8.RuneCountInString(record.Text))
+			}
+			record.EditHistory = ""
+			record.Redacted = true
+			dropFileDetails(record)
+		}
+	}
+}
+
+// writeSealedRecords encrypts the content of records of one channel into a new archive file
+func writeSealedRecords(channelID string, records []*sheets.MessageRecord) error {
+	if !sealedexport.Enabled() {
+		return fmt.Errorf("ENCRYPTED_EXPORT_PUBLIC_KEY_FILE is not set")
+	}
+
+	lines := make([][]byte, 0, len(records))
+	for _, record := range records {
+		line, err := json.Marshal(sealedRecord{
+			Channel:      record.Channel,
+			ChannelName:  record.ChannelName,
+			MessageTS:    record.MessageTS,
+			ThreadTS:     record.ThreadTS,
+			PostedAt:     record.Timestamp.Format(time.RFC3339),
+			User:         record.User,
+			UserHandle:   record.UserHandle,
+			UserRealName: record.UserRealName,
+			Text:         record.Text,
+			Files:        record.Files,
+			Edited:       record.EditorID != "",
+		})
+		if err != nil {
+			return err
+		}
+		lines = append(lines, line)
+	}
+
+	path, err := sealedexport.Write(channelID, lines)
+	if err != nil {
+		return err
+	}
+	log.Printf("Wrote %d messages of channel %s to the encrypted archive %s", len(records), channelID, path)
+	return nil
+}
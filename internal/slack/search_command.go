@@ -0,0 +1,70 @@
+package slack
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+
+	"slack-to-google-sheets-bot/internal/config"
+	"slack-to-google-sheets-bot/internal/i18n"
+	"slack-to-google-sheets-bot/internal/search"
+)
+
+// searchResultLimit is the number of hits shown in reply to "@bot search"
+const searchResultLimit = 5
+
+var searchCommandPattern = regexp.MustCompile(`(?is)^search\s+(.+)`)
+
+// extractSearchQuery extracts the query from a "search <query>" command
+func extractSearchQuery(text string) string {
+	matches := searchCommandPattern.FindStringSubmatch(text)
+	if len(matches) > 1 {
+		return strings.TrimSpace(matches[1])
+	}
+	return ""
+}
+
+// handleSearchCommand replies with the best matching recorded messages of the channel
+func handleSearchCommand(cfg *config.Config, slackClient *Client, event *Event, channelInfo *ChannelInfo, query string) error {
+	lang := replyLanguage(cfg, slackClient, event.Event.User)
+
+	if !cfg.MessageStoreEnabled {
+		message := i18n.T(lang, i18n.KeySearchDisabled)
+		if err := slackClient.SendMessage(event.Event.Channel, message); err != nil {
+			log.Printf("Error sending search disabled message: %v", err)
+		}
+		return nil
+	}
+
+	if query == "" {
+		message := i18n.T(lang, i18n.KeySearchUsage)
+		if err := slackClient.SendMessage(event.Event.Channel, message); err != nil {
+			log.Printf("Error sending search usage message: %v", err)
+		}
+		return nil
+	}
+
+	results := search.Default().Search(query, event.Event.Channel, searchResultLimit)
+
+	var reply string
+	if len(results) == 0 {
+		reply = i18n.T(lang, i18n.KeySearchNoResults, query)
+	} else {
+		lines := []string{i18n.T(lang, i18n.KeySearchResults, query, len(results))}
+		for _, result := range results {
+			lines = append(lines, fmt.Sprintf("• %s %s: %s",
+				result.Record.Timestamp.Format("2006-01-02 15:04"),
+				result.Record.UserHandle,
+				truncateText(strings.ReplaceAll(result.Record.Text, "\n", " "), 80)))
+		}
+		reply = strings.Join(lines, "\n")
+	}
+
+	if err := slackClient.SendMessage(event.Event.Channel, reply); err != nil {
+		log.Printf("Error sending search results: %v", err)
+	}
+
+	log.Printf("Search in channel %s for %q returned %d results", channelInfo.Name, query, len(results))
+	return nil
+}
@@ -0,0 +1,89 @@
+package slack
+
+import (
+	"log"
+	"strconv"
+	"strings"
+
+	"slack-to-google-sheets-bot/internal/config"
+	"slack-to-google-sheets-bot/internal/settings"
+	"slack-to-google-sheets-bot/internal/sheets"
+)
+
+// recordingSettings returns the settings that change how the rows of a channel are written, by name
+func recordingSettings(cfg *config.Config, sheetsClient *sheets.Client, channelID string) map[string]string {
+	timestampFormat := cfg.TimestampFormat
+	if timestampFormat == "" {
+		timestampFormat = "default"
+	}
+	return map[string]string{
+		settings.KeyTimezone:   cfg.Location(channelID).String(),
+		settings.KeyRecordMode: cfg.RecordMode(channelID),
+		"timestamp_format":     timestampFormat,
+		"opt_out_policy":       cfg.OptOutPolicy,
+		"link_formulas":        strconv.FormatBool(cfg.LinkFormulas),
+		"normalize_text":       strconv.FormatBool(cfg.NormalizeRecordedText),
+		"columns":              strconv.Itoa(len(sheetsClient.ColumnHeaders())),
+	}
+}
+
+// settingChangeNote returns a note such as "--- 記録設定変更: timezone UTC→Asia/Tokyo ---" when the recording
+// settings of a channel changed since its rows were last written, or "" when they did not, and stores the current ones
+func settingChangeNote(cfg *config.Config, sheetsClient *sheets.Client, channelID string) string {
+	changes, err := settings.Recorded().Changes(channelID, recordingSettings(cfg, sheetsClient, channelID))
+	if err != nil {
+		log.Printf("Warning: Could not save the recording settings of channel %s: %v", channelID, err)
+	}
+	if len(changes) == 0 {
+		return ""
+	}
+
+	var described []string
+	for _, change := range changes {
+		described = append(described, change.String())
+	}
+	log.Printf("Recording settings of channel %s changed: %s", channelID, strings.Join(described, ", "))
+	return "--- 記録設定変更: " + strings.Join(described, ", ") + " ---"
+}
+
+// annotateSettingChanges notes a change of the recording settings on the row of a message just written, so readers
+// of the archive can tell why the formatting of the sheet changes there
+func annotateSettingChanges(cfg *config.Config, sheetsClient *sheets.Client, spreadsheetID, channelID, sheetName string, row int) {
+	note := settingChangeNote(cfg, sheetsClient, channelID)
+	if note == "" || row < 2 {
+		return
+	}
+	if err := sheetsClient.AddRowNote(spreadsheetID, sheetName, row, note); err != nil {
+		log.Printf("Warning: Could not annotate the setting change in %s: %v", sheetName, err)
+	}
+}
+
+// annotateSettingChangesAt notes a change of the recording settings on the row of the first of records appended to
+// a channel's tab; the row is only looked up when there is a change
+func annotateSettingChangesAt(cfg *config.Config, sheetsClient *sheets.Client, spreadsheetID string, records []*sheets.MessageRecord) {
+	if len(records) == 0 {
+		return
+	}
+	first := records[0]
+	note := settingChangeNote(cfg, sheetsClient, first.Channel)
+	if note == "" {
+		return
+	}
+
+	sheetName := sheets.SheetName(first.Channel, first.ChannelName)
+	row, err := sheetsClient.FindMessageRow(spreadsheetID, sheetName, first.MessageTS)
+	if err != nil || row < 2 {
+		log.Printf("Warning: Could not find the row of message %s to annotate the setting change: %v", first.MessageTS, err)
+		return
+	}
+	if err := sheetsClient.AddRowNote(spreadsheetID, sheetName, row, note); err != nil {
+		log.Printf("Warning: Could not annotate the setting change in %s: %v", sheetName, err)
+	}
+}
+
+// rememberRecordingSettings stores the settings a channel's whole tab was just written with, without a note
+func rememberRecordingSettings(cfg *config.Config, sheetsClient *sheets.Client, channelID string) {
+	if _, err := settings.Recorded().Changes(channelID, recordingSettings(cfg, sheetsClient, channelID)); err != nil {
+		log.Printf("Warning: Could not save the recording settings of channel %s: %v", channelID, err)
+	}
+}
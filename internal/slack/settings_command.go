@@ -0,0 +1,134 @@
+package slack
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"slack-to-google-sheets-bot/internal/audit"
+	"slack-to-google-sheets-bot/internal/config"
+	"slack-to-google-sheets-bot/internal/i18n"
+	"slack-to-google-sheets-bot/internal/settings"
+)
+
+// settingsCommandPattern matches "get [key]", "set <key> <value>" and "unset <key>" right after the bot mention
+var settingsCommandPattern = regexp.MustCompile(`(?is)^(get|set|unset)\b\s*(\S*)\s*(.*?)\s*$`)
+
+// effectiveSetting describes the value currently in effect for a channel setting
+func effectiveSetting(cfg *config.Config, channelID, key string) string {
+	switch key {
+	case settings.KeyTimezone:
+		return cfg.Location(channelID).String()
+	case settings.KeyRecordingSchedule:
+		if spec := cfg.RecordingSchedule(channelID); spec != "" {
+			return spec
+		}
+		return "(always)"
+	case settings.KeyAlertThreshold:
+		if threshold := cfg.MessageAlertThreshold(channelID); threshold > 0 {
+			return strconv.Itoa(threshold)
+		}
+		return "(off)"
+	case settings.KeyNotificationTarget:
+		return cfg.NotificationTarget(channelID)
+	case settings.KeyNotificationVerbosity:
+		return cfg.NotificationVerbosityFor(channelID)
+	case settings.KeyRecordMode:
+		return cfg.RecordMode(channelID)
+	}
+	return ""
+}
+
+// handleSettingsCommand shows or changes per-channel settings stored in the local settings file
+func handleSettingsCommand(cfg *config.Config, slackClient *Client, event *Event, channelInfo *ChannelInfo, command string) error {
+	lang := replyLanguage(cfg, slackClient, event.Event.User)
+	channelID := event.Event.Channel
+
+	matches := settingsCommandPattern.FindStringSubmatch(command)
+	if len(matches) != 4 {
+		return nil
+	}
+	action, key, value := strings.ToLower(matches[1]), strings.ToLower(matches[2]), matches[3]
+
+	// Anyone can view the settings; changing them is up to admins and the channel's creator. The record mode decides
+	// whether content may be logged at all, so only admins can lift metadata-only or encrypted recording.
+	if action != "get" {
+		denied := ""
+		if !cfg.IsAdmin(event.Event.User) {
+			if key == settings.KeyRecordMode {
+				denied = i18n.T(lang, i18n.KeySettingsAdminOnly, key)
+			} else if channelInfo.Creator == "" || channelInfo.Creator != event.Event.User {
+				denied = i18n.T(lang, i18n.KeySettingsOwnerOnly)
+			}
+		}
+		if denied != "" {
+			recordAudit(cfg, audit.Entry{Action: "setting_denied", ChannelID: channelID, User: event.Event.User, Detail: strings.TrimSpace(action + " " + key + " " + value)})
+			if err := slackClient.SendMessage(channelID, denied); err != nil {
+				log.Printf("Error sending settings reply: %v", err)
+			}
+			return nil
+		}
+	}
+
+	var reply string
+	switch action {
+	case "get":
+		keys := settings.Keys
+		if key != "" {
+			keys = []string{key}
+		}
+		var lines []string
+		for _, k := range keys {
+			if !settings.IsKnownKey(k) {
+				lines = append(lines, fmt.Sprintf("• %s: (unknown)", k))
+				continue
+			}
+			lines = append(lines, fmt.Sprintf("• `%s`: %s", k, effectiveSetting(cfg, channelID, k)))
+		}
+		reply = i18n.T(lang, i18n.KeySettingsList, strings.Join(lines, "\n"))
+
+	case "set":
+		if err := settings.Validate(key, value); err != nil {
+			log.Printf("Rejected setting %s=%q for channel %s: %v", key, value, channelID, err)
+			reply = i18n.T(lang, i18n.KeySettingsInvalid, err)
+			break
+		}
+		previous := effectiveSetting(cfg, channelID, key)
+		if _, err := settings.Default().Set(channelID, key, value); err != nil {
+			log.Printf("Error saving setting %s for channel %s: %v", key, channelID, err)
+			reply = i18n.T(lang, i18n.KeySettingsSaveFailed)
+			break
+		}
+		recordSettingAudit(cfg, channelID, event.Event.User, fmt.Sprintf("%s: %s -> %s", key, previous, value))
+		reply = i18n.T(lang, i18n.KeySettingsUpdated, key, value)
+
+	case "unset":
+		if !settings.IsKnownKey(key) {
+			reply = i18n.T(lang, i18n.KeySettingsInvalid, fmt.Errorf("unknown setting %q (supported: %s)", key, strings.Join(settings.Keys, ", ")))
+			break
+		}
+		previous := effectiveSetting(cfg, channelID, key)
+		if _, err := settings.Default().Unset(channelID, key); err != nil {
+			log.Printf("Error removing setting %s for channel %s: %v", key, channelID, err)
+			reply = i18n.T(lang, i18n.KeySettingsSaveFailed)
+			break
+		}
+		current := effectiveSetting(cfg, channelID, key)
+		recordSettingAudit(cfg, channelID, event.Event.User, fmt.Sprintf("%s: %s -> %s (unset)", key, previous, current))
+		reply = i18n.T(lang, i18n.KeySettingsRemoved, key, current)
+	}
+
+	if err := slackClient.SendMessage(channelID, reply); err != nil {
+		log.Printf("Error sending settings reply: %v", err)
+	}
+
+	log.Printf("Settings command %q handled for channel %s", action, channelInfo.Name)
+	return nil
+}
+
+// recordSettingAudit writes a setting change to the audit log
+func recordSettingAudit(cfg *config.Config, channelID, userID, detail string) {
+	recordAudit(cfg, audit.Entry{Action: "setting_changed", ChannelID: channelID, User: userID, Detail: detail})
+}
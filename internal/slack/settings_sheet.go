@@ -0,0 +1,66 @@
+package slack
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"slack-to-google-sheets-bot/internal/config"
+	"slack-to-google-sheets-bot/internal/settings"
+)
+
+// StartSettingsSheetSync periodically reads per-channel settings from the settings tab of the spreadsheet
+func StartSettingsSheetSync(cfg *config.Config) {
+	if !cfg.SettingsSheetEnabled || cfg.GoogleSheetsCredentials == "" || cfg.SpreadsheetID == "" {
+		return
+	}
+
+	interval := time.Duration(cfg.SettingsSheetRefreshMinutes) * time.Minute
+	if interval <= 0 {
+		interval = 10 * time.Minute
+	}
+
+	go func() {
+		lastReport := ""
+		for {
+			lastReport = syncSettingsSheet(cfg, lastReport)
+			time.Sleep(interval)
+		}
+	}()
+}
+
+// syncSettingsSheet reads and validates the settings tab once; invalid rows are reported to the admin channel
+// when they change, and the report is returned so unchanged problems are not reported again
+func syncSettingsSheet(cfg *config.Config, lastReport string) string {
+	sheetsClient, err := newSheetsClient(cfg)
+	if err != nil {
+		log.Printf("Error creating Google Sheets client for settings sheet: %v", err)
+		return lastReport
+	}
+
+	rows, err := sheetsClient.ReadSettingsRows(cfg.SpreadsheetID, cfg.SettingsSheetName)
+	if err != nil {
+		log.Printf("Error reading settings sheet %s: %v", cfg.SettingsSheetName, err)
+		return lastReport
+	}
+
+	values, errs := settings.ParseRows(rows)
+	settings.Default().ReplaceSheetSettings(values)
+	log.Printf("Loaded settings for %d channels from settings sheet %s", len(values), cfg.SettingsSheetName)
+
+	var problems []string
+	for _, err := range errs {
+		log.Printf("Warning: invalid settings sheet entry: %v", err)
+		problems = append(problems, "• "+err.Error())
+	}
+	report := strings.Join(problems, "\n")
+
+	if report != "" && report != lastReport && cfg.AdminChannelID != "" {
+		message := fmt.Sprintf("⚠️ 設定シート「%s」に無効な行があるため無視しました:\n%s", cfg.SettingsSheetName, report)
+		if err := newClient(cfg).SendMessage(cfg.AdminChannelID, message); err != nil {
+			log.Printf("Error sending settings sheet report: %v", err)
+		}
+	}
+	return report
+}
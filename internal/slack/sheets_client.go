@@ -0,0 +1,236 @@
+package slack
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"slack-to-google-sheets-bot/internal/config"
+	"slack-to-google-sheets-bot/internal/identity"
+	"slack-to-google-sheets-bot/internal/sheets"
+	"slack-to-google-sheets-bot/internal/textnorm"
+)
+
+// metadataOnlyText replaces the message text in channels that record metadata only
+const metadataOnlyText = "[本文は記録されません: %d文字]"
+
+var (
+	identityResolver     identity.Resolver
+	identityResolverOnce sync.Once
+)
+
+// newSheetsClient creates a Google Sheets client with the optional columns enabled by the configuration
+func newSheetsClient(cfg *config.Config) (*sheets.Client, error) {
+	sheetsClient, err := sheets.NewClient(cfg.GoogleSheetsCredentials)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.TimestampFormat == sheets.TimestampFormatISO8601 {
+		sheetsClient.UseISO8601Timestamps()
+	}
+	if cfg.LinkFormulas {
+		sheetsClient.UseLinkFormulas()
+	}
+
+	if cfg.RecordProfileFields {
+		sheetsClient.AddColumns(sheets.ColumnUserTitle, sheets.ColumnUserTeam)
+	}
+	if cfg.IdentityResolver != "" {
+		sheetsClient.AddColumns(sheets.ColumnEmployeeID)
+	}
+	if cfg.RecordClientMetadata {
+		sheetsClient.AddColumns(sheets.ColumnAppSource, sheets.ColumnEditedBy)
+	}
+	if cfg.RecordTextStats {
+		sheetsClient.AddColumns(sheets.ColumnCharCount, sheets.ColumnWordCount)
+	}
+	if cfg.RecordReactions {
+		sheetsClient.AddColumns(sheets.ColumnReactions)
+	}
+	if cfg.RecordEngagement {
+		sheetsClient.AddColumns(sheets.ColumnReplyCount, sheets.ColumnReactionCount)
+	}
+	if cfg.RecordFiles {
+		sheetsClient.AddColumns(sheets.ColumnFileName, sheets.ColumnFileType, sheets.ColumnFileSize, sheets.ColumnFilePermalink)
+	}
+	if cfg.RecordPins {
+		sheetsClient.AddColumns(sheets.ColumnPinned)
+	}
+	if cfg.RecordTraceID {
+		sheetsClient.AddColumns(sheets.ColumnTraceID)
+	}
+	if cfg.RecordPermalinks {
+		sheetsClient.AddColumns(sheets.ColumnPermalink)
+	}
+	if cfg.RecordDeletions {
+		sheetsClient.AddColumns(sheets.ColumnStatus)
+	}
+	if cfg.RecordEditHistory {
+		sheetsClient.AddColumns(sheets.ColumnEditHistory)
+	}
+	if cfg.RecordTeamID {
+		sheetsClient.AddColumns(sheets.ColumnTeamID)
+	}
+
+	return sheetsClient, nil
+}
+
+// getIdentityResolver lazily creates the configured identity resolver (nil when disabled or misconfigured)
+func getIdentityResolver(cfg *config.Config) identity.Resolver {
+	identityResolverOnce.Do(func() {
+		if cfg.IdentityResolver == "" {
+			return
+		}
+		resolver, err := identity.NewResolver(cfg.IdentityResolver, cfg.IdentitySource)
+		if err != nil {
+			log.Printf("Error creating identity resolver: %v", err)
+			return
+		}
+		identityResolver = resolver
+	})
+	return identityResolver
+}
+
+// enrichRecords fills in the optional per-user columns and normalizes the text of records before they are written
+func enrichRecords(cfg *config.Config, slackClient *Client, records []*sheets.MessageRecord) {
+	enrichRecordsWithProfile(cfg, slackClient, records)
+	enrichRecordsWithEmployeeID(cfg, records)
+	enrichRecordsWithEditor(cfg, slackClient, records)
+	enrichRecordsWithPermalink(cfg, slackClient, records)
+
+	if cfg.NormalizeRecordedText {
+		for _, record := range records {
+			record.Text = textnorm.Normalize(record.Text)
+		}
+	}
+
+	// Show timestamps in the channel's configured timezone
+	for _, record := range records {
+		record.Timestamp = record.Timestamp.In(cfg.Location(record.Channel))
+	}
+
+	sealRecords(cfg, records)
+	minimizeRecords(cfg, records)
+}
+
+// minimizeRecords drops the content of records in channels that record metadata only; the text is replaced by its
+// length, the edit history is cleared, and shared files keep their type and size but not their name or link
+func minimizeRecords(cfg *config.Config, records []*sheets.MessageRecord) {
+	for _, record := range records {
+		if !cfg.RecordsMetadataOnly(record.Channel) {
+			continue
+		}
+		record.Text = fmt.Sprintf(metadataOnlyText, utf8.RuneCountInString(record.Text))
+		record.EditHistory = ""
+		record.Redacted = true
+		dropFileDetails(record)
+	}
+}
+
+// dropFileDetails leaves the type and size of a record's files but not their name or link
+func dropFileDetails(record *sheets.MessageRecord) {
+	for i := range record.Files {
+		record.Files[i].Name = ""
+		record.Files[i].Permalink = ""
+	}
+}
+
+// enrichRecordsWithProfile fills in the profile columns (title, team) of records when enabled
+func enrichRecordsWithProfile(cfg *config.Config, slackClient *Client, records []*sheets.MessageRecord) {
+	if !cfg.RecordProfileFields {
+		return
+	}
+
+	for _, record := range records {
+		if record.User == "" {
+			continue // Bots and system messages have no profile
+		}
+
+		userInfo, err := slackClient.GetUserInfo(record.User)
+		if err != nil {
+			log.Printf("Error getting profile for %s: %v", record.User, err)
+			continue
+		}
+		record.UserTitle = userInfo.Profile.Title
+
+		if cfg.ProfileTeamFieldID != "" {
+			team, err := slackClient.GetUserCustomField(record.User, cfg.ProfileTeamFieldID)
+			if err != nil {
+				log.Printf("Error getting team field for %s: %v", record.User, err)
+				continue
+			}
+			record.UserTeam = team
+		}
+	}
+}
+
+// enrichRecordsWithEmployeeID fills in the employee ID column of records using the identity resolver
+func enrichRecordsWithEmployeeID(cfg *config.Config, records []*sheets.MessageRecord) {
+	resolver := getIdentityResolver(cfg)
+	if resolver == nil {
+		return
+	}
+
+	for _, record := range records {
+		if record.User == "" {
+			continue
+		}
+
+		employeeID, err := resolver.Resolve(record.User)
+		if err != nil {
+			log.Printf("Error resolving employee ID for %s: %v", record.User, err)
+			continue
+		}
+		record.EmployeeID = employeeID
+	}
+}
+
+// enrichRecordsWithPermalink fills in the Slack link of records when enabled. Links are built from the workspace
+// URL instead of calling chat.getPermalink, which would cost one rate-limited call per message of a backfill.
+func enrichRecordsWithPermalink(cfg *config.Config, slackClient *Client, records []*sheets.MessageRecord) {
+	if !cfg.RecordPermalinks {
+		return
+	}
+
+	workspaceURL, err := slackClient.WorkspaceURL()
+	if err != nil {
+		log.Printf("Error getting workspace URL for message links: %v", err)
+		return
+	}
+	for _, record := range records {
+		record.Permalink = messagePermalink(workspaceURL, record.Channel, record.MessageTS, record.ThreadTS)
+	}
+}
+
+// messagePermalink builds the link Slack's "Copy link" gives for a message; replies link into their thread
+func messagePermalink(workspaceURL, channelID, messageTS, threadTS string) string {
+	link := fmt.Sprintf("%sarchives/%s/p%s", workspaceURL, channelID, strings.Replace(messageTS, ".", "", 1))
+	if threadTS != "" && threadTS != messageTS {
+		link += fmt.Sprintf("?thread_ts=%s&cid=%s", threadTS, channelID)
+	}
+	return link
+}
+
+// enrichRecordsWithEditor resolves the last editor of records to a handle name when client metadata is recorded
+func enrichRecordsWithEditor(cfg *config.Config, slackClient *Client, records []*sheets.MessageRecord) {
+	if !cfg.RecordClientMetadata {
+		return
+	}
+
+	for _, record := range records {
+		if record.EditorID == "" {
+			continue
+		}
+
+		userInfo, err := slackClient.GetUserInfo(record.EditorID)
+		if err != nil {
+			log.Printf("Error getting editor info for %s: %v", record.EditorID, err)
+			record.EditedBy = record.EditorID
+			continue
+		}
+		record.EditedBy = userInfo.Name
+	}
+}
@@ -0,0 +1,167 @@
+package slack
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+
+	"slack-to-google-sheets-bot/internal/config"
+)
+
+// sharedChannelRefusedMessage is posted when a Slack Connect channel is not recorded because of RECORD_SHARED_CHANNELS
+const sharedChannelRefusedMessage = "🔒 このチャンネルは外部の組織と共有されている（Slack Connect）ため、設定により記録しません。\n" +
+	"記録が必要な場合は管理者にお問い合わせください。"
+
+// MessageUserProfile is the author profile Slack sends with messages of users from other organizations
+type MessageUserProfile struct {
+	Name        string `json:"name"`
+	RealName    string `json:"real_name"`
+	DisplayName string `json:"display_name"`
+	Team        string `json:"team"`
+}
+
+// userInfo returns the author described by the profile, for when users.info cannot see the user
+func (p *MessageUserProfile) userInfo(userID string) *UserInfo {
+	name := p.Name
+	if name == "" {
+		name = p.DisplayName
+	}
+	if name == "" {
+		name = "Unknown"
+	}
+	realName := p.RealName
+	if realName == "" {
+		realName = name
+	}
+	return &UserInfo{ID: userID, Name: name, RealName: realName, TeamID: p.Team}
+}
+
+// externalAuthor returns the author of a message users.info cannot see, from the profile in the message or, without
+// one, from the author's workspace alone
+func externalAuthor(userID, userTeam string, userProfile *MessageUserProfile) *UserInfo {
+	if userProfile == nil {
+		userProfile = &MessageUserProfile{}
+	}
+	userInfo := userProfile.userInfo(userID)
+	if userInfo.TeamID == "" {
+		userInfo.TeamID = userTeam
+	}
+	return userInfo
+}
+
+// isUserNotFoundError reports whether users.info failed because the user belongs to an organization the bot cannot see
+func isUserNotFoundError(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "user_not_found")
+}
+
+// EnterpriseUser is the Enterprise Grid organization of a user
+type EnterpriseUser struct {
+	EnterpriseID string `json:"enterprise_id"`
+}
+
+// TeamInfoResponse is the response of team.info
+type TeamInfoResponse struct {
+	OK   bool `json:"ok"`
+	Team struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"team"`
+}
+
+// refusesSharedChannel reports whether a channel is shared with another organization (Slack Connect) and
+// RECORD_SHARED_CHANNELS=false keeps it from being recorded
+func refusesSharedChannel(cfg *config.Config, channelInfo *ChannelInfo) bool {
+	if cfg.RecordSharedChannels || channelInfo == nil || !channelInfo.IsExtShared {
+		return false
+	}
+	log.Printf("Channel %s (#%s) is shared with another organization, not recording it (RECORD_SHARED_CHANNELS=false)", channelInfo.ID, channelInfo.Name)
+	return true
+}
+
+// labelExternalUser returns the user with "(external)" appended to the handle and "(external: organization)" to the
+// real name when they belong to another organization than the bot; users of other workspaces of the same Enterprise
+// Grid are not external
+func (c *Client) labelExternalUser(userInfo *UserInfo) *UserInfo {
+	if userInfo.TeamID == "" {
+		return userInfo
+	}
+
+	botIdentitiesMutex.Lock()
+	identity, exists := botIdentities[c.token]
+	if !exists || identity.teamID == "" {
+		if err := c.authTest(); err != nil {
+			botIdentitiesMutex.Unlock()
+			log.Printf("Warning: Could not resolve the bot's workspace to label external users: %v", err)
+			return userInfo
+		}
+		identity = botIdentities[c.token]
+	}
+	teamID, enterpriseID := identity.teamID, identity.enterpriseID
+	botIdentitiesMutex.Unlock()
+
+	if userInfo.TeamID == teamID {
+		return userInfo
+	}
+	if enterpriseID != "" && userInfo.EnterpriseUser != nil && userInfo.EnterpriseUser.EnterpriseID == enterpriseID {
+		return userInfo
+	}
+
+	// The cached user is shared, so the label goes on a copy
+	labeled := *userInfo
+	labeled.Name = userInfo.Name + " (external)"
+	labeled.RealName = fmt.Sprintf("%s (external: %s)", userInfo.RealName, c.teamName(userInfo.TeamID))
+	return &labeled
+}
+
+// teamName returns the name of a workspace from team.info, or its ID when it cannot be looked up (e.g. without the
+// team:read scope)
+func (c *Client) teamName(teamID string) string {
+	if name, exists := c.teamNameCache[teamID]; exists {
+		return name
+	}
+
+	name := teamID
+	var teamResp TeamInfoResponse
+	err := retryWithBackoff(func() error {
+		req, err := http.NewRequest("GET", apiBaseURL+"team.info?team="+teamID, nil)
+		if err != nil {
+			return err
+		}
+
+		req.Header.Set("Authorization", "Bearer "+c.token)
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return err
+		}
+
+		teamResp = TeamInfoResponse{}
+		if err := json.Unmarshal(body, &teamResp); err != nil {
+			return err
+		}
+
+		if !teamResp.OK {
+			return fmt.Errorf("slack API error: %s", string(body))
+		}
+
+		return nil
+	}, fmt.Sprintf("get team info for %s", teamID))
+	if err != nil {
+		log.Printf("Warning: Could not get the name of workspace %s: %v", teamID, err)
+	} else if teamResp.Team.Name != "" {
+		name = teamResp.Team.Name
+	}
+
+	// Failures are cached too, so a missing scope does not cost a call per message
+	c.teamNameCache[teamID] = name
+	return name
+}
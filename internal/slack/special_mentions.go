@@ -0,0 +1,75 @@
+package slack
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// specialMentionRe matches <!here>, <!channel> and <!everyone>, with or without a label such as <!here|here>
+	specialMentionRe = regexp.MustCompile(`<!(here|channel|everyone)(?:\|[^>]*)?>`)
+	// dateTokenRe matches <!date^unix_seconds^format^optional_link|fallback text>
+	dateTokenRe = regexp.MustCompile(`<!date\^(\d+)\^([^^|>]*)(?:\^[^|>]*)?(?:\|([^>]*))?>`)
+)
+
+// formatSpecialMentions replaces special mentions with @here / @channel / @everyone and date tokens with the date
+// they stand for in JST, written the way Slack shows them
+func formatSpecialMentions(text string) string {
+	if !strings.Contains(text, "<!") {
+		return text
+	}
+
+	text = specialMentionRe.ReplaceAllString(text, "@$1")
+	return dateTokenRe.ReplaceAllStringFunc(text, func(match string) string {
+		groups := dateTokenRe.FindStringSubmatch(match)
+		seconds, err := strconv.ParseInt(groups[1], 10, 64)
+		if err != nil {
+			if groups[3] != "" {
+				return groups[3]
+			}
+			return match // Keep original if it cannot be read
+		}
+		return formatDateToken(time.Unix(seconds, 0).In(jstLocation), groups[2])
+	})
+}
+
+// formatDateToken fills in the {date...} and {time...} placeholders of a date token's format. The "pretty" variants
+// ("Today", "Yesterday") and {ago} depend on when the message is read, so the sheet gets the absolute date instead.
+func formatDateToken(t time.Time, format string) string {
+	date := fmt.Sprintf("%s %s, %d", t.Format("January"), ordinal(t.Day()), t.Year())
+	dateShort := t.Format("Jan 2, 2006")
+	dateLong := fmt.Sprintf("%s, %s", t.Format("Monday"), date)
+
+	return strings.NewReplacer(
+		"{date_num}", t.Format("2006-01-02"),
+		"{date_slash}", t.Format("01/02/2006"),
+		"{date_long_pretty}", dateLong,
+		"{date_long}", dateLong,
+		"{date_short_pretty}", dateShort,
+		"{date_short}", dateShort,
+		"{date_pretty}", date,
+		"{date}", date,
+		"{time_secs}", t.Format("3:04:05 PM"),
+		"{time}", t.Format("3:04 PM"),
+		"{ago}", t.Format("2006-01-02 15:04"),
+	).Replace(format)
+}
+
+// ordinal returns a day of the month with its English suffix, e.g. 1st, 2nd, 11th, 23rd
+func ordinal(day int) string {
+	suffix := "th"
+	if day < 11 || day > 13 {
+		switch day % 10 {
+		case 1:
+			suffix = "st"
+		case 2:
+			suffix = "nd"
+		case 3:
+			suffix = "rd"
+		}
+	}
+	return fmt.Sprintf("%d%s", day, suffix)
+}
@@ -0,0 +1,91 @@
+package slack
+
+import (
+	"log"
+	"sync"
+
+	"slack-to-google-sheets-bot/internal/config"
+	"slack-to-google-sheets-bot/internal/sheets"
+)
+
+var (
+	groupClient     *sheets.GroupClient
+	groupClientErr  error
+	groupClientOnce sync.Once
+)
+
+// getGroupClient lazily creates the Directory API client used to add people to groups
+func getGroupClient(cfg *config.Config) (*sheets.GroupClient, error) {
+	groupClientOnce.Do(func() {
+		groupClient, groupClientErr = sheets.NewGroupClient(cfg.GoogleSheetsCredentials, cfg.GroupAdminEmail)
+	})
+	return groupClient, groupClientErr
+}
+
+// spreadsheetGroup returns the Google Group of a spreadsheet; continuations use the group of the original
+func spreadsheetGroup(cfg *config.Config, spreadsheetID string) string {
+	if group := cfg.SpreadsheetGroup(spreadsheetID); group != "" || spreadsheetID == cfg.SpreadsheetID {
+		return group
+	}
+	return cfg.SpreadsheetGroup(cfg.SpreadsheetID)
+}
+
+// EnsureSpreadsheetGroups grants each spreadsheet's Google Group read access at startup, in the background
+func EnsureSpreadsheetGroups(cfg *config.Config) {
+	if len(cfg.SpreadsheetGroups) == 0 || cfg.GoogleSheetsCredentials == "" || cfg.SpreadsheetID == "" {
+		return
+	}
+
+	go func() {
+		sheetsClient, err := newSheetsClient(cfg)
+		if err != nil {
+			log.Printf("Error creating Google Sheets client for group access: %v", err)
+			return
+		}
+		for _, spreadsheetID := range allSpreadsheetIDs(cfg) {
+			shareWithGroup(cfg, sheetsClient, spreadsheetID)
+		}
+	}()
+}
+
+// shareWithGroup grants the Google Group of a spreadsheet read access, if one is configured
+func shareWithGroup(cfg *config.Config, sheetsClient *sheets.Client, spreadsheetID string) {
+	group := spreadsheetGroup(cfg, spreadsheetID)
+	if group == "" {
+		return
+	}
+	if err := sheetsClient.ShareWithGroup(spreadsheetID, group); err != nil {
+		log.Printf("Error sharing spreadsheet %s with group %s: %v", spreadsheetID, group, err)
+	}
+}
+
+// grantReadAccess gives a person read access to the spreadsheets: through their Google Group when one is configured
+// and GROUP_ADMIN_EMAIL allows managing its members, or by sharing each spreadsheet directly. It returns the groups
+// the person was added to and whether any spreadsheet was shared directly.
+func grantReadAccess(cfg *config.Config, sheetsClient *sheets.Client, email string) (groups []string, shared bool, err error) {
+	added := make(map[string]bool)
+	for _, spreadsheetID := range allSpreadsheetIDs(cfg) {
+		group := spreadsheetGroup(cfg, spreadsheetID)
+		if group == "" || cfg.GroupAdminEmail == "" {
+			if err := sheetsClient.ShareSpreadsheet(spreadsheetID, email); err != nil {
+				return groups, shared, err
+			}
+			shared = true
+			continue
+		}
+		if added[group] {
+			continue
+		}
+
+		client, err := getGroupClient(cfg)
+		if err != nil {
+			return groups, shared, err
+		}
+		if err := client.AddMember(group, email); err != nil {
+			return groups, shared, err
+		}
+		added[group] = true
+		groups = append(groups, group)
+	}
+	return groups, shared, nil
+}
@@ -0,0 +1,120 @@
+package slack
+
+import (
+	"fmt"
+	"log"
+	"sync"
+
+	"slack-to-google-sheets-bot/internal/audit"
+	"slack-to-google-sheets-bot/internal/config"
+	"slack-to-google-sheets-bot/internal/sheetroute"
+	"slack-to-google-sheets-bot/internal/sheets"
+)
+
+// spreadsheetRoutes remembers which spreadsheet each channel is recorded in once the spreadsheet is split
+var spreadsheetRoutes = sheetroute.NewStore()
+
+// routingMutex serializes channel assignment so concurrent new channels do not create two continuations
+var routingMutex sync.Mutex
+
+// channelSpreadsheetID returns the spreadsheet a channel is recorded in.
+// Channels keep the spreadsheet that already has their tab; new channels go to the latest spreadsheet,
+// and a continuation spreadsheet is created first when the latest one has reached the tab or cell threshold.
+func channelSpreadsheetID(cfg *config.Config, sheetsClient *sheets.Client, channelID string) string {
+	if !cfg.SpreadsheetSplitEnabled {
+		return cfg.SpreadsheetID
+	}
+
+	if spreadsheetID, exists, err := spreadsheetRoutes.SpreadsheetFor(channelID); err != nil {
+		log.Printf("Warning: Could not read spreadsheet routes: %v", err)
+		return cfg.SpreadsheetID
+	} else if exists {
+		return spreadsheetID
+	}
+
+	routingMutex.Lock()
+	defer routingMutex.Unlock()
+
+	// Another event may have assigned the channel while we waited
+	if spreadsheetID, exists, err := spreadsheetRoutes.SpreadsheetFor(channelID); err == nil && exists {
+		return spreadsheetID
+	}
+
+	candidates := allSpreadsheetIDs(cfg)
+	for _, spreadsheetID := range candidates {
+		hasSheet, err := sheetsClient.HasChannelSheet(spreadsheetID, channelID)
+		if err != nil {
+			log.Printf("Warning: Could not look up channel %s in spreadsheet %s: %v", channelID, spreadsheetID, err)
+			return cfg.SpreadsheetID
+		}
+		if hasSheet {
+			assignSpreadsheet(channelID, spreadsheetID)
+			return spreadsheetID
+		}
+	}
+
+	current := candidates[len(candidates)-1]
+	tabs, cells, err := sheetsClient.SpreadsheetUsage(current)
+	if err != nil {
+		log.Printf("Warning: Could not check usage of spreadsheet %s: %v", current, err)
+	} else if tabs >= cfg.SpreadsheetSplitMaxTabs || cells >= cfg.SpreadsheetSplitMaxCells {
+		log.Printf("Spreadsheet %s has %d tabs and %d cells, creating a continuation", current, tabs, cells)
+		if continuationID, err := createContinuation(cfg, sheetsClient, len(candidates)+1, tabs, cells); err != nil {
+			log.Printf("Error creating continuation spreadsheet: %v", err)
+		} else {
+			current = continuationID
+		}
+	}
+
+	assignSpreadsheet(channelID, current)
+	return current
+}
+
+// assignSpreadsheet persists the spreadsheet of a channel
+func assignSpreadsheet(channelID, spreadsheetID string) {
+	if err := spreadsheetRoutes.Assign(channelID, spreadsheetID); err != nil {
+		log.Printf("Warning: Could not save spreadsheet route for channel %s: %v", channelID, err)
+	}
+}
+
+// createContinuation creates the next spreadsheet, links it from the index tab of the original and records it
+func createContinuation(cfg *config.Config, sheetsClient *sheets.Client, number, tabs int, cells int64) (string, error) {
+	continuationID, err := sheetsClient.CreateContinuation(cfg.SpreadsheetID, number)
+	if err != nil {
+		return "", err
+	}
+	if err := spreadsheetRoutes.AddContinuation(continuationID); err != nil {
+		return "", err
+	}
+	shareWithGroup(cfg, sheetsClient, continuationID)
+
+	note := fmt.Sprintf("前のスプレッドシートが上限に近づいたため作成（%d タブ / %d セル）", tabs, cells)
+	if err := sheetsClient.AppendSpreadsheetIndexRow(cfg.SpreadsheetID, cfg.SpreadsheetIndexSheetName, continuationID, note); err != nil {
+		log.Printf("Warning: Could not link continuation spreadsheet from the index tab: %v", err)
+	}
+
+	recordAudit(cfg, audit.Entry{
+		Action: "spreadsheet_split",
+		Detail: fmt.Sprintf("created continuation spreadsheet %s (%d tabs, %d cells)", continuationID, tabs, cells),
+	})
+	log.Printf("Created continuation spreadsheet %s", continuationID)
+	return continuationID, nil
+}
+
+// allSpreadsheetIDs returns the original spreadsheet followed by its continuations, oldest first
+func allSpreadsheetIDs(cfg *config.Config) []string {
+	spreadsheetIDs := []string{cfg.SpreadsheetID}
+	if !cfg.SpreadsheetSplitEnabled {
+		return spreadsheetIDs
+	}
+
+	continuations, err := spreadsheetRoutes.Continuations()
+	if err != nil {
+		log.Printf("Warning: Could not read spreadsheet routes: %v", err)
+		return spreadsheetIDs
+	}
+	for _, continuation := range continuations {
+		spreadsheetIDs = append(spreadsheetIDs, continuation.SpreadsheetID)
+	}
+	return spreadsheetIDs
+}
@@ -0,0 +1,133 @@
+package slack
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// statusUpdateInterval throttles chat.update calls for progress changes to stay within Slack rate limits
+const statusUpdateInterval = 5 * time.Second
+
+// statusMessage is the Block Kit message that started a job; it shows live progress
+// and its thread receives the job's detailed progress and completion messages
+type statusMessage struct {
+	ts          string
+	title       string
+	lastUpdated time.Time
+}
+
+// statusThreads maps channel IDs to the status message of the job currently running there
+var (
+	statusThreads      = make(map[string]*statusMessage)
+	statusThreadsMutex sync.Mutex
+)
+
+// startStatusThread posts the message that starts a job and remembers it as the channel's status thread
+func startStatusThread(slackClient *Client, channelID, text string) error {
+	blocks := []Block{
+		sectionBlock(text),
+		contextBlock("状態: 準備中"),
+	}
+	ts, err := slackClient.PostBlocks(channelID, text, blocks)
+	if err != nil {
+		return err
+	}
+
+	statusThreadsMutex.Lock()
+	defer statusThreadsMutex.Unlock()
+	if ts != "" {
+		statusThreads[channelID] = &statusMessage{ts: ts, title: text}
+	}
+	return nil
+}
+
+// statusThreadTS returns the channel's current status thread, or "" if no job thread is active
+func statusThreadTS(channelID string) string {
+	statusThreadsMutex.Lock()
+	defer statusThreadsMutex.Unlock()
+	if status, exists := statusThreads[channelID]; exists {
+		return status.ts
+	}
+	return ""
+}
+
+// endStatusThread forgets the channel's status thread once its job has finished
+func endStatusThread(channelID string) {
+	statusThreadsMutex.Lock()
+	defer statusThreadsMutex.Unlock()
+	delete(statusThreads, channelID)
+}
+
+// sendStatusMessage posts a job status message into the channel's status thread, or top-level if there is none
+func sendStatusMessage(slackClient *Client, channelID, text string) error {
+	if threadTS := statusThreadTS(channelID); threadTS != "" {
+		err := slackClient.SendThreadReply(channelID, threadTS, text)
+		if err == nil {
+			return nil
+		}
+		log.Printf("Warning: Could not reply in status thread %s, posting top-level: %v", threadTS, err)
+	}
+	return slackClient.SendMessage(channelID, text)
+}
+
+// updateStatusProgress shows the job's current phase and a progress bar on the status message
+// (total <= 0 shows the phase only); updates are throttled unless the phase is complete
+func updateStatusProgress(slackClient *Client, channelID, phase string, done, total int) {
+	statusThreadsMutex.Lock()
+	status, exists := statusThreads[channelID]
+	if !exists || (done < total && time.Since(status.lastUpdated) < statusUpdateInterval) {
+		statusThreadsMutex.Unlock()
+		return
+	}
+	status.lastUpdated = time.Now()
+	ts, title := status.ts, status.title
+	statusThreadsMutex.Unlock()
+
+	progressText := "—"
+	if total > 0 {
+		progressText = "`" + progressBar(done, total) + "`"
+	}
+	blocks := []Block{
+		sectionBlock(title),
+		fieldsBlock("*状態*\n"+phase, "*進捗*\n"+progressText),
+	}
+	if err := slackClient.UpdateBlocks(channelID, ts, title, blocks); err != nil {
+		log.Printf("Warning: Could not update status message in channel %s: %v", channelID, err)
+	}
+}
+
+// finishStatusMessage turns the status message into a summary with result fields and an "Open sheet" button
+func finishStatusMessage(slackClient *Client, channelID, headline string, fields []string, sheetURL string) {
+	statusThreadsMutex.Lock()
+	status, exists := statusThreads[channelID]
+	statusThreadsMutex.Unlock()
+	if !exists {
+		return
+	}
+
+	blocks := []Block{sectionBlock(headline)}
+	if len(fields) > 0 {
+		blocks = append(blocks, fieldsBlock(fields...))
+	}
+	if sheetURL != "" {
+		blocks = append(blocks, linkButtonBlock("open_sheet", "シートを開く", sheetURL))
+	}
+	if err := slackClient.UpdateBlocks(channelID, status.ts, headline, blocks); err != nil {
+		log.Printf("Warning: Could not update status message in channel %s: %v", channelID, err)
+	}
+}
+
+// failStatusMessage marks the status message as failed; details are posted in its thread
+func failStatusMessage(slackClient *Client, channelID string) {
+	statusThreadsMutex.Lock()
+	status, exists := statusThreads[channelID]
+	statusThreadsMutex.Unlock()
+	if !exists {
+		return
+	}
+
+	headline := fmt.Sprintf("%s\n❌ 処理に失敗しました。詳細はスレッドを確認してください。", status.title)
+	finishStatusMessage(slackClient, channelID, headline, nil, "")
+}
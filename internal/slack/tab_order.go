@@ -0,0 +1,30 @@
+package slack
+
+import (
+	"log"
+	"time"
+
+	"slack-to-google-sheets-bot/internal/config"
+)
+
+// arrangeSheetTabs orders channel tabs by recent activity and colors them by recency
+func arrangeSheetTabs(cfg *config.Config) {
+	if !cfg.ArrangeTabs {
+		return
+	}
+
+	sheetsClient, err := newSheetsClient(cfg)
+	if err != nil {
+		log.Printf("Error creating Google Sheets client for tab arrangement: %v", err)
+		return
+	}
+
+	for _, spreadsheetID := range allSpreadsheetIDs(cfg) {
+		arranged, err := sheetsClient.ArrangeTabs(spreadsheetID, time.Now().In(jstLocation))
+		if err != nil {
+			log.Printf("Error arranging sheet tabs of spreadsheet %s: %v", spreadsheetID, err)
+			continue
+		}
+		log.Printf("Arranged %d channel tabs of spreadsheet %s by activity", arranged, spreadsheetID)
+	}
+}
@@ -0,0 +1,119 @@
+package slack
+
+import (
+	"fmt"
+	"log"
+
+	"slack-to-google-sheets-bot/internal/audit"
+	"slack-to-google-sheets-bot/internal/config"
+	"slack-to-google-sheets-bot/internal/sheets"
+)
+
+// isThreadExportReaction reports whether a reaction_added event asks for the export of a thread
+func isThreadExportReaction(cfg *config.Config, event *Event) bool {
+	item := event.Event.Item
+	return cfg.ThreadExportEmoji != "" && event.Event.Type == "reaction_added" &&
+		event.Event.Reaction == cfg.ThreadExportEmoji && item != nil && item.Type == "message"
+}
+
+// handleThreadExportReaction exports the thread of a message an admin reacted to with THREAD_EXPORT_EMOJI (the
+// parent and every reply) to its own tab of the channel's spreadsheet, and replies in the thread with the link
+func handleThreadExportReaction(cfg *config.Config, event *Event) error {
+	if cfg.GoogleSheetsCredentials == "" || cfg.SpreadsheetID == "" {
+		return nil
+	}
+	item := event.Event.Item
+	if !cfg.IsAdmin(event.Event.User) {
+		log.Printf("Ignoring thread export of %s requested by non-admin %s", item.Timestamp, event.Event.User)
+		return nil
+	}
+
+	slackClient := newClient(cfg)
+	channelInfo, err := slackClient.GetChannelInfo(item.Channel)
+	if err != nil {
+		return fmt.Errorf("failed to get channel info for thread export: %v", err)
+	}
+	if refusesSharedChannel(cfg, channelInfo) {
+		return nil
+	}
+
+	threadTS := item.Timestamp
+	messages, err := slackClient.getThreadMessages(item.Channel, threadTS)
+	if err != nil {
+		return fmt.Errorf("failed to get thread %s for export: %v", threadTS, err)
+	}
+	// A reaction to a reply exports the whole thread it belongs to
+	if len(messages) > 0 && messages[0].ThreadTS != "" && messages[0].ThreadTS != messages[0].Timestamp {
+		threadTS = messages[0].ThreadTS
+		if messages, err = slackClient.getThreadMessages(item.Channel, threadTS); err != nil {
+			return fmt.Errorf("failed to get thread %s for export: %v", threadTS, err)
+		}
+	}
+
+	var records []*sheets.MessageRecord
+	for _, message := range messages {
+		if message.Type == "message" {
+			records = append(records, slackClient.historyMessageRecord(item.Channel, channelInfo.Name, message))
+		}
+	}
+	records = filterRecordsByOptOut(cfg, filterRecordsBySchedule(cfg, item.Channel, records))
+	if len(records) == 0 {
+		log.Printf("Thread %s in channel %s has no messages to export", threadTS, item.Channel)
+		return nil
+	}
+	enrichRecords(cfg, slackClient, records)
+	for _, record := range records {
+		record.TraceID = event.TraceID
+	}
+
+	sheetsClient, err := newSheetsClient(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create Google Sheets client for thread export: %v", err)
+	}
+	spreadsheetID := channelSpreadsheetID(cfg, sheetsClient, item.Channel)
+	sheetName := sheets.ThreadExportSheetName(channelInfo.Name, threadTS)
+	sheetID, err := sheetsClient.WriteThreadExport(spreadsheetID, sheetName, records)
+	if err != nil {
+		if replyErr := slackClient.SendThreadReply(item.Channel, threadTS, "❌ スレッドの書き出しに失敗しました。しばらく時間をおいてから再度お試しください。"); replyErr != nil {
+			log.Printf("Error sending thread export failure: %v", replyErr)
+		}
+		return err
+	}
+
+	sheetURL := fmt.Sprintf("https://docs.google.com/spreadsheets/d/%s/edit?gid=%d#gid=%d", spreadsheetID, sheetID, sheetID)
+	message := fmt.Sprintf("📤 このスレッドの %d件のメッセージをシート「%s」に書き出しました。\n記録先: %s", len(records), sheetName, sheetURL)
+	if err := slackClient.SendThreadReply(item.Channel, threadTS, message); err != nil {
+		log.Printf("Error sending thread export link: %v", err)
+	}
+	recordAudit(cfg, audit.Entry{
+		Action:    "thread_exported",
+		ChannelID: item.Channel,
+		User:      event.Event.User,
+		Detail:    fmt.Sprintf("#%s: thread %s, %d messages to %s", channelInfo.Name, threadTS, len(records), sheetName),
+	})
+	return nil
+}
+
+// historyMessageRecord converts a message of conversations.history or conversations.replies to a record
+func (c *Client) historyMessageRecord(channelID, channelName string, msg HistoryMessage) *sheets.MessageRecord {
+	userInfo := c.messageAuthor(msg.User, msg.BotID, msg.Username, msg.UserTeam, msg.BotProfile, msg.UserProfile)
+	return &sheets.MessageRecord{
+		Timestamp:     convertSlackTimestampToJST(msg.Timestamp),
+		Channel:       channelID,
+		ChannelName:   channelName,
+		User:          msg.User,
+		UserHandle:    userInfo.Name,
+		UserRealName:  userInfo.RealName,
+		Text:          c.FormatMessageWithAttachments(msg.Subtype, messageText(systemMessageText(msg.Subtype, msg.Text, msg.Topic, msg.Purpose, msg.User, msg.Inviter, msg.Room), msg.Blocks), msg.Attachments, msg.Files),
+		ThreadTS:      msg.ThreadTS,
+		MessageTS:     msg.Timestamp,
+		AppSource:     appSource(msg.AppID, msg.BotProfile),
+		EditorID:      editorID(msg.Edited),
+		Reactions:     formatReactions(msg.Reactions),
+		ReactionCount: reactionCount(msg.Reactions),
+		ReplyCount:    msg.ReplyCount,
+		Files:         fileRecords(msg.Files),
+		Pinned:        len(msg.PinnedTo) > 0,
+		TeamID:        msg.Team,
+	}
+}
@@ -0,0 +1,92 @@
+package slack
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"slack-to-google-sheets-bot/internal/config"
+	"slack-to-google-sheets-bot/internal/sheets"
+)
+
+const (
+	// ThreadMirrorModeReaction adds a reaction to the thread parent once the thread concludes
+	ThreadMirrorModeReaction = "reaction"
+	// ThreadMirrorModeNote posts a thread reply linking to the recorded rows once the thread concludes
+	ThreadMirrorModeNote = "note"
+)
+
+var (
+	threadMirrorTimers = make(map[string]*time.Timer)
+	threadMirrorMutex  = sync.Mutex{}
+)
+
+// scheduleThreadMirror (re)starts the idle timer for a thread; when it fires the thread is treated as concluded
+func scheduleThreadMirror(cfg *config.Config, channelID, channelName, threadTS string) {
+	if cfg.ThreadMirrorMode != ThreadMirrorModeReaction && cfg.ThreadMirrorMode != ThreadMirrorModeNote {
+		return
+	}
+
+	idle := time.Duration(cfg.ThreadMirrorIdleMinutes) * time.Minute
+	key := fmt.Sprintf("%s_%s", channelID, threadTS)
+
+	threadMirrorMutex.Lock()
+	defer threadMirrorMutex.Unlock()
+
+	if timer, exists := threadMirrorTimers[key]; exists {
+		timer.Stop()
+	}
+
+	threadMirrorTimers[key] = time.AfterFunc(idle, func() {
+		threadMirrorMutex.Lock()
+		delete(threadMirrorTimers, key)
+		threadMirrorMutex.Unlock()
+
+		if err := mirrorThreadRecord(cfg, channelID, channelName, threadTS); err != nil {
+			log.Printf("Error mirroring thread record for %s in channel %s: %v", threadTS, channelID, err)
+		}
+	})
+}
+
+// stopThreadMirrors cancels the pending thread timers of a channel
+func stopThreadMirrors(channelID string) {
+	threadMirrorMutex.Lock()
+	defer threadMirrorMutex.Unlock()
+
+	for key, timer := range threadMirrorTimers {
+		if strings.HasPrefix(key, channelID+"_") {
+			timer.Stop()
+			delete(threadMirrorTimers, key)
+		}
+	}
+}
+
+// mirrorThreadRecord posts a reaction or thread note pointing at the sheet rows recorded for a concluded thread
+func mirrorThreadRecord(cfg *config.Config, channelID, channelName, threadTS string) error {
+	slackClient := newClient(cfg)
+
+	if cfg.ThreadMirrorMode == ThreadMirrorModeReaction {
+		return slackClient.AddReaction(channelID, threadTS, cfg.ThreadMirrorReaction)
+	}
+
+	sheetsClient, err := newSheetsClient(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create Google Sheets client: %v", err)
+	}
+
+	sheetName := sheets.SheetName(channelID, channelName)
+	firstRow, lastRow, count, err := sheetsClient.FindThreadRows(channelSpreadsheetID(cfg, sheetsClient, channelID), sheetName, threadTS)
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		log.Printf("No recorded rows found for thread %s in sheet %s, skipping mirror", threadTS, sheetName)
+		return nil
+	}
+
+	sheetURL := buildSheetRangeURL(cfg, sheetsClient, channelID, channelName, firstRow, lastRow)
+	note := fmt.Sprintf("📝 このスレッドの %d 件の投稿は<%s|スプレッドシート>に記録されています。", count, sheetURL)
+	return slackClient.SendThreadReply(channelID, threadTS, note)
+}
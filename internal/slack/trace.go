@@ -0,0 +1,30 @@
+package slack
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+)
+
+// NewTraceID returns a short random ID that follows one event through the logs, notifications and sheet rows
+func NewTraceID() string {
+	b := make([]byte, 6)
+	if _, err := rand.Read(b); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(b)
+}
+
+// tracef logs a message prefixed with the event's trace ID (trace=<id>) so one message's journey can be grepped
+func tracef(event *Event, format string, args ...interface{}) {
+	log.Printf("trace=%s event_id=%s %s", event.TraceID, event.EventID, fmt.Sprintf(format, args...))
+}
+
+// traceSuffix returns the line appended to error notifications so support can find the event in the logs
+func traceSuffix(event *Event) string {
+	if event.TraceID == "" {
+		return ""
+	}
+	return fmt.Sprintf("\nトレースID: `%s`", event.TraceID)
+}
@@ -1,13 +1,64 @@
 package slack
 
+import (
+	"encoding/json"
+	"time"
+)
+
 type Event struct {
 	Type      string    `json:"type"`
 	Challenge string    `json:"challenge,omitempty"`
 	Event     EventData `json:"event,omitempty"`
 	TeamID    string    `json:"team_id,omitempty"`
-	APIAppID  string    `json:"api_app_id,omitempty"`
-	EventID   string    `json:"event_id,omitempty"`
-	EventTime int64     `json:"event_time,omitempty"`
+	// EnterpriseID is set for events of an Enterprise Grid organization
+	EnterpriseID string `json:"enterprise_id,omitempty"`
+	APIAppID     string `json:"api_app_id,omitempty"`
+	EventID      string `json:"event_id,omitempty"`
+	EventTime    int64  `json:"event_time,omitempty"`
+
+	// RetryNum and RetryReason come from the X-Slack-Retry-Num / X-Slack-Retry-Reason headers of redelivered events
+	RetryNum    int    `json:"-"`
+	RetryReason string `json:"-"`
+	// TraceID is assigned on receipt and appears in logs, error notifications and (optionally) the sheet row
+	TraceID string `json:"-"`
+	// ReceivedAt is when the event reached the bot, for measuring the latency until its row is written
+	ReceivedAt time.Time `json:"-"`
+}
+
+// SlashCommand is the form payload Slack posts to /slack/commands
+type SlashCommand struct {
+	Command      string // e.g. "/sheetbot"
+	Text         string // Everything after the command name
+	TeamID       string
+	EnterpriseID string // Set on Enterprise Grid
+	ChannelID    string
+	UserID       string
+	ResponseURL  string
+	TriggerID    string
+}
+
+// InteractionPayload is the "payload" form field Slack posts to /slack/interactivity when a button is clicked
+type InteractionPayload struct {
+	Type        string        `json:"type"` // block_actions
+	TriggerID   string        `json:"trigger_id"`
+	ResponseURL string        `json:"response_url"`
+	User        IDField       `json:"user"`
+	Channel     IDField       `json:"channel"`
+	Team        IDField       `json:"team"`
+	Enterprise  IDField       `json:"enterprise"` // Set on Enterprise Grid
+	Actions     []BlockAction `json:"actions"`
+}
+
+// IDField is an object of an interaction payload that is only read for its ID
+type IDField struct {
+	ID string `json:"id"`
+}
+
+// BlockAction is one clicked Block Kit element
+type BlockAction struct {
+	ActionID string `json:"action_id"`
+	BlockID  string `json:"block_id"`
+	Value    string `json:"value"`
 }
 
 type EventData struct {
@@ -20,22 +71,126 @@ type EventData struct {
 	EventTS     string          `json:"event_ts,omitempty"`
 	ChannelType string          `json:"channel_type,omitempty"`
 	Inviter     string          `json:"inviter,omitempty"`
+	BotID       string          `json:"bot_id,omitempty"`      // Set when the message was posted by a bot
+	Username    string          `json:"username,omitempty"`    // Display name some integrations post with
+	AppID       string          `json:"app_id,omitempty"`      // Set when the message was posted through an app
+	BotProfile  *BotProfile     `json:"bot_profile,omitempty"` // Name of the app that posted the message
 	Message     *MessageChanged `json:"message,omitempty"`     // For message_changed events
+	Root        *MessageChanged `json:"root,omitempty"`        // Thread parent of thread_broadcast messages
 	Subtype     string          `json:"subtype,omitempty"`     // For message subtypes
 	Attachments []Attachment    `json:"attachments,omitempty"` // Message attachments
 	Files       []FileInfo      `json:"files,omitempty"`       // File attachments
+	Blocks      MessageBlocks   `json:"blocks,omitempty"`      // Block Kit content, the only content of some app messages
+	Reaction    string          `json:"reaction,omitempty"`    // For reaction_added / reaction_removed events
+	Item        *ReactionItem   `json:"item,omitempty"`        // The message a reaction or pin was added to or removed from
+	ChannelID   string          `json:"channel_id,omitempty"`  // For pin_added / pin_removed events
+	DeletedTS   string          `json:"deleted_ts,omitempty"`  // For message_deleted events
+	Topic       string          `json:"topic,omitempty"`       // For channel_topic messages
+	Purpose     string          `json:"purpose,omitempty"`     // For channel_purpose messages
+	Room        *HuddleRoom     `json:"room,omitempty"`        // For huddle_thread messages
+	Team        string          `json:"team,omitempty"`        // Workspace the message was posted from
+	UserTeam    string          `json:"user_team,omitempty"`   // Home workspace of the author
+
+	// UserProfile is sent with messages of users from other organizations in Slack Connect channels
+	UserProfile *MessageUserProfile `json:"user_profile,omitempty"`
+
+	// RenamedChannel is set for channel_rename / group_rename events, whose "channel" is an object
+	RenamedChannel *RenamedChannel `json:"-"`
+	// ChangedUser is set for user_change events, whose "user" is an object
+	ChangedUser *UserInfo `json:"-"`
+}
+
+// RenamedChannel is the channel object of a channel_rename / group_rename event
+type RenamedChannel struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// UnmarshalJSON accepts "channel" and "user" both as IDs and as the objects sent with rename and user_change events
+func (e *EventData) UnmarshalJSON(data []byte) error {
+	type eventDataFields EventData
+	var raw struct {
+		eventDataFields
+		Channel json.RawMessage `json:"channel,omitempty"`
+		User    json.RawMessage `json:"user,omitempty"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*e = EventData(raw.eventDataFields)
+
+	if len(raw.User) > 0 && raw.User[0] == '{' {
+		var user UserInfo
+		if err := json.Unmarshal(raw.User, &user); err != nil {
+			return err
+		}
+		e.User = user.ID
+		e.ChangedUser = &user
+	} else if len(raw.User) > 0 && string(raw.User) != "null" {
+		if err := json.Unmarshal(raw.User, &e.User); err != nil {
+			return err
+		}
+	}
+
+	if len(raw.Channel) == 0 || string(raw.Channel) == "null" {
+		return nil
+	}
+	if raw.Channel[0] == '{' {
+		var renamed RenamedChannel
+		if err := json.Unmarshal(raw.Channel, &renamed); err != nil {
+			return err
+		}
+		e.Channel = renamed.ID
+		e.RenamedChannel = &renamed
+		return nil
+	}
+	return json.Unmarshal(raw.Channel, &e.Channel)
+}
+
+// ReactionItem is the item of a reaction or pin event
+type ReactionItem struct {
+	Type      string          `json:"type"`
+	Channel   string          `json:"channel,omitempty"`
+	Timestamp string          `json:"ts,omitempty"`
+	Message   *MessageChanged `json:"message,omitempty"` // The pinned message of pin_added / pin_removed events
+}
+
+// Reaction is one emoji on a message and how many users reacted with it
+type Reaction struct {
+	Name  string   `json:"name"`
+	Count int      `json:"count"`
+	Users []string `json:"users,omitempty"`
 }
 
 // MessageChanged represents the structure of a changed message in Slack
 type MessageChanged struct {
-	Type        string       `json:"type"`
-	User        string       `json:"user,omitempty"`
-	Text        string       `json:"text,omitempty"`
-	Timestamp   string       `json:"ts,omitempty"`
-	ThreadTS    string       `json:"thread_ts,omitempty"`
-	Edited      *EditInfo    `json:"edited,omitempty"`
-	Attachments []Attachment `json:"attachments,omitempty"`
-	Files       []FileInfo   `json:"files,omitempty"`
+	Type        string        `json:"type"`
+	Subtype     string        `json:"subtype,omitempty"`
+	User        string        `json:"user,omitempty"`
+	Text        string        `json:"text,omitempty"`
+	Timestamp   string        `json:"ts,omitempty"`
+	ThreadTS    string        `json:"thread_ts,omitempty"`
+	Edited      *EditInfo     `json:"edited,omitempty"`
+	BotID       string        `json:"bot_id,omitempty"`
+	Username    string        `json:"username,omitempty"`
+	AppID       string        `json:"app_id,omitempty"`
+	BotProfile  *BotProfile   `json:"bot_profile,omitempty"`
+	Attachments []Attachment  `json:"attachments,omitempty"`
+	Files       []FileInfo    `json:"files,omitempty"`
+	Blocks      MessageBlocks `json:"blocks,omitempty"`
+	Reactions   []Reaction    `json:"reactions,omitempty"`
+	PinnedTo    []string      `json:"pinned_to,omitempty"`
+	ReplyCount  int           `json:"reply_count,omitempty"`
+	UserTeam    string        `json:"user_team,omitempty"`
+
+	UserProfile *MessageUserProfile `json:"user_profile,omitempty"`
+}
+
+// BotProfile is the profile of the app or integration that posted a message
+type BotProfile struct {
+	ID    string `json:"id,omitempty"`
+	AppID string `json:"app_id,omitempty"`
+	Name  string `json:"name,omitempty"`
 }
 
 // EditInfo contains information about when and by whom a message was edited
@@ -81,6 +236,7 @@ type FileInfo struct {
 	PrettyType         string `json:"pretty_type,omitempty"`
 	User               string `json:"user,omitempty"`
 	Mode               string `json:"mode,omitempty"`
+	FileAccess         string `json:"file_access,omitempty"` // "check_file_info" when an event only carries the file ID
 	Editable           bool   `json:"editable,omitempty"`
 	IsExternal         bool   `json:"is_external,omitempty"`
 	ExternalType       string `json:"external_type,omitempty"`
@@ -0,0 +1,41 @@
+package slack
+
+import (
+	"log"
+	"sync"
+	"time"
+)
+
+// userChanges records when each user last changed their name or profile so cached user info can be invalidated
+var (
+	userChanges      = make(map[string]time.Time)
+	userChangesMutex sync.Mutex
+)
+
+// invalidateUser marks the cached info of a user as stale in every client
+func invalidateUser(userID string) {
+	userChangesMutex.Lock()
+	defer userChangesMutex.Unlock()
+	userChanges[userID] = time.Now()
+}
+
+// userChangedSince reports whether the user changed their profile after the given time
+func userChangedSince(userID string, since time.Time) bool {
+	userChangesMutex.Lock()
+	defer userChangesMutex.Unlock()
+	changedAt, exists := userChanges[userID]
+	return exists && changedAt.After(since)
+}
+
+// handleUserChange evicts the changed user from the user caches so later rows use the new handle and real name
+func handleUserChange(event *Event) error {
+	user := event.Event.ChangedUser
+	if user == nil || user.ID == "" {
+		log.Printf("Ignoring user_change event without user")
+		return nil
+	}
+
+	invalidateUser(user.ID)
+	log.Printf("User %s changed their profile (now @%s / %s), cached user info invalidated", user.ID, user.Name, user.RealName)
+	return nil
+}
@@ -0,0 +1,129 @@
+package slack
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// userGroupCacheTTL is how long the handles from usergroups.list are used before the list is fetched again
+const userGroupCacheTTL = 10 * time.Minute
+
+// userGroupListing is the cached usergroups.list of one token and workspace
+type userGroupListing struct {
+	handles   map[string]string // User group ID -> handle
+	fetchedAt time.Time
+}
+
+var (
+	// userGroupListings caches usergroups.list by workspaceKey; it outlives clients, which are created per event
+	userGroupListings      = make(map[string]*userGroupListing)
+	userGroupListingsMutex sync.Mutex
+)
+
+// userGroupMentionRe matches user group mentions such as <!subteam^S123456|@design> or <!subteam^S123456>
+var userGroupMentionRe = regexp.MustCompile(`<!subteam\^([A-Z0-9]+)(?:\|([^>]*))?>`)
+
+// UserGroupsListResponse is the response of usergroups.list
+type UserGroupsListResponse struct {
+	OK         bool        `json:"ok"`
+	UserGroups []UserGroup `json:"usergroups"`
+}
+
+// UserGroup is a user group (e.g. @design) in usergroups.list
+type UserGroup struct {
+	ID     string `json:"id"`
+	Handle string `json:"handle"`
+}
+
+// formatUserGroupMentions replaces user group mentions with @handle, falling back to the label in the markup
+// when the group cannot be resolved (e.g. without the usergroups:read scope)
+func (c *Client) formatUserGroupMentions(text string) string {
+	if !strings.Contains(text, "<!subteam^") {
+		return text
+	}
+	return userGroupMentionRe.ReplaceAllStringFunc(text, func(match string) string {
+		groups := userGroupMentionRe.FindStringSubmatch(match)
+		if handle := c.userGroupHandle(groups[1]); handle != "" {
+			return "@" + handle
+		}
+		if label := strings.TrimPrefix(groups[2], "@"); label != "" {
+			return "@" + label
+		}
+		return match // Keep original if failed to resolve
+	})
+}
+
+// userGroupHandle returns the handle of a user group, fetching usergroups.list when the cached list is stale
+func (c *Client) userGroupHandle(groupID string) string {
+	userGroupListingsMutex.Lock()
+	defer userGroupListingsMutex.Unlock()
+
+	listing, exists := userGroupListings[c.workspaceKey()]
+	if !exists {
+		listing = &userGroupListing{}
+		userGroupListings[c.workspaceKey()] = listing
+	}
+	if time.Since(listing.fetchedAt) >= userGroupCacheTTL {
+		// Failures are not retried until the TTL has passed, so a missing scope does not cost a call per message
+		listing.fetchedAt = time.Now()
+		handles, err := c.listUserGroups()
+		if err != nil {
+			log.Printf("Warning: Could not list user groups: %v", err)
+		} else {
+			listing.handles = handles
+		}
+	}
+	return listing.handles[groupID]
+}
+
+// listUserGroups returns the handles of the workspace's user groups by ID, including disabled ones so that
+// mentions in old messages still resolve (usergroups.list)
+func (c *Client) listUserGroups() (map[string]string, error) {
+	var listResp UserGroupsListResponse
+	err := retryWithBackoff(func() error {
+		req, err := http.NewRequest("GET", c.withTeam(apiBaseURL+"usergroups.list?include_disabled=true"), nil)
+		if err != nil {
+			return err
+		}
+
+		req.Header.Set("Authorization", "Bearer "+c.token)
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return err
+		}
+
+		listResp = UserGroupsListResponse{}
+		if err := json.Unmarshal(body, &listResp); err != nil {
+			return err
+		}
+
+		if !listResp.OK {
+			return fmt.Errorf("slack API error: %s", string(body))
+		}
+
+		return nil
+	}, "list user groups")
+	if err != nil {
+		return nil, err
+	}
+
+	handles := make(map[string]string, len(listResp.UserGroups))
+	for _, group := range listResp.UserGroups {
+		handles[group.ID] = group.Handle
+	}
+	return handles, nil
+}
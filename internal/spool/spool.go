@@ -0,0 +1,142 @@
+package spool
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"slack-to-google-sheets-bot/internal/sheets"
+	"slack-to-google-sheets-bot/internal/statecrypt"
+)
+
+// Spool keeps records that could not be written to the sheet so they can be retried later
+type Spool struct {
+	tmpDir string
+	mutex  sync.Mutex
+}
+
+// NewSpool creates a new retry spool
+func NewSpool() *Spool {
+	return &Spool{
+		tmpDir: "/tmp/slack-bot-spool",
+	}
+}
+
+// getSpoolFilePath returns the file path of a channel's spooled records
+func (s *Spool) getSpoolFilePath(channelID string) string {
+	return filepath.Join(s.tmpDir, fmt.Sprintf("channel_%s.json", channelID))
+}
+
+// load reads a channel's spooled records; callers must hold the mutex
+func (s *Spool) load(channelID string) ([]*sheets.MessageRecord, error) {
+	data, err := statecrypt.ReadFile(s.getSpoolFilePath(channelID))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read spool file: %v", err)
+	}
+
+	var records []*sheets.MessageRecord
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal spool file: %v", err)
+	}
+	return records, nil
+}
+
+// save writes a channel's spooled records, removing the file when nothing is left; callers must hold the mutex
+func (s *Spool) save(channelID string, records []*sheets.MessageRecord) error {
+	filePath := s.getSpoolFilePath(channelID)
+	if len(records) == 0 {
+		if err := os.Remove(filePath); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to delete spool file: %v", err)
+		}
+		return nil
+	}
+
+	if err := os.MkdirAll(s.tmpDir, 0700); err != nil {
+		return fmt.Errorf("failed to create tmp directory: %v", err)
+	}
+
+	data, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal spool: %v", err)
+	}
+
+	if err := statecrypt.WriteFile(filePath, data); err != nil {
+		return fmt.Errorf("failed to write spool file: %v", err)
+	}
+	return nil
+}
+
+// Add spools records for a channel, ignoring ones that are already spooled (by MessageTS)
+func (s *Spool) Add(channelID string, records []*sheets.MessageRecord) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	existing, err := s.load(channelID)
+	if err != nil {
+		return err
+	}
+
+	spooled := make(map[string]bool)
+	for _, record := range existing {
+		spooled[record.MessageTS] = true
+	}
+	for _, record := range records {
+		if !spooled[record.MessageTS] {
+			existing = append(existing, record)
+			spooled[record.MessageTS] = true
+		}
+	}
+
+	log.Printf("Spooled %d records for retry in channel %s (%d pending)", len(records), channelID, len(existing))
+	return s.save(channelID, existing)
+}
+
+// Pending returns the spooled records of a channel
+func (s *Spool) Pending(channelID string) ([]*sheets.MessageRecord, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.load(channelID)
+}
+
+// HasPending reports whether a channel has spooled records
+func (s *Spool) HasPending(channelID string) bool {
+	_, err := os.Stat(s.getSpoolFilePath(channelID))
+	return err == nil
+}
+
+// Remove drops records from a channel's spool once they have been written
+func (s *Spool) Remove(channelID string, records []*sheets.MessageRecord) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	existing, err := s.load(channelID)
+	if err != nil {
+		return err
+	}
+
+	written := make(map[string]bool)
+	for _, record := range records {
+		written[record.MessageTS] = true
+	}
+
+	var remaining []*sheets.MessageRecord
+	for _, record := range existing {
+		if !written[record.MessageTS] {
+			remaining = append(remaining, record)
+		}
+	}
+	return s.save(channelID, remaining)
+}
+
+// Clear drops all spooled records of a channel
+func (s *Spool) Clear(channelID string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.save(channelID, nil)
+}
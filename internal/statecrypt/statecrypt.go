@@ -0,0 +1,165 @@
+package statecrypt
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+var (
+	// sealedPrefix marks encrypted files; files without it are read as plain text so existing state still loads
+	sealedPrefix = []byte("SBENC1:")
+	// sealedLinePrefix marks encrypted lines of JSON Lines files, which are appended to line by line
+	sealedLinePrefix = []byte("enc:")
+)
+
+var (
+	aead      cipher.AEAD
+	aeadMutex sync.RWMutex
+)
+
+// ParseKey decodes a base64 encoded 32 byte key (e.g. from `openssl rand -base64 32`)
+func ParseKey(value string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(value))
+	if err != nil {
+		return nil, fmt.Errorf("state encryption key is not valid base64: %v", err)
+	}
+	if len(key) != 32 {
+		return nil, fmt.Errorf("state encryption key must be 32 bytes, got %d", len(key))
+	}
+	return key, nil
+}
+
+// LoadKey returns the key given directly or in a file (e.g. a secret manager mount); nil means no encryption
+func LoadKey(value, path string) ([]byte, error) {
+	if value == "" && path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read state encryption key file: %v", err)
+		}
+		value = string(data)
+	}
+	if value == "" {
+		return nil, nil
+	}
+	return ParseKey(value)
+}
+
+// SetKey encrypts local state written from now on with AES-256-GCM
+func SetKey(key []byte) error {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return fmt.Errorf("failed to create cipher: %v", err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return fmt.Errorf("failed to create GCM: %v", err)
+	}
+
+	aeadMutex.Lock()
+	defer aeadMutex.Unlock()
+	aead = gcm
+	return nil
+}
+
+// Enabled reports whether a key is set
+func Enabled() bool {
+	aeadMutex.RLock()
+	defer aeadMutex.RUnlock()
+	return aead != nil
+}
+
+// Seal encrypts data, or returns it unchanged when no key is set
+func Seal(data []byte) ([]byte, error) {
+	aeadMutex.RLock()
+	defer aeadMutex.RUnlock()
+	if aead == nil {
+		return data, nil
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %v", err)
+	}
+	sealed := append([]byte{}, sealedPrefix...)
+	sealed = append(sealed, nonce...)
+	return aead.Seal(sealed, nonce, data, nil), nil
+}
+
+// Open decrypts data written by Seal; plain text data is returned unchanged
+func Open(data []byte) ([]byte, error) {
+	if !bytes.HasPrefix(data, sealedPrefix) {
+		return data, nil
+	}
+
+	aeadMutex.RLock()
+	defer aeadMutex.RUnlock()
+	if aead == nil {
+		return nil, fmt.Errorf("state is encrypted but no STATE_ENCRYPTION_KEY is set")
+	}
+
+	data = data[len(sealedPrefix):]
+	if len(data) < aead.NonceSize() {
+		return nil, fmt.Errorf("encrypted state is truncated")
+	}
+	plaintext, err := aead.Open(nil, data[:aead.NonceSize()], data[aead.NonceSize():], nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt state (wrong key?): %v", err)
+	}
+	return plaintext, nil
+}
+
+// SealLine encrypts one line of a JSON Lines file into a single text line
+func SealLine(line []byte) ([]byte, error) {
+	if !Enabled() {
+		return line, nil
+	}
+	sealed, err := Seal(line)
+	if err != nil {
+		return nil, err
+	}
+	encoded := make([]byte, len(sealedLinePrefix)+base64.StdEncoding.EncodedLen(len(sealed)))
+	copy(encoded, sealedLinePrefix)
+	base64.StdEncoding.Encode(encoded[len(sealedLinePrefix):], sealed)
+	return encoded, nil
+}
+
+// OpenLine decrypts a line written by SealLine; plain text lines are returned unchanged
+func OpenLine(line []byte) ([]byte, error) {
+	if !bytes.HasPrefix(line, sealedLinePrefix) {
+		return line, nil
+	}
+	sealed, err := base64.StdEncoding.DecodeString(string(line[len(sealedLinePrefix):]))
+	if err != nil {
+		return nil, fmt.Errorf("encrypted line is not valid base64: %v", err)
+	}
+	return Open(sealed)
+}
+
+// WriteFile encrypts data and writes it readable by the bot's user only
+func WriteFile(path string, data []byte) error {
+	sealed, err := Seal(data)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, sealed, 0600); err != nil {
+		return err
+	}
+	// Files created before encryption was enabled may still be world-readable
+	return os.Chmod(path, 0600)
+}
+
+// ReadFile reads a file written by WriteFile (or a plain text file from before encryption was enabled)
+func ReadFile(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Open(data)
+}
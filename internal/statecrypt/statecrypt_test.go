@@ -0,0 +1,196 @@
+package statecrypt
+
+import (
+	"bytes"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// useKey sets a fixed key for one test and clears it afterwards
+func useKey(t *testing.T, fill byte) {
+	t.Helper()
+	if err := SetKey(bytes.Repeat([]byte{fill}, 32)); err != nil {
+		t.Fatalf("SetKey: %v", err)
+	}
+	t.Cleanup(func() {
+		aeadMutex.Lock()
+		aead = nil
+		aeadMutex.Unlock()
+	})
+}
+
+func TestSealOpenRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"empty", []byte{}},
+		{"json", []byte(`{"channel":"C123","text":"こんにちは"}`)},
+		{"binary", []byte{0x00, 0xff, 0x10, '\n'}},
+	}
+	useKey(t, 1)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sealed, err := Seal(tt.data)
+			if err != nil {
+				t.Fatalf("Seal: %v", err)
+			}
+			if !bytes.HasPrefix(sealed, sealedPrefix) {
+				t.Fatalf("sealed data does not start with %q", sealedPrefix)
+			}
+			if len(tt.data) > 0 && bytes.Contains(sealed, tt.data) {
+				t.Fatalf("sealed data contains the plain text")
+			}
+
+			opened, err := Open(sealed)
+			if err != nil {
+				t.Fatalf("Open: %v", err)
+			}
+			if !bytes.Equal(opened, tt.data) {
+				t.Fatalf("Open = %q, want %q", opened, tt.data)
+			}
+		})
+	}
+}
+
+func TestSealLineOpenLineRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		line []byte
+	}{
+		{"record", []byte(`{"ts":"1700000000.000100","text":"hello"}`)},
+		{"newline in text", []byte(`{"text":"line 1\nline 2"}`)},
+	}
+	useKey(t, 2)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sealed, err := SealLine(tt.line)
+			if err != nil {
+				t.Fatalf("SealLine: %v", err)
+			}
+			if !bytes.HasPrefix(sealed, sealedLinePrefix) || bytes.ContainsAny(sealed, "\n") {
+				t.Fatalf("sealed line %q is not a single %q line", sealed, sealedLinePrefix)
+			}
+
+			opened, err := OpenLine(sealed)
+			if err != nil {
+				t.Fatalf("OpenLine: %v", err)
+			}
+			if !bytes.Equal(opened, tt.line) {
+				t.Fatalf("OpenLine = %q, want %q", opened, tt.line)
+			}
+		})
+	}
+}
+
+func TestWriteFileReadFileRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	data := []byte(`{"last_ts":"1700000000.000100"}`)
+	useKey(t, 3)
+
+	if err := WriteFile(path, data); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("os.ReadFile: %v", err)
+	}
+	if bytes.Contains(raw, data) {
+		t.Fatalf("file holds the plain text")
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Stat: %v", err)
+	}
+	if mode := info.Mode().Perm(); mode != 0600 {
+		t.Fatalf("file mode = %o, want 600", mode)
+	}
+
+	read, err := ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if !bytes.Equal(read, data) {
+		t.Fatalf("ReadFile = %q, want %q", read, data)
+	}
+}
+
+func TestOpen(t *testing.T) {
+	useKey(t, 4)
+	sealed, err := Seal([]byte("secret"))
+	if err != nil {
+		t.Fatalf("Seal: %v", err)
+	}
+	tampered := append([]byte{}, sealed...)
+	tampered[len(tampered)-1] ^= 0xff
+
+	tests := []struct {
+		name    string
+		data    []byte
+		want    []byte
+		wantErr bool
+	}{
+		{"plain text from before encryption", []byte(`{"a":1}`), []byte(`{"a":1}`), false},
+		{"sealed", sealed, []byte("secret"), false},
+		{"tampered", tampered, nil, true},
+		{"truncated", sealedPrefix, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Open(tt.data)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Open error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !bytes.Equal(got, tt.want) {
+				t.Fatalf("Open = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOpenWithWrongOrMissingKey(t *testing.T) {
+	useKey(t, 5)
+	sealed, err := Seal([]byte("secret"))
+	if err != nil {
+		t.Fatalf("Seal: %v", err)
+	}
+
+	useKey(t, 6)
+	if _, err := Open(sealed); err == nil {
+		t.Fatalf("Open with a different key succeeded")
+	}
+
+	aeadMutex.Lock()
+	aead = nil
+	aeadMutex.Unlock()
+	if _, err := Open(sealed); err == nil {
+		t.Fatalf("Open without a key succeeded")
+	}
+	if plain, err := Seal([]byte("x")); err != nil || string(plain) != "x" {
+		t.Fatalf("Seal without a key = %q, %v; want the data unchanged", plain, err)
+	}
+}
+
+func TestParseKey(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		wantErr bool
+	}{
+		{"32 bytes", base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{7}, 32)), false},
+		{"trailing newline", base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{7}, 32)) + "\n", false},
+		{"16 bytes", base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{7}, 16)), true},
+		{"not base64", "not base64!", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseKey(tt.value); (err != nil) != tt.wantErr {
+				t.Fatalf("ParseKey error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,377 @@
+package store
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"slack-to-google-sheets-bot/internal/sheets"
+	"slack-to-google-sheets-bot/internal/statecrypt"
+)
+
+// channelIDPattern matches Slack conversation IDs (public and private channels, direct and group messages)
+var channelIDPattern = regexp.MustCompile(`^[CDG][A-Z0-9]+$`)
+
+// ValidChannelID reports whether id looks like a Slack conversation ID; only such IDs become file names
+func ValidChannelID(id string) bool {
+	return channelIDPattern.MatchString(id)
+}
+
+// Query describes a read-only search over stored messages
+type Query struct {
+	ChannelID string
+	User      string // Slack user ID or handle
+	From      time.Time
+	To        time.Time
+	Text      string // Case-insensitive substring match
+	Limit     int
+	Offset    int
+}
+
+// Store persists full message records per channel as JSON Lines and serves queries from memory.
+// Later writes for the same MessageTS (edits, deletions) replace earlier ones; queries leave out deleted messages.
+type Store struct {
+	dir      string
+	mutex    sync.RWMutex
+	channels map[string]map[string]*sheets.MessageRecord // channel ID -> MessageTS -> record
+}
+
+// legacyDir is where the store was kept before it moved to a data directory; its files are copied over when the
+// data directory does not exist yet
+const legacyDir = "/tmp/slack-bot-store"
+
+var (
+	defaultStore     *Store
+	defaultStoreOnce sync.Once
+	defaultDir       = "message-store"
+)
+
+// SetDir sets the durable data directory of the default store; call it before Default
+func SetDir(dir string) {
+	defaultDir = dir
+}
+
+// Default returns the process-wide message store shared by the event handlers and the query API
+func Default() *Store {
+	defaultStoreOnce.Do(func() {
+		defaultStore = NewStore(defaultDir)
+		if err := defaultStore.copyLegacyFiles(); err != nil {
+			log.Printf("Warning: Could not copy the message store from %s to %s: %v", legacyDir, defaultDir, err)
+		}
+	})
+	return defaultStore
+}
+
+// NewStore creates a message store kept in dir
+func NewStore(dir string) *Store {
+	return &Store{
+		dir:      dir,
+		channels: make(map[string]map[string]*sheets.MessageRecord),
+	}
+}
+
+// copyLegacyFiles copies the channel files of the old /tmp store into a data directory that does not exist yet
+func (s *Store) copyLegacyFiles() error {
+	if _, err := os.Stat(s.dir); !os.IsNotExist(err) {
+		return nil
+	}
+	entries, err := os.ReadDir(legacyDir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(s.dir, 0700); err != nil {
+		return fmt.Errorf("failed to create store directory: %v", err)
+	}
+	copied := 0
+	for _, entry := range entries {
+		if !strings.HasPrefix(entry.Name(), "channel_") || !strings.HasSuffix(entry.Name(), ".jsonl") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(legacyDir, entry.Name()))
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(s.dir, entry.Name()), data, 0600); err != nil {
+			return err
+		}
+		copied++
+	}
+	log.Printf("Copied %d channel files of the message store from %s to %s", copied, legacyDir, s.dir)
+	return nil
+}
+
+// getChannelFilePath returns the file path for a channel's records
+func (s *Store) getChannelFilePath(channelID string) string {
+	return filepath.Join(s.dir, fmt.Sprintf("channel_%s.jsonl", channelID))
+}
+
+// loadChannel loads a channel's records into memory once; callers must hold the write lock. A channel without a
+// file is not cached, so looking up unknown IDs does not grow the index.
+func (s *Store) loadChannel(channelID string) (map[string]*sheets.MessageRecord, error) {
+	if !ValidChannelID(channelID) {
+		return nil, fmt.Errorf("invalid channel ID %q", channelID)
+	}
+	if records, exists := s.channels[channelID]; exists {
+		return records, nil
+	}
+
+	records := make(map[string]*sheets.MessageRecord)
+
+	file, err := os.Open(s.getChannelFilePath(channelID))
+	if os.IsNotExist(err) {
+		return records, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open store file: %v", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 1024*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line, err := statecrypt.OpenLine(scanner.Bytes())
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt stored record: %v", err)
+		}
+		var record sheets.MessageRecord
+		if err := json.Unmarshal(line, &record); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal stored record: %v", err)
+		}
+		records[record.MessageTS] = &record
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read store file: %v", err)
+	}
+
+	s.channels[channelID] = records
+	return records, nil
+}
+
+// Save appends records to their channel files and updates the in-memory index
+func (s *Store) Save(records []*sheets.MessageRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.save(records)
+}
+
+// MarkDeleted records the status of a deleted message and returns its updated record, or nil when the message is
+// not stored
+func (s *Store) MarkDeleted(channelID, messageTS, status string) (*sheets.MessageRecord, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	records, err := s.loadChannel(channelID)
+	if err != nil {
+		return nil, err
+	}
+	stored, exists := records[messageTS]
+	if !exists {
+		return nil, nil
+	}
+
+	deleted := *stored
+	deleted.Status = status
+	if err := s.save([]*sheets.MessageRecord{&deleted}); err != nil {
+		return nil, err
+	}
+	return &deleted, nil
+}
+
+// save appends records to their channel files; callers must hold the write lock
+func (s *Store) save(records []*sheets.MessageRecord) error {
+	if err := os.MkdirAll(s.dir, 0700); err != nil {
+		return fmt.Errorf("failed to create store directory: %v", err)
+	}
+
+	byChannel := make(map[string][]*sheets.MessageRecord)
+	for _, record := range records {
+		byChannel[record.Channel] = append(byChannel[record.Channel], record)
+	}
+
+	for channelID, channelRecords := range byChannel {
+		existing, err := s.loadChannel(channelID)
+		if err != nil {
+			return err
+		}
+		s.channels[channelID] = existing
+
+		file, err := os.OpenFile(s.getChannelFilePath(channelID), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+		if err != nil {
+			return fmt.Errorf("failed to open store file: %v", err)
+		}
+
+		writer := bufio.NewWriter(file)
+		for _, record := range channelRecords {
+			data, err := json.Marshal(record)
+			if err != nil {
+				file.Close()
+				return fmt.Errorf("failed to marshal record: %v", err)
+			}
+			if data, err = statecrypt.SealLine(data); err != nil {
+				file.Close()
+				return fmt.Errorf("failed to encrypt record: %v", err)
+			}
+			writer.Write(append(data, '\n'))
+
+			stored := *record
+			existing[record.MessageTS] = &stored
+		}
+
+		if err := writer.Flush(); err != nil {
+			file.Close()
+			return fmt.Errorf("failed to write store file: %v", err)
+		}
+		if err := file.Close(); err != nil {
+			return fmt.Errorf("failed to close store file: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// channelIDs lists the channels that have stored records
+func (s *Store) channelIDs() ([]string, error) {
+	entries, err := os.ReadDir(s.dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list store directory: %v", err)
+	}
+
+	var ids []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if strings.HasPrefix(name, "channel_") && strings.HasSuffix(name, ".jsonl") {
+			if id := strings.TrimSuffix(strings.TrimPrefix(name, "channel_"), ".jsonl"); ValidChannelID(id) {
+				ids = append(ids, id)
+			}
+		}
+	}
+	return ids, nil
+}
+
+// ChannelSummary describes the stored records of a single channel
+type ChannelSummary struct {
+	ChannelID    string
+	ChannelName  string
+	MessageCount int
+	FirstMessage time.Time
+	LastMessage  time.Time
+}
+
+// Channels returns a summary of every channel with stored records, ordered by channel ID
+func (s *Store) Channels() ([]ChannelSummary, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	ids, err := s.channelIDs()
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(ids)
+
+	var summaries []ChannelSummary
+	for _, channelID := range ids {
+		records, err := s.loadChannel(channelID)
+		if err != nil {
+			return nil, err
+		}
+
+		summary := ChannelSummary{ChannelID: channelID}
+		for _, record := range records {
+			if record.Status != "" {
+				continue // Deleted in Slack
+			}
+			summary.MessageCount++
+			if summary.FirstMessage.IsZero() || record.Timestamp.Before(summary.FirstMessage) {
+				summary.FirstMessage = record.Timestamp
+			}
+			if record.Timestamp.After(summary.LastMessage) {
+				summary.LastMessage = record.Timestamp
+				summary.ChannelName = record.ChannelName // Latest known name
+			}
+		}
+		summaries = append(summaries, summary)
+	}
+
+	return summaries, nil
+}
+
+// Query returns stored records matching q, oldest first, along with the total number of matches before paging
+func (s *Store) Query(q Query) ([]*sheets.MessageRecord, int, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	channelIDs := []string{q.ChannelID}
+	if q.ChannelID == "" {
+		ids, err := s.channelIDs()
+		if err != nil {
+			return nil, 0, err
+		}
+		channelIDs = ids
+	}
+
+	text := strings.ToLower(q.Text)
+
+	var matches []*sheets.MessageRecord
+	for _, channelID := range channelIDs {
+		records, err := s.loadChannel(channelID)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		for _, record := range records {
+			if record.Status != "" {
+				continue // Deleted in Slack
+			}
+			if q.User != "" && record.User != q.User && record.UserHandle != q.User {
+				continue
+			}
+			if !q.From.IsZero() && record.Timestamp.Before(q.From) {
+				continue
+			}
+			if !q.To.IsZero() && !record.Timestamp.Before(q.To) {
+				continue
+			}
+			if text != "" && !strings.Contains(strings.ToLower(record.Text), text) {
+				continue
+			}
+			matched := *record
+			matches = append(matches, &matched)
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		return matches[i].Timestamp.Before(matches[j].Timestamp)
+	})
+
+	total := len(matches)
+	if q.Offset > 0 {
+		if q.Offset >= len(matches) {
+			return nil, total, nil
+		}
+		matches = matches[q.Offset:]
+	}
+	if q.Limit > 0 && len(matches) > q.Limit {
+		matches = matches[:q.Limit]
+	}
+
+	return matches, total, nil
+}
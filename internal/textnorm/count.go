@@ -0,0 +1,56 @@
+package textnorm
+
+import "unicode"
+
+// scriptClass groups characters into runs that count as one word
+type scriptClass int
+
+const (
+	scriptNone scriptClass = iota
+	scriptWord             // Latin letters, digits and other space-separated scripts
+	scriptHan
+	scriptHiragana
+	scriptKatakana
+)
+
+// classify returns the script class of a rune (scriptNone for spaces and punctuation)
+func classify(r rune) scriptClass {
+	switch {
+	case unicode.Is(unicode.Han, r) || r == '々':
+		return scriptHan
+	case unicode.Is(unicode.Hiragana, r):
+		return scriptHiragana
+	case unicode.Is(unicode.Katakana, r) || r == 'ー':
+		return scriptKatakana
+	case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '\'':
+		return scriptWord
+	}
+	return scriptNone
+}
+
+// CharCount returns the number of characters in text, not counting whitespace
+func CharCount(text string) int {
+	count := 0
+	for _, r := range text {
+		if !unicode.IsSpace(r) {
+			count++
+		}
+	}
+	return count
+}
+
+// WordCount returns an approximate word count that also works for Japanese, which is written without spaces:
+// space-separated words count once each, and within Japanese text every run of kanji, hiragana or katakana
+// counts as one word (e.g. "今日は良い天気" -> 今日/は/良/い/天気 = 5)
+func WordCount(text string) int {
+	count := 0
+	previous := scriptNone
+	for _, r := range text {
+		class := classify(r)
+		if class != scriptNone && class != previous {
+			count++
+		}
+		previous = class
+	}
+	return count
+}
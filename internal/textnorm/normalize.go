@@ -0,0 +1,22 @@
+package textnorm
+
+import (
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// waveDashReplacer unifies look-alike characters that NFKC leaves distinct but Japanese input methods mix freely
+var waveDashReplacer = strings.NewReplacer(
+	"〜", "~", // Wave dash
+	"～", "~", // Fullwidth tilde (already folded by NFKC, kept for clarity)
+	"−", "-", // Minus sign
+	"‐", "-", // Hyphen
+	"―", "-", // Horizontal bar
+)
+
+// Normalize applies NFKC normalization, which unifies zenkaku/hankaku forms
+// (fullwidth ASCII -> halfwidth, halfwidth katakana -> fullwidth), and folds common dash/tilde variants
+func Normalize(text string) string {
+	return waveDashReplacer.Replace(norm.NFKC.String(text))
+}
@@ -6,17 +6,53 @@ import (
 	"io"
 	"log"
 	"net/http"
+	"net/url"
+	"strconv"
+	"sync/atomic"
+	"time"
 
+	"slack-to-google-sheets-bot/internal/api"
+	"slack-to-google-sheets-bot/internal/chaos"
+	"slack-to-google-sheets-bot/internal/completeness"
 	"slack-to-google-sheets-bot/internal/config"
+	"slack-to-google-sheets-bot/internal/instancelock"
+	"slack-to-google-sheets-bot/internal/latency"
+	"slack-to-google-sheets-bot/internal/localsheets"
+	"slack-to-google-sheets-bot/internal/quota"
+	"slack-to-google-sheets-bot/internal/sealedexport"
+	"slack-to-google-sheets-bot/internal/search"
+	"slack-to-google-sheets-bot/internal/settings"
+	"slack-to-google-sheets-bot/internal/sheets"
 	"slack-to-google-sheets-bot/internal/slack"
+	"slack-to-google-sheets-bot/internal/statecrypt"
+	"slack-to-google-sheets-bot/internal/store"
 )
 
+// devSheetsLocal is the DEV_SHEETS value that replaces Google Sheets with local CSV files
+const devSheetsLocal = "local"
+
 func main() {
 	cfg := config.Load()
 
+	// Local CSV files stand in for Google Sheets in development; no credentials or spreadsheet are needed
+	if cfg.DevSheets == devSheetsLocal {
+		endpoint, err := localsheets.Start(cfg.DevSheetsDir)
+		if err != nil {
+			log.Fatalf("Unable to start the local sheets emulator: %v", err)
+		}
+		cfg.GoogleAPIEndpoint = endpoint
+		if cfg.GoogleSheetsCredentials == "" {
+			cfg.GoogleSheetsCredentials = devSheetsLocal
+		}
+		if cfg.SpreadsheetID == "" {
+			cfg.SpreadsheetID = devSheetsLocal
+		}
+		log.Printf("DEV_SHEETS=local: writing spreadsheets as CSV files under %s", cfg.DevSheetsDir)
+	}
+
 	// Validate required configuration
-	if cfg.SlackBotToken == "" || cfg.SlackSigningSecret == "" {
-		log.Fatal("SLACK_BOT_TOKEN and SLACK_SIGNING_SECRET are required")
+	if cfg.SlackSigningSecret == "" || (cfg.SlackBotToken == "" && !cfg.OAuthEnabled()) {
+		log.Fatal("SLACK_SIGNING_SECRET and either SLACK_BOT_TOKEN or SLACK_CLIENT_ID / SLACK_CLIENT_SECRET are required")
 	}
 
 	// Log configuration status
@@ -26,6 +62,85 @@ func main() {
 	log.Printf("  GOOGLE_SHEETS_CREDENTIALS length: %d", len(cfg.GoogleSheetsCredentials))
 	log.Printf("  GOOGLE_SPREADSHEET_ID: %s", maskToken(cfg.SpreadsheetID))
 	log.Printf("  PORT: %s", cfg.Port)
+	log.Printf("  MESSAGE_STORE_ENABLED: %t", cfg.MessageStoreEnabled)
+	log.Printf("  API_TOKENS: %d configured", len(cfg.APITokens))
+
+	// A second instance would write the same progress files and sheets, so only one may run per host; the durable
+	// data directories get their own lock next to them, which also covers other hosts only on a shared volume
+	// whose filesystem supports flock
+	hostLock, err := instancelock.Acquire(instancelock.HostPath)
+	if err != nil {
+		log.Fatalf("Refusing to start: %v", err)
+	}
+	defer hostLock.Release()
+	instanceLock, err := instancelock.Acquire(cfg.InstanceLockFile)
+	if err != nil {
+		log.Fatalf("Refusing to start: %v", err)
+	}
+	defer instanceLock.Release()
+	log.Printf("  INSTANCE_LOCK_FILE: %s", cfg.InstanceLockFile)
+
+	// Encrypt local state that contains message text
+	stateKey, err := statecrypt.LoadKey(cfg.StateEncryptionKey, cfg.StateEncryptionKeyFile)
+	if err != nil {
+		log.Fatalf("Invalid state encryption key: %v", err)
+	}
+	if stateKey != nil {
+		if err := statecrypt.SetKey(stateKey); err != nil {
+			log.Fatalf("Invalid state encryption key: %v", err)
+		}
+		log.Printf("  STATE_ENCRYPTION_KEY: set, local state is encrypted at rest")
+	}
+
+	// Content of channels recorded in "encrypted" mode goes to an archive only the private key holder can read
+	if cfg.ExportPublicKeyFile != "" {
+		if err := sealedexport.LoadPublicKey(cfg.ExportPublicKeyFile, cfg.EncryptedExportDir); err != nil {
+			log.Fatalf("Invalid encrypted export key: %v", err)
+		}
+		log.Printf("  ENCRYPTED_EXPORT_PUBLIC_KEY_FILE: set, archives go to %s", cfg.EncryptedExportDir)
+	}
+
+	// Legal holds must survive restarts, so they live in a data directory rather than under /tmp
+	slack.SetLegalHoldDir(cfg.LegalHoldDir)
+	log.Printf("  LEGAL_HOLD_DIR: %s", cfg.LegalHoldDir)
+
+	// Bot tokens of workspaces installed through the OAuth flow must survive restarts as well
+	slack.SetInstallationDir(cfg.InstallationDir)
+	if cfg.OAuthEnabled() {
+		log.Printf("  INSTALLATION_DIR: %s, %d workspaces in TEAM_SPREADSHEET_IDS", cfg.InstallationDir, len(cfg.TeamSpreadsheetIDs))
+	}
+
+	// Per-spreadsheet service accounts
+	if len(cfg.SpreadsheetCredentials) > 0 {
+		log.Printf("  SPREADSHEET_CREDENTIALS: %d spreadsheets with their own service account", len(cfg.SpreadsheetCredentials))
+		sheets.SetSpreadsheetCredentials(cfg.SpreadsheetCredentials)
+	}
+
+	// Simulated API failures for testing retries and the retry spool; never enable this in production
+	if chaosRates := (chaos.Rates{
+		RateLimit:   cfg.ChaosRateLimitRate,
+		Timeout:     cfg.ChaosTimeoutRate,
+		SheetsWrite: cfg.ChaosSheetsWriteFailureRate,
+	}); chaosRates.Enabled() {
+		log.Printf("  WARNING: injecting API failures (429: %.0f%%, timeout: %.0f%%, Sheets write: %.0f%%)",
+			chaosRates.RateLimit*100, chaosRates.Timeout*100, chaosRates.SheetsWrite*100)
+		chaos.SetRates(chaosRates)
+	}
+
+	// Fake backends used by the load test harness (internal/loadtest)
+	if cfg.SlackAPIBaseURL != "" {
+		log.Printf("  SLACK_API_BASE_URL: %s (not the real Slack API)", cfg.SlackAPIBaseURL)
+		slack.SetAPIBaseURL(cfg.SlackAPIBaseURL)
+	}
+	if cfg.GoogleAPIEndpoint != "" {
+		log.Printf("  GOOGLE_API_ENDPOINT: %s (not the real Google API)", cfg.GoogleAPIEndpoint)
+		sheets.SetAPIEndpoint(cfg.GoogleAPIEndpoint)
+	}
+
+	// The bot's own user ID tells its mentions apart from mentions of other users
+	if cfg.SlackBotToken != "" {
+		slack.ResolveBotUserID(cfg.SlackBotToken)
+	}
 
 	// Health check endpoint
 	http.HandleFunc("/health", handleHealth)
@@ -33,6 +148,70 @@ func main() {
 	// Slack events endpoint
 	http.HandleFunc("/slack/events", handleSlackEvents(cfg))
 
+	// Slash command endpoint, running the same commands as mentions
+	http.HandleFunc("/slack/commands", handleSlackCommands(cfg))
+
+	// Interactive components endpoint for Block Kit buttons
+	http.HandleFunc("/slack/interactivity", handleSlackInteractivity(cfg))
+
+	// OAuth install flow for serving several workspaces from one deployment
+	if cfg.OAuthEnabled() {
+		log.Printf("  SLACK_CLIENT_ID: %s (OAuth install at /slack/oauth/start)", cfg.SlackClientID)
+		http.HandleFunc("/slack/oauth/start", slack.HandleOAuthStart(cfg))
+		http.HandleFunc("/slack/oauth/callback", slack.HandleOAuthCallback(cfg))
+	}
+
+	if cfg.NormalizeSearchText {
+		search.EnableNormalization()
+	}
+	// The message store must survive restarts, and the search index is rebuilt from it
+	store.SetDir(cfg.MessageStoreDir)
+	if cfg.MessageStoreEnabled {
+		log.Printf("  MESSAGE_STORE_DIR: %s", cfg.MessageStoreDir)
+	}
+
+	// Sheets API quota usage metrics and the weekly admin report
+	quota.Default().SetQuotas(cfg.SheetsReadQuotaPerMinute, cfg.SheetsWriteQuotaPerMinute)
+	http.HandleFunc("/api/v1/quota", api.RequireToken(cfg.APITokens, api.HandleQuota(quota.Default())))
+	http.HandleFunc("/api/v1/latency", api.RequireToken(cfg.APITokens, api.HandleLatency(latency.Default())))
+	http.HandleFunc("/api/v1/completeness", api.RequireToken(cfg.APITokens, api.HandleCompleteness(completeness.Default())))
+	slack.StartWeeklyAdminReport(cfg)
+
+	// Event-to-sheet latency SLO
+	latency.Default().SetSLO(time.Duration(cfg.LatencySLOP95Seconds) * time.Second)
+	slack.StartLatencySLOMonitor(cfg)
+
+	// Quarterly review of who the spreadsheets are shared with, and the Google Groups managing access
+	slack.StartAccessReview(cfg)
+	slack.EnsureSpreadsheetGroups(cfg)
+
+	// Move the columns of existing tabs when optional columns were enabled or disabled since the last start
+	slack.MigrateSheetSchemas(cfg)
+
+	// Per-day message counts linking into the channel tabs, and tab ordering by activity
+	slack.StartDailyRollup(cfg)
+
+	// Per-channel settings edited in the spreadsheet's settings tab
+	cfg.ChannelSettings = settings.Default()
+	slack.StartSettingsSheetSync(cfg)
+
+	// Record every channel the bot is a member of, not only the ones it hears about through events
+	slack.StartChannelDiscovery(cfg)
+
+	// Heal the channel tabs from events lost while the bot was down
+	slack.StartIncrementalSync(cfg)
+
+	// Read-only query API over the local message store
+	if cfg.MessageStoreEnabled {
+		if len(cfg.APITokens) == 0 {
+			log.Printf("Warning: API_TOKENS is empty, all query API requests will be rejected")
+		}
+		http.HandleFunc("/api/v1/messages", api.RequireToken(cfg.APITokens, api.HandleMessages(store.Default())))
+		http.HandleFunc("/api/v1/channels", api.RequireToken(cfg.APITokens, api.HandleChannels(store.Default())))
+		http.HandleFunc("/api/v1/search", api.RequireToken(cfg.APITokens, api.HandleSearch(search.Default())))
+		http.HandleFunc("/api/v1/openapi.yaml", api.HandleOpenAPI)
+	}
+
 	fmt.Printf("Server starting on port %s\n", cfg.Port)
 	log.Fatal(http.ListenAndServe(":"+cfg.Port, nil))
 }
@@ -44,10 +223,118 @@ func maskToken(token string) string {
 	return token[:4] + "..." + token[len(token)-4:]
 }
 
+// inFlightEvents is the number of events accepted but still being handled (the event queue depth)
+var inFlightEvents atomic.Int64
+
 func handleHealth(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-	w.Write([]byte(`{"status": "ok"}`))
+	fmt.Fprintf(w, `{"status": "ok", "in_flight_events": %d}`, inFlightEvents.Load())
+}
+
+// handleSlackCommands verifies a slash command request, acknowledges it and runs the command in the background
+func handleSlackCommands(cfg *config.Config) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			log.Printf("Error reading request body: %v", err)
+			http.Error(w, "Bad request", http.StatusBadRequest)
+			return
+		}
+
+		// Verify request signature
+		if !slack.VerifySignature(cfg.SlackSigningSecret, r.Header, body) {
+			log.Printf("Invalid signature")
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		form, err := url.ParseQuery(string(body))
+		if err != nil {
+			log.Printf("Error parsing slash command: %v", err)
+			http.Error(w, "Bad request", http.StatusBadRequest)
+			return
+		}
+		slashCommand := &slack.SlashCommand{
+			Command:      form.Get("command"),
+			Text:         form.Get("text"),
+			TeamID:       form.Get("team_id"),
+			EnterpriseID: form.Get("enterprise_id"),
+			ChannelID:    form.Get("channel_id"),
+			UserID:       form.Get("user_id"),
+			ResponseURL:  form.Get("response_url"),
+			TriggerID:    form.Get("trigger_id"),
+		}
+
+		// Acknowledge within Slack's 3 second limit; replies follow in the channel and through response_url
+		if ack := slack.SlashCommandAck(slashCommand); ack != "" {
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]string{"response_type": "ephemeral", "text": ack})
+		} else {
+			w.WriteHeader(http.StatusOK)
+		}
+
+		inFlightEvents.Add(1)
+		go func() {
+			defer inFlightEvents.Add(-1)
+			if err := slack.HandleSlashCommand(cfg, slashCommand); err != nil {
+				log.Printf("Error handling slash command: %v", err)
+			}
+		}()
+	}
+}
+
+// handleSlackInteractivity verifies a button click, acknowledges it and routes it in the background
+func handleSlackInteractivity(cfg *config.Config) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			log.Printf("Error reading request body: %v", err)
+			http.Error(w, "Bad request", http.StatusBadRequest)
+			return
+		}
+
+		// Verify request signature
+		if !slack.VerifySignature(cfg.SlackSigningSecret, r.Header, body) {
+			log.Printf("Invalid signature")
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		form, err := url.ParseQuery(string(body))
+		if err != nil {
+			log.Printf("Error parsing interaction: %v", err)
+			http.Error(w, "Bad request", http.StatusBadRequest)
+			return
+		}
+		var payload slack.InteractionPayload
+		if err := json.Unmarshal([]byte(form.Get("payload")), &payload); err != nil {
+			log.Printf("Error parsing interaction payload: %v", err)
+			http.Error(w, "Bad request", http.StatusBadRequest)
+			return
+		}
+
+		// Slack expects the acknowledgment within 3 seconds; results are sent through response_url
+		w.WriteHeader(http.StatusOK)
+
+		inFlightEvents.Add(1)
+		go func() {
+			defer inFlightEvents.Add(-1)
+			if err := slack.HandleInteraction(cfg, &payload); err != nil {
+				log.Printf("Error handling interaction: %v", err)
+			}
+		}()
+	}
 }
 
 func handleSlackEvents(cfg *config.Config) http.HandlerFunc {
@@ -78,6 +365,17 @@ func handleSlackEvents(cfg *config.Config) http.HandlerFunc {
 			return
 		}
 
+		// Follow this delivery through the logs, notifications and sheet rows
+		event.TraceID = slack.NewTraceID()
+		event.ReceivedAt = time.Now()
+
+		// Slack redelivers events it believes were not acknowledged in time
+		if retryNum := r.Header.Get("X-Slack-Retry-Num"); retryNum != "" {
+			event.RetryNum, _ = strconv.Atoi(retryNum)
+			event.RetryReason = r.Header.Get("X-Slack-Retry-Reason")
+			log.Printf("trace=%s Received redelivered event %s (retry %s, reason: %s)", event.TraceID, event.EventID, retryNum, event.RetryReason)
+		}
+
 		// Handle URL verification challenge
 		if event.Type == "url_verification" {
 			w.Header().Set("Content-Type", "text/plain")
@@ -87,14 +385,25 @@ func handleSlackEvents(cfg *config.Config) http.HandlerFunc {
 
 		// Handle events
 		if event.Type == "event_callback" {
+			// A redelivery of an event that was already received is acknowledged without handling it again,
+			// and X-Slack-No-Retry asks Slack to stop redelivering it
+			if event.RetryNum > 0 && slack.EventHandled(event.EventID) {
+				log.Printf("trace=%s Event %s was already received, acknowledging retry %d without reprocessing", event.TraceID, event.EventID, event.RetryNum)
+				w.Header().Set("X-Slack-No-Retry", "1")
+				w.WriteHeader(http.StatusOK)
+				return
+			}
+
 			// Response 200 OK immediately because HandleEvent usually takes time
 			// Slack Events API requires 200 OK within 3 seconds : https://api.slack.com/apis/events-api#responding
 			w.WriteHeader(http.StatusOK)
 
 			// Handle the event asynchronously
+			inFlightEvents.Add(1)
 			go func() {
+				defer inFlightEvents.Add(-1)
 				if err := slack.HandleEvent(cfg, &event); err != nil {
-					log.Printf("Error handling event: %v", err)
+					log.Printf("trace=%s Error handling event: %v", event.TraceID, err)
 				}
 			}()
 